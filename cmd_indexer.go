@@ -66,16 +66,6 @@ func waitForIndexerProgress(idx *indexer.Indexer) tea.Cmd {
 	}
 }
 
-func waitForV4BlockScanLine(scanner *helpers.V4BlockScanner) tea.Cmd {
-	return func() tea.Msg {
-		line, ok := <-scanner.Lines()
-		if !ok {
-			return v4BlockScanDoneMsg{}
-		}
-		return v4BlockScanLineMsg{line: line}
-	}
-}
-
 func loadV4PoolTableCmd(s *store.Store) tea.Cmd {
 	return func() tea.Msg {
 		if s == nil {
@@ -93,9 +83,9 @@ func waitForPoolEventData(monitor *helpers.PoolEventMonitor) tea.Cmd {
 	return func() tea.Msg {
 		ev, ok := <-monitor.Events()
 		if !ok {
-			return poolEventMonitorStoppedMsg{}
+			return poolEventMonitorStoppedMsg{monitor: monitor}
 		}
-		return poolMonitorEventMsg{event: ev}
+		return poolMonitorEventMsg{event: ev, monitor: monitor}
 	}
 }
 
@@ -103,8 +93,8 @@ func waitForPoolEvent(monitor *helpers.PoolEventMonitor) tea.Cmd {
 	return func() tea.Msg {
 		line, ok := <-monitor.Lines()
 		if !ok {
-			return poolEventMonitorStoppedMsg{}
+			return poolEventMonitorStoppedMsg{monitor: monitor}
 		}
-		return poolEventLineMsg{line: line}
+		return poolEventLineMsg{line: line, monitor: monitor}
 	}
 }
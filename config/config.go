@@ -12,6 +12,43 @@ type Config struct {
 	Wallets       []WalletEntry `json:"wallets"`
 	Logger        bool          `json:"logger"`
 	WatchedTokens []WatchedToken `json:"watched_tokens,omitempty"`
+	AddressBook   []Contact      `json:"address_book,omitempty"`
+	DApps         []DApp         `json:"dapps,omitempty"`
+	// DappSettings holds each dapp module's own persisted settings, keyed
+	// by module name. The wallet stores them opaquely (see
+	// dapp.Host.LoadSettings).
+	DappSettings map[string]json.RawMessage `json:"dapp_settings,omitempty"`
+	// PoolMonitorPresets are saved pool event monitor filters.
+	PoolMonitorPresets []PoolMonitorPreset `json:"pool_monitor_presets,omitempty"`
+	// AlertRules are the user's alerts. Rules that ask for them are also
+	// POSTed to AlertWebhook and passed to AlertCommand.
+	AlertRules   []AlertRule `json:"alert_rules,omitempty"`
+	AlertWebhook string      `json:"alert_webhook,omitempty"`
+	AlertCommand string      `json:"alert_command,omitempty"`
+}
+
+// AlertRule is an alert rule expression (see helpers.ParseAlertRule).
+type AlertRule struct {
+	Rule     string `json:"rule"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// PoolMonitorPreset is a named pool event monitor filter expression (see
+// helpers.ParsePoolEventFilter).
+type PoolMonitorPreset struct {
+	Name   string `json:"name"`
+	Filter string `json:"filter"`
+}
+
+// Contact is an address book entry: a labelled counterparty that is not one
+// of the user's own wallets. ENS is the .eth name the entry was added by, if
+// any; Address always holds the resolved, checksummed address.
+type Contact struct {
+	Address string   `json:"address"`
+	Label   string   `json:"label"`
+	ENS     string   `json:"ens,omitempty"`
+	Notes   string   `json:"notes,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
 }
 
 // WatchedToken represents a persisted ERC-20 token entry in the watchlist.
@@ -41,13 +78,23 @@ type WalletEntry struct {
 	Active  bool   `json:"active"`
 }
 
-// DApp represents a dApp in the config
+// DApp represents a dApp card in the dApp browser. Built-ins are the
+// registered dapp modules' cards plus DefaultDapps and open their own pages;
+// user-defined entries are saved in the config and open the generic
+// Contract page.
 type DApp struct {
-	Name        string `json:"name"`
-	Address     string `json:"address"`
-	Icon        string `json:"icon,omitempty"`
-	Network     string `json:"network,omitempty"`
-	Description string `json:"description,omitempty"`
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	Icon        string   `json:"icon,omitempty"`
+	Network     string   `json:"network,omitempty"`
+	ChainID     *big.Int `json:"chain_id,omitempty"` // nil: any network
+	Description string   `json:"description,omitempty"`
+	// ABI names an entry in the Contract page's ABI library, or is a path
+	// to an ABI JSON file. Empty means the ABI is asked for on first open.
+	ABI string `json:"abi,omitempty"`
+	// BuiltIn marks module and DefaultDapps entries, which are never
+	// written to the config file and cannot be edited or deleted.
+	BuiltIn bool `json:"-"`
 }
 
 // -------------------- UI TYPE DEFINITIONS --------------------
@@ -61,9 +108,14 @@ const (
 	PageDetails
 	PageSettings
 	PageDappBrowser
-	PageUniswap
-	PageTerraNullius
+	PageDapp // the active dapp.Module's page
 	PageWatchedTokens
+	PageTransactions
+	PageApprovals
+	PageAddressBook
+	PageContract
+	PageBlockExplorer
+	PageTxInspector
 )
 
 // ClickableArea represents a clickable region on screen for addresses
@@ -83,7 +135,7 @@ func Load(path string) Config {
 		// If file doesn't exist, create default config
 		if os.IsNotExist(err) {
 			cfg := DefaultConfig()
-			Save(path, cfg)
+			_ = Save(path, cfg)
 			return cfg
 		}
 		return Config{}
@@ -128,36 +180,28 @@ func DefaultConfig() Config {
 	}
 }
 
-// DefaultDapps returns the built-in dapp list. Dapps are managed here, not in the config file.
+// DefaultDapps returns the built-in cards for pages that are part of the
+// wallet itself rather than dapp modules. The browser lists module cards
+// first, then these, then the user-defined Config.DApps.
 func DefaultDapps() []DApp {
 	return []DApp{
 		{
-			Name:    "Uniswap v4",
-			Address: "0x000000009B1D0aF20D8C6d0A44e162d11F9b8f00",
-			Icon:    "🦄",
-			// Network intentionally left blank: Uniswap v4 is supported on
-			// both mainnet and Sepolia (see helpers.UniswapAddressesForChain),
-			// so its card shows whichever network is actively connected
-			// instead of a fixed label (see views/dapps.Render).
-			Description: "Uniswap is a leading decentralized cryptocurrency exchange (DEX) on the Ethereum blockchain that utilizes an automated market maker (AMM) system to allow users to swap tokens directly from their wallets without intermediaries. It enables anyone to provide liquidity to pools, earning fees in a non-custodial manner.",
-		},
-		{
-			Name:    "Terra Nullius",
-			Address: "0x6e38A457C722C6011B2DfA06d49240e797844d66",
-			Icon:    "🌵",
-			Network: "Mainnet",
-			Description: "The Ethereum Message Board from Block 49,880 (August 7, 2015) — Still Getting Claims\n\n" +
-				"Two weeks after Ethereum's genesis block, a Reddit user named \"Semiel\" deployed one of the earliest smart contracts on the network: TerraNullius.\n\n" +
-				"What it does: Anyone can \"claim\" a hex coordinate and attach a message to it — a permanent, uncensorable message board on the blockchain. No tokens, no governance, no economic incentive. Just messages, forever.",
+			Name:    "Contract",
+			Icon:    "📜",
+			BuiltIn: true,
+			// No fixed address: the page works against any contract, given
+			// its ABI (see the Contract page's ABI library).
+			Description: "Interact with any contract from its ABI\n\n" +
+				"Paste an ABI or load it from a file, then call read functions directly and package write functions as EIP-4527 transactions. ABIs are kept in a local library for reuse.",
 		},
 	}
 }
 
 // Save writes the config to the specified path
-func Save(path string, cfg Config) {
+func Save(path string, cfg Config) error {
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return
+		return err
 	}
-	_ = os.WriteFile(path, data, 0644)
+	return os.WriteFile(path, data, 0644)
 }
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCustomDappsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	dapps := []DApp{
+		{Name: "Vault", Address: "0x00000000000000000000000000000000000000a1", Icon: "🏦",
+			Network: "Sepolia", ChainID: big.NewInt(11155111), Description: "Deposit\nand withdraw", ABI: "vault"},
+		{Name: "Any chain", Address: "0x00000000000000000000000000000000000000b2", ABI: "/tmp/abi.json"},
+		{Name: "Bare", Address: "0x00000000000000000000000000000000000000c3"},
+	}
+	Save(path, Config{DApps: dapps})
+
+	got := Load(path).DApps
+	if !reflect.DeepEqual(got, dapps) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", got, dapps)
+	}
+}
+
+func TestCustomDappsSerialization(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		dapp    DApp
+		want    []string // substrings of the saved JSON
+		notWant []string
+	}{
+		{"built-in flag is never written", DApp{Name: "x", Address: "0x1", BuiltIn: true},
+			nil, []string{"BuiltIn", "built_in"}},
+		{"nil chain means any network", DApp{Name: "x", Address: "0x1"},
+			nil, []string{"chain_id", "abi", "icon"}},
+		{"chain and abi are kept", DApp{Name: "x", Address: "0x1", ChainID: big.NewInt(1), ABI: "erc20"},
+			[]string{`"chain_id": 1`, `"abi": "erc20"`}, nil},
+	} {
+		data, err := json.MarshalIndent(Config{DApps: []DApp{tc.dapp}}, "", "  ")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		for _, s := range tc.want {
+			if !strings.Contains(string(data), s) {
+				t.Errorf("%s: saved JSON lacks %s:\n%s", tc.name, s, data)
+			}
+		}
+		for _, s := range tc.notWant {
+			if strings.Contains(string(data), s) {
+				t.Errorf("%s: saved JSON has %s:\n%s", tc.name, s, data)
+			}
+		}
+	}
+}
+
+func TestLoadConfigWithoutDapps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"rpc_urls": [], "wallets": [], "logger": false}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Load(path).DApps; got != nil {
+		t.Errorf("DApps = %+v, want none for a config saved before custom dApps", got)
+	}
+}
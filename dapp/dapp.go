@@ -0,0 +1,200 @@
+// Package dapp defines the interface a dApp integration implements to plug
+// into the wallet. A module owns its own state, messages, rendering and key
+// handling; the wallet hosts it on a single generic page, forwards it
+// messages and supplies shared services (RPC client, active wallet, logger,
+// transaction packaging) through Host. Adding an integration means writing a
+// package that implements Module and registering it — model.go and the
+// page key dispatch stay untouched.
+package dapp
+
+import (
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/store"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Module is a self-contained dApp integration.
+type Module interface {
+	// Card describes the module's dApp Browser entry. Card().Name is the
+	// module's ID and must be unique across the registry.
+	Card() config.DApp
+
+	// Init is called every time the module's page is opened and resets
+	// whatever per-visit state the module keeps.
+	Init(h Host) tea.Cmd
+
+	// Update receives key messages while the module's page is active, and
+	// every message the wallet itself does not handle (the module's own
+	// command results among them) whether or not it is active.
+	Update(h Host, msg tea.Msg) tea.Cmd
+
+	// View renders the module's page into a width×height content area.
+	View(h Host, width, height int) View
+
+	// KeyBindings lists the keys shown in the page's nav bar. The wallet
+	// appends its own global keys (logger, indexer, back).
+	KeyBindings(h Host) []key.Binding
+
+	// Commands lists entry points offered on the module's dApp Browser card.
+	Commands() []Command
+}
+
+// InputCapturer is implemented by modules that sometimes run a free-text
+// input. While CapturesInput reports true every message goes to Update
+// first and the wallet's single-letter global keys are suspended.
+type InputCapturer interface {
+	CapturesInput() bool
+}
+
+// Command is an entry point into a module, surfaced on its dApp Browser card
+// (e.g. "open liquidity positions"). The wallet opens the module's page,
+// runs Init, then Run.
+type Command struct {
+	Name string
+	Run  func(h Host) tea.Cmd
+}
+
+// View is one rendered frame of a module's page.
+type View struct {
+	// Content is the page body. It is framed in the standard page panel
+	// unless Bare is set.
+	Content string
+	Bare    bool
+	// Regions are clickable areas relative to Content's top-left corner.
+	Regions []Region
+	// Overlay, when non-empty, is a full-screen popup drawn instead of the
+	// page; OverlayRegions are in absolute screen coordinates.
+	Overlay        string
+	OverlayRegions []Region
+}
+
+// Region is a clickable rectangle. X2/Y2 are exclusive. Input regions are
+// click-to-focus fields; the rest are buttons.
+type Region struct {
+	ID      string
+	Input   bool
+	X1, Y1  int
+	X2, Y2  int
+	OnClick func(h Host) tea.Cmd
+}
+
+// Packaged is what a module's build function hands back to Host.Package:
+// the main transaction plus an optional approval that must be signed first.
+type Packaged struct {
+	Summary     string
+	TxJSON      string
+	QR          string
+	ApproveQR   string
+	ApproveJSON string
+}
+
+// TxBuilder packages one unsigned EIP-1559 transaction at the wallet's next
+// free nonce and records it in the outbox. Calls within one Package pass get
+// consecutive nonces. For a Safe wallet the calls are collected into a Safe
+// transaction instead and the returned strings are empty.
+type TxBuilder interface {
+	Build(to common.Address, value *big.Int, gasLimit uint64, data []byte, summary string) (ur, txJSON string, err error)
+}
+
+// Host is the wallet as seen by a module.
+type Host interface {
+	Client() *rpc.Client // nil until an RPC endpoint is connected
+	RPCURL() string
+	ChainID() *big.Int // nil means unknown; treated as mainnet
+	ActiveAddress() string
+	Store() *store.Store // nil if the event store failed to open
+	Wallet() rpc.WalletDetails
+	WatchedTokens() []rpc.WatchedToken // active chain only
+	Spinner() string
+	ScreenSize() (width, height int)
+
+	LogInfo(msg string)
+	LogWarn(msg string)
+	LogError(msg string)
+	LogSuccess(msg string)
+
+	// LoadSettings decodes the module's persisted settings into v and
+	// reports whether any were saved. SaveSettings replaces them and writes
+	// the config file. module is the module's Card().Name.
+	LoadSettings(module string, v any) bool
+	SaveSettings(module string, v any) error
+
+	// Back leaves the module's page for the dApp Browser.
+	Back() tea.Cmd
+
+	// Package shows the transaction dialog in its packaging state and runs
+	// build off the UI goroutine; the result (or error) fills the dialog.
+	Package(build func(b TxBuilder) (Packaged, error)) tea.Cmd
+
+	// SignTypedData shows typed as an EIP-4527 sign request for the active
+	// wallet in the transaction dialog. When the signer's eth-signature is
+	// scanned back and recovers to the active wallet, onSigned runs with it
+	// (typically packaging a transaction that carries the signature); Esc
+	// abandons the request and onSigned never runs.
+	SignTypedData(summary string, typed apitypes.TypedData, onSigned func(h Host, sig [65]byte) tea.Cmd) tea.Cmd
+
+	// The Uniswap V4 pool event monitor is wallet-wide (its panel, pool
+	// info popup and log output are shared), so modules toggle it rather
+	// than owning it.
+	PoolMonitorActive() bool
+	TogglePoolMonitor() tea.Cmd
+	// PoolEventsPanel renders the live V4 events panel, framed; use it as
+	// Bare content.
+	PoolEventsPanel() string
+
+	// OpenBlockExplorer switches to the Block Explorer page, running query
+	// (a block number, tx hash or address) if it is not empty.
+	OpenBlockExplorer(query string) tea.Cmd
+}
+
+// PoolSelectedMsg is broadcast to modules when the user opens a pool from
+// the V4 events panel (o on the selected row, or a double-click).
+type PoolSelectedMsg struct {
+	PoolID common.Hash
+}
+
+var registry []Module
+
+// Register adds m to the registry. Modules are listed in the dApp Browser
+// in registration order; registering a second module under the same name
+// panics.
+func Register(m Module) {
+	if Lookup(m.Card().Name) != nil {
+		panic("dapp: module " + m.Card().Name + " registered twice")
+	}
+	registry = append(registry, m)
+}
+
+// Modules returns the registered modules in registration order.
+func Modules() []Module {
+	return registry
+}
+
+// Lookup returns the module registered under name (case-insensitive), or nil.
+func Lookup(name string) Module {
+	for _, m := range registry {
+		if strings.EqualFold(m.Card().Name, name) {
+			return m
+		}
+	}
+	return nil
+}
+
+// Cards returns the registered modules' dApp Browser cards, marked BuiltIn.
+func Cards() []config.DApp {
+	cards := make([]config.DApp, 0, len(registry))
+	for _, m := range registry {
+		c := m.Card()
+		c.BuiltIn = true
+		cards = append(cards, c)
+	}
+	return cards
+}
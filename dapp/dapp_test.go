@@ -0,0 +1,84 @@
+package dapp
+
+import (
+	"testing"
+
+	"charm-wallet-tui/config"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stubModule is a Module that only has a card.
+type stubModule struct{ name string }
+
+func (s stubModule) Card() config.DApp              { return config.DApp{Name: s.name} }
+func (s stubModule) Init(Host) tea.Cmd              { return nil }
+func (s stubModule) Update(Host, tea.Msg) tea.Cmd   { return nil }
+func (s stubModule) View(Host, int, int) View       { return View{} }
+func (s stubModule) KeyBindings(Host) []key.Binding { return nil }
+func (s stubModule) Commands() []Command            { return nil }
+
+// withRegistry runs f against an empty registry, restoring the real one
+// afterwards.
+func withRegistry(t *testing.T, f func()) {
+	t.Helper()
+	saved := registry
+	registry = nil
+	defer func() { registry = saved }()
+	f()
+}
+
+func TestRegistry(t *testing.T) {
+	withRegistry(t, func() {
+		Register(stubModule{"Uniswap"})
+		Register(stubModule{"Terra"})
+
+		if got := len(Modules()); got != 2 {
+			t.Fatalf("Modules() has %d entries, want 2", got)
+		}
+		if Modules()[0].Card().Name != "Uniswap" || Modules()[1].Card().Name != "Terra" {
+			t.Errorf("Modules() not in registration order: %s, %s", Modules()[0].Card().Name, Modules()[1].Card().Name)
+		}
+		for _, tc := range []struct {
+			name string
+			want string // "" for no module
+		}{
+			{"Uniswap", "Uniswap"},
+			{"uniswap", "Uniswap"},
+			{"TERRA", "Terra"},
+			{"Aave", ""},
+			{"", ""},
+		} {
+			m := Lookup(tc.name)
+			switch {
+			case tc.want == "" && m != nil:
+				t.Errorf("Lookup(%q) = %s, want nil", tc.name, m.Card().Name)
+			case tc.want != "" && (m == nil || m.Card().Name != tc.want):
+				t.Errorf("Lookup(%q) = %v, want %s", tc.name, m, tc.want)
+			}
+		}
+
+		cards := Cards()
+		if len(cards) != 2 {
+			t.Fatalf("Cards() has %d entries, want 2", len(cards))
+		}
+		for _, c := range cards {
+			if !c.BuiltIn {
+				t.Errorf("card %s is not marked BuiltIn", c.Name)
+			}
+		}
+	})
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	withRegistry(t, func() {
+		Register(stubModule{"Uniswap"})
+		defer func() {
+			if recover() == nil {
+				t.Error("registering a second module named uniswap did not panic")
+			}
+		}()
+		Register(stubModule{"uniswap"})
+	})
+}
@@ -0,0 +1,355 @@
+// Package terra is the Terra Nullius dApp module: browse the 2015 message
+// board's claims and package a new claim of your own.
+package terra
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/styles"
+	terraview "charm-wallet-tui/views/terra"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Name is the module's registry name and dApp Browser card title.
+const Name = "Terra Nullius"
+
+var keys = []key.Binding{
+	key.NewBinding(key.WithKeys("up", "down", "k", "j"), key.WithHelp("↑/↓", "navigate")),
+	key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("Tab", "next")),
+	key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "select")),
+}
+
+// claimsCountMsg contains the result of a number_of_claims() call
+type claimsCountMsg struct {
+	count *big.Int
+	err   error
+}
+
+// claimQueryMsg contains the result of a claims(uint256) call
+type claimQueryMsg struct {
+	result *helpers.TerraClaimResult
+	err    error
+}
+
+// Module holds the claims browser and claim popup state.
+type Module struct {
+	focusedField   int    // 1=Claims, 2=Claim
+	claimsCount    string // display value from number_of_claims()
+	claimsLoading  bool
+	claimInput     string // typed index for claims() query
+	claimResult    *helpers.TerraClaimResult
+	claimQuerying  bool
+	lastQueriedIdx string // index used for the current/last result
+	claimResultErr string
+
+	// Claim popup
+	popupOpen    bool
+	msgInput     textinput.Model
+	popupFocused int // 0=message input, 1=submit button
+	msgError     string
+}
+
+// New returns the Terra Nullius module.
+func New() *Module {
+	in := textinput.New()
+	in.Placeholder = "Enter your message…"
+	in.Prompt = ""
+	in.TextStyle = lipgloss.NewStyle().Foreground(styles.CText)
+	in.Cursor.Style = lipgloss.NewStyle().Foreground(styles.CAccent2)
+	in.CharLimit = 256
+	in.Width = 44
+	return &Module{focusedField: 1, claimInput: "0", msgInput: in}
+}
+
+func (t *Module) Card() config.DApp {
+	return config.DApp{
+		Name:    Name,
+		Address: helpers.TerraContractAddress,
+		Icon:    "🌵",
+		Network: "Mainnet",
+		Description: "The Ethereum Message Board from Block 49,880 (August 7, 2015) — Still Getting Claims\n\n" +
+			"Two weeks after Ethereum's genesis block, a Reddit user named \"Semiel\" deployed one of the earliest smart contracts on the network: TerraNullius.\n\n" +
+			"What it does: Anyone can \"claim\" a hex coordinate and attach a message to it — a permanent, uncensorable message board on the blockchain. No tokens, no governance, no economic incentive. Just messages, forever.",
+	}
+}
+
+func (t *Module) Init(h dapp.Host) tea.Cmd {
+	t.focusedField = 1
+	t.claimsCount = ""
+	t.claimsLoading = true
+	t.claimInput = "0"
+	t.claimResult = nil
+	t.claimQuerying = false
+	t.claimResultErr = ""
+	t.closePopup()
+	h.LogInfo("Terra Nullius: loading number of claims…")
+	return fetchNumberOfClaims(h.Client())
+}
+
+func (t *Module) Commands() []dapp.Command {
+	return []dapp.Command{{Name: "New claim", Run: func(h dapp.Host) tea.Cmd {
+		t.focusedField = 2
+		return t.openPopup()
+	}}}
+}
+
+func (t *Module) KeyBindings(h dapp.Host) []key.Binding {
+	return keys
+}
+
+// CapturesInput reports whether the claim popup's message input is open.
+func (t *Module) CapturesInput() bool {
+	return t.popupOpen
+}
+
+func (t *Module) Update(h dapp.Host, msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case claimsCountMsg:
+		t.claimsLoading = false
+		if msg.err != nil {
+			t.claimsCount = "Error"
+			h.LogError(fmt.Sprintf("Terra Nullius number_of_claims failed: %s", msg.err.Error()))
+		} else {
+			t.claimsCount = msg.count.String()
+			h.LogSuccess(fmt.Sprintf("Terra Nullius: %s total claims", msg.count.String()))
+		}
+		return nil
+	case claimQueryMsg:
+		t.claimQuerying = false
+		if msg.err != nil {
+			t.claimResultErr = msg.err.Error()
+			t.claimResult = nil
+			h.LogError(fmt.Sprintf("Terra Nullius claims query failed: %s", msg.err.Error()))
+		} else {
+			t.claimResult = msg.result
+			t.claimResultErr = ""
+			h.LogSuccess(fmt.Sprintf("Terra Nullius claim #%s by %s", t.lastQueriedIdx, helpers.ShortenAddr(msg.result.Claimant)))
+		}
+		return nil
+	}
+
+	if t.popupOpen {
+		return t.updatePopup(h, msg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		return t.handleKey(h, keyMsg)
+	}
+	return nil
+}
+
+func (t *Module) View(h dapp.Host, width, height int) dapp.View {
+	card := t.Card()
+	c, geo := terraview.Render(width, height, t.focusedField, card.Description,
+		t.claimsCount, t.claimsLoading,
+		t.claimInput, t.claimQuerying,
+		t.lastQueriedIdx, t.claimResult, t.claimResultErr)
+
+	v := dapp.View{
+		Content: c,
+		Regions: []dapp.Region{
+			{ID: "terra.claimsBox", Input: true,
+				X1: geo.ClaimsX1, Y1: geo.ClaimsY, X2: geo.ClaimsX2, Y2: geo.ClaimsY + geo.ClaimsH,
+				OnClick: func(dapp.Host) tea.Cmd {
+					t.focusedField = 1
+					return nil
+				}},
+			{ID: "terra.claimBox",
+				X1: geo.ClaimX1, Y1: geo.ClaimY, X2: geo.ClaimX2, Y2: geo.ClaimY + geo.ClaimH,
+				OnClick: func(dapp.Host) tea.Cmd {
+					t.focusedField = 2
+					return t.openPopup()
+				}},
+		},
+	}
+
+	if t.popupOpen {
+		sw, sh := h.ScreenSize()
+		popup, pgeo := terraview.RenderClaimPopup(sw, sh, t.msgInput.View(), t.msgError, t.popupFocused)
+		v.Overlay = popup
+		v.OverlayRegions = []dapp.Region{
+			{ID: "terraClaim.submit",
+				X1: pgeo.ButtonX1, Y1: pgeo.ButtonY, X2: pgeo.ButtonX2, Y2: pgeo.ButtonY + 1,
+				OnClick: t.submitClaim},
+			{ID: "terraClaim.input", Input: true,
+				X1: pgeo.InputX1, Y1: pgeo.InputY, X2: pgeo.InputX2, Y2: pgeo.InputY + 1,
+				OnClick: func(dapp.Host) tea.Cmd {
+					if t.popupFocused != 0 {
+						t.popupFocused = 0
+						return t.msgInput.Focus()
+					}
+					return nil
+				}},
+		}
+	}
+	return v
+}
+
+// openPopup opens the claim-message popup, focused and ready for input.
+// Shared by the keyboard Enter-on-Claim-box path and the Claim box's
+// mouse-click region.
+func (t *Module) openPopup() tea.Cmd {
+	t.popupOpen = true
+	t.popupFocused = 0
+	t.msgInput.SetValue("")
+	t.msgError = ""
+	return t.msgInput.Focus()
+}
+
+func (t *Module) closePopup() {
+	t.popupOpen = false
+	t.msgInput.Blur()
+	t.msgError = ""
+}
+
+// submitClaim validates and packages the claim message. Shared by the
+// keyboard Enter-on-button path and the popup's "Send Claim" button.
+func (t *Module) submitClaim(h dapp.Host) tea.Cmd {
+	message := strings.TrimSpace(t.msgInput.Value())
+	if message == "" {
+		t.msgError = "must not be blank"
+		t.popupFocused = 0
+		return t.msgInput.Focus()
+	}
+	t.closePopup()
+	h.LogInfo(fmt.Sprintf("Terra Nullius: packaging claim → \"%s\"", message))
+
+	rpcURL := h.RPCURL()
+	from := common.HexToAddress(h.ActiveAddress())
+	return h.Package(func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		calldata := helpers.BuildTerraClaimCalldata(message)
+		contract := common.HexToAddress(helpers.TerraContractAddress)
+		gasLimit, err := rpc.EstimateGasWithBuffer(rpcURL, from, contract, big.NewInt(0), calldata)
+		if err != nil {
+			return dapp.Packaged{}, fmt.Errorf("eth_estimateGas: %w", err)
+		}
+		p := dapp.Packaged{Summary: fmt.Sprintf("Terra Nullius claim: \"%s\"\nContract: %s", message, helpers.TerraContractAddress)}
+		p.QR, p.TxJSON, err = b.Build(contract, big.NewInt(0), gasLimit, calldata, p.Summary)
+		return p, err
+	})
+}
+
+func (t *Module) updatePopup(h dapp.Host, msg tea.Msg) tea.Cmd {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			t.closePopup()
+			return nil
+		case "tab", "down", "shift+tab", "up":
+			if t.popupFocused == 0 {
+				t.popupFocused = 1
+				t.msgInput.Blur()
+				return nil
+			}
+			t.popupFocused = 0
+			return t.msgInput.Focus()
+		case "enter":
+			if t.popupFocused == 0 {
+				// Move to button
+				t.popupFocused = 1
+				t.msgInput.Blur()
+				return nil
+			}
+			return t.submitClaim(h)
+		}
+	}
+	// Everything else (typing, cursor blink) goes to the input while it
+	// has focus.
+	if t.popupFocused == 0 {
+		var cmd tea.Cmd
+		t.msgInput, cmd = t.msgInput.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (t *Module) handleKey(h dapp.Host, msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		return h.Back()
+
+	case "up", "k":
+		if t.focusedField > 1 {
+			t.focusedField--
+		}
+
+	case "down", "j":
+		if t.focusedField < 2 {
+			t.focusedField++
+		}
+
+	case "tab", "shift+tab":
+		if t.focusedField == 1 {
+			t.focusedField = 2
+		} else {
+			t.focusedField = 1
+		}
+
+	case "enter":
+		if t.focusedField == 2 {
+			return t.openPopup()
+		}
+		// Execute claims(index) query
+		if t.claimInput == "" {
+			t.claimResultErr = "enter a claim index"
+			return nil
+		}
+		idx, ok := new(big.Int).SetString(t.claimInput, 10)
+		if !ok || idx.Sign() < 0 {
+			t.claimResultErr = "invalid index"
+			return nil
+		}
+		t.claimQuerying = true
+		t.claimResult = nil
+		t.claimResultErr = ""
+		t.lastQueriedIdx = t.claimInput
+		h.LogInfo(fmt.Sprintf("Terra Nullius: querying claim #%s", t.claimInput))
+		t.claimInput = new(big.Int).Add(idx, big.NewInt(1)).String()
+		return fetchClaim(h.Client(), idx)
+
+	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if t.focusedField == 1 {
+			t.claimInput += msg.String()
+			t.claimResult = nil
+			t.claimResultErr = ""
+		}
+
+	case "backspace":
+		if t.focusedField == 1 && len(t.claimInput) > 0 {
+			t.claimInput = t.claimInput[:len(t.claimInput)-1]
+			t.claimResult = nil
+			t.claimResultErr = ""
+		}
+	}
+	return nil
+}
+
+func fetchNumberOfClaims(client *rpc.Client) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return claimsCountMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		count, err := helpers.GetTerraNumberOfClaims(client.Client)
+		return claimsCountMsg{count, err}
+	}
+}
+
+func fetchClaim(client *rpc.Client, index *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return claimQueryMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		result, err := helpers.GetTerraClaim(client.Client, index)
+		return claimQueryMsg{result, err}
+	}
+}
@@ -0,0 +1,199 @@
+package uniswap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// poolQuotesMsg carries a quote from every direct pool for the pair, for the
+// swap view's comparison table. fromIdx/toIdx/amount identify the quote it
+// was fetched for, so a stale result can be dropped.
+type poolQuotesMsg struct {
+	fromIdx, toIdx int
+	amount         string
+	quotes         []helpers.PoolQuote
+}
+
+// fetchPoolQuotes quotes amountIn through each of g's direct pools.
+func fetchPoolQuotes(client *rpc.Client, addrs helpers.UniswapNetworkAddresses, g helpers.RouteGraph, amountIn *big.Int, fromIdx, toIdx int, amount string) tea.Cmd {
+	pools := g.Direct()
+	return func() tea.Msg {
+		msg := poolQuotesMsg{fromIdx: fromIdx, toIdx: toIdx, amount: amount}
+		if client == nil || client.Client == nil {
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		msg.quotes = helpers.QuotePools(ctx, client.Client, addrs, pools, g.TokenIn, g.TokenOut, amountIn)
+		return msg
+	}
+}
+
+func (u *Module) handlePoolQuotes(h dapp.Host, msg poolQuotesMsg) tea.Cmd {
+	if msg.fromIdx != u.fromTokenIdx || msg.toIdx != u.toTokenIdx || msg.amount != u.lastQuoteFromAmount {
+		return nil
+	}
+	u.poolQuotes = msg.quotes
+	u.poolChoice = 0
+	return nil
+}
+
+// selectPool makes comparison row idx the one the swap is packaged through:
+// row 0 is the router's best route, row i the direct pool poolQuotes[i-1].
+func (u *Module) selectPool(h dapp.Host, idx int) tea.Cmd {
+	if idx < 0 || idx > len(u.poolQuotes) || idx == u.poolChoice {
+		return nil
+	}
+	if idx == 0 {
+		if u.bestRoute == nil {
+			return nil
+		}
+		u.poolChoice = 0
+		return u.applyRoute(h, *u.bestRoute)
+	}
+	pq := u.poolQuotes[idx-1]
+	if pq.Err != nil || pq.Quote == nil {
+		h.LogWarn(fmt.Sprintf("%s pool has no quote: %v", hopLabel(pq.Pool), pq.Err))
+		return nil
+	}
+	if u.fromAmount == "" {
+		// A failed route quote clears the amount; the pool was quoted for
+		// the last one.
+		u.fromAmount = u.lastQuoteFromAmount
+	}
+	addrs := helpers.UniswapAddressesForChain(h.ChainID())
+	from, to, ok := u.resolveSwapTokens(h)
+	if !ok {
+		return nil
+	}
+	u.poolChoice = idx
+	r := helpers.SingleHopRoute(pq, tokenAddrForLookup(from, addrs.WETH), tokenAddrForLookup(to, addrs.WETH))
+	return u.applyRoute(h, r)
+}
+
+// poolSelectable reports whether comparison row idx has a quote to swap
+// through.
+func (u *Module) poolSelectable(idx int) bool {
+	if idx == 0 {
+		return u.bestRoute != nil
+	}
+	pq := u.poolQuotes[idx-1]
+	return pq.Err == nil && pq.Quote != nil
+}
+
+// nextSelectablePool returns the comparison row step (±1) away from the
+// selected one, skipping rows without a quote, or false when no other row
+// has one.
+func (u *Module) nextSelectablePool(step int) (int, bool) {
+	n := len(u.poolQuotes) + 1
+	idx := u.poolChoice
+	for range n - 1 {
+		idx = (idx + step + n) % n
+		if u.poolSelectable(idx) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// poolRows formats the comparison table: the router's pick, then each direct
+// pool in discovery order. The direct pools are listed even when the router
+// found no route, so one of them can still be picked.
+func (u *Module) poolRows(h dapp.Host) []uniswapview.PoolRow {
+	if len(u.poolQuotes) == 0 || u.estimating {
+		return nil
+	}
+	_, to, ok := u.resolveSwapTokens(h)
+	if !ok {
+		return nil
+	}
+	best := uniswapview.PoolRow{
+		Label:    "Best route",
+		Output:   "no route",
+		Failed:   true,
+		Selected: u.poolChoice == 0,
+	}
+	if u.bestRoute != nil {
+		best.Output = formatAmount(u.bestRoute.AmountOut, to)
+		best.Impact = fmt.Sprintf("%.2f%%", u.bestRoute.PriceImpact)
+		best.Liquidity = fmt.Sprintf("%d hop(s)", len(u.bestRoute.Hops))
+		best.Failed = false
+	}
+	rows := []uniswapview.PoolRow{best}
+	for i, pq := range u.poolQuotes {
+		row := uniswapview.PoolRow{
+			Label:     hopLabel(pq.Pool),
+			Liquidity: poolLiquidity(pq),
+			Selected:  u.poolChoice == i+1,
+		}
+		if pq.Pool.Version == helpers.PoolVersionV4 && pq.Pool.V4Key.Hooks != (common.Address{}) {
+			row.Label += " +hook"
+		}
+		if pq.Err != nil || pq.Quote == nil {
+			row.Failed = true
+			row.Output = "no quote"
+		} else {
+			row.Output = formatAmount(pq.Quote.AmountOut, to)
+			row.Impact = fmt.Sprintf("%.2f%%", pq.Quote.PriceImpact)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// poolRegions makes each comparison row clickable.
+func (u *Module) poolRegions(geo uniswapview.SwapGeometry) []dapp.Region {
+	if geo.PoolX2 == 0 {
+		return nil
+	}
+	regions := make([]dapp.Region, 0, len(u.poolQuotes)+1)
+	for i := 0; i <= len(u.poolQuotes); i++ {
+		idx := i
+		regions = append(regions, dapp.Region{
+			ID: fmt.Sprintf("uniswap.pool.%d", i),
+			X1: geo.PoolX1, Y1: geo.PoolY + i, X2: geo.PoolX2, Y2: geo.PoolY + i + 1,
+			OnClick: func(h dapp.Host) tea.Cmd { return u.selectPool(h, idx) },
+		})
+	}
+	return regions
+}
+
+// formatAmount renders amount in t's units, e.g. "1234.567890 USDC".
+func formatAmount(amount *big.Int, t uniswapview.TokenOption) string {
+	if amount == nil {
+		return "—"
+	}
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals)), nil))
+	return new(big.Float).Quo(new(big.Float).SetInt(amount), divisor).Text('f', 6) + " " + t.Symbol
+}
+
+// poolLiquidity summarizes a pool's depth: the two reserves for V2, the raw
+// in-range liquidity L for V3/V4. Both are only comparable between pools of
+// the same pair.
+func poolLiquidity(pq helpers.PoolQuote) string {
+	if pq.Pool.Version == helpers.PoolVersionV2 {
+		if pq.Quote == nil || pq.Quote.Token0Reserve == nil || pq.Quote.Token1Reserve == nil {
+			return "—"
+		}
+		return fmt.Sprintf("R %s / %s", compactInt(pq.Quote.Token0Reserve), compactInt(pq.Quote.Token1Reserve))
+	}
+	if pq.Pool.Liquidity == nil {
+		return "—"
+	}
+	return "L " + compactInt(pq.Pool.Liquidity)
+}
+
+// compactInt formats x with three significant digits, e.g. "1.23e+18".
+func compactInt(x *big.Int) string {
+	return new(big.Float).SetInt(x).Text('g', 3)
+}
@@ -0,0 +1,100 @@
+package uniswap
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeHost is a dapp.Host with a fixed wallet and token list. Methods the
+// tests don't override panic through the nil embedded interface.
+type fakeHost struct {
+	dapp.Host
+	watched []rpc.WatchedToken
+	warns   []string
+}
+
+func (f *fakeHost) ChainID() *big.Int                 { return big.NewInt(1) }
+func (f *fakeHost) Wallet() rpc.WalletDetails         { return rpc.WalletDetails{EthWei: big.NewInt(1e18)} }
+func (f *fakeHost) WatchedTokens() []rpc.WatchedToken { return f.watched }
+func (f *fakeHost) LogInfo(string)                    {}
+func (f *fakeHost) LogWarn(msg string)                { f.warns = append(f.warns, msg) }
+func (f *fakeHost) LogError(string)                   {}
+func (f *fakeHost) LogSuccess(string)                 {}
+
+func quoted(out int64) helpers.PoolQuote {
+	return helpers.PoolQuote{
+		Pool:  helpers.ResolvedPool{Version: helpers.PoolVersionV3, V3Fee: 500},
+		Quote: &helpers.SwapQuote{AmountIn: big.NewInt(1), AmountOut: big.NewInt(out)},
+	}
+}
+
+func failed() helpers.PoolQuote {
+	return helpers.PoolQuote{Pool: helpers.ResolvedPool{Version: helpers.PoolVersionV2}, Err: errors.New("reverted")}
+}
+
+func TestNextSelectablePool(t *testing.T) {
+	route := &helpers.Route{}
+	for _, tc := range []struct {
+		name   string
+		best   *helpers.Route
+		quotes []helpers.PoolQuote
+		choice int
+		step   int
+		want   int
+		ok     bool
+	}{
+		{"forward", route, []helpers.PoolQuote{quoted(1), quoted(2)}, 0, 1, 1, true},
+		{"skips failed", route, []helpers.PoolQuote{quoted(1), failed(), quoted(3)}, 1, 1, 3, true},
+		{"wraps to best", route, []helpers.PoolQuote{quoted(1), failed()}, 1, 1, 0, true},
+		{"backward skips failed", route, []helpers.PoolQuote{quoted(1), failed()}, 0, -1, 1, true},
+		{"no route skips best", nil, []helpers.PoolQuote{quoted(1), failed(), quoted(3)}, 3, 1, 1, true},
+		{"nothing else", nil, []helpers.PoolQuote{quoted(1), failed()}, 1, 1, 0, false},
+	} {
+		u := &Module{bestRoute: tc.best, poolQuotes: tc.quotes, poolChoice: tc.choice}
+		got, ok := u.nextSelectablePool(tc.step)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%s: nextSelectablePool(%d) = %d, %v; want %d, %v", tc.name, tc.step, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestPoolRegionsClickable(t *testing.T) {
+	u := &Module{poolQuotes: []helpers.PoolQuote{quoted(1), quoted(2)}}
+	regions := u.poolRegions(uniswapview.SwapGeometry{PoolY: 10, PoolX1: 2, PoolX2: 40})
+	if len(regions) != 3 {
+		t.Fatalf("got %d regions, want 3", len(regions))
+	}
+	for i, r := range regions {
+		if r.Y1 != 10+i || r.Y2 != r.Y1+1 {
+			t.Errorf("row %d spans y %d..%d, want %d..%d", i, r.Y1, r.Y2, 10+i, 11+i)
+		}
+	}
+}
+
+func TestPoolRowsWithoutBestRoute(t *testing.T) {
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	h := &fakeHost{watched: []rpc.WatchedToken{{Symbol: "TKN", Decimals: 6, Address: token}}}
+	u := &Module{fromTokenIdx: 0, toTokenIdx: 1, poolQuotes: []helpers.PoolQuote{quoted(1500000), failed()}}
+
+	rows := u.poolRows(h)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want best + 2 pools", len(rows))
+	}
+	if !rows[0].Failed || rows[0].Output != "no route" {
+		t.Errorf("best row = %+v, want a failed \"no route\" row", rows[0])
+	}
+	if rows[1].Failed || rows[1].Output != "1.500000 TKN" {
+		t.Errorf("pool row = %+v", rows[1])
+	}
+	if !rows[2].Failed {
+		t.Errorf("failed pool row = %+v, want Failed", rows[2])
+	}
+}
@@ -0,0 +1,225 @@
+package uniswap
+
+import (
+	"fmt"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// hookReportMsg carries the result of inspecting a quote's V4 hook.
+type hookReportMsg struct {
+	hook   common.Address
+	report *helpers.HookReport
+	err    error
+}
+
+func inspectHook(rpcURL string, hook common.Address, fee uint32) tea.Cmd {
+	return func() tea.Msg {
+		report, err := helpers.InspectHook(rpcURL, hook, fee)
+		return hookReportMsg{hook: hook, report: report, err: err}
+	}
+}
+
+// quoteHooks returns the hooks of every V4 pool the current quote swaps
+// through, in route order: each V4 hop of a multi-hop route, or the quoted
+// pool's hook for a direct swap.
+func (u *Module) quoteHooks() []common.Address {
+	if u.quote == nil {
+		return nil
+	}
+	var hooks []common.Address
+	add := func(hook common.Address) {
+		if hook == (common.Address{}) {
+			return
+		}
+		for _, h := range hooks {
+			if h == hook {
+				return
+			}
+		}
+		hooks = append(hooks, hook)
+	}
+	if u.route != nil {
+		for _, hop := range u.route.Hops {
+			if hop.Pool.Version == helpers.PoolVersionV4 {
+				add(hop.Pool.V4Key.Hooks)
+			}
+		}
+	}
+	add(u.quote.HookAddr)
+	return hooks
+}
+
+// hookPoolFee returns the pool-key fee of the quoted pool using hook, which
+// marks dynamic-fee pools.
+func (u *Module) hookPoolFee(hook common.Address) uint32 {
+	if u.route != nil {
+		for _, hop := range u.route.Hops {
+			if hop.Pool.Version == helpers.PoolVersionV4 && hop.Pool.V4Key.Hooks == hook {
+				return hop.Pool.V4Key.Fee
+			}
+		}
+	}
+	return u.lastV4Key.Fee
+}
+
+// checkQuoteHook starts inspecting each of the current quote's hooks that
+// an earlier quote has not. Reports are cached for the session: a hook's
+// permissions are fixed by its address, and the rest rarely changes. Failed
+// inspections are retried on the next quote.
+func (u *Module) checkQuoteHook(h dapp.Host) tea.Cmd {
+	u.hookAcked = nil
+	var cmds []tea.Cmd
+	for _, hook := range u.quoteHooks() {
+		if _, seen := u.hookReports[hook]; seen || h.RPCURL() == "" {
+			continue
+		}
+		if u.hookReports == nil {
+			u.hookReports = make(map[common.Address]*helpers.HookReport)
+		}
+		delete(u.hookFailed, hook)
+		u.hookReports[hook] = nil
+		h.LogInfo(fmt.Sprintf("Inspecting V4 hook %s…", helpers.ShortenAddr(hook.Hex())))
+		cmds = append(cmds, inspectHook(h.RPCURL(), hook, u.hookPoolFee(hook)))
+	}
+	u.updateHookWarn()
+	return tea.Batch(cmds...)
+}
+
+func (u *Module) handleHookReport(h dapp.Host, msg hookReportMsg) tea.Cmd {
+	if msg.err != nil {
+		// A failed inspection leaves the risk unknown, which gates the swap
+		// like a HIGH-risk report until the next quote retries it.
+		delete(u.hookReports, msg.hook)
+		if u.hookFailed == nil {
+			u.hookFailed = make(map[common.Address]error)
+		}
+		u.hookFailed[msg.hook] = msg.err
+		h.LogWarn(fmt.Sprintf("Hook inspection failed for %s: %v", helpers.ShortenAddr(msg.hook.Hex()), msg.err))
+		u.updateHookWarn()
+		return nil
+	}
+	u.hookReports[msg.hook] = msg.report
+	logHookReport(h, msg.report)
+	u.updateHookWarn()
+	return nil
+}
+
+// logHookReport writes a hook report's summary and details to the log.
+func logHookReport(h dapp.Host, r *helpers.HookReport) {
+	log := h.LogInfo
+	switch r.Risk {
+	case helpers.HookRiskHigh:
+		log = h.LogError
+	case helpers.HookRiskMedium:
+		log = h.LogWarn
+	}
+	log(r.Summary())
+	var names []string
+	for _, p := range r.Permissions {
+		names = append(names, p.Name)
+	}
+	h.LogInfo(fmt.Sprintf("  Callbacks: %v", names))
+	for _, f := range r.Findings {
+		h.LogInfo("  • " + f)
+	}
+	h.LogInfo(fmt.Sprintf("  Code size: %d bytes", r.CodeSize))
+	switch {
+	case r.Deployer != (common.Address{}):
+		h.LogInfo(fmt.Sprintf("  Deployed by %s in block %d", r.Deployer.Hex(), r.DeployBlock))
+	case r.DeployBlock > 0:
+		h.LogInfo(fmt.Sprintf("  Deployed in block %d (%s)", r.DeployBlock, r.DeployNote))
+	case r.DeployNote != "":
+		h.LogInfo("  Deployment: " + r.DeployNote)
+	}
+}
+
+// hookNeedsConfirm reports whether swapping through hook takes a second
+// Swap press: anything short of a report below HIGH risk — a failed or
+// pending inspection included — leaves the risk unknown.
+func (u *Module) hookNeedsConfirm(hook common.Address) bool {
+	if _, failed := u.hookFailed[hook]; failed {
+		return true
+	}
+	r := u.hookReports[hook]
+	return r == nil || r.Risk >= helpers.HookRiskHigh
+}
+
+// hookWarning describes what is known about hook for the swap view.
+func (u *Module) hookWarning(hook common.Address) string {
+	short := helpers.ShortenAddr(hook.Hex())
+	if err, failed := u.hookFailed[hook]; failed {
+		return fmt.Sprintf("⚠ Hook %s could not be inspected (%v) — treat as HIGH risk", short, err)
+	}
+	r, seen := u.hookReports[hook]
+	switch {
+	case !seen:
+		return fmt.Sprintf("⚠ Hook-gated pool (%s) — quoting or swapping may fail if you're not allowlisted (e.g. KYC/geo restrictions)", short)
+	case r == nil:
+		return fmt.Sprintf("⚠ Hook-gated pool (%s) — inspecting hook…", short)
+	}
+	return "⚠ " + r.Summary()
+}
+
+// updateHookWarn sets the swap view's hook warning from what is known about
+// the current quote's hooks, leading with one that gates the swap.
+func (u *Module) updateHookWarn() {
+	hooks := u.quoteHooks()
+	if len(hooks) == 0 {
+		u.hookWarn = ""
+		return
+	}
+	hook := hooks[0]
+	for _, h := range hooks {
+		if u.hookNeedsConfirm(h) {
+			hook = h
+			break
+		}
+	}
+	u.hookWarn = u.hookWarning(hook)
+	if len(hooks) > 1 {
+		u.hookWarn += fmt.Sprintf(" (+%d more hooked hop(s))", len(hooks)-1)
+	}
+	if u.hookAcked[hook] {
+		u.hookWarn += " — press Swap again to proceed anyway"
+	}
+}
+
+// confirmHookRisk reports whether a swap may be packaged through the current
+// quote's hooks. Any HIGH-risk hook, one whose inspection failed, or one
+// still being inspected takes a second Swap press.
+func (u *Module) confirmHookRisk(h dapp.Host) bool {
+	var risky []common.Address
+	for _, hook := range u.quoteHooks() {
+		if u.hookNeedsConfirm(hook) {
+			risky = append(risky, hook)
+		}
+	}
+	if len(risky) == 0 {
+		return true
+	}
+	acked := true
+	for _, hook := range risky {
+		acked = acked && u.hookAcked[hook]
+	}
+	if acked {
+		for _, hook := range risky {
+			h.LogWarn(fmt.Sprintf("Swapping through hook %s despite its risk summary", helpers.ShortenAddr(hook.Hex())))
+		}
+		return true
+	}
+	u.hookAcked = make(map[common.Address]bool, len(risky))
+	for _, hook := range risky {
+		u.hookAcked[hook] = true
+	}
+	u.updateHookWarn()
+	if r, seen := u.hookReports[risky[0]]; seen && r == nil {
+		u.hookWarn = fmt.Sprintf("⚠ Hook %s is still being inspected — press Swap again to proceed without waiting", helpers.ShortenAddr(risky[0].Hex()))
+	}
+	h.LogWarn(u.hookWarn)
+	return false
+}
@@ -0,0 +1,468 @@
+package uniswap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// -------------------- UNISWAP V4 LIQUIDITY PACKAGING --------------------
+//
+// Every V4 position change is one PositionManager.modifyLiquidities(
+// unlockData, deadline) call, where unlockData is abi.encode(actions,
+// params) exactly like a V4_SWAP input: a byte string of Actions.sol IDs and
+// one abi-encoded parameter blob per action. A change that moves liquidity
+// is followed by the actions that balance the currency deltas it leaves:
+//
+//	increase:  INCREASE_LIQUIDITY, CLOSE_CURRENCY ×2 (pays the deposit; accrued fees net against it)
+//	decrease:  DECREASE_LIQUIDITY, TAKE_PAIR
+//	collect:   DECREASE_LIQUIDITY with zero liquidity, TAKE_PAIR
+//	burn:      BURN_POSITION, TAKE_PAIR
+//	mint:      MINT_POSITION, SETTLE_PAIR
+//
+// A native-ETH deposit sends amount0Max as the transaction value and ends
+// with SWEEP to refund what the pool did not take. ERC-20 deposits are
+// pulled through Permit2, so they need the same two approvals as a Universal
+// Router swap, with the PositionManager as Permit2's spender.
+//
+// Action IDs and parameter layouts are v4-periphery's Actions.sol and
+// CalldataDecoder; liquidity_test.go pins each one word by word.
+
+const (
+	v4ActionIncreaseLiquidity = 0x00
+	v4ActionDecreaseLiquidity = 0x01
+	v4ActionMintPosition      = 0x02
+	v4ActionBurnPosition      = 0x03
+	v4ActionSettlePair        = 0x0d
+	v4ActionTakePair          = 0x11
+	v4ActionCloseCurrency     = 0x12
+	v4ActionSweep             = 0x14
+)
+
+const v4PositionEncodingABI = `[
+  {
+    "inputs": [
+      {"name": "tokenId", "type": "uint256"},
+      {"name": "liquidity", "type": "uint256"},
+      {"name": "amount0", "type": "uint128"},
+      {"name": "amount1", "type": "uint128"},
+      {"name": "hookData", "type": "bytes"}
+    ],
+    "name": "encodeModifyLiquidity",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "poolKey", "type": "tuple", "components": [
+        {"name": "currency0", "type": "address"},
+        {"name": "currency1", "type": "address"},
+        {"name": "fee", "type": "uint24"},
+        {"name": "tickSpacing", "type": "int24"},
+        {"name": "hooks", "type": "address"}
+      ]},
+      {"name": "tickLower", "type": "int24"},
+      {"name": "tickUpper", "type": "int24"},
+      {"name": "liquidity", "type": "uint256"},
+      {"name": "amount0Max", "type": "uint128"},
+      {"name": "amount1Max", "type": "uint128"},
+      {"name": "owner", "type": "address"},
+      {"name": "hookData", "type": "bytes"}
+    ],
+    "name": "encodeMintPosition",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "tokenId", "type": "uint256"},
+      {"name": "amount0Min", "type": "uint128"},
+      {"name": "amount1Min", "type": "uint128"},
+      {"name": "hookData", "type": "bytes"}
+    ],
+    "name": "encodeBurnPosition",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [{"name": "currency0", "type": "address"}, {"name": "currency1", "type": "address"}],
+    "name": "encodeCurrencyPair",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [{"name": "currency0", "type": "address"}, {"name": "currency1", "type": "address"}, {"name": "recipient", "type": "address"}],
+    "name": "encodeTakePair",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [{"name": "currency", "type": "address"}],
+    "name": "encodeCurrency",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [{"name": "currency", "type": "address"}, {"name": "to", "type": "address"}],
+    "name": "encodeSweep",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [{"name": "actions", "type": "bytes"}, {"name": "params", "type": "bytes[]"}],
+    "name": "encodeActionsParams",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [{"name": "unlockData", "type": "bytes"}, {"name": "deadline", "type": "uint256"}],
+    "name": "modifyLiquidities",
+    "outputs": [], "stateMutability": "payable", "type": "function"
+  }
+]`
+
+// Gas limits for each PositionManager call, before any hook's own cost.
+const (
+	lpIncreaseGas = 350000
+	lpDecreaseGas = 300000
+	lpCollectGas  = 250000
+	lpBurnGas     = 300000
+	lpMintGas     = 500000
+)
+
+// lpPool is a V4 pool a position lives in or can be minted into, with its
+// currencies as TokenOptions (native ETH for a zero currency) for amount
+// formatting.
+type lpPool struct {
+	key            helpers.V4PoolKey
+	id             common.Hash
+	token0, token1 uniswapview.TokenOption
+}
+
+// label renders the pool as "ETH/USDC 0.30% · spacing 60".
+func (p lpPool) label() string {
+	return fmt.Sprintf("%s/%s %.2f%% · spacing %d", p.token0.Symbol, p.token1.Symbol, float64(p.key.Fee)/10000.0, p.key.TickSpacing)
+}
+
+// lpRequest is everything a liquidity packaging pass needs, captured on the
+// UI goroutine so the build functions can run off it.
+type lpRequest struct {
+	client   *rpc.Client
+	from     common.Address
+	safe     bool
+	addrs    helpers.UniswapNetworkAddresses
+	pool     lpPool
+	deadline int64 // unix seconds
+}
+
+// hookNote is the summary line for a pool with a hook contract.
+func (r lpRequest) hookNote() string {
+	if r.pool.key.Hooks == (common.Address{}) {
+		return ""
+	}
+	return fmt.Sprintf("\nHook: %s (pool may enforce KYC/allowlist checks)", r.pool.key.Hooks.Hex())
+}
+
+// modifyLiquidities encodes the PositionManager call for actions/params.
+func (r lpRequest) modifyLiquidities(parsedABI *abi.ABI, actions []byte, params [][]byte) ([]byte, error) {
+	unlockData, err := packStripSelector(parsedABI, "encodeActionsParams", actions, params)
+	if err != nil {
+		return nil, fmt.Errorf("encode actions/params: %w", err)
+	}
+	calldata, err := parsedABI.Pack("modifyLiquidities", unlockData, big.NewInt(r.deadline))
+	if err != nil {
+		return nil, fmt.Errorf("encode modifyLiquidities(): %w", err)
+	}
+	return calldata, nil
+}
+
+// takePair encodes TAKE_PAIR, paying both currencies out to the wallet.
+func (r lpRequest) takePair(parsedABI *abi.ABI) ([]byte, error) {
+	return packStripSelector(parsedABI, "encodeTakePair", r.pool.key.Currency0, r.pool.key.Currency1, r.from)
+}
+
+// depositTail returns the actions/params that follow a deposit: close (or
+// settle, for a fresh mint) both currencies, then sweep unspent native ETH
+// back to the wallet.
+func (r lpRequest) depositTail(parsedABI *abi.ABI, settle bool) ([]byte, [][]byte, error) {
+	var actions []byte
+	var params [][]byte
+	if settle {
+		pair, err := packStripSelector(parsedABI, "encodeCurrencyPair", r.pool.key.Currency0, r.pool.key.Currency1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode SETTLE_PAIR params: %w", err)
+		}
+		actions, params = append(actions, v4ActionSettlePair), append(params, pair)
+	} else {
+		for _, c := range []common.Address{r.pool.key.Currency0, r.pool.key.Currency1} {
+			p, err := packStripSelector(parsedABI, "encodeCurrency", c)
+			if err != nil {
+				return nil, nil, fmt.Errorf("encode CLOSE_CURRENCY params: %w", err)
+			}
+			actions, params = append(actions, v4ActionCloseCurrency), append(params, p)
+		}
+	}
+	if r.pool.key.Currency0 == (common.Address{}) {
+		sweep, err := packStripSelector(parsedABI, "encodeSweep", common.Address{}, r.from)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode SWEEP params: %w", err)
+		}
+		actions, params = append(actions, v4ActionSweep), append(params, sweep)
+	}
+	return actions, params, nil
+}
+
+// depositValue is the transaction value for a deposit: amount0Max when
+// currency0 is native ETH.
+func (r lpRequest) depositValue(amount0Max *big.Int) *big.Int {
+	if r.pool.key.Currency0 == (common.Address{}) {
+		return amount0Max
+	}
+	return big.NewInt(0)
+}
+
+// lpApproval is one approval a deposit is still missing.
+type lpApproval struct {
+	to      common.Address
+	gas     uint64
+	data    []byte
+	summary string
+}
+
+// missingApprovals lists the ERC20→Permit2 and Permit2→PositionManager
+// approvals the wallet lacks for depositing max0/max1. Any RPC error counts
+// as "allowance unknown" and includes the step to be safe, as in
+// permit2Approval.
+func (r lpRequest) missingApprovals(max0, max1 *big.Int) ([]lpApproval, error) {
+	posm := r.addrs.V4PositionManager
+	var steps []lpApproval
+	for _, d := range []struct {
+		token  uniswapview.TokenOption
+		amount *big.Int
+	}{{r.pool.token0, max0}, {r.pool.token1, max1}} {
+		if d.token.IsETH || d.amount == nil || d.amount.Sign() == 0 {
+			continue
+		}
+		human := formatAmount(d.amount, d.token)
+		if r.client == nil {
+			return nil, fmt.Errorf("no RPC client")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		allowance, err := rpc.ERC20Allowance(ctx, r.client, d.token.Address, r.from, rpc.Permit2Address)
+		if err != nil || allowance.Cmp(d.amount) < 0 {
+			steps = append(steps, lpApproval{
+				to: d.token.Address, gas: 60000,
+				data:    helpers.BuildApproveCalldata(rpc.Permit2Address, d.amount),
+				summary: fmt.Sprintf("Approve %s for Permit2", human),
+			})
+		}
+		amount, expiration, err := rpc.Permit2Allowance(ctx, r.client, d.token.Address, r.from, posm)
+		cancel()
+		if err != nil || amount.Cmp(d.amount) < 0 || expiration <= uint64(time.Now().Unix()) {
+			data, perr := buildPermit2ApproveCalldata(d.token.Address, posm, d.amount, uint64(time.Now().Add(permitExpiry).Unix()))
+			if perr != nil {
+				return nil, perr
+			}
+			steps = append(steps, lpApproval{
+				to: rpc.Permit2Address, gas: 80000, data: data,
+				summary: fmt.Sprintf("Permit2 approve %s for V4 PositionManager", human),
+			})
+		}
+	}
+	return steps, nil
+}
+
+// packageDeposit packages a deposit (increase or mint) behind whatever
+// approvals it still needs. A Safe batches them all ahead of the deposit.
+// An EOA gets one approval as Step 1; when more are missing only the first
+// is packaged, on its own, and the action must be run again once it
+// confirms — the same one-approval-per-pass limit as Universal Router swaps.
+func (r lpRequest) packageDeposit(b dapp.TxBuilder, max0, max1 *big.Int, gas uint64, calldata []byte, summary string) (dapp.Packaged, error) {
+	var p dapp.Packaged
+	steps, err := r.missingApprovals(max0, max1)
+	if err != nil {
+		return p, err
+	}
+	switch {
+	case r.safe:
+		for _, s := range steps {
+			if _, _, err := b.Build(s.to, big.NewInt(0), s.gas, s.data, s.summary); err != nil {
+				return p, err
+			}
+		}
+	case len(steps) == 1:
+		p.ApproveQR, p.ApproveJSON, err = b.Build(steps[0].to, big.NewInt(0), steps[0].gas, steps[0].data, steps[0].summary)
+		if err != nil {
+			return p, err
+		}
+	case len(steps) > 1:
+		p.Summary = fmt.Sprintf("%s\n\nApproval 1 of %d needed before this deposit. Sign it, wait for it to confirm, then run the action again.",
+			steps[0].summary, len(steps))
+		p.QR, p.TxJSON, err = b.Build(steps[0].to, big.NewInt(0), steps[0].gas, steps[0].data, steps[0].summary)
+		return p, err
+	}
+	p.Summary = summary
+	p.QR, p.TxJSON, err = b.Build(r.addrs.V4PositionManager, r.depositValue(max0), gas, calldata, summary)
+	return p, err
+}
+
+// increaseCalldata encodes INCREASE_LIQUIDITY of liquidity into tokenID,
+// paying at most max0/max1, followed by the deposit tail.
+func (r lpRequest) increaseCalldata(tokenID, liquidity, max0, max1 *big.Int) ([]byte, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(v4PositionEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse V4 position encoding ABI: %w", err)
+	}
+	inc, err := packStripSelector(&parsedABI, "encodeModifyLiquidity", tokenID, liquidity, max0, max1, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("encode INCREASE_LIQUIDITY params: %w", err)
+	}
+	tailActions, tailParams, err := r.depositTail(&parsedABI, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.modifyLiquidities(&parsedABI,
+		append([]byte{v4ActionIncreaseLiquidity}, tailActions...), append([][]byte{inc}, tailParams...))
+}
+
+// buildIncrease packages INCREASE_LIQUIDITY of liquidity into tokenID,
+// paying at most max0/max1.
+func (r lpRequest) buildIncrease(tokenID, liquidity, max0, max1 *big.Int) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		calldata, err := r.increaseCalldata(tokenID, liquidity, max0, max1)
+		if err != nil {
+			return dapp.Packaged{}, err
+		}
+		summary := fmt.Sprintf("Uniswap V4 Increase Liquidity #%s (%s)\nDeposit up to %s + %s\nPositionManager: %s%s",
+			tokenID, r.pool.label(), formatAmount(max0, r.pool.token0), formatAmount(max1, r.pool.token1),
+			r.addrs.V4PositionManager.Hex(), r.hookNote())
+		return r.packageDeposit(b, max0, max1, lpIncreaseGas, calldata, summary)
+	}
+}
+
+// mintCalldata encodes MINT_POSITION of liquidity between tickLower and
+// tickUpper, paying at most max0/max1 with the wallet as owner, followed by
+// the deposit tail.
+func (r lpRequest) mintCalldata(tickLower, tickUpper int32, liquidity, max0, max1 *big.Int) ([]byte, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(v4PositionEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse V4 position encoding ABI: %w", err)
+	}
+	key := r.pool.key
+	mint, err := packStripSelector(&parsedABI, "encodeMintPosition",
+		struct {
+			Currency0   common.Address
+			Currency1   common.Address
+			Fee         *big.Int
+			TickSpacing *big.Int
+			Hooks       common.Address
+		}{key.Currency0, key.Currency1, new(big.Int).SetUint64(uint64(key.Fee)), big.NewInt(int64(key.TickSpacing)), key.Hooks},
+		big.NewInt(int64(tickLower)), big.NewInt(int64(tickUpper)), liquidity, max0, max1, r.from, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("encode MINT_POSITION params: %w", err)
+	}
+	tailActions, tailParams, err := r.depositTail(&parsedABI, true)
+	if err != nil {
+		return nil, err
+	}
+	return r.modifyLiquidities(&parsedABI,
+		append([]byte{v4ActionMintPosition}, tailActions...), append([][]byte{mint}, tailParams...))
+}
+
+// buildMint packages MINT_POSITION of liquidity between tickLower and
+// tickUpper, paying at most max0/max1, with the wallet as owner.
+func (r lpRequest) buildMint(tickLower, tickUpper int32, liquidity, max0, max1 *big.Int) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		calldata, err := r.mintCalldata(tickLower, tickUpper, liquidity, max0, max1)
+		if err != nil {
+			return dapp.Packaged{}, err
+		}
+		summary := fmt.Sprintf("Uniswap V4 New Position (%s)\nTicks %d → %d\nDeposit up to %s + %s\nPositionManager: %s%s",
+			r.pool.label(), tickLower, tickUpper, formatAmount(max0, r.pool.token0), formatAmount(max1, r.pool.token1),
+			r.addrs.V4PositionManager.Hex(), r.hookNote())
+		return r.packageDeposit(b, max0, max1, lpMintGas, calldata, summary)
+	}
+}
+
+// decreaseCalldata encodes DECREASE_LIQUIDITY of liquidity from tokenID,
+// receiving at least min0/min1, then TAKE_PAIR to the wallet.
+func (r lpRequest) decreaseCalldata(tokenID, liquidity, min0, min1 *big.Int) ([]byte, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(v4PositionEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse V4 position encoding ABI: %w", err)
+	}
+	dec, err := packStripSelector(&parsedABI, "encodeModifyLiquidity", tokenID, liquidity, min0, min1, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("encode DECREASE_LIQUIDITY params: %w", err)
+	}
+	take, err := r.takePair(&parsedABI)
+	if err != nil {
+		return nil, fmt.Errorf("encode TAKE_PAIR params: %w", err)
+	}
+	return r.modifyLiquidities(&parsedABI, []byte{v4ActionDecreaseLiquidity, v4ActionTakePair}, [][]byte{dec, take})
+}
+
+// buildDecrease packages DECREASE_LIQUIDITY of liquidity from tokenID,
+// receiving at least min0/min1 plus the position's accrued fees. Zero
+// liquidity with zero minimums collects the fees alone.
+func (r lpRequest) buildDecrease(tokenID, liquidity, min0, min1 *big.Int) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		calldata, err := r.decreaseCalldata(tokenID, liquidity, min0, min1)
+		if err != nil {
+			return dapp.Packaged{}, err
+		}
+
+		var p dapp.Packaged
+		gas := uint64(lpDecreaseGas)
+		if liquidity.Sign() == 0 {
+			gas = lpCollectGas
+			p.Summary = fmt.Sprintf("Uniswap V4 Collect Fees #%s (%s)\nPositionManager: %s%s",
+				tokenID, r.pool.label(), r.addrs.V4PositionManager.Hex(), r.hookNote())
+		} else {
+			p.Summary = fmt.Sprintf("Uniswap V4 Decrease Liquidity #%s (%s)\nRemove %s liquidity, receive at least %s + %s plus fees\nPositionManager: %s%s",
+				tokenID, r.pool.label(), liquidity, formatAmount(min0, r.pool.token0), formatAmount(min1, r.pool.token1),
+				r.addrs.V4PositionManager.Hex(), r.hookNote())
+		}
+		p.QR, p.TxJSON, err = b.Build(r.addrs.V4PositionManager, big.NewInt(0), gas, calldata, p.Summary)
+		return p, err
+	}
+}
+
+// burnCalldata encodes BURN_POSITION for tokenID, receiving at least
+// min0/min1, then TAKE_PAIR to the wallet.
+func (r lpRequest) burnCalldata(tokenID, min0, min1 *big.Int) ([]byte, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(v4PositionEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse V4 position encoding ABI: %w", err)
+	}
+	burn, err := packStripSelector(&parsedABI, "encodeBurnPosition", tokenID, min0, min1, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("encode BURN_POSITION params: %w", err)
+	}
+	take, err := r.takePair(&parsedABI)
+	if err != nil {
+		return nil, fmt.Errorf("encode TAKE_PAIR params: %w", err)
+	}
+	return r.modifyLiquidities(&parsedABI, []byte{v4ActionBurnPosition, v4ActionTakePair}, [][]byte{burn, take})
+}
+
+// buildBurn packages BURN_POSITION for tokenID: whatever liquidity is left
+// is removed (receiving at least min0/min1), fees are collected and the NFT
+// is destroyed.
+func (r lpRequest) buildBurn(tokenID, min0, min1 *big.Int) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		calldata, err := r.burnCalldata(tokenID, min0, min1)
+		if err != nil {
+			return dapp.Packaged{}, err
+		}
+
+		var p dapp.Packaged
+		p.Summary = fmt.Sprintf("Uniswap V4 Burn Position #%s (%s)\nRemove all liquidity, receive at least %s + %s plus fees\nPositionManager: %s%s",
+			tokenID, r.pool.label(), formatAmount(min0, r.pool.token0), formatAmount(min1, r.pool.token1),
+			r.addrs.V4PositionManager.Hex(), r.hookNote())
+		p.QR, p.TxJSON, err = b.Build(r.addrs.V4PositionManager, big.NewInt(0), lpBurnGas, calldata, p.Summary)
+		return p, err
+	}
+}
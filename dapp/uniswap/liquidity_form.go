@@ -0,0 +1,564 @@
+package uniswap
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// lpAction is the liquidity form's action.
+type lpAction int
+
+const (
+	lpIncrease lpAction = iota
+	lpDecrease
+	lpMint
+)
+
+// lpForm is the open increase/decrease/new-position form. Amounts and
+// prices are edit buffers; the preview is recomputed from them on every
+// render, against the pool's current price.
+type lpForm struct {
+	action  lpAction
+	pos     helpers.LiquidityPosition // increase/decrease: the selected position
+	pool    lpPool
+	pools   []lpPool // mint: candidate pools, cycled with ←/→
+	poolIdx int
+	focused int
+
+	// Pool price: the position's for increase/decrease, fetched from the
+	// StateView for mint.
+	sqrtPriceX96 *big.Int
+	tick         int32
+	loading      bool
+
+	amount string // deposit, in side's token
+	side   int    // 0=token0, 1=token1
+	pct    string // decrease: percent of the position's liquidity
+
+	minPrice, maxPrice   string // mint: range edit buffers, token1 per token0
+	tickLower, tickUpper int32
+
+	err string
+}
+
+// lpPoolStateMsg carries a mint candidate pool's current price.
+type lpPoolStateMsg struct {
+	id           common.Hash
+	sqrtPriceX96 *big.Int
+	tick         int32
+	err          error
+}
+
+// fetchLPPoolState reads a V4 pool's slot0 for the new-position form.
+func fetchLPPoolState(rpcURL string, id common.Hash) tea.Cmd {
+	return func() tea.Msg {
+		info, err := helpers.FetchPoolInfo(rpcURL, id)
+		if err != nil {
+			return lpPoolStateMsg{id: id, err: err}
+		}
+		sqrtPrice, ok := new(big.Int).SetString(info.SqrtPriceX96, 10)
+		if !ok || sqrtPrice.Sign() == 0 {
+			return lpPoolStateMsg{id: id, err: fmt.Errorf("pool is not initialized")}
+		}
+		return lpPoolStateMsg{id: id, sqrtPriceX96: sqrtPrice, tick: int32(info.Tick)}
+	}
+}
+
+// lpDefaultRangeTicks is the new-position form's initial half-width around
+// the current tick, about ±10% in price.
+const lpDefaultRangeTicks = 953
+
+// positionPool returns the V4 pool a position lives in.
+func positionPool(pos helpers.LiquidityPosition) lpPool {
+	token := func(addr common.Address, symbol string, decimals uint8) uniswapview.TokenOption {
+		return uniswapview.TokenOption{Symbol: symbol, Decimals: decimals, Address: addr, IsETH: addr == (common.Address{})}
+	}
+	key := helpers.V4PoolKey{Currency0: pos.Token0, Currency1: pos.Token1, Hooks: pos.Hooks, Fee: pos.Fee, TickSpacing: pos.TickSpacing}
+	return lpPool{
+		key:    key,
+		id:     helpers.ComputePoolId(key.Currency0, key.Currency1, key.Hooks, key.Fee, key.TickSpacing),
+		token0: token(pos.Token0, pos.Token0Symbol, pos.Token0Decimals),
+		token1: token(pos.Token1, pos.Token1Symbol, pos.Token1Decimals),
+	}
+}
+
+// focusedV4Position returns the liquidity view's selected position when it
+// is a V4 position actions can be built for.
+func (u *Module) focusedV4Position(h dapp.Host) (helpers.LiquidityPosition, bool) {
+	if u.liquidityFocusedIdx < 0 || u.liquidityFocusedIdx >= len(u.liquidityPositions) {
+		return helpers.LiquidityPosition{}, false
+	}
+	pos := u.liquidityPositions[u.liquidityFocusedIdx]
+	switch {
+	case pos.Stub:
+		h.LogWarn("Position #" + pos.TokenID.String() + " could not be read — no actions available")
+		return pos, false
+	case pos.Version != helpers.PoolVersionV4:
+		h.LogWarn("Liquidity actions are only available for V4 positions")
+		return pos, false
+	}
+	return pos, true
+}
+
+// lpRequestFor captures the wallet, network and deadline for packaging a
+// liquidity action on pool.
+func (u *Module) lpRequestFor(h dapp.Host, pool lpPool) lpRequest {
+	return lpRequest{
+		client:   h.Client(),
+		from:     common.HexToAddress(h.ActiveAddress()),
+		safe:     h.Wallet().Safe != nil,
+		addrs:    helpers.UniswapAddressesForChain(h.ChainID()),
+		pool:     pool,
+		deadline: time.Now().Unix() + int64(u.settings.DeadlineMinutes)*60,
+	}
+}
+
+// openLPForm opens the increase or decrease form for the selected position.
+func (u *Module) openLPForm(h dapp.Host, action lpAction) tea.Cmd {
+	pos, ok := u.focusedV4Position(h)
+	if !ok {
+		return nil
+	}
+	f := &lpForm{action: action, pos: pos, pool: positionPool(pos), sqrtPriceX96: pos.SqrtPriceX96, tick: pos.PoolTick}
+	if action == lpIncrease && pos.PoolTickKnown && pos.PoolTick >= pos.TickUpper {
+		f.side = 1 // above the range the position takes only token1
+	}
+	if action == lpDecrease {
+		f.pct = "100"
+	}
+	if pos.SqrtPriceX96 == nil {
+		f.err = "The pool's current price could not be read — reopen the liquidity view to retry"
+	}
+	u.lpForm = f
+	return nil
+}
+
+// openMintForm opens the new-position form over the V4 pools the module
+// knows: the swap pair's pools and those of the wallet's V4 positions.
+func (u *Module) openMintForm(h dapp.Host) tea.Cmd {
+	pools := u.mintPools(h)
+	if len(pools) == 0 {
+		h.LogWarn("No V4 pool to open a position in — quote a pair with a V4 pool on the swap page first")
+		return nil
+	}
+	u.lpForm = &lpForm{action: lpMint, pools: pools, pool: pools[0]}
+	return u.loadMintPool(h)
+}
+
+// mintPools lists candidate pools for a new position, deduplicated by pool
+// ID: the V4 pools quoted for the swap page's pair first, then the pools of
+// the wallet's V4 positions.
+func (u *Module) mintPools(h dapp.Host) []lpPool {
+	var pools []lpPool
+	seen := make(map[common.Hash]bool)
+	add := func(p lpPool) {
+		if !seen[p.id] {
+			seen[p.id] = true
+			pools = append(pools, p)
+		}
+	}
+	tokenFor := func(addr common.Address) uniswapview.TokenOption {
+		for _, t := range u.buildTokenList(h) {
+			if (t.IsETH && addr == (common.Address{})) || (!t.IsETH && t.Address == addr) {
+				return t
+			}
+		}
+		return uniswapview.TokenOption{Symbol: helpers.ShortenAddr(addr.Hex()), Decimals: 18, Address: addr}
+	}
+	fromKey := func(key helpers.V4PoolKey, id common.Hash) lpPool {
+		return lpPool{key: key, id: id, token0: tokenFor(key.Currency0), token1: tokenFor(key.Currency1)}
+	}
+
+	if u.quote != nil && u.lastVersion == helpers.PoolVersionV4 {
+		add(fromKey(u.lastV4Key, u.lastV4PoolID))
+	}
+	for _, pq := range u.poolQuotes {
+		if pq.Pool.Version == helpers.PoolVersionV4 {
+			add(fromKey(pq.Pool.V4Key, pq.Pool.V4PoolID))
+		}
+	}
+	for _, pos := range u.liquidityPositions {
+		if !pos.Stub && pos.Version == helpers.PoolVersionV4 {
+			add(positionPool(pos))
+		}
+	}
+	return pools
+}
+
+// loadMintPool selects the form's current candidate pool and fetches its
+// price.
+func (u *Module) loadMintPool(h dapp.Host) tea.Cmd {
+	f := u.lpForm
+	f.pool = f.pools[f.poolIdx]
+	f.sqrtPriceX96 = nil
+	f.minPrice, f.maxPrice = "", ""
+	f.err = ""
+	if h.RPCURL() == "" {
+		f.err = "No RPC endpoint connected"
+		return nil
+	}
+	f.loading = true
+	return fetchLPPoolState(h.RPCURL(), f.pool.id)
+}
+
+func (u *Module) handleLPPoolState(h dapp.Host, msg lpPoolStateMsg) tea.Cmd {
+	f := u.lpForm
+	if f == nil || f.action != lpMint || msg.id != f.pool.id {
+		return nil
+	}
+	f.loading = false
+	if msg.err != nil {
+		f.err = "Pool price: " + msg.err.Error()
+		h.LogError(fmt.Sprintf("V4 pool %s: %v", f.pool.label(), msg.err))
+		return nil
+	}
+	f.sqrtPriceX96, f.tick = msg.sqrtPriceX96, msg.tick
+	spacing := f.pool.key.TickSpacing
+	f.setTick(true, helpers.UsableTick(msg.tick-lpDefaultRangeTicks, spacing))
+	f.setTick(false, helpers.UsableTick(msg.tick+lpDefaultRangeTicks, spacing))
+	if f.tickUpper <= f.tickLower {
+		f.setTick(false, f.tickLower+spacing)
+	}
+	return nil
+}
+
+// setTick sets the range's lower or upper bound and its price buffer.
+func (f *lpForm) setTick(lower bool, tick int32) {
+	price := strconv.FormatFloat(helpers.TickToPrice(tick, f.pool.token0.Decimals, f.pool.token1.Decimals), 'g', 6, 64)
+	if lower {
+		f.tickLower, f.minPrice = tick, price
+	} else {
+		f.tickUpper, f.maxPrice = tick, price
+	}
+}
+
+// fields lists the form's rows: the mint form's pool and range first, then
+// the amount (or percentage) row.
+func (f *lpForm) fields() int {
+	if f.action == lpMint {
+		return 4
+	}
+	return 1
+}
+
+// ticks returns the range the form deposits into.
+func (f *lpForm) ticks() (int32, int32) {
+	if f.action == lpMint {
+		return f.tickLower, f.tickUpper
+	}
+	return f.pos.TickLower, f.pos.TickUpper
+}
+
+// sideToken returns the token the amount is entered in.
+func (f *lpForm) sideToken() uniswapview.TokenOption {
+	if f.side == 1 {
+		return f.pool.token1
+	}
+	return f.pool.token0
+}
+
+// deposit computes the liquidity the form's amount buys and the
+// slippage-padded maximum of each token it may pull. The maximums pad the
+// exact rounded-up amounts the pool will charge, so they hold at zero
+// slippage too; amount0/amount1 are the float estimates for display.
+func (f *lpForm) deposit(slippageBps uint32) (liquidity, amount0, amount1, max0, max1 *big.Int, err error) {
+	if f.sqrtPriceX96 == nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("pool price not loaded")
+	}
+	tickLower, tickUpper := f.ticks()
+	if tickLower >= tickUpper {
+		return nil, nil, nil, nil, nil, fmt.Errorf("min price must be below max price")
+	}
+	t := f.sideToken()
+	amount, ok := parseTokenAmount(f.amount, t.Decimals)
+	if !ok {
+		return nil, nil, nil, nil, nil, fmt.Errorf("enter an amount of %s", t.Symbol)
+	}
+	liquidity = helpers.LiquidityForAmount(f.sqrtPriceX96, tickLower, tickUpper, amount, f.side == 0)
+	if liquidity.Sign() == 0 {
+		other := f.pool.token1
+		if f.side == 1 {
+			other = f.pool.token0
+		}
+		return nil, nil, nil, nil, nil, fmt.Errorf("at the current price this range takes only %s — press Space to switch", other.Symbol)
+	}
+	amount0, amount1 = helpers.AmountsForLiquidity(liquidity, f.sqrtPriceX96, tickLower, tickUpper)
+	exact0, exact1 := helpers.MaxAmountsForLiquidity(liquidity, f.sqrtPriceX96, tickLower, tickUpper)
+	pad := func(a *big.Int) *big.Int {
+		out := new(big.Int).Mul(a, big.NewInt(int64(10000+slippageBps)))
+		return out.Div(out, big.NewInt(10000)).Add(out, big.NewInt(1))
+	}
+	return liquidity, amount0, amount1, pad(exact0), pad(exact1), nil
+}
+
+// withdrawal computes the liquidity the decrease form removes and the
+// slippage-adjusted minimum of each token it must return.
+func (f *lpForm) withdrawal(slippageBps uint32) (liquidity, min0, min1 *big.Int, err error) {
+	pct, perr := strconv.ParseFloat(f.pct, 64)
+	if perr != nil || pct <= 0 || pct > 100 {
+		return nil, nil, nil, fmt.Errorf("enter a percentage between 0 and 100")
+	}
+	if f.pos.Liquidity == nil || f.pos.Liquidity.Sign() == 0 {
+		return nil, nil, nil, fmt.Errorf("the position has no liquidity left — collect or burn it instead")
+	}
+	liquidity = new(big.Int).Mul(f.pos.Liquidity, big.NewInt(int64(pct*100)))
+	liquidity.Div(liquidity, big.NewInt(10000))
+	if liquidity.Sign() == 0 {
+		return nil, nil, nil, fmt.Errorf("percentage too small for this position")
+	}
+	min0, min1 = big.NewInt(0), big.NewInt(0)
+	if f.sqrtPriceX96 != nil {
+		a0, a1 := helpers.AmountsForLiquidity(liquidity, f.sqrtPriceX96, f.pos.TickLower, f.pos.TickUpper)
+		min0 = slippageMin(a0, slippageBps)
+		min1 = slippageMin(a1, slippageBps)
+	}
+	return liquidity, min0, min1, nil
+}
+
+// slippageMin applies a slippage tolerance to an expected amount.
+func slippageMin(amount *big.Int, slippageBps uint32) *big.Int {
+	if amount == nil {
+		return big.NewInt(0)
+	}
+	out := new(big.Int).Mul(amount, big.NewInt(int64(10000-slippageBps)))
+	return out.Div(out, big.NewInt(10000))
+}
+
+// parseTokenAmount converts a human-readable amount to base units.
+func parseTokenAmount(s string, decimals uint8) (*big.Int, bool) {
+	f, ok := new(big.Float).SetString(s)
+	if !ok || f.Sign() <= 0 {
+		return nil, false
+	}
+	f.Mul(f, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	amount, _ := f.Int(nil)
+	return amount, amount.Sign() > 0
+}
+
+// lpPercentPresets are the decrease form's ←/→ choices.
+var lpPercentPresets = []string{"25", "50", "75", "100"}
+
+// handleLPFormKey drives the liquidity form: ↑/↓ move between rows, digits
+// edit them, ←/→ cycle the pool, step a range bound by one tick spacing or
+// cycle the decrease presets, Space switches the deposit token, f sets a
+// full range and Enter packages the action.
+func (u *Module) handleLPFormKey(h dapp.Host, msg tea.KeyMsg) tea.Cmd {
+	f := u.lpForm
+	n := f.fields()
+	amountRow := n - 1
+	k := msg.String()
+	switch {
+	case k == "esc":
+		u.lpForm = nil
+		return nil
+	case k == "down" || k == "tab":
+		f.focused = (f.focused + 1) % n
+		return nil
+	case k == "up" || k == "shift+tab":
+		f.focused = (f.focused + n - 1) % n
+		return nil
+	case k == "enter":
+		return u.submitLPForm(h)
+	case k == "f" && f.action == lpMint && f.sqrtPriceX96 != nil:
+		f.setTick(true, helpers.UsableTick(helpers.MinTick, f.pool.key.TickSpacing))
+		f.setTick(false, helpers.UsableTick(helpers.MaxTick, f.pool.key.TickSpacing))
+		f.err = ""
+		return nil
+	case k == " " && f.action != lpDecrease && f.focused == amountRow:
+		f.side = 1 - f.side
+		f.amount = ""
+		f.err = ""
+		return nil
+	}
+
+	if k == "left" || k == "right" {
+		step := 1
+		if k == "left" {
+			step = -1
+		}
+		switch {
+		case f.action == lpMint && f.focused == 0:
+			f.poolIdx = (f.poolIdx + step + len(f.pools)) % len(f.pools)
+			return u.loadMintPool(h)
+		case f.action == lpMint && (f.focused == 1 || f.focused == 2) && f.sqrtPriceX96 != nil:
+			lower := f.focused == 1
+			tick := f.tickUpper
+			if lower {
+				tick = f.tickLower
+			}
+			tick = helpers.UsableTick(tick+int32(step)*f.pool.key.TickSpacing, f.pool.key.TickSpacing)
+			f.setTick(lower, tick)
+			f.err = ""
+		case f.action == lpDecrease:
+			idx := 0
+			for i, p := range lpPercentPresets {
+				if p == f.pct {
+					idx = (i + step + len(lpPercentPresets)) % len(lpPercentPresets)
+				}
+			}
+			f.pct = lpPercentPresets[idx]
+		}
+		return nil
+	}
+
+	buf := &f.amount
+	switch {
+	case f.action == lpDecrease:
+		buf = &f.pct
+	case f.action == lpMint && f.focused == 0:
+		return nil
+	case f.action == lpMint && f.focused == 1:
+		buf = &f.minPrice
+	case f.action == lpMint && f.focused == 2:
+		buf = &f.maxPrice
+	}
+	switch {
+	case k == "backspace":
+		if len(*buf) > 0 {
+			*buf = (*buf)[:len(*buf)-1]
+		}
+	case len(k) == 1 && strings.Contains("0123456789.", k):
+		if k == "." && strings.Contains(*buf, ".") {
+			return nil
+		}
+		*buf += k
+	default:
+		return nil
+	}
+	f.err = ""
+	if f.action == lpMint && (f.focused == 1 || f.focused == 2) {
+		if p, err := strconv.ParseFloat(*buf, 64); err == nil && p > 0 {
+			tick := helpers.PriceToTick(p, f.pool.token0.Decimals, f.pool.token1.Decimals, f.pool.key.TickSpacing)
+			if f.focused == 1 {
+				f.tickLower = tick
+			} else {
+				f.tickUpper = tick
+			}
+		}
+	}
+	return nil
+}
+
+// submitLPForm validates the form and packages its action.
+func (u *Module) submitLPForm(h dapp.Host) tea.Cmd {
+	f := u.lpForm
+	r := u.lpRequestFor(h, f.pool)
+	switch f.action {
+	case lpDecrease:
+		liquidity, min0, min1, err := f.withdrawal(u.settings.SlippageBps)
+		if err != nil {
+			f.err = err.Error()
+			return nil
+		}
+		u.lpForm = nil
+		h.LogInfo(fmt.Sprintf("Packaging V4 decrease liquidity #%s: %s%% (%s slippage)", f.pos.TokenID, f.pct, formatBps(u.settings.SlippageBps)))
+		return h.Package(r.buildDecrease(f.pos.TokenID, liquidity, min0, min1))
+	default:
+		liquidity, _, _, max0, max1, err := f.deposit(u.settings.SlippageBps)
+		if err != nil {
+			f.err = err.Error()
+			return nil
+		}
+		u.lpForm = nil
+		if f.action == lpMint {
+			h.LogInfo(fmt.Sprintf("Packaging V4 new position in %s: ticks %d → %d, up to %s + %s",
+				f.pool.label(), f.tickLower, f.tickUpper, formatAmount(max0, f.pool.token0), formatAmount(max1, f.pool.token1)))
+			return h.Package(r.buildMint(f.tickLower, f.tickUpper, liquidity, max0, max1))
+		}
+		h.LogInfo(fmt.Sprintf("Packaging V4 increase liquidity #%s: up to %s + %s",
+			f.pos.TokenID, formatAmount(max0, f.pool.token0), formatAmount(max1, f.pool.token1)))
+		return h.Package(r.buildIncrease(f.pos.TokenID, liquidity, max0, max1))
+	}
+}
+
+// collectFees packages a fee collection for the selected V4 position.
+func (u *Module) collectFees(h dapp.Host) tea.Cmd {
+	pos, ok := u.focusedV4Position(h)
+	if !ok {
+		return nil
+	}
+	h.LogInfo("Packaging V4 fee collection for #" + pos.TokenID.String())
+	zero := big.NewInt(0)
+	return h.Package(u.lpRequestFor(h, positionPool(pos)).buildDecrease(pos.TokenID, zero, zero, zero))
+}
+
+// burnPosition packages a burn of the selected V4 position, guarding what
+// its remaining liquidity returns with the slippage tolerance.
+func (u *Module) burnPosition(h dapp.Host) tea.Cmd {
+	pos, ok := u.focusedV4Position(h)
+	if !ok {
+		return nil
+	}
+	h.LogInfo("Packaging V4 burn for #" + pos.TokenID.String())
+	min0 := slippageMin(pos.Amount0, u.settings.SlippageBps)
+	min1 := slippageMin(pos.Amount1, u.settings.SlippageBps)
+	return h.Package(u.lpRequestFor(h, positionPool(pos)).buildBurn(pos.TokenID, min0, min1))
+}
+
+// lpFormView is the view state for RenderLiquidityForm.
+func (u *Module) lpFormView(h dapp.Host) uniswapview.LiquidityForm {
+	f := u.lpForm
+	v := uniswapview.LiquidityForm{Pool: f.pool.label(), Focused: f.focused, Err: f.err}
+	t0, t1 := f.pool.token0, f.pool.token1
+	switch {
+	case f.loading:
+		v.Price = h.Spinner() + " Loading pool price…"
+	case f.sqrtPriceX96 != nil:
+		price := helpers.TickToPrice(f.tick, t0.Decimals, t1.Decimals)
+		v.Price = fmt.Sprintf("Current price: %s %s/%s (tick %d)", strconv.FormatFloat(price, 'g', 6, 64), t1.Symbol, t0.Symbol, f.tick)
+	}
+
+	switch f.action {
+	case lpIncrease:
+		v.Title = "Increase Liquidity #" + f.pos.TokenID.String()
+		v.Fields = []uniswapview.LiquidityFormField{{Label: "Deposit", Value: f.amount, Unit: f.sideToken().Symbol + "  (Space to switch token)"}}
+		v.Hint = "Space switch token • Enter package • Esc cancel"
+	case lpDecrease:
+		v.Title = "Decrease Liquidity #" + f.pos.TokenID.String()
+		v.Fields = []uniswapview.LiquidityFormField{{Label: "Remove", Value: f.pct, Unit: "% of liquidity  (←/→ presets)"}}
+		v.Hint = "←/→ 25/50/75/100% • Enter package • Esc cancel"
+	case lpMint:
+		v.Title = "New V4 Position"
+		rangeUnit := fmt.Sprintf("%s/%s", t1.Symbol, t0.Symbol)
+		v.Fields = []uniswapview.LiquidityFormField{
+			{Label: fmt.Sprintf("Pool (%d of %d)", f.poolIdx+1, len(f.pools)), Value: f.pool.label(), Unit: "←/→"},
+			{Label: "Min price", Value: f.minPrice, Unit: fmt.Sprintf("%s  tick %d", rangeUnit, f.tickLower)},
+			{Label: "Max price", Value: f.maxPrice, Unit: fmt.Sprintf("%s  tick %d", rangeUnit, f.tickUpper)},
+			{Label: "Deposit", Value: f.amount, Unit: f.sideToken().Symbol + "  (Space to switch token)"},
+		}
+		v.Hint = "←/→ pool / step range by one tick spacing • f full range • Space switch token • Enter package • Esc cancel"
+		if f.pool.key.Hooks != (common.Address{}) {
+			v.Warn = fmt.Sprintf("⚠ Hook-gated pool (%s) — deposits may be restricted", helpers.ShortenAddr(f.pool.key.Hooks.Hex()))
+		}
+	}
+
+	slip := formatBps(u.settings.SlippageBps)
+	if f.action == lpDecrease {
+		if liquidity, min0, min1, err := f.withdrawal(u.settings.SlippageBps); err == nil {
+			v.Preview = []string{
+				fmt.Sprintf("Removes %s of %s liquidity", liquidity, f.pos.Liquidity),
+				fmt.Sprintf("Receive at least %s + %s plus fees (%s slippage)", formatAmount(min0, t0), formatAmount(min1, t1), slip),
+			}
+		}
+	} else if f.amount != "" && !f.loading {
+		if _, a0, a1, max0, max1, err := f.deposit(u.settings.SlippageBps); err == nil {
+			v.Preview = []string{
+				fmt.Sprintf("Deposits ≈ %s + %s", formatAmount(a0, t0), formatAmount(a1, t1)),
+				fmt.Sprintf("At most %s + %s (%s slippage)", formatAmount(max0, t0), formatAmount(max1, t1), slip),
+			}
+		} else if v.Err == "" {
+			v.Err = err.Error()
+		}
+	}
+	return v
+}
@@ -0,0 +1,142 @@
+package uniswap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"charm-wallet-tui/helpers"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// words joins 32-byte ABI words written as hex, left-padding each with
+// zeros ("ff…" words are given in full).
+func words(ws ...string) []byte {
+	var out []byte
+	for _, w := range ws {
+		b, err := hex.DecodeString(strings.Repeat("0", 64-len(w)) + w)
+		if err != nil {
+			panic(err)
+		}
+		out = append(out, b...)
+	}
+	return out
+}
+
+// decodeModifyLiquidities splits modifyLiquidities calldata into its
+// actions, per-action params and deadline.
+func decodeModifyLiquidities(t *testing.T, calldata []byte) ([]byte, [][]byte, *big.Int) {
+	t.Helper()
+	if got := hex.EncodeToString(calldata[:4]); got != "dd46508f" {
+		t.Fatalf("selector = %s, want modifyLiquidities(bytes,uint256) dd46508f", got)
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(v4PositionEncodingABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer, err := parsedABI.Methods["modifyLiquidities"].Inputs.Unpack(calldata[4:])
+	if err != nil {
+		t.Fatalf("unpack modifyLiquidities: %v", err)
+	}
+	inner, err := parsedABI.Methods["encodeActionsParams"].Inputs.Unpack(outer[0].([]byte))
+	if err != nil {
+		t.Fatalf("unpack unlockData: %v", err)
+	}
+	return inner[0].([]byte), inner[1].([][]byte), outer[1].(*big.Int)
+}
+
+const (
+	lpToken1 = "00000000000000000000000000000000000000b2"
+	lpHooks  = "00000000000000000000000000000000000000c3"
+	lpOwner  = "00000000000000000000000000000000000000f1"
+)
+
+// lpTestRequest is a request against an ETH/token pool with a hook.
+func lpTestRequest() lpRequest {
+	return lpRequest{
+		from: common.HexToAddress(lpOwner),
+		pool: lpPool{key: helpers.V4PoolKey{
+			Currency1:   common.HexToAddress(lpToken1),
+			Hooks:       common.HexToAddress(lpHooks),
+			Fee:         3000,
+			TickSpacing: 60,
+		}},
+		deadline: 1700000000,
+	}
+}
+
+func checkActions(t *testing.T, name string, calldata []byte, wantActions []byte, wantParams ...[]byte) {
+	t.Helper()
+	actions, params, deadline := decodeModifyLiquidities(t, calldata)
+	if !bytes.Equal(actions, wantActions) {
+		t.Errorf("%s: actions = %x, want %x", name, actions, wantActions)
+	}
+	if deadline.Int64() != 1700000000 {
+		t.Errorf("%s: deadline = %s", name, deadline)
+	}
+	if len(params) != len(wantParams) {
+		t.Fatalf("%s: %d params, want %d", name, len(params), len(wantParams))
+	}
+	for i := range params {
+		if !bytes.Equal(params[i], wantParams[i]) {
+			t.Errorf("%s: params[%d] =\n%x\nwant\n%x", name, i, params[i], wantParams[i])
+		}
+	}
+}
+
+var (
+	closeETH   = words("")
+	closeToken = words(lpToken1)
+	sweepETH   = words("", lpOwner)
+	takePair   = words("", lpToken1, lpOwner)
+)
+
+func TestIncreaseCalldataGolden(t *testing.T) {
+	data, err := lpTestRequest().increaseCalldata(big.NewInt(7), big.NewInt(1000), big.NewInt(11), big.NewInt(22))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// INCREASE_LIQUIDITY (tokenId, liquidity, amount0Max, amount1Max, hookData),
+	// CLOSE_CURRENCY ×2, then SWEEP of the native ETH currency0.
+	checkActions(t, "increase", data, []byte{0x00, 0x12, 0x12, 0x14},
+		words("7", "3e8", "b", "16", "a0", "0"), closeETH, closeToken, sweepETH)
+}
+
+func TestDecreaseCalldataGolden(t *testing.T) {
+	data, err := lpTestRequest().decreaseCalldata(big.NewInt(7), big.NewInt(1000), big.NewInt(1), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// DECREASE_LIQUIDITY (tokenId, liquidity, amount0Min, amount1Min, hookData), TAKE_PAIR.
+	checkActions(t, "decrease", data, []byte{0x01, 0x11},
+		words("7", "3e8", "1", "2", "a0", "0"), takePair)
+}
+
+func TestMintCalldataGolden(t *testing.T) {
+	data, err := lpTestRequest().mintCalldata(-600, 600, big.NewInt(1000), big.NewInt(11), big.NewInt(22))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// MINT_POSITION (poolKey{currency0, currency1, fee, tickSpacing, hooks},
+	// tickLower, tickUpper, liquidity, amount0Max, amount1Max, owner, hookData),
+	// SETTLE_PAIR, then SWEEP of the native ETH currency0.
+	mint := words("", lpToken1, "bb8", "3c", lpHooks,
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffda8", "258",
+		"3e8", "b", "16", lpOwner, "180", "0")
+	checkActions(t, "mint", data, []byte{0x02, 0x0d, 0x14},
+		mint, words("", lpToken1), sweepETH)
+}
+
+func TestBurnCalldataGolden(t *testing.T) {
+	data, err := lpTestRequest().burnCalldata(big.NewInt(7), big.NewInt(1), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// BURN_POSITION (tokenId, amount0Min, amount1Min, hookData), TAKE_PAIR.
+	checkActions(t, "burn", data, []byte{0x03, 0x11},
+		words("7", "1", "2", "80", "0"), takePair)
+}
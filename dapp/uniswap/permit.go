@@ -0,0 +1,170 @@
+package uniswap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/rpc"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// -------------------- PERMIT2 SIGNATURE APPROVALS --------------------
+//
+// In signature mode a Universal Router swap skips the on-chain
+// Permit2.approve(router) step: the wallet signs a Permit2 PermitSingle as
+// EIP-712 typed data (an eth-sign-request QR, see Host.SignTypedData) and the
+// swap's execute() opens with a PERMIT2_PERMIT command carrying it, so the
+// router grants itself the allowance and spends it in the same transaction.
+// The one-time ERC20.approve(Permit2) is still a transaction of its own.
+//
+// [VERIFY] PERMIT2_PERMIT's input layout (a static PermitSingle followed by
+// the signature bytes) is the Universal Router's Dispatcher on current main;
+// confirm it against the deployed router alongside the V4_SWAP encoding.
+
+const urCommandPermit2Permit = 0x0a
+
+// permitExpiry is how long a signed permit's allowance stays live, matching
+// the on-chain Permit2.approve path in permit2Approval.
+const permitExpiry = 30 * 24 * time.Hour
+
+// permitGas is added to a swap's gas limit for the permit's signature check
+// and allowance write.
+const permitGas = 60000
+
+const permit2PermitEncodingABI = `[{
+  "inputs": [
+    {"name": "permitSingle", "type": "tuple", "components": [
+      {"name": "details", "type": "tuple", "components": [
+        {"name": "token", "type": "address"},
+        {"name": "amount", "type": "uint160"},
+        {"name": "expiration", "type": "uint48"},
+        {"name": "nonce", "type": "uint48"}
+      ]},
+      {"name": "spender", "type": "address"},
+      {"name": "sigDeadline", "type": "uint256"}
+    ]},
+    {"name": "signature", "type": "bytes"}
+  ],
+  "name": "encodePermit2Permit", "outputs": [], "stateMutability": "pure", "type": "function"
+}]`
+
+// encodePermit2Permit builds the PERMIT2_PERMIT command input for a signed
+// permit, with v normalised to 27/28 as Permit2's SignatureVerification
+// expects.
+func encodePermit2Permit(p rpc.PermitSingle, sig [65]byte) ([]byte, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(permit2PermitEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse PERMIT2_PERMIT encoding ABI: %w", err)
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	type permitDetails struct {
+		Token      common.Address
+		Amount     *big.Int
+		Expiration *big.Int
+		Nonce      *big.Int
+	}
+	return packStripSelector(&parsedABI, "encodePermit2Permit", struct {
+		Details     permitDetails
+		Spender     common.Address
+		SigDeadline *big.Int
+	}{
+		Details: permitDetails{
+			Token:      p.Token,
+			Amount:     p.Amount,
+			Expiration: new(big.Int).SetUint64(p.Expiration),
+			Nonce:      new(big.Int).SetUint64(p.Nonce),
+		},
+		Spender:     p.Spender,
+		SigDeadline: p.SigDeadline,
+	}, sig[:])
+}
+
+// swapBuild packages a swapRequest; executeSwap picks one per pool version
+// so signature mode can run it again once the permit is signed.
+type swapBuild func(s swapRequest) func(dapp.TxBuilder) (dapp.Packaged, error)
+
+// permitCheckMsg reports whether a swap in signature mode needs a permit:
+// permit is nil when Permit2's allowance for the router already covers it.
+type permitCheckMsg struct {
+	req    swapRequest
+	build  swapBuild
+	permit *rpc.PermitSingle
+	err    error
+}
+
+// checkPermit reads Permit2's allowance for the router and, when it falls
+// short of the swap, prepares a PermitSingle at the allowance's nonce.
+func checkPermit(s swapRequest, build swapBuild) tea.Cmd {
+	return func() tea.Msg {
+		msg := permitCheckMsg{req: s, build: build}
+		if s.client == nil || s.client.Client == nil {
+			msg.err = fmt.Errorf("no RPC client")
+			return msg
+		}
+		router := s.addrs.UniversalRouter
+		amountIn, _ := s.amounts()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		amount, expiration, nonce, err := rpc.Permit2AllowanceWithNonce(ctx, s.client, s.fromToken.Address, s.from, router)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		if amount.Cmp(amountIn) >= 0 && expiration > uint64(time.Now().Unix()) {
+			return msg
+		}
+		msg.permit = &rpc.PermitSingle{
+			Token:       s.fromToken.Address,
+			Amount:      amountIn,
+			Expiration:  uint64(time.Now().Add(permitExpiry).Unix()),
+			Nonce:       nonce,
+			Spender:     router,
+			SigDeadline: big.NewInt(s.deadline),
+		}
+		return msg
+	}
+}
+
+// handlePermitCheck asks for the permit signature, or packages the swap
+// straight away when none is needed. If the allowance could not be read the
+// swap falls back to approval transactions.
+func (u *Module) handlePermitCheck(h dapp.Host, msg permitCheckMsg) tea.Cmd {
+	if msg.err != nil {
+		h.LogWarn("Permit2 allowance check failed, using approval transactions: " + msg.err.Error())
+		return h.Package(msg.build(msg.req))
+	}
+	if msg.permit == nil {
+		h.LogInfo("Permit2 allowance already covers this swap — no permit needed")
+		return h.Package(msg.build(msg.req))
+	}
+
+	chainID := h.ChainID()
+	if chainID == nil {
+		chainID = big.NewInt(1)
+	}
+	p := *msg.permit
+	s := msg.req
+	summary := fmt.Sprintf("Permit2 permit: %s %s for Universal Router\nSpender: %s\nAllowance expires: %s · Signature valid until: %s\n\nAfter signing, the swap is packaged as one transaction.",
+		s.amountIn, s.fromToken.Symbol, p.Spender.Hex(),
+		time.Unix(int64(p.Expiration), 0).Format("2006-01-02 15:04"),
+		time.Unix(p.SigDeadline.Int64(), 0).Format("2006-01-02 15:04"))
+	h.LogInfo(fmt.Sprintf("Requesting Permit2 signature for %s %s (nonce %d)", s.amountIn, s.fromToken.Symbol, p.Nonce))
+
+	return h.SignTypedData(summary, rpc.Permit2TypedData(chainID, p), func(h dapp.Host, sig [65]byte) tea.Cmd {
+		input, err := encodePermit2Permit(p, sig)
+		if err != nil {
+			return h.Package(func(dapp.TxBuilder) (dapp.Packaged, error) { return dapp.Packaged{}, err })
+		}
+		s.permit = input
+		return h.Package(msg.build(s))
+	})
+}
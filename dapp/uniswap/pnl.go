@@ -0,0 +1,63 @@
+package uniswap
+
+import (
+	"fmt"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// positionPnLMsg carries one liquidity position's PnL.
+type positionPnLMsg struct {
+	version helpers.PoolVersion
+	tokenID string
+	pnl     *helpers.PositionPnL
+	err     error
+}
+
+// loadPositionPnLs starts a PnL lookup for each loaded position. They run
+// in parallel, as the V3 ones each scan the position manager's logs.
+func loadPositionPnLs(rpcURL string, st *store.Store, positions []helpers.LiquidityPosition) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, pos := range positions {
+		if pos.Stub {
+			continue
+		}
+		pos := pos
+		cmds = append(cmds, func() tea.Msg {
+			pnl, err := helpers.LoadPositionPnL(rpcURL, st, pos)
+			return positionPnLMsg{version: pos.Version, tokenID: pos.TokenID.String(), pnl: pnl, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (u *Module) handlePositionPnL(h dapp.Host, msg positionPnLMsg) tea.Cmd {
+	for i := range u.liquidityPositions {
+		pos := &u.liquidityPositions[i]
+		if pos.Version != msg.version || pos.TokenID.String() != msg.tokenID {
+			continue
+		}
+		label := "V4 #" + msg.tokenID
+		if pos.Version == helpers.PoolVersionV3 {
+			label = "V3 #" + msg.tokenID
+		}
+		if msg.err != nil {
+			h.LogWarn(fmt.Sprintf("PnL for %s not loaded: %v", label, msg.err))
+			return nil
+		}
+		pos.PnL = msg.pnl
+		if msg.pnl.Priced {
+			h.LogInfo(fmt.Sprintf("PnL for %s: net %+.2f USD, fees %.2f USD, IL %+.2f USD (%+.2f%%)",
+				label, msg.pnl.NetUSD, msg.pnl.FeesUSD, msg.pnl.ILUSD, msg.pnl.ILPct))
+		}
+		for _, n := range msg.pnl.Notes {
+			h.LogInfo(fmt.Sprintf("  %s: %s", label, n))
+		}
+		return nil
+	}
+	return nil
+}
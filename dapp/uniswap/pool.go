@@ -0,0 +1,196 @@
+package uniswap
+
+import (
+	"fmt"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/store"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// candleInterval is one selectable candle width. The store has block
+// numbers but no timestamps, so intervals are in blocks at ~12s each.
+type candleInterval struct {
+	blocks uint64
+	label  string
+}
+
+var candleIntervals = []candleInterval{
+	{25, "5m"},
+	{300, "1h"},
+	{1800, "6h"},
+	{7200, "1d"},
+}
+
+// defaultCandleInterval indexes candleIntervals (1h).
+const defaultCandleInterval = 1
+
+// depthZooms are the depth histogram's bucket widths, in tick spacings.
+var depthZooms = []int32{1, 2, 4, 8, 16, 32}
+
+// topTraderCount is how many swap senders the analytics page ranks.
+const topTraderCount = 5
+
+// poolAnalytics is the open pool detail page: the pool's indexed events
+// and the chart settings they are drawn with.
+type poolAnalytics struct {
+	id       common.Hash
+	loading  bool
+	err      string
+	meta     store.V4PoolMeta
+	swaps    []store.V4SwapRow
+	events   []store.V4LiquidityRow
+	interval int // index into candleIntervals
+	zoom     int // index into depthZooms
+}
+
+// poolAnalyticsMsg carries a pool's indexed events from the event store.
+type poolAnalyticsMsg struct {
+	id     common.Hash
+	meta   store.V4PoolMeta
+	found  bool
+	swaps  []store.V4SwapRow
+	events []store.V4LiquidityRow
+	err    error
+}
+
+// loadPoolAnalytics reads id's metadata, swaps and liquidity events from st.
+func loadPoolAnalytics(st *store.Store, id common.Hash) tea.Cmd {
+	return func() tea.Msg {
+		msg := poolAnalyticsMsg{id: id}
+		msg.meta, msg.found, msg.err = st.V4Pool(id.Hex())
+		if msg.err != nil || !msg.found {
+			return msg
+		}
+		if msg.swaps, msg.err = st.V4PoolSwaps(id.Hex()); msg.err != nil {
+			return msg
+		}
+		msg.events, msg.err = st.V4PoolLiquidityEvents(id.Hex())
+		return msg
+	}
+}
+
+// openPoolAnalytics opens the analytics page for id (selected in the V4
+// events panel) and starts loading its events.
+func (u *Module) openPoolAnalytics(h dapp.Host, id common.Hash) tea.Cmd {
+	u.pool = &poolAnalytics{id: id, interval: defaultCandleInterval}
+	if h.Store() == nil {
+		u.pool.err = "Event store unavailable — pool analytics needs indexed events."
+		return nil
+	}
+	u.pool.loading = true
+	return loadPoolAnalytics(h.Store(), id)
+}
+
+func (u *Module) handlePoolAnalytics(h dapp.Host, msg poolAnalyticsMsg) tea.Cmd {
+	if u.pool == nil || u.pool.id != msg.id {
+		return nil
+	}
+	p := u.pool
+	p.loading = false
+	switch {
+	case msg.err != nil:
+		p.err = fmt.Sprintf("Failed to load pool events: %v", msg.err)
+		h.LogError(p.err)
+	case !msg.found:
+		p.err = "Pool not indexed yet — its Initialize event has not been stored."
+	default:
+		p.err = ""
+		p.meta, p.swaps, p.events = msg.meta, msg.swaps, msg.events
+		h.LogInfo(fmt.Sprintf("Loaded %d swaps and %d liquidity events for pool %s",
+			len(p.swaps), len(p.events), helpers.ShortenAddr(msg.id.Hex())))
+	}
+	return nil
+}
+
+func (u *Module) handlePoolKey(h dapp.Host, msg tea.KeyMsg) tea.Cmd {
+	p := u.pool
+	switch msg.String() {
+	case "esc", "q", "Q":
+		u.pool = nil
+	case "[":
+		p.interval = (p.interval - 1 + len(candleIntervals)) % len(candleIntervals)
+	case "]":
+		p.interval = (p.interval + 1) % len(candleIntervals)
+	case "+", "=":
+		if p.zoom > 0 {
+			p.zoom--
+		}
+	case "-":
+		if p.zoom < len(depthZooms)-1 {
+			p.zoom++
+		}
+	case "r", "R":
+		if !p.loading && h.Store() != nil {
+			p.loading = true
+			return loadPoolAnalytics(h.Store(), p.id)
+		}
+	}
+	return nil
+}
+
+// poolAnalyticsView derives the page's charts from the loaded events.
+func (u *Module) poolAnalyticsView(width int) uniswapview.PoolAnalytics {
+	p := u.pool
+	iv := candleIntervals[p.interval]
+	v := uniswapview.PoolAnalytics{
+		Title:    "Pool " + helpers.ShortenAddr(p.id.Hex()),
+		Subtitle: p.id.Hex(),
+		Loading:  p.loading,
+		Err:      p.err,
+		Interval: fmt.Sprintf("%s (%d blocks)", iv.label, iv.blocks),
+		Hint:     "[/] candle interval   +/- depth zoom   r refresh   Esc back",
+	}
+	if p.loading || p.err != "" {
+		return v
+	}
+
+	m := p.meta
+	v.Sym0, v.Sym1 = poolSymbol(m.Token0Sym, m.Currency0), poolSymbol(m.Token1Sym, m.Currency1)
+	v.Decimals0, v.Decimals1 = m.Decimals0, m.Decimals1
+	v.Title = v.Sym0 + " / " + v.Sym1
+	v.Subtitle = fmt.Sprintf("V4 · fee %.4f%% · spacing %d", float64(m.Fee)/10000, m.TickSpacing)
+	if hooks := common.HexToAddress(m.Hooks); hooks != (common.Address{}) {
+		v.Subtitle += " · hooks " + helpers.ShortenAddr(hooks.Hex())
+	}
+	v.Subtitle += " · " + helpers.ShortenAddr(p.id.Hex())
+	v.Swaps, v.LiqEvents = len(p.swaps), len(p.events)
+
+	// The current state is the last indexed swap's, or the pool's
+	// initialization when it has never been swapped.
+	tick := int32(m.InitTick)
+	sqrtPrice := helpers.SqrtPriceAtTick(tick)
+	if n := len(p.swaps); n > 0 {
+		tick = int32(p.swaps[n-1].Tick)
+		sqrtPrice = p.swaps[n-1].SqrtPrice
+	}
+	v.CurrentTick = tick
+	v.Price = helpers.SqrtPriceToPrice(sqrtPrice, m.Decimals0, m.Decimals1)
+
+	v.Candles = helpers.BuildCandles(p.swaps, iv.blocks, m.Decimals0, m.Decimals1)
+	spacing := int32(m.TickSpacing)
+	if spacing <= 0 {
+		spacing = 1
+	}
+	v.Depth = helpers.LiquidityDepth(p.events, tick, spacing*depthZooms[p.zoom], uniswapview.PoolChartColumns(width))
+	v.Fees = helpers.EstimateFeeAPR(p.swaps, p.events, sqrtPrice, m.Decimals0, m.Decimals1)
+	v.Traders = helpers.TopTraders(p.swaps, m.Decimals1, topTraderCount)
+	return v
+}
+
+// poolSymbol is sym, or ETH for the native currency, or the shortened
+// address when the token's metadata has not been indexed.
+func poolSymbol(sym, currency string) string {
+	switch {
+	case sym != "":
+		return sym
+	case common.HexToAddress(currency) == (common.Address{}):
+		return "ETH"
+	default:
+		return helpers.ShortenAddr(currency)
+	}
+}
@@ -0,0 +1,564 @@
+package uniswap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// quoteMsg contains result of Uniswap price quote fetch
+type quoteMsg struct {
+	quote *helpers.SwapQuote
+	err   error
+}
+
+// pairLookupResultMsg carries the route graph discovered on-chain for a
+// token pair (see resolvePairOnChain).
+type pairLookupResultMsg struct {
+	cacheKey       string
+	fromIdx, toIdx int  // token indices active when the lookup was dispatched
+	reverse        bool // which quote direction triggered this lookup
+	graph          helpers.RouteGraph
+	ok             bool
+}
+
+// liquidityPositionsMsg carries the result of a V4 liquidity position lookup
+type liquidityPositionsMsg struct {
+	positions   []helpers.LiquidityPosition
+	nftCount    uint64   // total NFTs reported by balanceOf before filtering
+	diagnostics []string // per-step diagnostic lines for the logger
+	err         error
+}
+
+// buildTokenList builds the list of swappable tokens from wallet details and watchlist.
+func (u *Module) buildTokenList(h dapp.Host) []uniswapview.TokenOption {
+	type heldToken struct {
+		balance  *big.Int
+		decimals uint8
+	}
+	details := h.Wallet()
+	held := make(map[string]heldToken, len(details.Tokens))
+	for _, token := range details.Tokens {
+		held[token.Symbol] = heldToken{balance: token.Balance, decimals: token.Decimals}
+	}
+
+	tokens := []uniswapview.TokenOption{{
+		Symbol:   "ETH",
+		Balance:  details.EthWei,
+		Decimals: 18,
+		IsETH:    true,
+	}}
+
+	for _, wt := range h.WatchedTokens() {
+		opt := uniswapview.TokenOption{
+			Symbol:   wt.Symbol,
+			Decimals: wt.Decimals,
+			IsETH:    false,
+			Address:  wt.Address,
+		}
+		if ht, ok := held[wt.Symbol]; ok {
+			opt.Balance = ht.balance
+			opt.Decimals = ht.decimals
+		}
+		tokens = append(tokens, opt)
+	}
+	return tokens
+}
+
+// pairResolution carries routing metadata for a resolved token pair.
+type pairResolution struct {
+	pairAddr   common.Address // V2 pair contract or V3 pool contract; zero for V4
+	tokenIn    common.Address
+	version    helpers.PoolVersion
+	v3Fee      uint32
+	v3TokenOut common.Address // explicit tokenOut needed by QuoterV2
+	v4Key      helpers.V4PoolKey
+	v4PoolID   common.Hash
+}
+
+// pairCacheEntry caches the route graph discovered for a token pair
+// (direction-agnostic — resolvePairCached orients it to whichever direction
+// is currently selected). ok=false means no route of up to maxRouteHops
+// connects the pair.
+type pairCacheEntry struct {
+	graph helpers.RouteGraph
+	ok    bool
+}
+
+// tokenAddrForLookup returns the ERC-20 address to use when querying
+// factories/pools for opt — native ETH has no contract, so WETH stands in,
+// matching how the router/quoter calls already treat ETH elsewhere.
+func tokenAddrForLookup(opt uniswapview.TokenOption, weth common.Address) common.Address {
+	if opt.IsETH {
+		return weth
+	}
+	return opt.Address
+}
+
+// pairCacheKey normalizes two token addresses into an order-independent key,
+// since a pool is the same regardless of swap direction.
+func pairCacheKey(a, b common.Address) string {
+	ah, bh := strings.ToLower(a.Hex()), strings.ToLower(b.Hex())
+	if ah > bh {
+		ah, bh = bh, ah
+	}
+	return ah + "_" + bh
+}
+
+// cacheForChain returns the pair cache, emptying it first if the connected
+// chain has changed since it was filled.
+func (u *Module) cacheForChain(h dapp.Host) map[string]pairCacheEntry {
+	chain := "unknown"
+	if id := h.ChainID(); id != nil {
+		chain = id.String()
+	}
+	if u.pairCacheChain != chain {
+		u.pairCache = make(map[string]pairCacheEntry)
+		u.pairCacheChain = chain
+	}
+	return u.pairCache
+}
+
+// resolvePairCached returns the cached route graph for the selected pair,
+// oriented from→to, without any on-chain I/O. found=false means this pair has
+// never been looked up on this chain (the caller should dispatch
+// resolvePairOnChain). found=true with ok=false means it was already looked
+// up and definitively has no route.
+func (u *Module) resolvePairCached(h dapp.Host, from, to uniswapview.TokenOption) (g helpers.RouteGraph, ok bool, found bool) {
+	addrs := helpers.UniswapAddressesForChain(h.ChainID())
+	tokenInAddr := tokenAddrForLookup(from, addrs.WETH)
+	tokenOutAddr := tokenAddrForLookup(to, addrs.WETH)
+
+	entry, found := u.cacheForChain(h)[pairCacheKey(tokenInAddr, tokenOutAddr)]
+	if !found {
+		return helpers.RouteGraph{}, false, false
+	}
+	g = entry.graph
+	g.TokenIn, g.TokenOut = tokenInAddr, tokenOutAddr
+	return g, entry.ok, true
+}
+
+// directResolution picks the pool a direct (single-hop) quote for g's pair
+// uses — the same preference ResolvePairOnChain applies — or ok=false when
+// the pair only trades through a hub token.
+func directResolution(g helpers.RouteGraph) (res pairResolution, ok bool) {
+	pool, ok := helpers.PreferredPool(g.Direct())
+	if !ok {
+		return pairResolution{}, false
+	}
+	res = pairResolution{
+		pairAddr: pool.PairAddr, tokenIn: g.TokenIn, version: pool.Version, v3Fee: pool.V3Fee,
+		v4Key: pool.V4Key, v4PoolID: pool.V4PoolID,
+	}
+	if res.version == helpers.PoolVersionV3 {
+		res.v3TokenOut = g.TokenOut
+	}
+	return res, true
+}
+
+// resolvePairOnChain dispatches discovery of the route graph between
+// tokenA/tokenB and the router's hub tokens. fromIdx/toIdx capture the
+// dropdown selection active when the lookup was dispatched, so
+// handlePairLookupResult can detect a stale result if the user changes the
+// selection before the lookup returns.
+func resolvePairOnChain(client *rpc.Client, addrs helpers.UniswapNetworkAddresses, tokenA, tokenB common.Address, fromIdx, toIdx int, reverse bool) tea.Cmd {
+	return func() tea.Msg {
+		key := pairCacheKey(tokenA, tokenB)
+		if client == nil || client.Client == nil {
+			return pairLookupResultMsg{cacheKey: key, fromIdx: fromIdx, toIdx: toIdx, reverse: reverse, ok: false}
+		}
+		// 30s: every edge is looked up concurrently, but the direct edge
+		// still includes the V4 tier's bounded recent-block log scan
+		// (resolveV4Pool), which FetchPoolKey budgets 20s for on its own.
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		g := helpers.DiscoverRouteGraph(ctx, client.Client, addrs, tokenA, tokenB)
+		return pairLookupResultMsg{
+			cacheKey: key, fromIdx: fromIdx, toIdx: toIdx, reverse: reverse,
+			graph: g, ok: len(g.Paths(maxRouteHops)) > 0,
+		}
+	}
+}
+
+// handlePairLookupResult applies the result of an on-chain factory lookup
+// dispatched by resolvePairOnChain: caches it, then resumes whichever quote
+// direction triggered the lookup. If the from/to selection has changed since
+// the lookup was dispatched, the result is still cached (not wasted) but no
+// quote is resumed for it.
+func (u *Module) handlePairLookupResult(h dapp.Host, msg pairLookupResultMsg) tea.Cmd {
+	u.resolvingPair = false
+	u.cacheForChain(h)[msg.cacheKey] = pairCacheEntry{graph: msg.graph, ok: msg.ok}
+
+	if msg.fromIdx != u.fromTokenIdx || msg.toIdx != u.toTokenIdx {
+		return nil
+	}
+	if !msg.ok {
+		if fromToken, toToken, ok := u.resolveSwapTokens(h); ok {
+			u.quoteError = fmt.Sprintf("No Uniswap route found for %s/%s", fromToken.Symbol, toToken.Symbol)
+			h.LogWarn(u.quoteError)
+		}
+		return nil
+	}
+	if msg.reverse {
+		return u.maybeRequestReverseQuote(h)
+	}
+	return u.maybeRequestQuote(h)
+}
+
+// resolveSwapTokens returns the from/to TokenOptions and whether the pair is valid.
+func (u *Module) resolveSwapTokens(h dapp.Host) (from, to uniswapview.TokenOption, ok bool) {
+	tokens := u.buildTokenList(h)
+	if u.fromTokenIdx < 0 || u.fromTokenIdx >= len(tokens) {
+		return
+	}
+	if u.toTokenIdx < 0 || u.toTokenIdx >= len(tokens) {
+		return
+	}
+	from = tokens[u.fromTokenIdx]
+	to = tokens[u.toTokenIdx]
+	ok = from.Symbol != to.Symbol
+	return
+}
+
+// clearQuoteState resets all swap quote fields.
+func (u *Module) clearQuoteState() {
+	u.quote = nil
+	u.quoteError = ""
+	u.priceImpactWarn = ""
+	u.hookWarn = ""
+	u.route = nil
+	u.bestRoute = nil
+	u.poolQuotes = nil
+	u.poolChoice = 0
+}
+
+// maybeRequestQuote triggers a forward swap quote fetch (input → output).
+func (u *Module) maybeRequestQuote(h dapp.Host) tea.Cmd {
+	if u.fromAmount == "" || u.fromAmount == "0" {
+		u.toAmount = ""
+		u.clearQuoteState()
+		u.lastQuoteFromAmount = ""
+		return nil
+	}
+
+	fromToken, toToken, ok := u.resolveSwapTokens(h)
+	if !ok {
+		return nil
+	}
+
+	if u.lastQuoteFromAmount == u.fromAmount &&
+		u.lastQuoteFromTokenIdx == u.fromTokenIdx &&
+		u.lastQuoteToTokenIdx == u.toTokenIdx &&
+		u.quote != nil && u.toAmount != "" {
+		return nil
+	}
+
+	// Wraps are 1:1 and packaged at exactly this amount, so parse it
+	// without float rounding.
+	if wrap, ok := wrapDirection(fromToken, toToken, helpers.UniswapAddressesForChain(h.ChainID()).WETH); ok {
+		amount, err := helpers.ParseTokenAmount(u.fromAmount, fromToken.Decimals)
+		if err != nil || amount.Sign() <= 0 {
+			return nil
+		}
+		return u.quoteWrap(h, wrap, amount, false)
+	}
+
+	amountFloat := new(big.Float)
+	if _, ok := amountFloat.SetString(u.fromAmount); !ok {
+		return nil
+	}
+	multiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromToken.Decimals)), nil))
+	amountIn, _ := new(big.Float).Mul(amountFloat, multiplier).Int(nil)
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return nil
+	}
+
+	addrs := helpers.UniswapAddressesForChain(h.ChainID())
+	g, ok, found := u.resolvePairCached(h, fromToken, toToken)
+	if !found {
+		tokenA := tokenAddrForLookup(fromToken, addrs.WETH)
+		tokenB := tokenAddrForLookup(toToken, addrs.WETH)
+		u.resolvingPair = true
+		u.clearQuoteState()
+		return resolvePairOnChain(h.Client(), addrs, tokenA, tokenB, u.fromTokenIdx, u.toTokenIdx, false)
+	}
+	if !ok {
+		u.quoteError = fmt.Sprintf("No Uniswap route found for %s/%s", fromToken.Symbol, toToken.Symbol)
+		return nil
+	}
+
+	u.lastQuoteFromAmount = u.fromAmount
+	u.lastQuoteFromTokenIdx = u.fromTokenIdx
+	u.lastQuoteToTokenIdx = u.toTokenIdx
+	u.toAmount = ""
+	u.clearQuoteState()
+	u.estimating = true
+
+	// Forward quotes go through the router, which weighs the direct pools
+	// against every path through the hub tokens; each direct pool is also
+	// quoted on its own for the comparison table.
+	return tea.Batch(
+		fetchRouteQuote(h.Client(), addrs, g, amountIn),
+		fetchPoolQuotes(h.Client(), addrs, g, amountIn, u.fromTokenIdx, u.toTokenIdx, u.fromAmount),
+	)
+}
+
+// maybeRequestReverseQuote triggers a reverse swap quote fetch (output → required input).
+func (u *Module) maybeRequestReverseQuote(h dapp.Host) tea.Cmd {
+	if u.toAmount == "" || u.toAmount == "0" {
+		u.fromAmount = ""
+		u.clearQuoteState()
+		return nil
+	}
+
+	fromToken, toToken, ok := u.resolveSwapTokens(h)
+	if !ok {
+		return nil
+	}
+
+	if u.lastQuoteToAmount == u.toAmount &&
+		u.lastQuoteFromTokenIdx == u.fromTokenIdx &&
+		u.lastQuoteToTokenIdx == u.toTokenIdx &&
+		u.quote != nil && u.fromAmount != "" {
+		return nil
+	}
+
+	// Wraps are 1:1 and packaged at exactly this amount, so parse it
+	// without float rounding.
+	if wrap, ok := wrapDirection(fromToken, toToken, helpers.UniswapAddressesForChain(h.ChainID()).WETH); ok {
+		amount, err := helpers.ParseTokenAmount(u.toAmount, toToken.Decimals)
+		if err != nil || amount.Sign() <= 0 {
+			return nil
+		}
+		return u.quoteWrap(h, wrap, amount, true)
+	}
+
+	amountOutFloat := new(big.Float)
+	if _, ok := amountOutFloat.SetString(u.toAmount); !ok {
+		return nil
+	}
+	multiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(toToken.Decimals)), nil))
+	amountOut, _ := new(big.Float).Mul(amountOutFloat, multiplier).Int(nil)
+	if amountOut == nil || amountOut.Sign() <= 0 {
+		return nil
+	}
+
+	addrs := helpers.UniswapAddressesForChain(h.ChainID())
+	g, ok, found := u.resolvePairCached(h, fromToken, toToken)
+	if !found {
+		tokenA := tokenAddrForLookup(fromToken, addrs.WETH)
+		tokenB := tokenAddrForLookup(toToken, addrs.WETH)
+		u.resolvingPair = true
+		u.clearQuoteState()
+		return resolvePairOnChain(h.Client(), addrs, tokenA, tokenB, u.fromTokenIdx, u.toTokenIdx, true)
+	}
+	if !ok {
+		u.quoteError = fmt.Sprintf("No Uniswap route found for %s/%s", fromToken.Symbol, toToken.Symbol)
+		return nil
+	}
+	// Exact-output quotes stay single-hop: the router only quotes forward.
+	pr, ok := directResolution(g)
+	if !ok {
+		u.quoteError = fmt.Sprintf("No direct %s/%s pool — enter a From amount to route through hub tokens", fromToken.Symbol, toToken.Symbol)
+		return nil
+	}
+
+	u.lastQuoteToAmount = u.toAmount
+	u.lastQuoteFromTokenIdx = u.fromTokenIdx
+	u.lastQuoteToTokenIdx = u.toTokenIdx
+	u.fromAmount = ""
+	u.clearQuoteState()
+	u.estimating = true
+	h.LogInfo(fmt.Sprintf("Calculating required input for %s %s", u.toAmount, toToken.Symbol))
+
+	u.lastVersion = pr.version
+	switch pr.version {
+	case helpers.PoolVersionV3:
+		u.lastFee = pr.v3Fee
+		return fetchV3ReverseSwapQuote(h.Client(), addrs.QuoterV2, pr.pairAddr, pr.tokenIn, pr.v3TokenOut, pr.v3Fee, amountOut)
+	case helpers.PoolVersionV4:
+		u.lastV4Key = pr.v4Key
+		u.lastV4PoolID = pr.v4PoolID
+		return fetchV4ReverseSwapQuote(h.Client(), addrs, pr.v4Key, pr.v4PoolID, pr.tokenIn, amountOut)
+	default:
+		u.lastFee = 0
+		return fetchV2ReverseSwapQuote(h.Client(), pr.pairAddr, pr.tokenIn, amountOut)
+	}
+}
+
+func (u *Module) handleQuote(h dapp.Host, msg quoteMsg) tea.Cmd {
+	u.estimating = false
+	if msg.err != nil {
+		u.quoteError = msg.err.Error()
+		u.quote = nil
+		u.toAmount = ""
+		u.fromAmount = ""
+		u.priceImpactWarn = ""
+		u.hookWarn = ""
+		h.LogError(fmt.Sprintf("Swap quote error: %v", msg.err))
+		return nil
+	}
+	u.quoteError = ""
+	u.quote = msg.quote
+	u.priceImpactWarn = ""
+	u.hookWarn = ""
+
+	if msg.quote == nil {
+		return nil
+	}
+
+	fromToken, toToken, ok := u.resolveSwapTokens(h)
+	if !ok {
+		return nil
+	}
+	isReverseQuote := u.fromAmount == "" && u.toAmount != ""
+
+	version := "V2"
+	if msg.quote.IsV3 {
+		version = "V3"
+	}
+	if msg.quote.IsV4 {
+		version = "V4"
+	}
+	if u.route != nil && len(u.route.Hops) > 1 {
+		version = "Multi-hop"
+	}
+
+	if isReverseQuote {
+		divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromToken.Decimals)), nil))
+		u.fromAmount = new(big.Float).Quo(new(big.Float).SetInt(msg.quote.AmountIn), divisor).Text('f', 6)
+		u.editingFrom = false
+		h.LogInfo(fmt.Sprintf("📊 %s Reverse Quote: %s → %s", version, fromToken.Symbol, toToken.Symbol))
+		h.LogInfo(fmt.Sprintf("  Amount In: %s %s", u.fromAmount, fromToken.Symbol))
+		h.LogInfo(fmt.Sprintf("  Amount Out: %s %s", u.toAmount, toToken.Symbol))
+	} else {
+		h.LogInfo(fmt.Sprintf("📊 %s Swap Quote: %s → %s", version, fromToken.Symbol, toToken.Symbol))
+		h.LogInfo(fmt.Sprintf("  Amount In: %s %s", u.fromAmount, fromToken.Symbol))
+		divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(toToken.Decimals)), nil))
+		u.toAmount = new(big.Float).Quo(new(big.Float).SetInt(msg.quote.AmountOut), divisor).Text('f', 6)
+		h.LogInfo(fmt.Sprintf("  Amount Out: %s %s", u.toAmount, toToken.Symbol))
+	}
+
+	h.LogInfo(fmt.Sprintf("  Price Impact: %.4f%%", msg.quote.PriceImpact))
+	if !msg.quote.IsV3 && msg.quote.Token0Reserve != nil && msg.quote.Token1Reserve != nil {
+		divisor18 := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+		r0 := new(big.Float).Quo(new(big.Float).SetInt(msg.quote.Token0Reserve), divisor18)
+		r1 := new(big.Float).Quo(new(big.Float).SetInt(msg.quote.Token1Reserve), divisor18)
+		h.LogInfo(fmt.Sprintf("  Reserves: %s / %s", r0.Text('f', 2), r1.Text('f', 2)))
+	}
+
+	if msg.quote.PriceImpact > 1.0 {
+		u.priceImpactWarn = fmt.Sprintf("⚠ High price impact: %.2f%%", msg.quote.PriceImpact)
+		h.LogWarn(u.priceImpactWarn)
+	} else if msg.quote.PriceImpact > 0.5 {
+		u.priceImpactWarn = fmt.Sprintf("⚠ Moderate price impact: %.2f%%", msg.quote.PriceImpact)
+	}
+
+	return u.checkQuoteHook(h)
+}
+
+func (u *Module) handleLiquidityPositions(h dapp.Host, msg liquidityPositionsMsg) tea.Cmd {
+	u.liquidityLoading = false
+	if msg.err != nil {
+		u.liquidityErr = msg.err.Error()
+		h.LogError("Liquidity positions error: " + msg.err.Error())
+		for _, d := range msg.diagnostics {
+			h.LogInfo("  " + d)
+		}
+		return nil
+	}
+	u.liquidityPositions = msg.positions
+	h.LogInfo(fmt.Sprintf("Uniswap PositionManagers: balanceOf=%d NFT(s) (V3 + V4)", msg.nftCount))
+	for _, d := range msg.diagnostics {
+		h.LogInfo("  " + d)
+	}
+	if len(msg.positions) == 0 {
+		h.LogInfo("No active V3 or V4 liquidity positions found")
+	} else {
+		h.LogInfo(fmt.Sprintf("%d position(s) with active liquidity", len(msg.positions)))
+	}
+	return loadPositionPnLs(h.RPCURL(), h.Store(), msg.positions)
+}
+
+func fetchLiquidityPositions(rpcURL string, ownerAddr common.Address) tea.Cmd {
+	return func() tea.Msg {
+		positions, nftCount, diags, err := helpers.GetLiquidityPositions(rpcURL, ownerAddr)
+		return liquidityPositionsMsg{positions: positions, nftCount: nftCount, diagnostics: diags, err: err}
+	}
+}
+
+// fetchV2SwapQuote fetches a forward swap quote from Uniswap V2.
+func fetchV2SwapQuote(client *rpc.Client, pairAddr, tokenInAddr common.Address, amountIn *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return quoteMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		quote, err := helpers.GetSwapQuote(client.Client, pairAddr, tokenInAddr, amountIn)
+		return quoteMsg{quote, err}
+	}
+}
+
+// fetchV2ReverseSwapQuote calculates the required input amount for a desired output amount.
+func fetchV2ReverseSwapQuote(client *rpc.Client, pairAddr, tokenInAddr common.Address, amountOut *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return quoteMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		quote, err := helpers.GetReverseSwapQuote(client.Client, pairAddr, tokenInAddr, amountOut)
+		return quoteMsg{quote, err}
+	}
+}
+
+// fetchV3SwapQuote fetches an exact-input quote from the Uniswap V3 QuoterV2.
+func fetchV3SwapQuote(client *rpc.Client, quoterV2, poolAddr, tokenIn, tokenOut common.Address, fee uint32, amountIn *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return quoteMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		quote, err := helpers.GetV3SwapQuote(client.Client, quoterV2, poolAddr, tokenIn, tokenOut, fee, amountIn)
+		return quoteMsg{quote, err}
+	}
+}
+
+// fetchV3ReverseSwapQuote fetches an exact-output quote from the Uniswap V3 QuoterV2.
+func fetchV3ReverseSwapQuote(client *rpc.Client, quoterV2, poolAddr, tokenIn, tokenOut common.Address, fee uint32, amountOut *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return quoteMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		quote, err := helpers.GetV3ReverseSwapQuote(client.Client, quoterV2, poolAddr, tokenIn, tokenOut, fee, amountOut)
+		return quoteMsg{quote, err}
+	}
+}
+
+// fetchV4SwapQuote fetches an exact-input quote from the Uniswap V4Quoter.
+func fetchV4SwapQuote(client *rpc.Client, addrs helpers.UniswapNetworkAddresses, key helpers.V4PoolKey, poolID common.Hash, tokenIn common.Address, amountIn *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return quoteMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		quote, err := helpers.GetV4SwapQuote(client.Client, addrs, key, poolID, tokenIn, amountIn)
+		return quoteMsg{quote, err}
+	}
+}
+
+// fetchV4ReverseSwapQuote fetches an exact-output quote from the Uniswap V4Quoter.
+func fetchV4ReverseSwapQuote(client *rpc.Client, addrs helpers.UniswapNetworkAddresses, key helpers.V4PoolKey, poolID common.Hash, tokenIn common.Address, amountOut *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return quoteMsg{nil, fmt.Errorf("no RPC client")}
+		}
+		quote, err := helpers.GetV4ReverseSwapQuote(client.Client, addrs, key, poolID, tokenIn, amountOut)
+		return quoteMsg{quote, err}
+	}
+}
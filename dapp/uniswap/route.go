@@ -0,0 +1,342 @@
+package uniswap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxRouteHops bounds the router's path search (see helpers.RouteGraph.Paths).
+const maxRouteHops = 3
+
+// routeQuoteMsg carries the ranked routes for a forward quote.
+type routeQuoteMsg struct {
+	routes []helpers.Route
+	err    error
+}
+
+// fetchRouteQuote quotes amountIn along every path in g.
+func fetchRouteQuote(client *rpc.Client, addrs helpers.UniswapNetworkAddresses, g helpers.RouteGraph, amountIn *big.Int) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil || client.Client == nil {
+			return routeQuoteMsg{err: fmt.Errorf("no RPC client")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		routes, err := helpers.QuoteRoutes(ctx, client.Client, addrs, g, amountIn, maxRouteHops)
+		return routeQuoteMsg{routes: routes, err: err}
+	}
+}
+
+// handleRouteQuote adopts the best-ranked route.
+func (u *Module) handleRouteQuote(h dapp.Host, msg routeQuoteMsg) tea.Cmd {
+	if msg.err != nil {
+		return u.handleQuote(h, quoteMsg{err: msg.err})
+	}
+	best := msg.routes[0]
+	u.bestRoute = &best
+	cmd := u.applyRoute(h, best)
+	if len(msg.routes) > 1 {
+		h.LogInfo(fmt.Sprintf("  Best of %d routes by output after gas", len(msg.routes)))
+	}
+	return cmd
+}
+
+// applyRoute makes r the route the swap is packaged along. A single-hop route
+// keeps its pool's own quote and packages through that version's router
+// exactly as a direct quote would; a multi-hop route is packaged by
+// buildRoute.
+func (u *Module) applyRoute(h dapp.Host, r helpers.Route) tea.Cmd {
+	best := r
+	u.route = &best
+
+	quote := &helpers.SwapQuote{
+		AmountIn:       best.AmountIn,
+		AmountOut:      best.AmountOut,
+		PriceImpact:    best.PriceImpact,
+		EffectivePrice: best.Hops[len(best.Hops)-1].Quote.EffectivePrice,
+	}
+	for _, hop := range best.Hops {
+		if hop.Pool.Version == helpers.PoolVersionV4 && hop.Pool.V4Key.Hooks != (common.Address{}) {
+			quote.HookAddr = hop.Pool.V4Key.Hooks
+		}
+	}
+	if len(best.Hops) == 1 {
+		hop := best.Hops[0]
+		quote = hop.Quote
+		u.lastVersion = hop.Pool.Version
+		u.lastFee = hop.Pool.V3Fee
+		u.lastV4Key = hop.Pool.V4Key
+		u.lastV4PoolID = hop.Pool.V4PoolID
+	}
+
+	cmd := u.handleQuote(h, quoteMsg{quote: quote})
+	h.LogInfo("  Route: " + u.routeLabel(h, best))
+	return cmd
+}
+
+// routeSymbols maps the addresses a route can pass through to display
+// symbols: the swap's own token list plus the router's hub tokens.
+func (u *Module) routeSymbols(h dapp.Host) map[common.Address]string {
+	addrs := helpers.UniswapAddressesForChain(h.ChainID())
+	syms := map[common.Address]string{
+		addrs.WETH: "WETH", addrs.USDC: "USDC", addrs.USDT: "USDT", addrs.DAI: "DAI", addrs.WBTC: "WBTC",
+	}
+	for _, t := range u.buildTokenList(h) {
+		if !t.IsETH {
+			syms[t.Address] = t.Symbol
+		}
+	}
+	return syms
+}
+
+// hopLabel names the pool a hop trades through, e.g. "V3 0.05%".
+func hopLabel(p helpers.ResolvedPool) string {
+	switch p.Version {
+	case helpers.PoolVersionV3:
+		return fmt.Sprintf("V3 %.2f%%", float64(p.V3Fee)/10000.0)
+	case helpers.PoolVersionV4:
+		return fmt.Sprintf("V4 %.2f%%", float64(p.V4Key.Fee)/10000.0)
+	}
+	return "V2"
+}
+
+// routeLabel renders a route as "ETH → USDC → TOKEN (V3 0.05% · V2)". Native
+// ETH is routed as WETH and shown as ETH at either end.
+func (u *Module) routeLabel(h dapp.Host, r helpers.Route) string {
+	from, to, _ := u.resolveSwapTokens(h)
+	syms := u.routeSymbols(h)
+	tokens := r.Tokens()
+	names := make([]string, len(tokens))
+	for i, t := range tokens {
+		names[i] = syms[t]
+		if names[i] == "" {
+			names[i] = helpers.ShortenAddr(t.Hex())
+		}
+	}
+	if from.IsETH {
+		names[0] = "ETH"
+	}
+	if to.IsETH {
+		names[len(names)-1] = "ETH"
+	}
+	pools := make([]string, len(r.Hops))
+	for i, hop := range r.Hops {
+		pools[i] = hopLabel(hop.Pool)
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(names, " → "), strings.Join(pools, " · "))
+}
+
+// routeSummary is the swap view's one-line route display, empty unless the
+// current quote came from the router.
+func (u *Module) routeSummary(h dapp.Host) string {
+	if u.route == nil || u.quote == nil {
+		return ""
+	}
+	return fmt.Sprintf("Route: %s · ~%dk gas", u.routeLabel(h, *u.route), u.route.GasUnits/1000)
+}
+
+// -------------------- UNIVERSAL ROUTER MULTI-HOP ENCODING --------------------
+//
+// Command bytes are Commands.sol (Uniswap/universal-router). Multi-hop routes
+// only chain V2 and V3 pools: V4's ExactInputParams layout has changed across
+// v4-periphery releases and is not pinned against the deployed router, so
+// the router (helpers.QuoteRoutes) swaps V4 pools only as a direct hop,
+// packaged by the single-pool V4 path in swap.go.
+
+const (
+	urCommandV3SwapExactIn = 0x00
+	urCommandV2SwapExactIn = 0x08
+	urCommandWrapETH       = 0x0b
+	urCommandUnwrapWETH    = 0x0c
+)
+
+// Universal Router sentinels: recipient MSG_SENDER / ADDRESS_THIS and an
+// amount of CONTRACT_BALANCE ("whatever the router holds").
+var (
+	urMsgSender       = common.BigToAddress(big.NewInt(1))
+	urAddressThis     = common.BigToAddress(big.NewInt(2))
+	urContractBalance = new(big.Int).Lsh(big.NewInt(1), 255)
+)
+
+const urRouteEncodingABI = `[
+  {
+    "inputs": [
+      {"name": "recipient", "type": "address"},
+      {"name": "amountIn", "type": "uint256"},
+      {"name": "amountOutMin", "type": "uint256"},
+      {"name": "path", "type": "address[]"},
+      {"name": "payerIsUser", "type": "bool"}
+    ],
+    "name": "v2SwapExactIn", "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "recipient", "type": "address"},
+      {"name": "amountIn", "type": "uint256"},
+      {"name": "amountOutMin", "type": "uint256"},
+      {"name": "path", "type": "bytes"},
+      {"name": "payerIsUser", "type": "bool"}
+    ],
+    "name": "v3SwapExactIn", "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "recipient", "type": "address"},
+      {"name": "amountMin", "type": "uint256"}
+    ],
+    "name": "wrapUnwrap", "outputs": [], "stateMutability": "pure", "type": "function"
+  }
+]`
+
+// routeSegments splits hops into runs that share a Uniswap version; each run
+// becomes one Universal Router command.
+func routeSegments(hops []helpers.RouteHop) [][]helpers.RouteHop {
+	var segs [][]helpers.RouteHop
+	for i, hop := range hops {
+		if i == 0 || hop.Pool.Version != hops[i-1].Pool.Version {
+			segs = append(segs, nil)
+		}
+		segs[len(segs)-1] = append(segs[len(segs)-1], hop)
+	}
+	return segs
+}
+
+// buildRouteCalldata encodes r as a Universal Router execute() call. Every
+// segment but the last pays out to the router itself (ADDRESS_THIS) and the
+// next spends the router's whole balance of it (CONTRACT_BALANCE), so only
+// the first segment pulls from the user, through Permit2. Native ETH is
+// wrapped up front or unwrapped at the end, as the route itself trades WETH.
+// amountOutMin is enforced on the final segment only. A non-nil permit (a
+// PERMIT2_PERMIT input) runs first.
+func buildRouteCalldata(r helpers.Route, fromETH, toETH bool, amountOutMin *big.Int, deadline int64, permit []byte) ([]byte, error) {
+	routeABI, err := abi.JSON(strings.NewReader(urRouteEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse route encoding ABI: %w", err)
+	}
+	swapABI, err := abi.JSON(strings.NewReader(v4SwapEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse V4 swap encoding ABI: %w", err)
+	}
+
+	var commands []byte
+	var inputs [][]byte
+	if permit != nil {
+		commands = append(commands, urCommandPermit2Permit)
+		inputs = append(inputs, permit)
+	}
+
+	amount := r.AmountIn
+	payerIsUser := true
+	if fromETH {
+		in, err := packStripSelector(&routeABI, "wrapUnwrap", urAddressThis, r.AmountIn)
+		if err != nil {
+			return nil, fmt.Errorf("encode WRAP_ETH: %w", err)
+		}
+		commands = append(commands, urCommandWrapETH)
+		inputs = append(inputs, in)
+		payerIsUser = false
+	}
+
+	segs := routeSegments(r.Hops)
+	for i, seg := range segs {
+		last := i == len(segs)-1
+		recipient, minOut := urAddressThis, big.NewInt(0)
+		if last {
+			minOut = amountOutMin
+			if !toETH {
+				recipient = urMsgSender
+			}
+		}
+
+		var cmd byte
+		var in []byte
+		switch seg[0].Pool.Version {
+		case helpers.PoolVersionV2:
+			path := []common.Address{seg[0].TokenIn}
+			for _, hop := range seg {
+				path = append(path, hop.TokenOut)
+			}
+			cmd = urCommandV2SwapExactIn
+			in, err = packStripSelector(&routeABI, "v2SwapExactIn", recipient, amount, minOut, path, payerIsUser)
+		case helpers.PoolVersionV3:
+			// V3 paths are packed token(20) | fee(3) | token(20) | ...
+			path := append([]byte{}, seg[0].TokenIn.Bytes()...)
+			for _, hop := range seg {
+				fee := hop.Pool.V3Fee
+				path = append(path, byte(fee>>16), byte(fee>>8), byte(fee))
+				path = append(path, hop.TokenOut.Bytes()...)
+			}
+			cmd = urCommandV3SwapExactIn
+			in, err = packStripSelector(&routeABI, "v3SwapExactIn", recipient, amount, minOut, path, payerIsUser)
+		default:
+			err = fmt.Errorf("V4 pools are not routed multi-hop")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("encode route segment %d: %w", i+1, err)
+		}
+		commands = append(commands, cmd)
+		inputs = append(inputs, in)
+
+		amount = urContractBalance
+		payerIsUser = false
+	}
+
+	if toETH {
+		in, err := packStripSelector(&routeABI, "wrapUnwrap", urMsgSender, amountOutMin)
+		if err != nil {
+			return nil, fmt.Errorf("encode UNWRAP_WETH: %w", err)
+		}
+		commands = append(commands, urCommandUnwrapWETH)
+		inputs = append(inputs, in)
+	}
+
+	calldata, err := swapABI.Pack("execute", commands, inputs, big.NewInt(deadline))
+	if err != nil {
+		return nil, fmt.Errorf("encode execute(): %w", err)
+	}
+	return calldata, nil
+}
+
+// buildRoute packages a multi-hop route through the Universal Router, with
+// the same Permit2 approval steps (or signed permit) as a direct V4 swap.
+func (s swapRequest) buildRoute(r helpers.Route) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		var p dapp.Packaged
+		router := s.addrs.UniversalRouter
+		amountIn, minOutHuman := s.amounts()
+		r.AmountIn = amountIn
+
+		calldata, err := buildRouteCalldata(r, s.fromToken.IsETH, s.toToken.IsETH, s.amountOutMin, s.deadline, s.permit)
+		if err != nil {
+			return p, err
+		}
+		txValue := big.NewInt(0)
+		if s.fromToken.IsETH {
+			txValue = amountIn
+		}
+		if err := s.permit2Approval(b, router, amountIn, &p); err != nil {
+			return p, err
+		}
+
+		pools := make([]string, len(r.Hops))
+		for i, hop := range r.Hops {
+			pools[i] = hopLabel(hop.Pool)
+		}
+		p.Summary = fmt.Sprintf("Uniswap %d-hop Swap (%s): %s %s → %s (min %s)\nUniversal Router: %s%s",
+			len(r.Hops), strings.Join(pools, " · "), s.amountIn, s.fromToken.Symbol, s.toToken.Symbol, minOutHuman, router.Hex(), s.permitNote())
+		gasLimit := r.GasUnits*3/2 + s.permitGas()
+		p.QR, p.TxJSON, err = b.Build(router, txValue, gasLimit, calldata, p.Summary)
+		return p, err
+	}
+}
@@ -0,0 +1,70 @@
+package uniswap
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"charm-wallet-tui/helpers"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func routeHop(version helpers.PoolVersion, in, out common.Address) helpers.RouteHop {
+	return helpers.RouteHop{Pool: helpers.ResolvedPool{Version: version, V3Fee: 500}, TokenIn: in, TokenOut: out}
+}
+
+func TestBuildRouteCalldataCommands(t *testing.T) {
+	a := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	b := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	c := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	swapABI, err := abi.JSON(strings.NewReader(v4SwapEncodingABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name           string
+		hops           []helpers.RouteHop
+		fromETH, toETH bool
+		want           []byte
+	}{
+		{"v3 then v2", []helpers.RouteHop{routeHop(helpers.PoolVersionV3, a, b), routeHop(helpers.PoolVersionV2, b, c)}, false, false,
+			[]byte{urCommandV3SwapExactIn, urCommandV2SwapExactIn}},
+		{"same version is one command", []helpers.RouteHop{routeHop(helpers.PoolVersionV3, a, b), routeHop(helpers.PoolVersionV3, b, c)}, false, false,
+			[]byte{urCommandV3SwapExactIn}},
+		{"eth in and out", []helpers.RouteHop{routeHop(helpers.PoolVersionV2, a, b), routeHop(helpers.PoolVersionV3, b, a)}, true, true,
+			[]byte{urCommandWrapETH, urCommandV2SwapExactIn, urCommandV3SwapExactIn, urCommandUnwrapWETH}},
+	} {
+		r := helpers.Route{Hops: tc.hops, AmountIn: big.NewInt(1000)}
+		data, err := buildRouteCalldata(r, tc.fromETH, tc.toETH, big.NewInt(900), 1700000000, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		args, err := swapABI.Methods["execute"].Inputs.Unpack(data[4:])
+		if err != nil {
+			t.Fatalf("%s: unpack: %v", tc.name, err)
+		}
+		if got := args[0].([]byte); !bytes.Equal(got, tc.want) {
+			t.Errorf("%s: commands = %x, want %x", tc.name, got, tc.want)
+		}
+		if n := len(args[1].([][]byte)); n != len(tc.want) {
+			t.Errorf("%s: %d inputs for %d commands", tc.name, n, len(tc.want))
+		}
+	}
+}
+
+func TestBuildRouteCalldataRejectsV4Hop(t *testing.T) {
+	a := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	b := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	c := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	r := helpers.Route{
+		Hops:     []helpers.RouteHop{routeHop(helpers.PoolVersionV3, a, b), routeHop(helpers.PoolVersionV4, b, c)},
+		AmountIn: big.NewInt(1000),
+	}
+	if _, err := buildRouteCalldata(r, false, false, big.NewInt(900), 1700000000, nil); err == nil {
+		t.Fatal("route with a V4 hop encoded; want an error")
+	}
+}
@@ -0,0 +1,207 @@
+package uniswap
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"charm-wallet-tui/dapp"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// swapSettings is the slippage tolerance and deadline a swap is packaged
+// with, and whether Universal Router swaps approve through a signed Permit2
+// permit (see permit.go). The module's persisted default is stored through
+// Host.SaveSettings; each visit starts from it and may override it for the
+// swaps that follow.
+type swapSettings struct {
+	SlippageBps     uint32 `json:"slippage_bps"`
+	DeadlineMinutes uint32 `json:"deadline_minutes"`
+	PermitSignature bool   `json:"permit_signature,omitempty"`
+}
+
+const (
+	defaultSlippageBps     = 50 // 0.5%
+	defaultDeadlineMinutes = 20
+
+	// Bounds and warning thresholds, in line with the Uniswap interface.
+	maxSlippageBps     = 5000 // 50%
+	lowSlippageBps     = 5    // below 0.05% most swaps revert on ordinary price movement
+	highSlippageBps    = 100  // above 1% the swap is an easy sandwich target
+	maxDeadlineMinutes = 4320 // 3 days
+)
+
+// loadSwapSettings returns the persisted default, or the built-in one.
+func loadSwapSettings(h dapp.Host) swapSettings {
+	s := swapSettings{SlippageBps: defaultSlippageBps, DeadlineMinutes: defaultDeadlineMinutes}
+	var saved swapSettings
+	if h.LoadSettings(Name, &saved) {
+		if saved.SlippageBps > 0 && saved.SlippageBps <= maxSlippageBps {
+			s.SlippageBps = saved.SlippageBps
+		}
+		if saved.DeadlineMinutes > 0 && saved.DeadlineMinutes <= maxDeadlineMinutes {
+			s.DeadlineMinutes = saved.DeadlineMinutes
+		}
+		s.PermitSignature = saved.PermitSignature
+	}
+	return s
+}
+
+// String formats s as "0.50% · 20 min", plus " · permit" in signature mode.
+func (s swapSettings) String() string {
+	str := fmt.Sprintf("%s · %d min", formatBps(s.SlippageBps), s.DeadlineMinutes)
+	if s.PermitSignature {
+		str += " · permit"
+	}
+	return str
+}
+
+// minOut applies the slippage tolerance to a quoted output.
+func (s swapSettings) minOut(amountOut *big.Int) *big.Int {
+	out := new(big.Int).Mul(amountOut, big.NewInt(int64(10000-s.SlippageBps)))
+	return out.Div(out, big.NewInt(10000))
+}
+
+// slippageWarning returns the warning to show for an unusual tolerance, or "".
+func slippageWarning(bps uint32) string {
+	switch {
+	case bps < lowSlippageBps:
+		return fmt.Sprintf("⚠ Very low slippage (%s) — the swap may revert if the price moves", formatBps(bps))
+	case bps > highSlippageBps:
+		return fmt.Sprintf("⚠ High slippage (%s) — the swap may be front-run and fill at up to %s less", formatBps(bps), formatBps(bps))
+	}
+	return ""
+}
+
+// formatBps renders basis points as a percentage, e.g. 50 → "0.50%".
+func formatBps(bps uint32) string {
+	return fmt.Sprintf("%.2f%%", float64(bps)/100)
+}
+
+// openSettings shows the settings form, seeded with the current values.
+func (u *Module) openSettings() {
+	u.showingSettings = true
+	u.settingsFocused = 0
+	u.settingsSlippage = strconv.FormatFloat(float64(u.settings.SlippageBps)/100, 'f', -1, 64)
+	u.settingsDeadline = strconv.FormatUint(uint64(u.settings.DeadlineMinutes), 10)
+	u.settingsPermit = u.settings.PermitSignature
+	u.settingsErr = ""
+}
+
+// parseSettingsForm validates the form's edit buffers.
+func (u *Module) parseSettingsForm() (swapSettings, error) {
+	pct, err := strconv.ParseFloat(u.settingsSlippage, 64)
+	if err != nil || pct <= 0 {
+		return swapSettings{}, fmt.Errorf("slippage must be a percentage above 0")
+	}
+	bps := uint32(math.Round(pct * 100))
+	if bps == 0 || bps > maxSlippageBps {
+		return swapSettings{}, fmt.Errorf("slippage must be between 0.01%% and %s", formatBps(maxSlippageBps))
+	}
+	mins, err := strconv.ParseUint(u.settingsDeadline, 10, 32)
+	if err != nil || mins == 0 || mins > maxDeadlineMinutes {
+		return swapSettings{}, fmt.Errorf("deadline must be between 1 and %d minutes", maxDeadlineMinutes)
+	}
+	return swapSettings{SlippageBps: bps, DeadlineMinutes: uint32(mins), PermitSignature: u.settingsPermit}, nil
+}
+
+// settingsForm is the view state for RenderSettings.
+func (u *Module) settingsForm(h dapp.Host) uniswapview.SwapSettingsForm {
+	f := uniswapview.SwapSettingsForm{
+		Slippage: u.settingsSlippage,
+		Deadline: u.settingsDeadline,
+		Permit:   u.settingsPermit,
+		Focused:  u.settingsFocused,
+		Err:      u.settingsErr,
+		Default:  loadSwapSettings(h).String(),
+	}
+	if s, err := u.parseSettingsForm(); err == nil {
+		f.Warn = slippageWarning(s.SlippageBps)
+	}
+	return f
+}
+
+// settingsFields is the number of rows in the settings form: slippage,
+// deadline and the approval-mode toggle.
+const settingsFields = 3
+
+// handleSettingsKey drives the settings form: ↑/↓ move between the fields,
+// digits edit them, Space toggles the approval mode, Enter applies to this
+// visit's swaps, d also saves them as the default.
+func (u *Module) handleSettingsKey(h dapp.Host, msg tea.KeyMsg) tea.Cmd {
+	buf := &u.settingsSlippage
+	if u.settingsFocused == 1 {
+		buf = &u.settingsDeadline
+	}
+	switch k := msg.String(); {
+	case k == "esc":
+		u.showingSettings = false
+	case k == "down" || k == "tab":
+		u.settingsFocused = (u.settingsFocused + 1) % settingsFields
+	case k == "up" || k == "shift+tab":
+		u.settingsFocused = (u.settingsFocused + settingsFields - 1) % settingsFields
+	case u.settingsFocused == 2:
+		if k == " " || k == "left" || k == "right" {
+			u.settingsPermit = !u.settingsPermit
+		} else if k == "enter" || k == "d" || k == "D" {
+			return u.applySettings(h, k)
+		}
+	case k == "backspace":
+		if len(*buf) > 0 {
+			*buf = (*buf)[:len(*buf)-1]
+		}
+		u.settingsErr = ""
+	case len(k) == 1 && strings.Contains("0123456789.", k):
+		if k == "." && (u.settingsFocused == 1 || strings.Contains(*buf, ".")) {
+			return nil
+		}
+		*buf += k
+		u.settingsErr = ""
+	case k == "enter" || k == "d" || k == "D":
+		return u.applySettings(h, k)
+	}
+	return nil
+}
+
+// applySettings validates the form and applies it; key d/D also saves it as
+// the default.
+func (u *Module) applySettings(h dapp.Host, k string) tea.Cmd {
+	s, err := u.parseSettingsForm()
+	if err != nil {
+		u.settingsErr = err.Error()
+		return nil
+	}
+	u.settings = s
+	u.showingSettings = false
+	if k == "enter" {
+		h.LogInfo("Swap settings: " + s.String())
+		return nil
+	}
+	if err := h.SaveSettings(Name, s); err != nil {
+		h.LogError("Saving swap settings failed: " + err.Error())
+		return nil
+	}
+	h.LogSuccess("Default swap settings saved: " + s.String())
+	return nil
+}
+
+// minReceivedLine summarizes what the packaged swap will guarantee, shown
+// under the quote before the user packages it.
+func (u *Module) minReceivedLine(h dapp.Host) string {
+	if u.quote == nil || u.quote.AmountOut == nil || u.estimating {
+		return ""
+	}
+	_, to, ok := u.resolveSwapTokens(h)
+	if !ok {
+		return ""
+	}
+	if wrap, ok := u.wrapPair(h); ok {
+		return fmt.Sprintf("%s via the WETH contract: receive exactly %s · no slippage", wrapVerb(wrap), formatAmount(u.quote.AmountOut, to))
+	}
+	return fmt.Sprintf("Min received: %s · Slippage %s · Deadline %d min",
+		formatAmount(u.settings.minOut(u.quote.AmountOut), to), formatBps(u.settings.SlippageBps), u.settings.DeadlineMinutes)
+}
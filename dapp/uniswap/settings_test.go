@@ -0,0 +1,77 @@
+package uniswap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestMinOut(t *testing.T) {
+	for _, tc := range []struct {
+		bps       uint32
+		out, want int64
+	}{
+		{0, 1000, 1000},
+		{50, 1000, 995},
+		{50, 999, 994}, // 994.005 rounds down, never above the tolerance
+		{100, 1, 0},
+		{maxSlippageBps, 1000, 500},
+		{10000, 1000, 0},
+	} {
+		got := swapSettings{SlippageBps: tc.bps}.minOut(big.NewInt(tc.out))
+		if got.Int64() != tc.want {
+			t.Errorf("minOut(%d) at %d bps = %s, want %d", tc.out, tc.bps, got, tc.want)
+		}
+	}
+
+	// Amounts past 64 bits stay exact.
+	out, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	want, _ := new(big.Int).SetString("995000000000000000000000", 10)
+	if got := (swapSettings{SlippageBps: 50}).minOut(out); got.Cmp(want) != 0 {
+		t.Errorf("minOut(1e24) at 50 bps = %s, want %s", got, want)
+	}
+}
+
+func TestSlippageWarningBounds(t *testing.T) {
+	for _, tc := range []struct {
+		bps  uint32
+		want string // substring, "" for no warning
+	}{
+		{0, "Very low"},
+		{lowSlippageBps - 1, "Very low"},
+		{lowSlippageBps, ""},
+		{defaultSlippageBps, ""},
+		{highSlippageBps, ""},
+		{highSlippageBps + 1, "High slippage"},
+		{maxSlippageBps, "High slippage"},
+	} {
+		got := slippageWarning(tc.bps)
+		if tc.want == "" && got != "" || tc.want != "" && !strings.Contains(got, tc.want) {
+			t.Errorf("slippageWarning(%d) = %q, want %q", tc.bps, got, tc.want)
+		}
+	}
+}
+
+func TestBuildV3DeadlineMulticall(t *testing.T) {
+	inner := []byte{0x04, 0xe4, 0x5a, 0xaf, 0x01, 0x02}
+	got, err := buildV3DeadlineMulticall(inner, 1700000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// multicall(uint256 deadline, bytes[] data) with one element.
+	want := "5ae401dc" + hex.EncodeToString(words(
+		"6553f100", // deadline
+		"40",       // offset of data
+		"1",        // data length
+		"20",       // offset of data[0]
+		"6",        // data[0] length
+	)) + "04e45aaf0102" + strings.Repeat("0", 64-12)
+	if hex.EncodeToString(got) != want {
+		t.Errorf("multicall calldata =\n%x\nwant\n%s", got, want)
+	}
+	if !bytes.Contains(got, inner) {
+		t.Error("multicall does not carry the inner call")
+	}
+}
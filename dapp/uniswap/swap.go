@@ -0,0 +1,587 @@
+package uniswap
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// executeSwap packages and displays the swap transaction QR — the action
+// Enter performs on the Swap button, shared with its mouse-click region.
+func (u *Module) executeSwap(h dapp.Host) tea.Cmd {
+	if u.fromAmount == "" || u.toAmount == "" {
+		h.LogError("Please enter an amount and get a quote first")
+		return nil
+	}
+	if u.quote == nil {
+		h.LogError("Please get a swap quote first")
+		return nil
+	}
+
+	tokens := u.buildTokenList(h)
+	if u.fromTokenIdx < 0 || u.fromTokenIdx >= len(tokens) {
+		return nil
+	}
+	if u.toTokenIdx < 0 || u.toTokenIdx >= len(tokens) {
+		return nil
+	}
+
+	fromToken := tokens[u.fromTokenIdx]
+	toToken := tokens[u.toTokenIdx]
+
+	if wrap, ok := wrapDirection(fromToken, toToken, helpers.UniswapAddressesForChain(h.ChainID()).WETH); ok {
+		h.LogInfo(fmt.Sprintf("Packaging %s: %s %s → %s %s", strings.ToLower(wrapVerb(wrap)),
+			u.fromAmount, fromToken.Symbol, u.fromAmount, toToken.Symbol))
+		return h.Package(swapRequest{
+			fromToken:    fromToken,
+			toToken:      toToken,
+			amountIn:     u.fromAmount,
+			amountOutMin: u.quote.AmountOut,
+			addrs:        helpers.UniswapAddressesForChain(h.ChainID()),
+		}.buildWrap(wrap, u.quote.AmountIn))
+	}
+
+	if !u.confirmHookRisk(h) {
+		return nil
+	}
+	for _, hook := range u.quoteHooks() {
+		if r := u.hookReports[hook]; r != nil {
+			h.LogInfo("Swap routes through " + r.Summary())
+		}
+	}
+
+	amountOutMin := u.settings.minOut(u.quote.AmountOut)
+	h.LogInfo(fmt.Sprintf("Packaging swap: %s %s → %s %s (min out: %s, %s slippage, %d min deadline)",
+		u.fromAmount, fromToken.Symbol, u.toAmount, toToken.Symbol, formatAmount(amountOutMin, toToken),
+		formatBps(u.settings.SlippageBps), u.settings.DeadlineMinutes))
+	if w := slippageWarning(u.settings.SlippageBps); w != "" {
+		h.LogWarn(w)
+	}
+
+	s := swapRequest{
+		client:       h.Client(),
+		from:         common.HexToAddress(h.ActiveAddress()),
+		fromToken:    fromToken,
+		toToken:      toToken,
+		amountIn:     u.fromAmount,
+		amountOutMin: amountOutMin,
+		deadline:     time.Now().Unix() + int64(u.settings.DeadlineMinutes)*60,
+		addrs:        helpers.UniswapAddressesForChain(h.ChainID()),
+	}
+	var build swapBuild
+	switch {
+	case u.route != nil && len(u.route.Hops) > 1:
+		r := *u.route
+		build = func(s swapRequest) func(dapp.TxBuilder) (dapp.Packaged, error) { return s.buildRoute(r) }
+	case u.quote.IsV4:
+		key := u.lastV4Key
+		build = func(s swapRequest) func(dapp.TxBuilder) (dapp.Packaged, error) { return s.buildV4(key) }
+	case u.quote.IsV3:
+		return h.Package(s.buildV3(u.lastFee))
+	default:
+		return h.Package(s.buildV2())
+	}
+
+	// Universal Router swaps spend through Permit2; in signature mode the
+	// router allowance comes from a signed permit instead of a transaction.
+	if u.settings.PermitSignature && !fromToken.IsETH {
+		if h.Wallet().Safe == nil {
+			return checkPermit(s, build)
+		}
+		h.LogWarn("Permit2 signatures are not supported for Safe wallets — using approval transactions")
+	}
+	return h.Package(build(s))
+}
+
+// swapRequest is everything a swap packaging pass needs, captured on the UI
+// goroutine so the build functions can run off it.
+type swapRequest struct {
+	client       *rpc.Client
+	from         common.Address
+	fromToken    uniswapview.TokenOption
+	toToken      uniswapview.TokenOption
+	amountIn     string // human-readable, in fromToken units
+	amountOutMin *big.Int
+	deadline     int64 // unix seconds
+	addrs        helpers.UniswapNetworkAddresses
+	permit       []byte // signed PERMIT2_PERMIT input (see permit.go), or nil
+}
+
+// amounts converts amountIn to base units and formats amountOutMin for the
+// summary line.
+func (s swapRequest) amounts() (amountIn *big.Int, minOutHuman string) {
+	amountFloat := new(big.Float)
+	amountFloat.SetString(s.amountIn)
+	multiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(s.fromToken.Decimals)), nil))
+	amountIn, _ = new(big.Float).Mul(amountFloat, multiplier).Int(nil)
+
+	outDecimals := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(s.toToken.Decimals)), nil)
+	minOutHuman = new(big.Float).Quo(new(big.Float).SetInt(s.amountOutMin), new(big.Float).SetInt(outDecimals)).Text('f', 6)
+	return amountIn, minOutHuman
+}
+
+// needsApprove reports whether spender's ERC-20 allowance from the wallet is
+// short of amount. Any RPC error counts as "allowance unknown", so an
+// approve step is included to be safe.
+func (s swapRequest) needsApprove(spender common.Address, amount *big.Int) bool {
+	if s.fromToken.IsETH || s.client == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	allowance, err := rpc.ERC20Allowance(ctx, s.client, s.fromToken.Address, s.from, spender)
+	return err != nil || allowance.Cmp(amount) < 0
+}
+
+// buildV2 packages a Uniswap V2 swap. When the ERC-20 allowance is
+// insufficient an approve tx is packaged ahead of the swap at consecutive
+// free nonces so both can be pre-signed in sequence.
+func (s swapRequest) buildV2() func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		router := s.addrs.Router
+		amountIn, minOutHuman := s.amounts()
+		calldata := buildSwapCalldata(s.fromToken, s.toToken, s.from, amountIn, s.amountOutMin, s.addrs.WETH, s.deadline)
+		txValue := big.NewInt(0)
+		if s.fromToken.IsETH {
+			txValue = amountIn
+		}
+
+		var p dapp.Packaged
+		var err error
+		if s.needsApprove(router, amountIn) {
+			approveSummary := fmt.Sprintf("Approve %s %s for Uniswap V2 Router", s.amountIn, s.fromToken.Symbol)
+			p.ApproveQR, p.ApproveJSON, err = b.Build(s.fromToken.Address, big.NewInt(0), 60000, helpers.BuildApproveCalldata(router, amountIn), approveSummary)
+			if err != nil {
+				return p, err
+			}
+		}
+
+		p.Summary = fmt.Sprintf("Uniswap V2 Swap: %s %s → %s (min %s)\nRouter: %s",
+			s.amountIn, s.fromToken.Symbol, s.toToken.Symbol, minOutHuman, router.Hex())
+		p.QR, p.TxJSON, err = b.Build(router, txValue, 200000, calldata, p.Summary)
+		return p, err
+	}
+}
+
+// buildV3 packages a Uniswap V3 exactInputSingle swap. fee is the pool fee
+// tier in hundredths of a bip (e.g. 10000 = 1%, 3000 = 0.3%). Approval is
+// handled as in buildV2.
+func (s swapRequest) buildV3(fee uint32) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		router := s.addrs.SwapRouterV3
+		amountIn, minOutHuman := s.amounts()
+		calldata, err := buildV3DeadlineMulticall(buildV3SwapCalldata(s.fromToken, s.toToken, s.from, amountIn, s.amountOutMin, s.addrs.WETH, fee), s.deadline)
+		if err != nil {
+			return dapp.Packaged{}, err
+		}
+		txValue := big.NewInt(0)
+		if s.fromToken.IsETH {
+			txValue = amountIn
+		}
+
+		var p dapp.Packaged
+		if s.needsApprove(router, amountIn) {
+			approveSummary := fmt.Sprintf("Approve %s %s for Uniswap V3 SwapRouter", s.amountIn, s.fromToken.Symbol)
+			p.ApproveQR, p.ApproveJSON, err = b.Build(s.fromToken.Address, big.NewInt(0), 60000, helpers.BuildApproveCalldata(router, amountIn), approveSummary)
+			if err != nil {
+				return p, err
+			}
+		}
+
+		feeLabel := fmt.Sprintf("%.2f%%", float64(fee)/10000.0)
+		p.Summary = fmt.Sprintf("Uniswap V3 Swap (%s): %s %s → %s (min %s)\nRouter: %s",
+			feeLabel, s.amountIn, s.fromToken.Symbol, s.toToken.Symbol, minOutHuman, router.Hex())
+		p.QR, p.TxJSON, err = b.Build(router, txValue, 200000, calldata, p.Summary)
+		return p, err
+	}
+}
+
+// buildV4 packages a Uniswap V4 single-hop swap via the Universal Router.
+// Unlike V2/V3 (direct router approval), V4/Universal Router spends through
+// Permit2, so a non-ETH input token needs up to two approval steps ahead of
+// the swap: ERC20.approve(Permit2) if Permit2's own allowance from the token
+// is insufficient, then Permit2.approve(router, token, amount, expiry) if
+// Permit2's recorded allowance for the router is insufficient/expired.
+// Both are optional/independent — a wallet that already approved Permit2
+// unlimited, and has a live Permit2->router approval, needs neither.
+func (s swapRequest) buildV4(key helpers.V4PoolKey) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		var p dapp.Packaged
+		router := s.addrs.UniversalRouter
+		amountIn, minOutHuman := s.amounts()
+
+		calldata, err := buildV4SwapCalldata(s.fromToken, s.toToken, key, amountIn, s.amountOutMin, s.deadline, s.permit)
+		if err != nil {
+			return p, err
+		}
+		txValue := big.NewInt(0)
+		if s.fromToken.IsETH {
+			txValue = amountIn
+		}
+
+		if err := s.permit2Approval(b, router, amountIn, &p); err != nil {
+			return p, err
+		}
+
+		feeLabel := fmt.Sprintf("%.2f%%", float64(key.Fee)/10000.0)
+		hookNote := ""
+		if key.Hooks != (common.Address{}) {
+			hookNote = fmt.Sprintf("\nHook: %s (pool may enforce KYC/allowlist checks)", key.Hooks.Hex())
+		}
+		p.Summary = fmt.Sprintf("Uniswap V4 Swap (%s): %s %s → %s (min %s)\nUniversal Router: %s%s%s",
+			feeLabel, s.amountIn, s.fromToken.Symbol, s.toToken.Symbol, minOutHuman, router.Hex(), hookNote, s.permitNote())
+		p.QR, p.TxJSON, err = b.Build(router, txValue, 300000+s.permitGas(), calldata, p.Summary)
+		return p, err
+	}
+}
+
+// permit2Approval packages into p whichever of the two Universal Router
+// approval steps described on buildV4 the wallet still needs. Any RPC error
+// counts as "allowance unknown" and includes the step to be safe, matching
+// V2/V3's needsApprove. A swap carrying a signed permit never needs the
+// Permit2.approve step.
+func (s swapRequest) permit2Approval(b dapp.TxBuilder, router common.Address, amountIn *big.Int, p *dapp.Packaged) error {
+	needsERC20Approve := s.needsApprove(rpc.Permit2Address, amountIn)
+	needsPermit2Approve := false
+	if !s.fromToken.IsETH && s.client != nil && s.permit == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		permit2Amount, permit2Expiration, err := rpc.Permit2Allowance(ctx, s.client, s.fromToken.Address, s.from, router)
+		cancel()
+		nowUnix := uint64(time.Now().Unix())
+		if err != nil || permit2Amount.Cmp(amountIn) < 0 || permit2Expiration <= nowUnix {
+			needsPermit2Approve = true
+		}
+	}
+
+	if needsERC20Approve && needsPermit2Approve {
+		// This app's QR flow (ApproveQR/ApproveJSON) only carries one
+		// pre-swap approval step; V4/Universal Router's Permit2 model can
+		// need two independent ones (ERC20→Permit2, then Permit2→router).
+		// Silently packaging only one would produce a swap tx that fails
+		// on execution with no indication why — fail loud instead and
+		// tell the user how to get there in two passes.
+		return fmt.Errorf(
+			"%s needs two approvals before this Universal Router swap can run: first ERC20→Permit2, then Permit2→Universal Router. "+
+				"This app only packages one approve step per swap attempt — submit this swap once to sign the first approval, "+
+				"wait for it to confirm, then reopen the swap to get the second approval + swap "+
+				"(or turn on Permit2 signatures in swap settings to replace the second approval with a signature)",
+			s.fromToken.Symbol)
+	}
+
+	var err error
+	if needsERC20Approve {
+		approveSummary := fmt.Sprintf("Approve %s %s for Permit2", s.amountIn, s.fromToken.Symbol)
+		p.ApproveQR, p.ApproveJSON, err = b.Build(s.fromToken.Address, big.NewInt(0), 60000, helpers.BuildApproveCalldata(rpc.Permit2Address, amountIn), approveSummary)
+	} else if needsPermit2Approve {
+		permit2ExpiryU48 := uint64(time.Now().Unix() + 60*60*24*30) // 30 days
+		permit2Calldata, perr := buildPermit2ApproveCalldata(s.fromToken.Address, router, amountIn, permit2ExpiryU48)
+		if perr != nil {
+			return perr
+		}
+		approveSummary := fmt.Sprintf("Permit2 approve %s %s for Universal Router", s.amountIn, s.fromToken.Symbol)
+		p.ApproveQR, p.ApproveJSON, err = b.Build(rpc.Permit2Address, big.NewInt(0), 80000, permit2Calldata, approveSummary)
+	}
+	return err
+}
+
+// permitNote is the summary line for a swap carrying a signed permit.
+func (s swapRequest) permitNote() string {
+	if s.permit == nil {
+		return ""
+	}
+	return "\nApproval: signed Permit2 permit (PERMIT2_PERMIT)"
+}
+
+// permitGas is the extra gas a signed permit adds to the swap.
+func (s swapRequest) permitGas() uint64 {
+	if s.permit == nil {
+		return 0
+	}
+	return permitGas
+}
+
+// buildSwapCalldata builds ABI-encoded calldata for the appropriate Uniswap V2 swap function.
+func buildSwapCalldata(fromToken, toToken uniswapview.TokenOption, to common.Address, amountIn, amountOutMin *big.Int, weth common.Address, deadline int64) []byte {
+	dl := big.NewInt(deadline)
+	if fromToken.IsETH {
+		// swapExactETHForTokens — selector 0x7ff36ab5
+		var d []byte
+		d = append(d, 0x7f, 0xf3, 0x6a, 0xb5)
+		d = append(d, helpers.ABIEncodeUint256(amountOutMin)...)
+		d = append(d, helpers.ABIEncodeUint256(big.NewInt(128))...)
+		d = append(d, helpers.ABIEncodeAddress(to)...)
+		d = append(d, helpers.ABIEncodeUint256(dl)...)
+		d = append(d, helpers.ABIEncodeUint256(big.NewInt(2))...)
+		d = append(d, helpers.ABIEncodeAddress(weth)...)
+		d = append(d, helpers.ABIEncodeAddress(toToken.Address)...)
+		return d
+	}
+	if toToken.IsETH {
+		// swapExactTokensForETH — selector 0x18cbafe5
+		var d []byte
+		d = append(d, 0x18, 0xcb, 0xaf, 0xe5)
+		d = append(d, helpers.ABIEncodeUint256(amountIn)...)
+		d = append(d, helpers.ABIEncodeUint256(amountOutMin)...)
+		d = append(d, helpers.ABIEncodeUint256(big.NewInt(160))...)
+		d = append(d, helpers.ABIEncodeAddress(to)...)
+		d = append(d, helpers.ABIEncodeUint256(dl)...)
+		d = append(d, helpers.ABIEncodeUint256(big.NewInt(2))...)
+		d = append(d, helpers.ABIEncodeAddress(fromToken.Address)...)
+		d = append(d, helpers.ABIEncodeAddress(weth)...)
+		return d
+	}
+	// swapExactTokensForTokens — selector 0x38ed1739
+	var d []byte
+	d = append(d, 0x38, 0xed, 0x17, 0x39)
+	d = append(d, helpers.ABIEncodeUint256(amountIn)...)
+	d = append(d, helpers.ABIEncodeUint256(amountOutMin)...)
+	d = append(d, helpers.ABIEncodeUint256(big.NewInt(160))...)
+	d = append(d, helpers.ABIEncodeAddress(to)...)
+	d = append(d, helpers.ABIEncodeUint256(dl)...)
+	d = append(d, helpers.ABIEncodeUint256(big.NewInt(2))...)
+	d = append(d, helpers.ABIEncodeAddress(fromToken.Address)...)
+	d = append(d, helpers.ABIEncodeAddress(toToken.Address)...)
+	return d
+}
+
+// buildV3SwapCalldata ABI-encodes exactInputSingle for SwapRouter02.
+// Selector 0x04e45aaf: exactInputSingle((address,address,uint24,address,uint256,uint256,uint160))
+// SwapRouter02 omits the deadline field present in SwapRouter v1.
+func buildV3SwapCalldata(fromToken, toToken uniswapview.TokenOption, to common.Address, amountIn, amountOutMin *big.Int, weth common.Address, fee uint32) []byte {
+	tokenIn := fromToken.Address
+	tokenOut := toToken.Address
+	if fromToken.IsETH {
+		tokenIn = weth
+	}
+	if toToken.IsETH {
+		tokenOut = weth
+	}
+
+	var d []byte
+	d = append(d, 0x04, 0xe4, 0x5a, 0xaf) // exactInputSingle selector
+	d = append(d, helpers.ABIEncodeAddress(tokenIn)...)
+	d = append(d, helpers.ABIEncodeAddress(tokenOut)...)
+	d = append(d, helpers.ABIEncodeUint256(new(big.Int).SetUint64(uint64(fee)))...)
+	d = append(d, helpers.ABIEncodeAddress(to)...)
+	d = append(d, helpers.ABIEncodeUint256(amountIn)...)
+	d = append(d, helpers.ABIEncodeUint256(amountOutMin)...)
+	d = append(d, helpers.ABIEncodeUint256(big.NewInt(0))...) // sqrtPriceLimitX96 = 0 (no limit)
+	return d
+}
+
+// buildV3DeadlineMulticall wraps SwapRouter02 calldata in
+// multicall(uint256 deadline, bytes[] data) (selector 0x5ae401dc), the
+// router's only way to enforce a deadline since its exactInputSingle has none.
+func buildV3DeadlineMulticall(calldata []byte, deadline int64) ([]byte, error) {
+	const multicallABI = `[{"inputs":[{"name":"deadline","type":"uint256"},{"name":"data","type":"bytes[]"}],"name":"multicall","outputs":[{"name":"","type":"bytes[]"}],"stateMutability":"payable","type":"function"}]`
+	parsedABI, err := abi.JSON(strings.NewReader(multicallABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsedABI.Pack("multicall", big.NewInt(deadline), [][]byte{calldata})
+}
+
+// -------------------- UNISWAP V4 SWAP PACKAGING --------------------
+//
+// [VERIFY] Everything in this section encodes calldata against Uniswap's
+// Universal Router V4_SWAP path. The action-ID bytes (0x06/0x0c/0x0f) and the
+// V4_SWAP command byte (0x10) are cross-checked against Uniswap's official
+// GitHub source (Commands.sol, Actions.sol) as of this writing. The
+// ExactInputSingleParams struct shape (including a minHopPriceX36 field) is
+// taken from the same source's current main branch — since Solidity ABI
+// encoding is offset-sensitive, if the specific deployed bytecode at
+// helpers.UniswapNetworkAddresses.UniversalRouter predates that field, this
+// encoding will not match what the contract expects and the resulting
+// unsigned tx will fail on execution (it is never auto-broadcast — the user
+// reviews/signs it — so this is a functional-correctness risk, not a
+// fund-loss one, but it must be confirmed against the deployed contract's
+// verified source on Etherscan before being trusted as correct).
+
+const v4SwapEncodingABI = `[
+  {
+    "inputs": [{
+      "name": "params", "type": "tuple",
+      "components": [
+        {"name": "poolKey", "type": "tuple", "components": [
+          {"name": "currency0", "type": "address"},
+          {"name": "currency1", "type": "address"},
+          {"name": "fee", "type": "uint24"},
+          {"name": "tickSpacing", "type": "int24"},
+          {"name": "hooks", "type": "address"}
+        ]},
+        {"name": "zeroForOne", "type": "bool"},
+        {"name": "amountIn", "type": "uint128"},
+        {"name": "amountOutMinimum", "type": "uint128"},
+        {"name": "minHopPriceX36", "type": "uint256"},
+        {"name": "hookData", "type": "bytes"}
+      ]
+    }],
+    "name": "encodeExactInputSingle",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "currency", "type": "address"},
+      {"name": "amount", "type": "uint256"}
+    ],
+    "name": "encodeCurrencyAmount",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "actions", "type": "bytes"},
+      {"name": "params", "type": "bytes[]"}
+    ],
+    "name": "encodeActionsParams",
+    "outputs": [], "stateMutability": "pure", "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "commands", "type": "bytes"},
+      {"name": "inputs", "type": "bytes[]"},
+      {"name": "deadline", "type": "uint256"}
+    ],
+    "name": "execute",
+    "outputs": [], "stateMutability": "nonpayable", "type": "function"
+  }
+]`
+
+// v4ActionSwapExactInSingle, v4ActionSettleAll, v4ActionTakeAll are Actions.sol
+// action IDs (Uniswap/v4-periphery). v4CommandV4Swap is Commands.sol's V4_SWAP
+// command ID (Uniswap/universal-router).
+const (
+	v4ActionSwapExactInSingle = 0x06
+	v4ActionSettleAll         = 0x0c
+	v4ActionTakeAll           = 0x0f
+	v4CommandV4Swap           = 0x10
+)
+
+// v4CurrencyForToken returns the V4 Currency (an address, zero for native ETH)
+// for a swap token, matching the zero-address-means-native convention already
+// used throughout this codebase's V4 code (helpers/uniswap_v4_listener.go).
+func v4CurrencyForToken(t uniswapview.TokenOption) common.Address {
+	if t.IsETH {
+		return common.Address{}
+	}
+	return t.Address
+}
+
+// packStripSelector packs args against method in parsedABI and strips the
+// leading 4-byte method selector, yielding the raw abi.encode(...) bytes a
+// Solidity contract's abi.encode(struct) / abi.encode(a, b) would produce —
+// there is no method selector for those (only real external function calls
+// have one), so v4SwapEncodingABI's helper "functions" exist purely to reuse
+// go-ethereum's struct/dynamic-array ABI packing instead of hand-deriving
+// offsets, matching the same approach already used for V4Quoter's calldata
+// (helpers/uniswap_v4_quote.go) where a nested dynamic bytes field made
+// manual packing error-prone.
+func packStripSelector(parsedABI *abi.ABI, method string, args ...interface{}) ([]byte, error) {
+	packed, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return packed[4:], nil
+}
+
+// buildV4SwapCalldata ABI-encodes a Universal Router execute() call carrying
+// a single V4_SWAP command: SWAP_EXACT_IN_SINGLE, then SETTLE_ALL (pay the
+// input) and TAKE_ALL (receive the output) — the standard V4 single-hop
+// exact-input swap pattern. A non-nil permit (a PERMIT2_PERMIT input) runs
+// as a command ahead of the swap.
+func buildV4SwapCalldata(fromToken, toToken uniswapview.TokenOption, key helpers.V4PoolKey, amountIn, amountOutMin *big.Int, deadline int64, permit []byte) ([]byte, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(v4SwapEncodingABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse V4 swap encoding ABI: %w", err)
+	}
+
+	tokenIn := v4CurrencyForToken(fromToken)
+	tokenOut := v4CurrencyForToken(toToken)
+	zeroForOne := tokenIn == key.Currency0
+
+	swapParams, err := packStripSelector(&parsedABI, "encodeExactInputSingle", struct {
+		PoolKey struct {
+			Currency0   common.Address
+			Currency1   common.Address
+			Fee         *big.Int
+			TickSpacing *big.Int
+			Hooks       common.Address
+		}
+		ZeroForOne       bool
+		AmountIn         *big.Int
+		AmountOutMinimum *big.Int
+		MinHopPriceX36   *big.Int
+		HookData         []byte
+	}{
+		PoolKey: struct {
+			Currency0   common.Address
+			Currency1   common.Address
+			Fee         *big.Int
+			TickSpacing *big.Int
+			Hooks       common.Address
+		}{
+			Currency0:   key.Currency0,
+			Currency1:   key.Currency1,
+			Fee:         new(big.Int).SetUint64(uint64(key.Fee)),
+			TickSpacing: big.NewInt(int64(key.TickSpacing)),
+			Hooks:       key.Hooks,
+		},
+		ZeroForOne:       zeroForOne,
+		AmountIn:         amountIn,
+		AmountOutMinimum: amountOutMin,
+		MinHopPriceX36:   big.NewInt(0), // no additional per-hop price floor beyond amountOutMinimum
+		HookData:         []byte{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode ExactInputSingleParams: %w", err)
+	}
+
+	settleParams, err := packStripSelector(&parsedABI, "encodeCurrencyAmount", tokenIn, amountIn)
+	if err != nil {
+		return nil, fmt.Errorf("encode SETTLE_ALL params: %w", err)
+	}
+	takeParams, err := packStripSelector(&parsedABI, "encodeCurrencyAmount", tokenOut, amountOutMin)
+	if err != nil {
+		return nil, fmt.Errorf("encode TAKE_ALL params: %w", err)
+	}
+
+	actions := []byte{v4ActionSwapExactInSingle, v4ActionSettleAll, v4ActionTakeAll}
+	v4SwapInput, err := packStripSelector(&parsedABI, "encodeActionsParams", actions, [][]byte{swapParams, settleParams, takeParams})
+	if err != nil {
+		return nil, fmt.Errorf("encode actions/params: %w", err)
+	}
+
+	commands := []byte{v4CommandV4Swap}
+	inputs := [][]byte{v4SwapInput}
+	if permit != nil {
+		commands = append([]byte{urCommandPermit2Permit}, commands...)
+		inputs = append([][]byte{permit}, inputs...)
+	}
+	calldata, err := parsedABI.Pack("execute", commands, inputs, big.NewInt(deadline))
+	if err != nil {
+		return nil, fmt.Errorf("encode execute(): %w", err)
+	}
+	return calldata, nil
+}
+
+// buildPermit2ApproveCalldata ABI-encodes Permit2's
+// approve(address token, address spender, uint160 amount, uint48 expiration).
+// [VERIFY] against Permit2's verified Etherscan source alongside rpc.Permit2Address.
+func buildPermit2ApproveCalldata(token, spender common.Address, amount *big.Int, expiration uint64) ([]byte, error) {
+	const permit2ApproveABI = `[{"inputs":[{"name":"token","type":"address"},{"name":"spender","type":"address"},{"name":"amount","type":"uint160"},{"name":"expiration","type":"uint48"}],"name":"approve","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	parsedABI, err := abi.JSON(strings.NewReader(permit2ApproveABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsedABI.Pack("approve", token, spender, amount, expiration)
+}
@@ -0,0 +1,514 @@
+// Package uniswap is the Uniswap dApp module: token swaps routed through
+// whichever V2/V3/V4 pool the pair resolves to, and the wallet's V3/V4
+// liquidity positions, with V4 positions managed in place.
+package uniswap
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Name is the module's registry name and dApp Browser card title.
+const Name = "Uniswap v4"
+
+type keyMap struct {
+	Navigate, Max, Pool, Settings, Liquidity, PoolMonitor, Explorer key.Binding
+}
+
+var keys = keyMap{
+	Navigate:    key.NewBinding(key.WithKeys("up", "down", "k", "j"), key.WithHelp("↑/↓", "navigate")),
+	Max:         key.NewBinding(key.WithKeys("m", "M"), key.WithHelp("m", "max")),
+	Pool:        key.NewBinding(key.WithKeys("[", "]"), key.WithHelp("[/]", "pick pool")),
+	Settings:    key.NewBinding(key.WithKeys("s", "S"), key.WithHelp("s", "slippage/deadline")),
+	Liquidity:   key.NewBinding(key.WithKeys("q", "Q"), key.WithHelp("q", "liquidity positions")),
+	PoolMonitor: key.NewBinding(key.WithKeys("p", "P"), key.WithHelp("p", "pool event monitor")),
+	Explorer:    key.NewBinding(key.WithKeys("b", "B"), key.WithHelp("b", "block explorer")),
+}
+
+// Module holds the swap form, token selector, quote and liquidity state.
+type Module struct {
+	fromTokenIdx    int    // index in available tokens
+	toTokenIdx      int    // index in available tokens
+	fromAmount      string // user input amount
+	toAmount        string // estimated output amount
+	focusedField    int    // 0=from, 1=to, 2=swap button
+	showingSelector bool   // true when showing token selector popup
+	selectorFor     int    // 0=from, 1=to
+	selectorIdx     int    // selected index in token selector
+	estimating      bool   // true when estimating swap output
+	quote           *helpers.SwapQuote
+	quoteError      string
+	priceImpactWarn string
+	hookWarn        string              // shown when the resolved V4 pool has a non-zero hook address
+	route           *helpers.Route      // route the swap will be packaged along; nil for reverse quotes
+	bestRoute       *helpers.Route      // router's pick for the current forward quote
+	poolQuotes      []helpers.PoolQuote // every direct pool quoted for comparison
+	poolChoice      int                 // selected comparison row: 0 = bestRoute, i = poolQuotes[i-1]
+	editingFrom     bool                // true if user has been editing From field
+	editingTo       bool                // true if user has been editing To field
+
+	// V4 hook inspection (see hooks.go)
+	hookReports map[common.Address]*helpers.HookReport // inspected hooks; nil value while inspecting
+	hookFailed  map[common.Address]error               // hooks whose inspection failed, gated like HIGH risk
+	hookAcked   map[common.Address]bool                // risky hooks the user pressed Swap through once
+
+	// Track last quote parameters to avoid unnecessary fetches
+	lastQuoteFromAmount   string
+	lastQuoteToAmount     string
+	lastQuoteFromTokenIdx int
+	lastQuoteToTokenIdx   int
+	lastVersion           helpers.PoolVersion // which Uniswap version the last resolved pair used
+	lastFee               uint32              // valid when lastVersion == PoolVersionV3
+	lastV4Key             helpers.V4PoolKey   // valid when lastVersion == PoolVersionV4
+	lastV4PoolID          common.Hash         // valid when lastVersion == PoolVersionV4
+
+	// On-chain pair/pool resolution, cached per chain
+	pairCache      map[string]pairCacheEntry
+	pairCacheChain string
+	resolvingPair  bool // true while route graph discovery is in flight
+
+	// Slippage tolerance and deadline (see settings.go)
+	settings         swapSettings
+	showingSettings  bool
+	settingsFocused  int // 0=slippage, 1=deadline, 2=approval mode
+	settingsSlippage string
+	settingsDeadline string
+	settingsPermit   bool
+	settingsErr      string
+
+	// Liquidity positions view
+	showingLiquidity    bool
+	liquidityPositions  []helpers.LiquidityPosition
+	liquidityLoading    bool
+	liquidityFocusedIdx int
+	liquidityErr        string
+	lpForm              *lpForm // open V4 increase/decrease/new-position form (see liquidity_form.go)
+
+	// Pool analytics page, opened from the V4 events panel (see pool.go)
+	pool *poolAnalytics
+}
+
+// New returns the Uniswap module.
+func New() *Module {
+	return &Module{pairCache: make(map[string]pairCacheEntry)}
+}
+
+func (u *Module) Card() config.DApp {
+	return config.DApp{
+		Name:    Name,
+		Address: "0x000000009B1D0aF20D8C6d0A44e162d11F9b8f00",
+		Icon:    "🦄",
+		// Network intentionally left blank: Uniswap v4 is supported on
+		// both mainnet and Sepolia (see helpers.UniswapAddressesForChain),
+		// so its card shows whichever network is actively connected
+		// instead of a fixed label (see views/dapps.Render).
+		Description: "Uniswap is a leading decentralized cryptocurrency exchange (DEX) on the Ethereum blockchain that utilizes an automated market maker (AMM) system to allow users to swap tokens directly from their wallets without intermediaries. It enables anyone to provide liquidity to pools, earning fees in a non-custodial manner.",
+	}
+}
+
+func (u *Module) Init(h dapp.Host) tea.Cmd {
+	u.fromTokenIdx = 0
+	u.toTokenIdx = 1
+	u.fromAmount = ""
+	u.toAmount = ""
+	u.focusedField = 0
+	u.showingSelector = false
+	u.selectorFor = 0
+	u.selectorIdx = 0
+	u.estimating = false
+	u.quote = nil
+	u.quoteError = ""
+	u.priceImpactWarn = ""
+	u.lastQuoteFromAmount = ""
+	u.lastQuoteFromTokenIdx = -1
+	u.lastQuoteToTokenIdx = -1
+	u.showingLiquidity = false
+	u.lpForm = nil
+	u.pool = nil
+	u.showingSettings = false
+	u.settings = loadSwapSettings(h)
+	return nil
+}
+
+// CapturesInput reports whether a liquidity form is open, so its keys
+// (f, Space, digits) reach the form rather than the wallet's global keys.
+func (u *Module) CapturesInput() bool {
+	return u.showingLiquidity && u.lpForm != nil
+}
+
+func (u *Module) Commands() []dapp.Command {
+	return []dapp.Command{
+		{Name: "Liquidity positions", Run: u.openLiquidity},
+		{Name: "Pool event monitor", Run: func(h dapp.Host) tea.Cmd {
+			if h.PoolMonitorActive() {
+				return nil
+			}
+			return h.TogglePoolMonitor()
+		}},
+	}
+}
+
+func (u *Module) KeyBindings(h dapp.Host) []key.Binding {
+	return []key.Binding{
+		keys.Navigate,
+		keys.Max,
+		keys.Pool,
+		activeBinding(keys.Settings, u.showingSettings, styles.CAccent2),
+		activeBinding(keys.PoolMonitor, h.PoolMonitorActive(), styles.CWarn),
+		activeBinding(keys.Liquidity, u.showingLiquidity, styles.CAccent2),
+		keys.Explorer,
+	}
+}
+
+// activeBinding colors b's help text while the mode it toggles is on.
+func activeBinding(b key.Binding, active bool, c lipgloss.Color) key.Binding {
+	if !active {
+		return b
+	}
+	help := b.Help()
+	b.SetHelp(help.Key, lipgloss.NewStyle().Foreground(c).Render(help.Desc))
+	return b
+}
+
+func (u *Module) Update(h dapp.Host, msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case quoteMsg:
+		return u.handleQuote(h, msg)
+	case routeQuoteMsg:
+		return u.handleRouteQuote(h, msg)
+	case poolQuotesMsg:
+		return u.handlePoolQuotes(h, msg)
+	case pairLookupResultMsg:
+		return u.handlePairLookupResult(h, msg)
+	case liquidityPositionsMsg:
+		return u.handleLiquidityPositions(h, msg)
+	case positionPnLMsg:
+		return u.handlePositionPnL(h, msg)
+	case permitCheckMsg:
+		return u.handlePermitCheck(h, msg)
+	case lpPoolStateMsg:
+		return u.handleLPPoolState(h, msg)
+	case dapp.PoolSelectedMsg:
+		return u.openPoolAnalytics(h, msg.PoolID)
+	case poolAnalyticsMsg:
+		return u.handlePoolAnalytics(h, msg)
+	case hookReportMsg:
+		return u.handleHookReport(h, msg)
+	case tea.KeyMsg:
+		return u.handleKey(h, msg)
+	}
+	return nil
+}
+
+func (u *Module) View(h dapp.Host, width, height int) dapp.View {
+	tokens := u.buildTokenList(h)
+
+	if u.showingSelector {
+		// The selector centers itself over the full window width rather
+		// than the panel's content area.
+		c := uniswapview.RenderTokenSelector(width+2, height, tokens, u.selectorIdx, u.selectorFor == 0)
+		return dapp.View{Content: c, Bare: true}
+	}
+	if u.showingSettings {
+		return dapp.View{Content: uniswapview.RenderSettings(width, height, u.settingsForm(h))}
+	}
+	if u.showingLiquidity && u.lpForm != nil {
+		return dapp.View{Content: uniswapview.RenderLiquidityForm(width, height, u.lpFormView(h))}
+	}
+	if u.showingLiquidity {
+		c := uniswapview.RenderLiquidity(width, height, u.liquidityPositions, u.liquidityLoading,
+			u.liquidityFocusedIdx, u.liquidityErr, h.Spinner())
+		return dapp.View{Content: c}
+	}
+	if u.pool != nil {
+		return dapp.View{Content: uniswapview.RenderPoolAnalytics(width, height, u.poolAnalyticsView(width), h.Spinner())}
+	}
+	if h.PoolMonitorActive() {
+		return dapp.View{Content: h.PoolEventsPanel(), Bare: true}
+	}
+
+	c, geo := uniswapview.Render(width, height, tokens,
+		u.fromTokenIdx, u.toTokenIdx,
+		u.fromAmount, u.toAmount,
+		u.focusedField, u.estimating, u.resolvingPair,
+		u.priceImpactWarn, u.hookWarn, u.routeSummary(h),
+		u.minReceivedLine(h), slippageWarning(u.settings.SlippageBps), u.poolRows(h))
+	return dapp.View{
+		Content: c,
+		Regions: append([]dapp.Region{
+			{ID: "uniswap.fromBox", Input: true,
+				X1: geo.FromX1, Y1: geo.FromY, X2: geo.FromX2, Y2: geo.FromY + geo.FromH,
+				OnClick: func(h dapp.Host) tea.Cmd { return u.focusField(h, 0) }},
+			{ID: "uniswap.toBox", Input: true,
+				X1: geo.ToX1, Y1: geo.ToY, X2: geo.ToX2, Y2: geo.ToY + geo.ToH,
+				OnClick: func(h dapp.Host) tea.Cmd { return u.focusField(h, 1) }},
+			{ID: "uniswap.swapButton",
+				X1: geo.SwapX1, Y1: geo.SwapY, X2: geo.SwapX2, Y2: geo.SwapY + geo.SwapH,
+				OnClick: func(h dapp.Host) tea.Cmd {
+					u.focusedField = 2
+					return u.executeSwap(h)
+				}},
+		}, u.poolRegions(geo)...),
+	}
+}
+
+// openLiquidity switches to the liquidity positions view and starts loading
+// the active wallet's positions.
+func (u *Module) openLiquidity(h dapp.Host) tea.Cmd {
+	u.showingLiquidity = true
+	u.liquidityFocusedIdx = 0
+	if h.ActiveAddress() != "" && h.RPCURL() != "" {
+		u.liquidityLoading = true
+		u.liquidityPositions = nil
+		u.liquidityErr = ""
+		h.LogInfo(fmt.Sprintf("Fetching V3 and V4 liquidity positions for %s…", helpers.ShortenAddr(h.ActiveAddress())))
+		return fetchLiquidityPositions(h.RPCURL(), common.HexToAddress(h.ActiveAddress()))
+	}
+	return nil
+}
+
+func (u *Module) handleKey(h dapp.Host, msg tea.KeyMsg) tea.Cmd {
+	if u.pool != nil {
+		return u.handlePoolKey(h, msg)
+	}
+	// Handle liquidity positions view
+	if u.showingLiquidity && u.lpForm != nil {
+		return u.handleLPFormKey(h, msg)
+	}
+	if u.showingLiquidity {
+		switch msg.String() {
+		case "esc", "q", "Q":
+			u.showingLiquidity = false
+		case "+", "a", "A":
+			return u.openLPForm(h, lpIncrease)
+		case "-", "r", "R":
+			return u.openLPForm(h, lpDecrease)
+		case "c", "C":
+			return u.collectFees(h)
+		case "x", "X":
+			return u.burnPosition(h)
+		case "n", "N":
+			return u.openMintForm(h)
+		case "up", "k":
+			if u.liquidityFocusedIdx > 0 {
+				u.liquidityFocusedIdx--
+			}
+		case "down", "j":
+			if u.liquidityFocusedIdx < len(u.liquidityPositions)-1 {
+				u.liquidityFocusedIdx++
+			}
+		}
+		return nil
+	}
+
+	if u.showingSettings {
+		return u.handleSettingsKey(h, msg)
+	}
+
+	// Handle token selector popup
+	if u.showingSelector {
+		switch msg.String() {
+		case "esc":
+			u.showingSelector = false
+		case "up", "k":
+			if u.selectorIdx > 0 {
+				u.selectorIdx--
+			}
+		case "down", "j":
+			if u.selectorIdx < len(u.buildTokenList(h))-1 {
+				u.selectorIdx++
+			}
+		case "enter":
+			if u.selectorFor == 0 {
+				u.fromTokenIdx = u.selectorIdx
+			} else {
+				u.toTokenIdx = u.selectorIdx
+			}
+			u.showingSelector = false
+			// Trigger quote fetch since token selection changed
+			return u.maybeRequestQuote(h)
+		}
+		return nil
+	}
+
+	// Main swap interface controls
+	switch {
+	case msg.String() == "esc":
+		if h.PoolMonitorActive() {
+			h.TogglePoolMonitor()
+		}
+		return h.Back()
+
+	case msg.String() == "up" || msg.String() == "k":
+		if u.focusedField > 0 {
+			return u.focusField(h, u.focusedField-1)
+		}
+		return nil
+
+	case msg.String() == "down" || msg.String() == "j":
+		if u.focusedField < 2 {
+			return u.focusField(h, u.focusedField+1)
+		}
+		return nil
+
+	case msg.String() == "tab":
+		return u.focusField(h, (u.focusedField+1)%3)
+
+	case msg.String() == "shift+tab":
+		return u.focusField(h, (u.focusedField-1+3)%3)
+
+	case msg.String() == "enter":
+		switch u.focusedField {
+		case 0:
+			// If user has been editing, move to next field instead of opening selector
+			if u.editingFrom {
+				return u.focusField(h, 1)
+			}
+			var cmd tea.Cmd
+			if u.fromAmount != "" {
+				cmd = u.maybeRequestQuote(h)
+			}
+			u.showingSelector = true
+			u.selectorFor = 0
+			u.selectorIdx = u.fromTokenIdx
+			return cmd
+		case 1:
+			if u.editingTo {
+				return u.focusField(h, 2)
+			}
+			u.showingSelector = true
+			u.selectorFor = 1
+			u.selectorIdx = u.toTokenIdx
+			return nil
+		case 2:
+			return u.executeSwap(h)
+		}
+		return nil
+
+	case len(msg.String()) == 1 && strings.Contains("0123456789.", msg.String()):
+		switch u.focusedField {
+		case 0:
+			if v, e, ok := appendNumericInput(u.fromAmount, u.editingFrom, msg.String()); ok {
+				u.fromAmount, u.editingFrom = v, e
+			}
+		case 1:
+			if v, e, ok := appendNumericInput(u.toAmount, u.editingTo, msg.String()); ok {
+				u.toAmount, u.editingTo = v, e
+			}
+		}
+		return nil
+
+	case msg.String() == "backspace":
+		// Quote is fetched when the user leaves the field
+		if u.focusedField == 0 && len(u.fromAmount) > 0 {
+			u.fromAmount = u.fromAmount[:len(u.fromAmount)-1]
+			u.editingFrom = true
+		} else if u.focusedField == 1 && len(u.toAmount) > 0 {
+			u.toAmount = u.toAmount[:len(u.toAmount)-1]
+			u.editingTo = true
+		}
+		return nil
+
+	case key.Matches(msg, keys.Max):
+		// Max: populate From field with full balance
+		if u.focusedField != 0 {
+			return nil
+		}
+		tokens := u.buildTokenList(h)
+		if u.fromTokenIdx < 0 || u.fromTokenIdx >= len(tokens) {
+			return nil
+		}
+		fromToken := tokens[u.fromTokenIdx]
+		if fromToken.Balance == nil || fromToken.Balance.Sign() <= 0 {
+			return nil
+		}
+		divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromToken.Decimals)), nil))
+		balanceFloat := new(big.Float).Quo(new(big.Float).SetInt(fromToken.Balance), divisor)
+		u.fromAmount = balanceFloat.Text('f', 6)
+		u.editingFrom = true
+		h.LogInfo(fmt.Sprintf("Max balance: %s %s", u.fromAmount, fromToken.Symbol))
+		return u.maybeRequestQuote(h)
+
+	case key.Matches(msg, keys.Pool):
+		if len(u.poolQuotes) == 0 {
+			return nil
+		}
+		step := 1
+		if msg.String() == "[" {
+			step = -1
+		}
+		// Step past rows whose quote failed so cycling never stalls on them.
+		if idx, ok := u.nextSelectablePool(step); ok {
+			return u.selectPool(h, idx)
+		}
+		return nil
+
+	case key.Matches(msg, keys.Settings):
+		u.openSettings()
+		return nil
+
+	case key.Matches(msg, keys.Liquidity):
+		return u.openLiquidity(h)
+
+	case key.Matches(msg, keys.PoolMonitor):
+		return h.TogglePoolMonitor()
+
+	case key.Matches(msg, keys.Explorer):
+		return h.OpenBlockExplorer("")
+	}
+	return nil
+}
+
+// focusField moves focus to target (0=From, 1=To, 2=Swap). Leaving a field
+// that still has an uncommitted typed amount fires that direction's quote
+// refresh first. Shared by the keyboard handlers and the From/To box
+// mouse-click regions so clicking behaves identically to tabbing there.
+func (u *Module) focusField(h dapp.Host, target int) tea.Cmd {
+	if u.focusedField == 0 && target != 0 && u.editingFrom && u.fromAmount != "" && u.fromAmount != "0" {
+		u.focusedField = target
+		if target == 1 {
+			u.editingTo = false
+		}
+		return u.maybeRequestQuote(h)
+	}
+	if u.focusedField == 1 && target != 1 && u.editingTo && u.toAmount != "" && u.toAmount != "0" {
+		u.focusedField = target
+		if target == 0 {
+			u.editingFrom = false
+		}
+		u.editingTo = false
+		return u.maybeRequestReverseQuote(h)
+	}
+	u.focusedField = target
+	if target == 0 {
+		u.editingFrom = false
+	} else if target == 1 {
+		u.editingTo = false
+	}
+	return nil
+}
+
+// appendNumericInput appends a digit or '.' to value.
+// On the first keystroke after a non-zero prior value, the field is cleared.
+// Returns (newValue, newEditing, ok) — ok is false when a duplicate '.' is rejected.
+func appendNumericInput(value string, editing bool, char string) (string, bool, bool) {
+	if !editing && value != "" && value != "0" {
+		value = ""
+	}
+	if char == "." && strings.Contains(value, ".") {
+		return value, editing, false
+	}
+	return value + char, true, true
+}
@@ -0,0 +1,106 @@
+package uniswap
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ETH⇄WETH needs no pool: WETH's deposit() mints WETH 1:1 for the ETH sent
+// and withdraw(amount) burns it back, so the pair is quoted and packaged
+// against the WETH contract directly.
+
+var (
+	wethDepositSelector  = []byte{0xd0, 0xe3, 0x0d, 0xb0} // deposit()
+	wethWithdrawSelector = []byte{0x2e, 0x1a, 0x7d, 0x4d} // withdraw(uint256)
+)
+
+// wrapGas covers WETH deposit() and withdraw(), including the ETH transfer
+// withdraw makes back to the caller.
+const wrapGas = 60000
+
+// wrapDirection reports whether from→to is ETH→WETH (wrap) or WETH→ETH
+// (unwrap); ok is false for any other pair, or when the chain has no WETH.
+func wrapDirection(from, to uniswapview.TokenOption, weth common.Address) (wrap, ok bool) {
+	if weth == (common.Address{}) {
+		return false, false
+	}
+	switch {
+	case from.IsETH && !to.IsETH && to.Address == weth:
+		return true, true
+	case to.IsETH && !from.IsETH && from.Address == weth:
+		return false, true
+	}
+	return false, false
+}
+
+// wrapPair reports whether the selected pair is a wrap or unwrap.
+func (u *Module) wrapPair(h dapp.Host) (wrap, ok bool) {
+	from, to, valid := u.resolveSwapTokens(h)
+	if !valid {
+		return false, false
+	}
+	return wrapDirection(from, to, helpers.UniswapAddressesForChain(h.ChainID()).WETH)
+}
+
+// wrapVerb is "Wrap" or "Unwrap" for log and summary lines.
+func wrapVerb(wrap bool) string {
+	if wrap {
+		return "Wrap"
+	}
+	return "Unwrap"
+}
+
+// quoteWrap sets a 1:1 quote for amount (base units; ETH and WETH share 18
+// decimals) and fills in whichever side the user did not type.
+func (u *Module) quoteWrap(h dapp.Host, wrap bool, amount *big.Int, reverse bool) tea.Cmd {
+	from, to, _ := u.resolveSwapTokens(h)
+	u.clearQuoteState()
+	u.estimating = false
+	u.resolvingPair = false
+	u.quote = &helpers.SwapQuote{AmountIn: amount, AmountOut: new(big.Int).Set(amount), EffectivePrice: 1}
+	u.lastQuoteFromTokenIdx = u.fromTokenIdx
+	u.lastQuoteToTokenIdx = u.toTokenIdx
+	if reverse {
+		u.fromAmount = u.toAmount
+		u.lastQuoteToAmount = u.toAmount
+		u.editingFrom = false
+	} else {
+		u.toAmount = u.fromAmount
+		u.lastQuoteFromAmount = u.fromAmount
+	}
+	h.LogInfo(fmt.Sprintf("📊 %s: %s %s → %s %s (1:1 via the WETH contract, no slippage)",
+		wrapVerb(wrap), u.fromAmount, from.Symbol, u.toAmount, to.Symbol))
+	return nil
+}
+
+// buildWrap packages WETH deposit() with amount (base units, the quote's
+// exact AmountIn) as value, or withdraw(amount). Neither needs an approval:
+// withdraw burns the caller's own balance.
+func (s swapRequest) buildWrap(wrap bool, amount *big.Int) func(dapp.TxBuilder) (dapp.Packaged, error) {
+	return func(b dapp.TxBuilder) (dapp.Packaged, error) {
+		var p dapp.Packaged
+		weth := s.addrs.WETH
+		if amount == nil || amount.Sign() <= 0 {
+			return p, fmt.Errorf("nothing to %s", strings.ToLower(wrapVerb(wrap)))
+		}
+
+		value, calldata := amount, wethDepositSelector
+		if !wrap {
+			value = big.NewInt(0)
+			calldata = append(append([]byte{}, wethWithdrawSelector...), common.LeftPadBytes(amount.Bytes(), 32)...)
+		}
+		p.Summary = fmt.Sprintf("%s %s %s → %s %s (1:1)\nWETH: %s",
+			wrapVerb(wrap), s.amountIn, s.fromToken.Symbol, s.amountIn, s.toToken.Symbol, weth.Hex())
+		var err error
+		p.QR, p.TxJSON, err = b.Build(weth, value, wrapGas, calldata, p.Summary)
+		return p, err
+	}
+}
@@ -0,0 +1,115 @@
+package uniswap
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	uniswapview "charm-wallet-tui/views/uniswap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// recordingBuilder is a dapp.TxBuilder that keeps every tx it is asked to
+// build.
+type recordingBuilder struct {
+	txs []builtTx
+}
+
+type builtTx struct {
+	to    common.Address
+	value *big.Int
+	gas   uint64
+	data  []byte
+}
+
+func (r *recordingBuilder) Build(to common.Address, value *big.Int, gasLimit uint64, data []byte, summary string) (string, string, error) {
+	r.txs = append(r.txs, builtTx{to, value, gasLimit, data})
+	return "ur", "{}", nil
+}
+
+var _ dapp.TxBuilder = (*recordingBuilder)(nil)
+
+func TestWrapDirection(t *testing.T) {
+	weth := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	usdc := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	eth := uniswapview.TokenOption{Symbol: "ETH", IsETH: true}
+	wethTok := uniswapview.TokenOption{Symbol: "WETH", Address: weth}
+	usdcTok := uniswapview.TokenOption{Symbol: "USDC", Address: usdc}
+
+	for _, tc := range []struct {
+		name     string
+		from, to uniswapview.TokenOption
+		weth     common.Address
+		wrap, ok bool
+	}{
+		{"wrap", eth, wethTok, weth, true, true},
+		{"unwrap", wethTok, eth, weth, false, true},
+		{"eth to token", eth, usdcTok, weth, false, false},
+		{"weth to token", wethTok, usdcTok, weth, false, false},
+		{"eth to eth", eth, eth, weth, false, false},
+		{"no weth on chain", eth, wethTok, common.Address{}, false, false},
+	} {
+		wrap, ok := wrapDirection(tc.from, tc.to, tc.weth)
+		if wrap != tc.wrap || ok != tc.ok {
+			t.Errorf("%s: wrapDirection = %v, %v; want %v, %v", tc.name, wrap, ok, tc.wrap, tc.ok)
+		}
+	}
+}
+
+func TestWETHSelectors(t *testing.T) {
+	for sig, sel := range map[string][]byte{
+		"deposit()":         wethDepositSelector,
+		"withdraw(uint256)": wethWithdrawSelector,
+	} {
+		if want := crypto.Keccak256([]byte(sig))[:4]; !bytes.Equal(sel, want) {
+			t.Errorf("%s selector = %x, want %x", sig, sel, want)
+		}
+	}
+}
+
+func TestBuildWrapCalldata(t *testing.T) {
+	weth := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	// 1.1 ETH is not exact in binary floating point; the tx must carry the
+	// quote's base units unchanged.
+	amount, err := helpers.ParseTokenAmount("1.1", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := swapRequest{
+		fromToken: uniswapview.TokenOption{Symbol: "ETH", IsETH: true, Decimals: 18},
+		toToken:   uniswapview.TokenOption{Symbol: "WETH", Address: weth, Decimals: 18},
+		amountIn:  "1.1",
+		addrs:     helpers.UniswapNetworkAddresses{WETH: weth},
+	}
+
+	var wrapB recordingBuilder
+	if _, err := req.buildWrap(true, amount)(&wrapB); err != nil {
+		t.Fatal(err)
+	}
+	if len(wrapB.txs) != 1 {
+		t.Fatalf("wrap built %d txs, want 1", len(wrapB.txs))
+	}
+	tx := wrapB.txs[0]
+	if tx.to != weth || tx.value.Cmp(amount) != 0 || !bytes.Equal(tx.data, wethDepositSelector) || tx.gas != wrapGas {
+		t.Errorf("wrap tx = to %s value %s data %x gas %d; want deposit() to WETH with value %s", tx.to.Hex(), tx.value, tx.data, tx.gas, amount)
+	}
+
+	req.fromToken, req.toToken = req.toToken, req.fromToken
+	var unwrapB recordingBuilder
+	if _, err := req.buildWrap(false, amount)(&unwrapB); err != nil {
+		t.Fatal(err)
+	}
+	tx = unwrapB.txs[0]
+	want := append(append([]byte{}, wethWithdrawSelector...), common.LeftPadBytes(amount.Bytes(), 32)...)
+	if tx.to != weth || tx.value.Sign() != 0 || !bytes.Equal(tx.data, want) {
+		t.Errorf("unwrap tx = to %s value %s data %x; want withdraw(%s) with no value", tx.to.Hex(), tx.value, tx.data, amount)
+	}
+
+	if _, err := req.buildWrap(false, big.NewInt(0))(&unwrapB); err == nil {
+		t.Error("unwrap of zero packaged; want an error")
+	}
+}
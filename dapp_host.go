@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/dapps"
+	"charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// moduleHost is the model as seen by dApp modules (dapp.Host).
+type moduleHost struct{ m *model }
+
+func (m *model) host() dapp.Host { return moduleHost{m} }
+
+func (h moduleHost) Client() *rpc.Client               { return h.m.ethClient }
+func (h moduleHost) RPCURL() string                    { return h.m.rpcURL }
+func (h moduleHost) ChainID() *big.Int                 { return h.m.chainID() }
+func (h moduleHost) ActiveAddress() string             { return h.m.activeAddress }
+func (h moduleHost) Store() *store.Store               { return h.m.eventStore }
+func (h moduleHost) Wallet() rpc.WalletDetails         { return h.m.details }
+func (h moduleHost) WatchedTokens() []rpc.WatchedToken { return h.m.tokenWatchForActiveChain() }
+func (h moduleHost) Spinner() string                   { return h.m.spin.View() }
+func (h moduleHost) ScreenSize() (int, int)            { return h.m.w, h.m.h }
+func (h moduleHost) LogInfo(msg string)                { h.m.logInfo(msg) }
+func (h moduleHost) LogWarn(msg string)                { h.m.logWarn(msg) }
+func (h moduleHost) LogError(msg string)               { h.m.logError(msg) }
+func (h moduleHost) LogSuccess(msg string)             { h.m.logSuccess(msg) }
+func (h moduleHost) Back() tea.Cmd                     { return h.m.navigateTo(config.PageDappBrowser) }
+func (h moduleHost) PoolMonitorActive() bool           { return h.m.poolEventMonitorActive }
+func (h moduleHost) TogglePoolMonitor() tea.Cmd        { return h.m.togglePoolMonitor() }
+
+func (h moduleHost) OpenBlockExplorer(query string) tea.Cmd {
+	return h.m.openBlockExplorer(query)
+}
+
+func (h moduleHost) LoadSettings(module string, v any) bool {
+	raw, ok := h.m.dappSettings[module]
+	return ok && json.Unmarshal(raw, v) == nil
+}
+
+func (h moduleHost) SaveSettings(module string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if h.m.dappSettings == nil {
+		h.m.dappSettings = make(map[string]json.RawMessage)
+	}
+	h.m.dappSettings[module] = raw
+	if err := h.m.saveConfig(); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
+
+func (h moduleHost) Package(build func(dapp.TxBuilder) (dapp.Packaged, error)) tea.Cmd {
+	m := h.m
+	m.activeDialog = dialogTxResult
+	m.txResultPackaging = true
+	m.txResultHex = ""
+	m.txResultError = ""
+	m.txResultFormat = "EIP-4527"
+	st, rpcURL, from := m.eventStore, m.rpcURL, common.HexToAddress(m.activeAddress)
+	safe := m.safeSourceFor(from)
+	return func() tea.Msg {
+		batch, err := newNonceBatch(st, rpcURL, from, safe)
+		if err != nil {
+			return packageTransactionMsg{err: err}
+		}
+		p, err := build(batchBuilder{batch})
+		if err != nil {
+			return batch.done(packageTransactionMsg{err: err})
+		}
+		return batch.done(packageTransactionMsg{txDisplay: p.Summary, txJSON: p.TxJSON, qrData: p.QR, format: "EIP-4527", approveQRData: p.ApproveQR, approveJSON: p.ApproveJSON})
+	}
+}
+
+func (h moduleHost) SignTypedData(summary string, typed apitypes.TypedData, onSigned func(dapp.Host, [65]byte) tea.Cmd) tea.Cmd {
+	return h.m.showTypedSignRequest(summary, typed, onSigned)
+}
+
+// PoolEventsPanel renders the V4 events panel and marks it visible for this
+// frame, which routes scroll keys, the wheel and panel-focus clicks to it.
+func (h moduleHost) PoolEventsPanel() string {
+	m := h.m
+	m.v4PanelShown = true
+	// PanelStyle adds 4 vertical lines; RenderV4Events overhead is 4 — so m.h/2-4 yields half-height.
+	v4View := uniswap.RenderV4Events(m.w-2, helpers.Max(1, m.h/2-4), m.v4EventsViewport, m.poolFilter.String())
+	borderColor := styles.CBorder
+	if m.focusedPanel == focusedPanelV4Events {
+		borderColor = styles.CAccent
+	}
+	return styles.PanelStyle.BorderForeground(borderColor).Width(m.contentW).Render(v4View)
+}
+
+// batchBuilder exposes a nonceBatch to modules as a dapp.TxBuilder.
+type batchBuilder struct{ *nonceBatch }
+
+func (b batchBuilder) Build(to common.Address, value *big.Int, gasLimit uint64, data []byte, summary string) (string, string, error) {
+	return b.build(to, value, gasLimit, data, summary)
+}
+
+// openModule makes mod the active module and opens its page.
+func (m *model) openModule(mod dapp.Module) tea.Cmd {
+	m.activeModule = mod
+	return m.navigateTo(config.PageDapp)
+}
+
+// moduleCapturesInput reports whether the active module is running a text
+// input (see dapp.InputCapturer).
+func (m model) moduleCapturesInput() bool {
+	if m.activePage != config.PageDapp || m.activeModule == nil {
+		return false
+	}
+	c, ok := m.activeModule.(dapp.InputCapturer)
+	return ok && c.CapturesInput()
+}
+
+// broadcastToModules hands a message the model did not handle to every
+// registered module, so results of a module's own commands reach it even
+// after the user has left its page.
+func (m *model) broadcastToModules(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, mod := range dapp.Modules() {
+		cmds = append(cmds, mod.Update(m.host(), msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// moduleView renders the active module once per frame; the overlay pass and
+// the page pass share the result.
+func (m *model) moduleView() dapp.View {
+	if m.moduleFrame == nil {
+		v := m.activeModule.View(m.host(), m.w-2, m.h-8)
+		m.moduleFrame = &v
+	}
+	return *m.moduleFrame
+}
+
+// renderModuleOverlay returns the active module's popup, if it has one open.
+func (m *model) renderModuleOverlay() string {
+	if m.activePage != config.PageDapp || m.activeModule == nil {
+		return ""
+	}
+	v := m.moduleView()
+	if v.Overlay == "" {
+		return ""
+	}
+	m.registerModuleRegions(v.OverlayRegions, 0, 0)
+	return v.Overlay
+}
+
+func (m *model) renderModulePage(headerPanel string) (pageContent, nav string) {
+	v := m.moduleView()
+	nav = dapps.ModuleNav(m.w-2, m.activeModule.KeyBindings(m.host()), m.txIndexerActive)
+
+	if m.v4PanelShown {
+		m.v4Scroll.PanelTop = lipgloss.Height(headerPanel) + 4
+		m.v4Scroll.TrackCol = m.v4EventsViewport.Width + 3
+	}
+	if v.Bare {
+		m.registerModuleRegions(v.Regions, 0, lipgloss.Height(headerPanel))
+		return v.Content, nav
+	}
+	// Content top-left within pageContent = PanelStyle's border(1)+padding(1,2).
+	m.registerModuleRegions(v.Regions, 3, lipgloss.Height(headerPanel)+2)
+	return styles.PanelStyle.Width(m.contentW).Render(v.Content), nav
+}
+
+// registerModuleRegions registers a module's regions offset by (dx, dy).
+func (m *model) registerModuleRegions(regions []dapp.Region, dx, dy int) {
+	for _, r := range regions {
+		kind := uiRegionButton
+		if r.Input {
+			kind = uiRegionInput
+		}
+		onClick := r.OnClick
+		m.registerRegion(r.ID, kind, dx+r.X1, dy+r.Y1, dx+r.X2, dy+r.Y2, func(m *model) (tea.Model, tea.Cmd) {
+			if onClick == nil {
+				return m, nil
+			}
+			return m, onClick(m.host())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/dapp"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeModule is a dapp.Module whose only state is whether it captures input.
+type fakeModule struct{ capturing bool }
+
+func (f fakeModule) Card() config.DApp                   { return config.DApp{Name: "Fake"} }
+func (f fakeModule) Init(dapp.Host) tea.Cmd              { return nil }
+func (f fakeModule) Update(dapp.Host, tea.Msg) tea.Cmd   { return nil }
+func (f fakeModule) View(dapp.Host, int, int) dapp.View  { return dapp.View{} }
+func (f fakeModule) KeyBindings(dapp.Host) []key.Binding { return nil }
+func (f fakeModule) Commands() []dapp.Command            { return nil }
+func (f fakeModule) CapturesInput() bool                 { return f.capturing }
+
+func TestModuleHostSettings(t *testing.T) {
+	type settings struct{ SlippageBps uint32 }
+	path := filepath.Join(t.TempDir(), "config.json")
+	m := &model{configPath: path}
+	h := m.host()
+
+	if err := h.SaveSettings("fake", settings{SlippageBps: 75}); err != nil {
+		t.Fatalf("SaveSettings: %v", err)
+	}
+	var got settings
+	if !h.LoadSettings("fake", &got) || got.SlippageBps != 75 {
+		t.Errorf("LoadSettings = %+v, want SlippageBps 75", got)
+	}
+	if h.LoadSettings("other", &got) {
+		t.Error("LoadSettings found settings for a module that never saved any")
+	}
+	if saved := config.Load(path).DappSettings["fake"]; !strings.Contains(string(saved), "75") {
+		t.Errorf("config file holds %s for the module, want its settings", saved)
+	}
+
+	// A config path that cannot be written must surface as an error.
+	m.configPath = filepath.Join(t.TempDir(), "missing", "config.json")
+	if err := h.SaveSettings("fake", settings{}); err == nil {
+		t.Error("SaveSettings to an unwritable path returned nil")
+	}
+	if _, err := os.Stat(m.configPath); !os.IsNotExist(err) {
+		t.Errorf("unwritable config path exists after a failed save: %v", err)
+	}
+}
+
+func TestModuleCapturesInput(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		page config.Page
+		mod  dapp.Module
+		want bool
+	}{
+		{"capturing on its page", config.PageDapp, fakeModule{capturing: true}, true},
+		{"not capturing", config.PageDapp, fakeModule{}, false},
+		{"off its page", config.PageWallets, fakeModule{capturing: true}, false},
+		{"no module", config.PageDapp, nil, false},
+	} {
+		m := model{activePage: tc.page, activeModule: tc.mod}
+		if got := m.moduleCapturesInput(); got != tc.want {
+			t.Errorf("%s: moduleCapturesInput() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRegisterModuleRegionsDispatch(t *testing.T) {
+	m := &model{}
+	var clicked dapp.Host
+	m.registerModuleRegions([]dapp.Region{
+		{ID: "btn", X1: 1, Y1: 2, X2: 5, Y2: 3, OnClick: func(h dapp.Host) tea.Cmd { clicked = h; return nil }},
+		{ID: "field", X1: 0, Y1: 4, X2: 9, Y2: 5, Input: true},
+	}, 3, 10)
+
+	r, ok := m.hitTestRegions(4, 12)
+	if !ok || r.ID != "btn" || r.Kind != uiRegionButton {
+		t.Fatalf("hit at (4,12) = %+v, %v; want the button offset by (3,10)", r, ok)
+	}
+	if _, ok := m.hitTestRegions(1, 2); ok {
+		t.Error("hit at the region's unshifted position")
+	}
+	r.OnClick(m)
+	if clicked == nil {
+		t.Error("clicking the region did not reach the module's OnClick")
+	}
+
+	r, ok = m.hitTestRegions(3, 14)
+	if !ok || r.Kind != uiRegionInput {
+		t.Fatalf("hit at (3,14) = %+v, %v; want the input region", r, ok)
+	}
+	if _, cmd := r.OnClick(m); cmd != nil {
+		t.Error("region without OnClick returned a command")
+	}
+}
@@ -0,0 +1,228 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ParseABIArg converts user input for one ABI parameter into the Go value
+// abi.Arguments.Pack expects for t. Scalars are typed as-is (decimal or 0x
+// hex for integers, true/false, 0x hex for bytes); arrays and tuples take a
+// JSON array whose elements follow the same rules, e.g. ["0xabc…", 5].
+func ParseABIArg(t abi.Type, s string) (interface{}, error) {
+	v, err := parseABIValue(t, strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func parseABIValue(t abi.Type, s string) (reflect.Value, error) {
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("not an address: %q", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+
+	case abi.BoolTy:
+		switch strings.ToLower(s) {
+		case "true", "1", "yes":
+			return reflect.ValueOf(true), nil
+		case "false", "0", "no":
+			return reflect.ValueOf(false), nil
+		}
+		return reflect.Value{}, fmt.Errorf("not a bool: %q", s)
+
+	case abi.StringTy:
+		return reflect.ValueOf(s), nil
+
+	case abi.BytesTy:
+		b, err := hexutil.Decode(normalizeHex(s))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("bytes must be 0x-prefixed hex")
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(normalizeHex(s))
+		if err != nil || len(b) > t.Size {
+			return reflect.Value{}, fmt.Errorf("bytes%d must be at most %d bytes of 0x hex", t.Size, t.Size)
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(common.RightPadBytes(b, t.Size)))
+		return v, nil
+
+	case abi.UintTy, abi.IntTy:
+		n, ok := parseABIInt(s)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("not an integer: %q", s)
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return reflect.Value{}, fmt.Errorf("uint%d cannot be negative", t.Size)
+		}
+		// uintN holds 0 .. 2^N-1; intN holds -2^(N-1) .. 2^(N-1)-1.
+		lo, hi := new(big.Int), new(big.Int).Lsh(big.NewInt(1), uint(t.Size))
+		if t.T == abi.IntTy {
+			hi.Rsh(hi, 1)
+			lo.Neg(hi)
+		}
+		hi.Sub(hi, big.NewInt(1))
+		if n.Cmp(lo) < 0 || n.Cmp(hi) > 0 {
+			return reflect.Value{}, fmt.Errorf("%s out of range for %s", s, t.String())
+		}
+		// Only 8/16/32/64-bit sizes map to native Go ints; the rest are *big.Int.
+		if t.GetType().Kind() == reflect.Ptr {
+			return reflect.ValueOf(n), nil
+		}
+		v := reflect.New(t.GetType()).Elem()
+		if t.T == abi.UintTy {
+			v.SetUint(n.Uint64())
+		} else {
+			v.SetInt(n.Int64())
+		}
+		return v, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		elems, err := splitJSONArray(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t.T == abi.ArrayTy && len(elems) != t.Size {
+			return reflect.Value{}, fmt.Errorf("%s needs exactly %d elements, got %d", t.String(), t.Size, len(elems))
+		}
+		var v reflect.Value
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.GetType(), len(elems), len(elems))
+		} else {
+			v = reflect.New(t.GetType()).Elem()
+		}
+		for i, e := range elems {
+			ev, err := parseABIValue(*t.Elem, e)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+
+	case abi.TupleTy:
+		elems, err := splitJSONArray(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(elems) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("tuple needs %d fields, got %d", len(t.TupleElems), len(elems))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		for i, e := range elems {
+			ev, err := parseABIValue(*t.TupleElems[i], e)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", t.TupleRawNames[i], err)
+			}
+			v.Field(i).Set(ev)
+		}
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported parameter type %s", t.String())
+}
+
+func normalizeHex(s string) string {
+	if s == "" {
+		return "0x"
+	}
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return "0x" + s
+	}
+	return s
+}
+
+// splitJSONArray returns the elements of a JSON array as input strings:
+// JSON strings are unquoted, anything else (numbers, bools, nested arrays)
+// is kept as its raw JSON text.
+func splitJSONArray(s string) ([]string, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON array, e.g. [\"0x…\", 1]")
+	}
+	out := make([]string, len(raw))
+	for i, r := range raw {
+		var str string
+		if json.Unmarshal(r, &str) == nil {
+			out[i] = str
+		} else {
+			out[i] = string(r)
+		}
+	}
+	return out, nil
+}
+
+// parseABIInt reads s as a decimal integer, or hex with an explicit 0x
+// prefix. Unlike base-0 parsing, leading zeros stay decimal and the
+// 0b/0o/underscore forms are rejected, so "0100" is one hundred.
+func parseABIInt(s string) (*big.Int, bool) {
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits, base = digits[2:], 16
+	}
+	if digits == "" || strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		return nil, false
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, false
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, true
+}
+
+// FormatABIValue renders a value unpacked from contract return data:
+// addresses checksummed, byte strings as 0x hex, arrays and tuples as
+// bracketed lists.
+func FormatABIValue(v interface{}) string {
+	switch x := v.(type) {
+	case common.Address:
+		return x.Hex()
+	case *big.Int:
+		return x.String()
+	case []byte:
+		return hexutil.Encode(x)
+	case string:
+		return fmt.Sprintf("%q", x)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = FormatABIValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case reflect.Struct:
+		parts := make([]string, rv.NumField())
+		for i := range parts {
+			parts[i] = rv.Type().Field(i).Name + ": " + FormatABIValue(rv.Field(i).Interface())
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+	return fmt.Sprintf("%v", v)
+}
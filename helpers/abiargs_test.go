@@ -0,0 +1,154 @@
+package helpers
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseABIArgPacks(t *testing.T) {
+	const abiJSON = `[{"type":"function","name":"f","inputs":[
+		{"name":"to","type":"address"},
+		{"name":"amount","type":"uint256"},
+		{"name":"small","type":"uint8"},
+		{"name":"delta","type":"int24"},
+		{"name":"flag","type":"bool"},
+		{"name":"tag","type":"bytes32"},
+		{"name":"data","type":"bytes"},
+		{"name":"path","type":"address[]"},
+		{"name":"key","type":"tuple","components":[{"name":"fee","type":"uint24"},{"name":"hooks","type":"address"}]}
+	]}]`
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := []string{
+		"0x00000000000000000000000000000000000000a1",
+		"1000000000000000000",
+		"255",
+		"-60",
+		"true",
+		"0x1234",
+		"0xdeadbeef",
+		`["0x00000000000000000000000000000000000000a1", "0x00000000000000000000000000000000000000b2"]`,
+		`[3000, "0x0000000000000000000000000000000000000000"]`,
+	}
+
+	method := parsed.Methods["f"]
+	var args []interface{}
+	for i, in := range method.Inputs {
+		v, err := ParseABIArg(in.Type, inputs[i])
+		if err != nil {
+			t.Fatalf("%s: %v", in.Name, err)
+		}
+		args = append(args, v)
+	}
+	packed, err := parsed.Pack("f", args...)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+	out, err := method.Inputs.Unpack(packed[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out[1].(*big.Int); got.String() != "1000000000000000000" {
+		t.Errorf("amount = %s", got)
+	}
+	if got := out[3].(*big.Int); got.Int64() != -60 {
+		t.Errorf("delta = %s", got)
+	}
+	if got := out[7].([]common.Address); len(got) != 2 || got[1] != common.HexToAddress("0xb2") {
+		t.Errorf("path = %v", got)
+	}
+	if got := FormatABIValue(out[5]); got != "0x1234"+strings.Repeat("0", 60) {
+		t.Errorf("FormatABIValue(bytes32) = %s", got)
+	}
+}
+
+func TestParseABIArgRejects(t *testing.T) {
+	u8, _ := abi.NewType("uint8", "", nil)
+	i8, _ := abi.NewType("int8", "", nil)
+	addr, _ := abi.NewType("address", "", nil)
+	fixed, _ := abi.NewType("address[2]", "", nil)
+	u256, _ := abi.NewType("uint256", "", nil)
+
+	for _, tc := range []struct {
+		typ abi.Type
+		in  string
+	}{
+		{u8, "256"},
+		{u8, "-1"},
+		{i8, "128"},
+		{i8, "-129"},
+		{u256, "1_000"},
+		{u256, "0b101"},
+		{u256, "0o17"},
+		{u256, "--1"},
+		{u256, "0x"},
+		{addr, "0x1234"},
+		{fixed, `["0x00000000000000000000000000000000000000a1"]`},
+	} {
+		if _, err := ParseABIArg(tc.typ, tc.in); err == nil {
+			t.Errorf("ParseABIArg(%s, %q) succeeded, want error", tc.typ, tc.in)
+		}
+	}
+}
+
+func TestParseABIArgIntBounds(t *testing.T) {
+	i8, _ := abi.NewType("int8", "", nil)
+	i24, _ := abi.NewType("int24", "", nil)
+	u8, _ := abi.NewType("uint8", "", nil)
+
+	for _, tc := range []struct {
+		typ abi.Type
+		in  string
+	}{
+		{i8, "-128"},
+		{i8, "127"},
+		{i24, "-8388608"},
+		{i24, "8388607"},
+		{u8, "0"},
+		{u8, "255"},
+	} {
+		if _, err := ParseABIArg(tc.typ, tc.in); err != nil {
+			t.Errorf("ParseABIArg(%s, %q): %v", tc.typ, tc.in, err)
+		}
+	}
+}
+
+func TestParseABIArgIntBases(t *testing.T) {
+	u256, _ := abi.NewType("uint256", "", nil)
+	i8, _ := abi.NewType("int8", "", nil)
+
+	for _, tc := range []struct {
+		typ  abi.Type
+		in   string
+		want int64
+	}{
+		{u256, "0100", 100},
+		{u256, "007", 7},
+		{u256, "0x100", 256},
+		{u256, "0X0a", 10},
+		{i8, "-0010", -10},
+		{i8, "-0x80", -128},
+	} {
+		v, err := ParseABIArg(tc.typ, tc.in)
+		if err != nil {
+			t.Errorf("ParseABIArg(%s, %q): %v", tc.typ, tc.in, err)
+			continue
+		}
+		var got int64
+		switch x := v.(type) {
+		case *big.Int:
+			got = x.Int64()
+		case int8:
+			got = int64(x)
+		}
+		if got != tc.want {
+			t.Errorf("ParseABIArg(%s, %q) = %v, want %d", tc.typ, tc.in, v, tc.want)
+		}
+	}
+}
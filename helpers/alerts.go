@@ -0,0 +1,437 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/indexer"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ---- Alert rules ----
+//
+// An alert rule is a kind followed by space-separated key:value terms;
+// comma-separated values are alternatives:
+//
+//	balance  [wallet:0x…] [token:ETH|SYM|0x…] [change:0.5]
+//	transfer [wallet:0x…] [token:SYM|0x…] [min:1000]
+//	price    token:SYM|0x… above:4000 | below:2500
+//	swap     [pool:0x…] min:100k
+//	range    [wallet:0x…] [position:v3:123,v4:456]
+//
+// plus notify:bell,osc9,osc777,webhook,command on any of them (default
+// bell; every alert is also highlighted in the log). Balance,
+// price and range rules are checked against periodic snapshots
+// (AlertSnapshot); transfer and swap rules against indexed events.
+
+// Alert rule kinds.
+const (
+	AlertKindBalance  = "balance"
+	AlertKindTransfer = "transfer"
+	AlertKindPrice    = "price"
+	AlertKindSwap     = "swap"
+	AlertKindRange    = "range"
+)
+
+// Alert delivery channels a rule can add to the log highlight every alert
+// gets.
+const (
+	NotifyBell    = "bell"    // terminal bell
+	NotifyOSC9    = "osc9"    // OSC 9 desktop notification (iTerm2, WezTerm, Windows Terminal, kitty)
+	NotifyOSC777  = "osc777"  // OSC 777 desktop notification (foot, Ghostty, rxvt-unicode, WezTerm)
+	NotifyWebhook = "webhook" // JSON POST to the configured webhook URL
+	NotifyCommand = "command" // run the configured shell command
+)
+
+// AlertRule is a parsed alert rule.
+type AlertRule struct {
+	Kind      string
+	Wallets   []common.Address // balance, transfer, range; empty = every saved wallet
+	Token     string           // as written: "ETH", a symbol or an address; "" = any
+	Pools     []common.Hash    // swap; empty = any pool
+	Positions []string         // range, as "v3:<id>"/"v4:<id>"; empty = every position
+	Change    float64          // balance: smallest change in whole tokens; 0 = any
+	Min       float64          // transfer: whole tokens; swap: USD
+	Above     float64          // price: fire on crossing up through this USD level
+	Below     float64          // price: fire on crossing down through this USD level
+	Notify    []string
+}
+
+// ParseAlertRule parses a rule expression (see above).
+func ParseAlertRule(expr string) (AlertRule, error) {
+	var r AlertRule
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return r, fmt.Errorf("empty rule: start with balance, transfer, price, swap or range")
+	}
+	r.Kind = strings.ToLower(fields[0])
+	switch r.Kind {
+	case AlertKindBalance, AlertKindTransfer, AlertKindPrice, AlertKindSwap, AlertKindRange:
+	default:
+		return r, fmt.Errorf("%q: a rule starts with balance, transfer, price, swap or range", fields[0])
+	}
+	for _, term := range fields[1:] {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			return r, fmt.Errorf("%q: expected key:value", term)
+		}
+		key = strings.ToLower(key)
+		if !alertKeyAllowed(r.Kind, key) {
+			return r, fmt.Errorf("%q: %s rules take no %s: term", term, r.Kind, key)
+		}
+		switch key {
+		case "wallet":
+			for _, v := range strings.Split(value, ",") {
+				if !common.IsHexAddress(v) {
+					return r, fmt.Errorf("wallet:%s: expected an address", v)
+				}
+				r.Wallets = append(r.Wallets, common.HexToAddress(v))
+			}
+		case "token":
+			r.Token = value
+		case "pool":
+			for _, v := range strings.Split(value, ",") {
+				if len(strings.TrimPrefix(v, "0x")) != 64 {
+					return r, fmt.Errorf("pool:%s: expected a 32-byte pool id", v)
+				}
+				r.Pools = append(r.Pools, common.HexToHash(v))
+			}
+		case "position":
+			for _, v := range strings.Split(strings.ToLower(value), ",") {
+				version, id, ok := strings.Cut(v, ":")
+				if _, isNum := new(big.Int).SetString(id, 10); !ok || !isNum || (version != "v3" && version != "v4") {
+					return r, fmt.Errorf("position:%s: expected v3:<token id> or v4:<token id>", v)
+				}
+				r.Positions = append(r.Positions, v)
+			}
+		case "change", "min", "above", "below":
+			amount, err := parseAmount(value)
+			if err != nil {
+				return r, fmt.Errorf("%s: %w", term, err)
+			}
+			switch key {
+			case "change":
+				r.Change = amount
+			case "min":
+				r.Min = amount
+			case "above":
+				r.Above = amount
+			case "below":
+				r.Below = amount
+			}
+		case "notify":
+			for _, v := range strings.Split(strings.ToLower(value), ",") {
+				switch v {
+				case NotifyBell, NotifyOSC9, NotifyOSC777, NotifyWebhook, NotifyCommand:
+					r.Notify = append(r.Notify, v)
+				default:
+					return r, fmt.Errorf("notify:%s: expected bell, osc9, osc777, webhook or command", v)
+				}
+			}
+		default:
+			return r, fmt.Errorf("%q: unknown key %q", term, key)
+		}
+	}
+	if len(r.Notify) == 0 {
+		r.Notify = []string{NotifyBell}
+	}
+	switch {
+	case r.Kind == AlertKindPrice && r.Token == "":
+		return r, fmt.Errorf("price rules need a token: term")
+	case r.Kind == AlertKindPrice && r.Above == 0 && r.Below == 0:
+		return r, fmt.Errorf("price rules need above: or below:")
+	case r.Kind == AlertKindSwap && r.Min == 0 && len(r.Pools) == 0:
+		return r, fmt.Errorf("swap rules need pool: or min: (every swap on the chain is too many)")
+	}
+	return r, nil
+}
+
+// alertKeyAllowed reports whether a rule of kind takes key: terms.
+func alertKeyAllowed(kind, key string) bool {
+	switch key {
+	case "notify":
+		return true
+	case "wallet":
+		return kind == AlertKindBalance || kind == AlertKindTransfer || kind == AlertKindRange
+	case "token":
+		return kind == AlertKindBalance || kind == AlertKindTransfer || kind == AlertKindPrice
+	case "change":
+		return kind == AlertKindBalance
+	case "min":
+		return kind == AlertKindTransfer || kind == AlertKindSwap
+	case "above", "below":
+		return kind == AlertKindPrice
+	case "pool":
+		return kind == AlertKindSwap
+	case "position":
+		return kind == AlertKindRange
+	}
+	return true // unknown keys are reported by the caller
+}
+
+// Has reports whether the rule delivers through channel.
+func (r AlertRule) Has(channel string) bool {
+	return containsString(r.Notify, channel)
+}
+
+// watches reports whether the rule covers wallet, given the saved wallets
+// an empty Wallets list stands for.
+func (r AlertRule) watches(wallet common.Address, saved []common.Address) bool {
+	if len(r.Wallets) > 0 {
+		return containsAddress(r.Wallets, wallet)
+	}
+	return containsAddress(saved, wallet)
+}
+
+// matchesToken reports whether a token passes the rule's token: term.
+// Native ETH is the zero address.
+func (r AlertRule) matchesToken(token common.Address, symbol string) bool {
+	switch {
+	case r.Token == "":
+		return true
+	case common.IsHexAddress(r.Token):
+		return token == common.HexToAddress(r.Token)
+	case strings.EqualFold(r.Token, "ETH"):
+		return token == (common.Address{})
+	}
+	return strings.EqualFold(r.Token, symbol)
+}
+
+// ---- Snapshots ----
+
+// AlertBalance is one wallet's balance of one token (ETH is the zero
+// address), in whole tokens.
+type AlertBalance struct {
+	Wallet common.Address
+	Token  common.Address
+	Symbol string
+	Amount float64
+}
+
+// AlertPosition is a liquidity position's range state.
+type AlertPosition struct {
+	Wallet  common.Address
+	Label   string // e.g. "V3 #1234 WETH/USDC"
+	InRange bool
+}
+
+// AlertSnapshot is the chain state periodic rules are checked against.
+type AlertSnapshot struct {
+	Head      uint64
+	Balances  []AlertBalance
+	Prices    map[string]float64         // USD price by upper-cased price rule token
+	USD       map[common.Address]float64 // USD prices for sizing swaps
+	Positions map[string]AlertPosition   // by "v3:<id>"/"v4:<id>"
+	Wallets   []common.Address           // the saved wallets when it was taken
+	At        time.Time
+}
+
+// Check compares two snapshots and returns a message for each way the rule
+// fired between them. Transfer and swap rules never fire here.
+func (r AlertRule) Check(prev, cur *AlertSnapshot) []string {
+	if prev == nil || cur == nil {
+		return nil
+	}
+	var out []string
+	switch r.Kind {
+	case AlertKindBalance:
+		for _, b := range cur.Balances {
+			if !r.watches(b.Wallet, cur.Wallets) || !r.matchesToken(b.Token, b.Symbol) || (r.Token == "" && b.Token != (common.Address{})) {
+				continue
+			}
+			for _, p := range prev.Balances {
+				if p.Wallet != b.Wallet || p.Token != b.Token {
+					continue
+				}
+				delta := b.Amount - p.Amount
+				if delta != 0 && math.Abs(delta) >= r.Change {
+					out = append(out, fmt.Sprintf("%s %s balance changed by %+g to %g",
+						ShortenAddr(b.Wallet.Hex()), b.Symbol, delta, b.Amount))
+				}
+			}
+		}
+	case AlertKindPrice:
+		key := strings.ToUpper(r.Token)
+		was, ok0 := prev.Prices[key]
+		now, ok1 := cur.Prices[key]
+		if !ok0 || !ok1 {
+			break
+		}
+		if r.Above > 0 && was < r.Above && now >= r.Above {
+			out = append(out, fmt.Sprintf("%s rose above $%g: $%.4g", r.Token, r.Above, now))
+		}
+		if r.Below > 0 && was > r.Below && now <= r.Below {
+			out = append(out, fmt.Sprintf("%s fell below $%g: $%.4g", r.Token, r.Below, now))
+		}
+	case AlertKindRange:
+		for key, pos := range cur.Positions {
+			if len(r.Positions) > 0 && !containsString(r.Positions, key) {
+				continue
+			}
+			if !r.watches(pos.Wallet, cur.Wallets) {
+				continue
+			}
+			if was, ok := prev.Positions[key]; ok && was.InRange && !pos.InRange {
+				out = append(out, pos.Label+" left its range and stopped earning fees")
+			}
+		}
+	}
+	return out
+}
+
+// CheckTransfer returns a message when ev is an incoming transfer the rule
+// covers; saved lists the wallets an empty wallet: term stands for.
+func (r AlertRule) CheckTransfer(ev indexer.IndexedEvent, saved []common.Address) (string, bool) {
+	if r.Kind != AlertKindTransfer || ev.Value == nil || !r.watches(ev.To, saved) || !r.matchesToken(ev.Token, ev.Symbol) {
+		return "", false
+	}
+	amount := wholeTokens(ev.Value, ev.Decimals)
+	if amount < r.Min || amount == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%s received %g %s from %s", ShortenAddr(ev.To.Hex()), amount, ev.Symbol, ShortenAddr(ev.From.Hex())), true
+}
+
+// AlertSwapPool is what sizing a swap needs to know about its pool.
+type AlertSwapPool struct {
+	Currency0, Currency1 common.Address
+	Symbol0, Symbol1     string
+	Decimals0, Decimals1 uint8
+}
+
+// CheckSwap returns a message when ev is a swap the rule covers, sized in
+// USD from whichever side usd prices. Unpriced swaps only match rules
+// without a min: term.
+func (r AlertRule) CheckSwap(ev indexer.V4PoolEvent, pool AlertSwapPool, usd map[common.Address]float64) (string, bool) {
+	if r.Kind != AlertKindSwap || ev.Kind != indexer.V4KindSwap {
+		return "", false
+	}
+	if len(r.Pools) > 0 && !containsHash(r.Pools, ev.PoolID) {
+		return "", false
+	}
+	size := 0.0
+	for _, side := range []struct {
+		currency common.Address
+		decimals uint8
+		amount   *big.Int
+	}{{pool.Currency0, pool.Decimals0, ev.Amount0}, {pool.Currency1, pool.Decimals1, ev.Amount1}} {
+		if price, ok := usd[side.currency]; ok && side.amount != nil {
+			size = math.Max(size, wholeTokens(new(big.Int).Abs(side.amount), side.decimals)*price)
+		}
+	}
+	if size < r.Min {
+		return "", false
+	}
+	sizeStr := "unpriced"
+	if size > 0 {
+		sizeStr = fmt.Sprintf("$%.0f", size)
+	}
+	return fmt.Sprintf("%s swap in %s/%s (pool %s, block %d)", sizeStr, pool.Symbol0, pool.Symbol1,
+		ShortenAddr(ev.PoolID.Hex()), ev.Block), true
+}
+
+// ---- Snapshot sources ----
+
+// LoadAlertPrices prices what the rules need: each price rule's token, and
+// ETH and the stablecoins for sizing swaps. tokens resolves symbols (upper
+// case) to addresses; the chain's WETH and stablecoins are known already.
+func LoadAlertPrices(rpcURL string, rules []AlertRule, tokens map[string]common.Address) (prices map[string]float64, usd map[common.Address]float64, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+	addrs := addressesForClient(ctx, client)
+	usd = positionUSDPrices(ctx, client, addrs)
+
+	known := map[string]common.Address{"ETH": addrs.WETH, "WETH": addrs.WETH, "USDC": addrs.USDC, "USDT": addrs.USDT, "DAI": addrs.DAI}
+	prices = make(map[string]float64)
+	for _, r := range rules {
+		if r.Kind != AlertKindPrice {
+			continue
+		}
+		key := strings.ToUpper(r.Token)
+		if _, done := prices[key]; done {
+			continue
+		}
+		token, ok := tokens[key]
+		if !ok {
+			token, ok = known[key]
+		}
+		if common.IsHexAddress(r.Token) {
+			token, ok = common.HexToAddress(r.Token), true
+		}
+		if !ok || token == (common.Address{}) {
+			err = fmt.Errorf("price %s: unknown token (watch it or use its address)", r.Token)
+			continue
+		}
+		if p, perr := tokenUSDPrice(ctx, client, addrs, token, usd); perr == nil {
+			prices[key] = p
+		} else {
+			err = fmt.Errorf("price %s: %w", r.Token, perr)
+		}
+	}
+	return prices, usd, err
+}
+
+// tokenUSDPrice quotes one whole token against USDC through its most liquid
+// pool, or against WETH at the ETH price when it has no USDC pool.
+func tokenUSDPrice(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, token common.Address, usd map[common.Address]float64) (float64, error) {
+	if p, ok := usd[token]; ok {
+		return p, nil
+	}
+	one := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(v4ERC20Decimals(ctx, client, token))), nil)
+	for _, quoteToken := range []common.Address{addrs.USDC, addrs.WETH} {
+		price, ok := usd[quoteToken]
+		if quoteToken == (common.Address{}) || !ok {
+			continue
+		}
+		pool, found := PreferredPool(LiquidPools(ctx, client, addrs, token, quoteToken, false))
+		if !found {
+			continue
+		}
+		quote, err := QuoteHop(client, addrs, pool, token, quoteToken, one)
+		if err != nil || quote == nil || quote.AmountOut == nil {
+			continue
+		}
+		return wholeTokens(quote.AmountOut, v4ERC20Decimals(ctx, client, quoteToken)) * price, nil
+	}
+	return 0, fmt.Errorf("no USDC or WETH pool")
+}
+
+// LoadAlertPositions reads the range state of every open liquidity position
+// the wallets hold.
+func LoadAlertPositions(rpcURL string, wallets []common.Address) (map[string]AlertPosition, error) {
+	out := make(map[string]AlertPosition)
+	var lastErr error
+	for _, w := range wallets {
+		positions, _, _, err := GetLiquidityPositions(rpcURL, w)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, p := range positions {
+			if p.Stub || !p.PoolTickKnown || p.Liquidity == nil || p.Liquidity.Sign() == 0 {
+				continue
+			}
+			version := "v3"
+			if p.Version == PoolVersionV4 {
+				version = "v4"
+			}
+			out[version+":"+p.TokenID.String()] = AlertPosition{
+				Wallet:  w,
+				Label:   fmt.Sprintf("%s #%s %s/%s", strings.ToUpper(version), p.TokenID, p.Token0Symbol, p.Token1Symbol),
+				InRange: p.PoolTick >= p.TickLower && p.PoolTick < p.TickUpper,
+			}
+		}
+	}
+	return out, lastErr
+}
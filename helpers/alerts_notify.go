@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// AlertPayload is what an alert's webhook receives as JSON, and its
+// command hook as environment variables.
+type AlertPayload struct {
+	Rule    string    `json:"rule"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// alertHookTimeout bounds a webhook request or command hook.
+const alertHookTimeout = 10 * time.Second
+
+// TerminalAlert writes the terminal side of an alert to w: a bell and/or
+// OSC 9 and OSC 777 desktop notifications, as the rule asks. Terminals
+// that do not support a sequence ignore it.
+func TerminalAlert(w io.Writer, r AlertRule, title, message string) error {
+	title, message = oscText(title), oscText(message)
+	var b strings.Builder
+	if r.Has(NotifyBell) {
+		b.WriteString("\a")
+	}
+	if r.Has(NotifyOSC9) {
+		fmt.Fprintf(&b, "\x1b]9;%s: %s\x07", title, message)
+	}
+	if r.Has(NotifyOSC777) {
+		fmt.Fprintf(&b, "\x1b]777;notify;%s;%s\x07", strings.ReplaceAll(title, ";", ","), message)
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// oscText strips styling and control characters, which would end or
+// corrupt an OSC sequence.
+func oscText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, ansi.Strip(s))
+}
+
+// PostAlertWebhook POSTs the alert to url as JSON.
+func PostAlertWebhook(url string, p AlertPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), alertHookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// RunAlertCommand runs command through the shell with the alert in
+// ALERT_RULE, ALERT_MESSAGE and ALERT_TIME.
+func RunAlertCommand(command string, p AlertPayload) error {
+	ctx, cancel := context.WithTimeout(context.Background(), alertHookTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(),
+		"ALERT_RULE="+p.Rule,
+		"ALERT_MESSAGE="+ansi.Strip(p.Message),
+		"ALERT_TIME="+p.Time.Format(time.RFC3339),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
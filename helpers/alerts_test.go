@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"charm-wallet-tui/indexer"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	wallet := "0x66a9893cC07D91D95644AEDD05D03f95e1dBA8Af"
+	r, err := ParseAlertRule("transfer wallet:" + wallet + " token:USDC min:1k notify:osc9,webhook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Kind != AlertKindTransfer || len(r.Wallets) != 1 || r.Wallets[0] != common.HexToAddress(wallet) || r.Token != "USDC" || r.Min != 1000 {
+		t.Errorf("rule = %+v", r)
+	}
+	if !r.Has(NotifyWebhook) || r.Has(NotifyBell) {
+		t.Errorf("Notify = %v", r.Notify)
+	}
+	if r, _ := ParseAlertRule("range position:V3:12"); len(r.Positions) != 1 || r.Positions[0] != "v3:12" || !r.Has(NotifyBell) {
+		t.Errorf("range rule = %+v", r)
+	}
+
+	for _, bad := range []string{
+		"", "alarm token:ETH", "price token:WETH", "price above:4000", "swap", "swap change:5",
+		"balance wallet:bob", "range position:7", "transfer min:lots", "balance notify:email", "balance token",
+	} {
+		if _, err := ParseAlertRule(bad); err == nil {
+			t.Errorf("ParseAlertRule(%q) accepted", bad)
+		}
+	}
+}
+
+func TestAlertRuleCheck(t *testing.T) {
+	w1, w2 := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	prev := &AlertSnapshot{
+		Wallets: []common.Address{w1, w2},
+		Balances: []AlertBalance{
+			{Wallet: w1, Symbol: "ETH", Amount: 2},
+			{Wallet: w1, Token: usdc, Symbol: "USDC", Amount: 100},
+			{Wallet: w2, Symbol: "ETH", Amount: 1},
+		},
+		Prices:    map[string]float64{"WETH": 3900},
+		Positions: map[string]AlertPosition{"v3:1": {Wallet: w1, Label: "V3 #1", InRange: true}, "v3:2": {Wallet: w2, Label: "V3 #2", InRange: true}},
+	}
+	cur := &AlertSnapshot{
+		Wallets: prev.Wallets,
+		Balances: []AlertBalance{
+			{Wallet: w1, Symbol: "ETH", Amount: 1.5},
+			{Wallet: w1, Token: usdc, Symbol: "USDC", Amount: 90},
+			{Wallet: w2, Symbol: "ETH", Amount: 1.1},
+		},
+		Prices:    map[string]float64{"WETH": 4100},
+		Positions: map[string]AlertPosition{"v3:1": {Wallet: w1, Label: "V3 #1", InRange: false}, "v3:2": {Wallet: w2, Label: "V3 #2", InRange: true}},
+	}
+
+	check := func(expr string) []string {
+		t.Helper()
+		r, err := ParseAlertRule(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Check(nil, cur); got != nil {
+			t.Errorf("%s fired without a baseline: %v", expr, got)
+		}
+		return r.Check(prev, cur)
+	}
+	if got := check("balance change:0.5"); len(got) != 1 || !strings.Contains(got[0], "ETH balance changed by -0.5") {
+		t.Errorf("balance = %v", got)
+	}
+	if got := check("balance token:usdc"); len(got) != 1 || !strings.Contains(got[0], "USDC") {
+		t.Errorf("token balance = %v", got)
+	}
+	if got := check("balance wallet:" + w2.Hex() + " change:1"); len(got) != 0 {
+		t.Errorf("small change = %v", got)
+	}
+	if got := check("price token:WETH above:4000 below:3000"); len(got) != 1 || !strings.Contains(got[0], "rose above $4000") {
+		t.Errorf("price = %v", got)
+	}
+	if got := check("price token:DAI below:0.99"); len(got) != 0 {
+		t.Errorf("unpriced token = %v", got)
+	}
+	if got := check("range"); len(got) != 1 || !strings.Contains(got[0], "V3 #1 left its range") {
+		t.Errorf("range = %v", got)
+	}
+	if got := check("range position:v3:2"); len(got) != 0 {
+		t.Errorf("in-range position = %v", got)
+	}
+}
+
+func TestAlertRuleCheckEvents(t *testing.T) {
+	w := common.HexToAddress("0x1")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	ev := indexer.IndexedEvent{From: common.HexToAddress("0x9"), To: w, Token: usdc, Symbol: "USDC", Decimals: 6, Value: big.NewInt(2500_000000)}
+
+	r, _ := ParseAlertRule("transfer token:USDC min:1000")
+	if msg, ok := r.CheckTransfer(ev, []common.Address{w}); !ok || !strings.Contains(msg, "received 2500 USDC") {
+		t.Errorf("transfer = %q, %v", msg, ok)
+	}
+	if _, ok := r.CheckTransfer(ev, nil); ok {
+		t.Error("transfer to an unwatched wallet fired")
+	}
+	ev.Value = big.NewInt(10_000000)
+	if _, ok := r.CheckTransfer(ev, []common.Address{w}); ok {
+		t.Error("transfer under min fired")
+	}
+
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	pool := AlertSwapPool{Currency0: usdc, Currency1: weth, Symbol0: "USDC", Symbol1: "WETH", Decimals0: 6, Decimals1: 18}
+	swap := indexer.V4PoolEvent{Kind: indexer.V4KindSwap, PoolID: common.Hash{1}, Amount0: big.NewInt(-60_000_000000), Amount1: big.NewInt(20)}
+	usd := map[common.Address]float64{usdc: 1}
+	r, _ = ParseAlertRule("swap min:50k")
+	if msg, ok := r.CheckSwap(swap, pool, usd); !ok || !strings.HasPrefix(msg, "$60000 swap in USDC/WETH") {
+		t.Errorf("swap = %q, %v", msg, ok)
+	}
+	if _, ok := r.CheckSwap(swap, pool, nil); ok {
+		t.Error("unpriced swap passed a min: rule")
+	}
+	r, _ = ParseAlertRule("swap pool:" + common.Hash{2}.Hex())
+	if _, ok := r.CheckSwap(swap, pool, usd); ok {
+		t.Error("swap in another pool fired")
+	}
+}
+
+func TestTerminalAlert(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := ParseAlertRule("balance notify:bell,osc9,osc777")
+	if err := TerminalAlert(&buf, r, "Wallet; alert", "moved\x1b[1m 1 ETH\n"); err != nil {
+		t.Fatal(err)
+	}
+	want := "\a\x1b]9;Wallet; alert: moved 1 ETH \x07\x1b]777;notify;Wallet, alert;moved 1 ETH \x07"
+	if buf.String() != want {
+		t.Errorf("TerminalAlert wrote %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	r, _ = ParseAlertRule("balance notify:webhook")
+	if err := TerminalAlert(&buf, r, "t", "m"); err != nil || buf.Len() != 0 {
+		t.Errorf("webhook-only rule wrote %q (%v)", buf.String(), err)
+	}
+}
@@ -0,0 +1,742 @@
+package helpers
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"charm-wallet-tui/rpc"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// ---- Block explorer ----
+//
+// The Block Explorer page decodes one block, or the block around one
+// transaction, into an ExplorerNode tree: the block header, each
+// transaction with its gas, input, revert reason, token transfers, Uniswap
+// V4 events and raw logs, then the block's PoolManager and PositionManager
+// activity and the live state of any pool created in it.
+
+// ExplorerQuery is what the Block Explorer was asked to show.
+type ExplorerQuery struct {
+	Block   uint64
+	Latest  bool           // the chain head rather than Block
+	Tx      common.Hash    // a single transaction; its block is explored
+	Address common.Address // only transactions touching it; zero = all
+}
+
+// ParseExplorerQuery parses the explorer's input: a block number (or
+// "latest"), a transaction hash, or an address, which narrows the block to
+// the transactions touching it ("24686488 0x5857…", or just the address for
+// the latest block).
+func ParseExplorerQuery(s string) (ExplorerQuery, error) {
+	var q ExplorerQuery
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return q, fmt.Errorf("enter a block number, transaction hash or address")
+	}
+	haveBlock := false
+	for _, f := range fields {
+		switch {
+		case strings.EqualFold(f, "latest"):
+			if haveBlock {
+				return q, fmt.Errorf("%q: only one block at a time", f)
+			}
+			q.Latest, haveBlock = true, true
+		case isHexHash(f):
+			if q.Tx != (common.Hash{}) {
+				return q, fmt.Errorf("%q: only one transaction at a time", f)
+			}
+			q.Tx = common.HexToHash(f)
+		case common.IsHexAddress(f):
+			if q.Address != (common.Address{}) {
+				return q, fmt.Errorf("%q: only one address at a time", f)
+			}
+			q.Address = common.HexToAddress(f)
+		default:
+			n, err := strconv.ParseUint(strings.NewReplacer(",", "", "_", "").Replace(strings.TrimPrefix(f, "#")), 10, 64)
+			if err != nil {
+				return q, fmt.Errorf("%q: not a block number, transaction hash or address", f)
+			}
+			if haveBlock {
+				return q, fmt.Errorf("%q: only one block at a time", f)
+			}
+			q.Block, haveBlock = n, true
+		}
+	}
+	if q.Tx != (common.Hash{}) && (haveBlock || q.Address != (common.Address{})) {
+		return q, fmt.Errorf("a transaction hash is explored on its own")
+	}
+	if !haveBlock && q.Tx == (common.Hash{}) {
+		q.Latest = true
+	}
+	return q, nil
+}
+
+func isHexHash(s string) bool {
+	if len(s) != 66 || !strings.HasPrefix(strings.ToLower(s), "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(s[2:])
+	return err == nil
+}
+
+// String describes the query for the page title.
+func (q ExplorerQuery) String() string {
+	if q.Tx != (common.Hash{}) {
+		return "tx " + ShortenAddr(q.Tx.Hex())
+	}
+	s := fmt.Sprintf("block %d", q.Block)
+	if q.Latest {
+		s = "latest block"
+	}
+	if q.Address != (common.Address{}) {
+		s += " · " + ShortenAddr(q.Address.Hex())
+	}
+	return s
+}
+
+// ExplorerNode is one line of the explorer's result tree. Text is styled;
+// Open nodes show their children. Tx is set on a transaction's node so the
+// page can open it in the Transaction Inspector.
+type ExplorerNode struct {
+	Text     string
+	Children []*ExplorerNode
+	Open     bool
+	Tx       common.Hash
+}
+
+func (n *ExplorerNode) add(text string) *ExplorerNode {
+	child := &ExplorerNode{Text: text}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// field adds a "label : value" line.
+func (n *ExplorerNode) field(label, value string) *ExplorerNode {
+	return n.add(bsLabel(fmt.Sprintf("%-12s:", label)) + " " + value)
+}
+
+// BlockExplorer runs explorer queries. PoolLookup, when set, names V4 pools
+// that were not created in the explored block (see PoolEventMonitor).
+type BlockExplorer struct {
+	PoolLookup func(common.Hash) (V4PoolKey, bool)
+}
+
+// explorerScan is one Explore call's state.
+type explorerScan struct {
+	ctx        context.Context
+	client     *ethclient.Client
+	rpcURL     string
+	signer     types.Signer
+	addrs      UniswapNetworkAddresses
+	pmABI      abi.ABI
+	eventNames map[common.Hash]string
+	lookup     func(common.Hash) (V4PoolKey, bool)
+
+	mu       sync.RWMutex
+	poolKeys map[common.Hash]v4PoolKey
+	syms     *v4SymbolCache
+	decs     map[common.Address]uint8
+}
+
+var (
+	explorerTransferSig = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	explorerIncLiqSig   = common.HexToHash("0x3067048beee31b25b2f1681f88dac838c8bba36af25bfb2b7cf7473a5847e35f")
+)
+
+// Explore decodes what q asks for into a tree of top-level sections.
+func (e BlockExplorer) Explore(ctx context.Context, rpcURL string, q ExplorerQuery) ([]*ExplorerNode, error) {
+	s, err := newExplorerScan(ctx, rpcURL, e.PoolLookup)
+	if err != nil {
+		return nil, err
+	}
+	defer s.client.Close()
+	return s.explore(q)
+}
+
+// newExplorerScan dials rpcURL and sets up the decoding state shared by the
+// Block Explorer and the Transaction Inspector. The caller closes s.client.
+func newExplorerScan(ctx context.Context, rpcURL string, lookup func(common.Hash) (V4PoolKey, bool)) (*explorerScan, error) {
+	dialCtx, dialCancel := context.WithTimeout(ctx, 12*time.Second)
+	defer dialCancel()
+	client, err := ethclient.DialContext(dialCtx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("dial RPC: %w", err)
+	}
+
+	pmABI, err := abi.JSON(strings.NewReader(poolManagerEventsABI))
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("parse ABI: %w", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("chain id: %w", err)
+	}
+	s := &explorerScan{
+		ctx:        ctx,
+		client:     client,
+		rpcURL:     rpcURL,
+		signer:     types.LatestSignerForChainID(chainID),
+		addrs:      UniswapAddressesForChain(chainID),
+		pmABI:      pmABI,
+		eventNames: make(map[common.Hash]string, len(pmABI.Events)),
+		lookup:     lookup,
+		poolKeys:   make(map[common.Hash]v4PoolKey),
+		syms:       newV4SymbolCache(),
+		decs:       make(map[common.Address]uint8),
+	}
+	for name, ev := range pmABI.Events {
+		s.eventNames[ev.ID] = name
+	}
+	return s, nil
+}
+
+func (s *explorerScan) explore(q ExplorerQuery) ([]*ExplorerNode, error) {
+	var blockNum *big.Int
+	switch {
+	case q.Tx != (common.Hash{}):
+		receipt, err := s.client.TransactionReceipt(s.ctx, q.Tx)
+		if errors.Is(err, ethereum.NotFound) {
+			if _, pending, txErr := s.client.TransactionByHash(s.ctx, q.Tx); txErr == nil && pending {
+				return nil, fmt.Errorf("transaction %s is pending, not mined yet", ShortenAddr(q.Tx.Hex()))
+			}
+			return nil, fmt.Errorf("transaction %s not found", ShortenAddr(q.Tx.Hex()))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receipt: %w", err)
+		}
+		blockNum = receipt.BlockNumber
+	case !q.Latest:
+		blockNum = new(big.Int).SetUint64(q.Block)
+	}
+	block, err := s.client.BlockByNumber(s.ctx, blockNum)
+	if err != nil {
+		if blockNum != nil {
+			return nil, fmt.Errorf("block %s: %w", blockNum, err)
+		}
+		return nil, fmt.Errorf("latest block: %w", err)
+	}
+
+	receipts, receiptsErr := s.blockReceipts(block)
+	txs, froms := s.selectTxs(block, q, receipts)
+	// Without block receipts, fetch them one by one for the selected txs.
+	if receiptsErr != nil {
+		receipts = make(map[common.Hash]*types.Receipt, len(txs))
+		for _, tx := range txs {
+			if r, err := s.client.TransactionReceipt(s.ctx, tx.Hash()); err == nil {
+				receipts[tx.Hash()] = r
+			}
+		}
+	}
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.loadPoolKeys(receipts)
+
+	roots := []*ExplorerNode{s.blockNode(block)}
+	if q.Address != (common.Address{}) {
+		roots = append(roots, s.accountNode(q.Address, block.Number()))
+	}
+
+	title := fmt.Sprintf("Transactions · %d", len(block.Transactions()))
+	switch {
+	case q.Tx != (common.Hash{}):
+		title = "Transaction"
+	case q.Address != (common.Address{}):
+		title = fmt.Sprintf("Transactions · %d of %d touching %s", len(txs), len(block.Transactions()), HyperAddr(q.Address))
+	}
+	txsNode := &ExplorerNode{Text: bsSection(title), Open: true}
+	for i, tx := range txs {
+		if err := s.ctx.Err(); err != nil {
+			return nil, err
+		}
+		n := s.txNode(block, tx, froms[i], receipts[tx.Hash()])
+		n.Open = q.Tx != (common.Hash{})
+		txsNode.Children = append(txsNode.Children, n)
+	}
+	if len(txs) == 0 {
+		txsNode.add(bsWarn.Render("no matching transactions"))
+	}
+	if receiptsErr != nil && q.Address != (common.Address{}) {
+		txsNode.add(bsMuted.Render("this node has no eth_getBlockReceipts: matched on sender and recipient only"))
+	}
+	roots = append(roots, txsNode)
+
+	// The block-wide sections are for block queries; a single transaction
+	// shows its own V4 events above.
+	if q.Tx == (common.Hash{}) {
+		pm, poolIDs := s.poolManagerNode(block.Number())
+		roots = append(roots, pm, s.positionManagerNode(block.Number()))
+		if len(poolIDs) > 0 {
+			roots = append(roots, s.poolStateNode(poolIDs))
+		}
+	}
+	return roots, s.ctx.Err()
+}
+
+// blockReceipts returns every receipt in the block by tx hash, in one
+// eth_getBlockReceipts call.
+func (s *explorerScan) blockReceipts(block *types.Block) (map[common.Hash]*types.Receipt, error) {
+	list, err := s.client.BlockReceipts(s.ctx, gethrpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[common.Hash]*types.Receipt, len(list))
+	for _, r := range list {
+		out[r.TxHash] = r
+	}
+	return out, nil
+}
+
+// selectTxs returns the block's transactions the query covers, with their
+// senders.
+func (s *explorerScan) selectTxs(block *types.Block, q ExplorerQuery, receipts map[common.Hash]*types.Receipt) ([]*types.Transaction, []common.Address) {
+	var txs []*types.Transaction
+	var froms []common.Address
+	for _, tx := range block.Transactions() {
+		from, _ := types.Sender(s.signer, tx)
+		switch {
+		case q.Tx != (common.Hash{}):
+			if tx.Hash() != q.Tx {
+				continue
+			}
+		case q.Address != (common.Address{}):
+			if !txTouches(tx, from, receipts[tx.Hash()], q.Address) {
+				continue
+			}
+		}
+		txs = append(txs, tx)
+		froms = append(froms, from)
+	}
+	return txs, froms
+}
+
+// txTouches reports whether addr sent or received the tx, was created by
+// it, emitted one of its logs, or sent or received a token in it.
+func txTouches(tx *types.Transaction, from common.Address, receipt *types.Receipt, addr common.Address) bool {
+	if from == addr || addrOrZero(tx.To()) == addr {
+		return true
+	}
+	if receipt == nil {
+		return false
+	}
+	if receipt.ContractAddress == addr {
+		return true
+	}
+	topic := common.BytesToHash(addr.Bytes())
+	for _, lg := range receipt.Logs {
+		if lg.Address == addr {
+			return true
+		}
+		if len(lg.Topics) >= 3 && lg.Topics[0] == explorerTransferSig && (lg.Topics[1] == topic || lg.Topics[2] == topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPoolKeys names the V4 pools the receipts' PoolManager events touch,
+// so event lines can show their pair.
+func (s *explorerScan) loadPoolKeys(receipts map[common.Hash]*types.Receipt) {
+	if s.lookup == nil {
+		return
+	}
+	for _, r := range receipts {
+		for _, lg := range r.Logs {
+			if lg.Address != s.addrs.V4PoolManager || len(lg.Topics) < 2 {
+				continue
+			}
+			if name := s.eventNames[lg.Topics[0]]; name == "Initialize" || name == "" {
+				continue
+			}
+			id := lg.Topics[1]
+			if _, ok := s.poolKeys[id]; ok {
+				continue
+			}
+			k, ok := s.lookup(id)
+			if !ok {
+				continue
+			}
+			s.poolKeys[id] = v4PoolKey{Currency0: k.Currency0, Currency1: k.Currency1, Fee: k.Fee, TickSpacing: k.TickSpacing, Hooks: k.Hooks}
+			s.syms.getOrFetch(s.ctx, s.client, k.Currency0)
+			s.syms.getOrFetch(s.ctx, s.client, k.Currency1)
+		}
+	}
+}
+
+func (s *explorerScan) blockNode(block *types.Block) *ExplorerNode {
+	at := time.Unix(int64(block.Time()), 0)
+	n := &ExplorerNode{Open: true, Text: bsSection("Block ") + bsHyperBlock(block.NumberU64()) + "  " +
+		bsMuted.Render(at.UTC().Format("2006-01-02 15:04:05 UTC")+" · "+explorerAge(time.Since(at))+" ago")}
+	n.field("hash", bsHyperHash(block.Hash(), "block/"))
+	n.field("parent", bsHyperHash(block.ParentHash(), "block/"))
+	n.field("miner", HyperAddr(block.Coinbase()))
+	n.field("transactions", bsValue(fmt.Sprint(len(block.Transactions()))))
+	n.field("gas used", fmt.Sprintf("%s / %s  %s", bsValue(fmt.Sprint(block.GasUsed())), bsValue(fmt.Sprint(block.GasLimit())),
+		bsMuted.Render(fmt.Sprintf("(%.1f%%)", percent(block.GasUsed(), block.GasLimit())))))
+	if baseFee := block.BaseFee(); baseFee != nil {
+		n.field("base fee", bsValue(explorerGwei(baseFee)))
+		burnt := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(block.GasUsed()))
+		n.field("burnt", bsValue(FormatETH(burnt)))
+	}
+	n.field("size", bsValue(fmt.Sprintf("%d bytes", block.Size())))
+	return n
+}
+
+func (s *explorerScan) accountNode(addr common.Address, blockNum *big.Int) *ExplorerNode {
+	n := &ExplorerNode{Open: true, Text: bsSection("Address ") + HyperAddr(addr) + "  " + bsMuted.Render(addr.Hex())}
+	if bal, err := s.client.BalanceAt(s.ctx, addr, blockNum); err == nil {
+		n.field("balance", bsValue(FormatETH(bal)))
+	} else {
+		n.field("balance", bsError.Render(err.Error()))
+	}
+	if nonce, err := s.client.NonceAt(s.ctx, addr, blockNum); err == nil {
+		n.field("nonce", bsValue(fmt.Sprint(nonce)))
+	}
+	if code, err := s.client.CodeAt(s.ctx, addr, blockNum); err == nil {
+		kind := "account (no code)"
+		if len(code) > 0 {
+			kind = fmt.Sprintf("contract (%d bytes of code)", len(code))
+		}
+		n.field("type", bsValue(kind))
+	}
+	return n
+}
+
+func (s *explorerScan) txNode(block *types.Block, tx *types.Transaction, from common.Address, receipt *types.Receipt) *ExplorerNode {
+	to := bsAccent2.Render("create")
+	if tx.To() != nil {
+		to = HyperAddr(*tx.To())
+	}
+	status := bsMuted.Render("?")
+	if receipt != nil {
+		status = bsAccent.Render("✓")
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			status = bsError.Render("✗")
+		}
+	}
+	text := fmt.Sprintf("%s %s  %s  %s → %s", status, bsMuted.Render(fmt.Sprintf("#%d", explorerTxIndex(block, tx, receipt))),
+		HyperTxHash(tx.Hash()), HyperAddr(from), to)
+	if tx.Value().Sign() > 0 {
+		text += "  " + bsValue(FormatETH(tx.Value()))
+	}
+	if len(tx.Data()) >= 4 {
+		text += "  " + bsMuted.Render("0x"+hex.EncodeToString(tx.Data()[:4]))
+	}
+	n := &ExplorerNode{Text: text, Tx: tx.Hash()}
+
+	n.field("hash", bsMuted.Render(tx.Hash().Hex()))
+	n.field("from", HyperAddr(from)+"  "+bsMuted.Render(from.Hex()))
+	if tx.To() != nil {
+		n.field("to", HyperAddr(*tx.To())+"  "+bsMuted.Render(tx.To().Hex()))
+	}
+	n.field("value", bsValue(FormatETH(tx.Value())))
+	n.field("nonce", bsValue(fmt.Sprint(tx.Nonce())))
+	if receipt == nil {
+		n.field("status", bsWarn.Render("receipt unavailable"))
+	} else if receipt.Status == types.ReceiptStatusSuccessful {
+		n.field("status", bsAccent.Render("SUCCESS"))
+	} else {
+		n.field("status", bsError.Render("FAILED"))
+		reason := rpc.RevertReason(s.ctx, s.client, tx, block.Number())
+		if reason == "" {
+			reason = "no reason returned"
+		}
+		n.field("revert", bsError.Render(reason))
+	}
+	if receipt != nil && receipt.ContractAddress != (common.Address{}) {
+		n.field("created", HyperAddr(receipt.ContractAddress))
+	}
+	n.Children = append(n.Children, s.gasNode(block.BaseFee(), tx, receipt), inputNode(tx.Data()))
+	if receipt == nil {
+		return n
+	}
+
+	transfers := &ExplorerNode{}
+	v4 := &ExplorerNode{}
+	logs := &ExplorerNode{}
+	for _, lg := range receipt.Logs {
+		if line := s.transferLine(lg); line != "" {
+			transfers.add(line)
+		}
+		if line := s.v4Line(lg); line != "" {
+			v4.add(line)
+		}
+		logs.Children = append(logs.Children, logNode(lg))
+	}
+	for _, sec := range []struct {
+		title string
+		node  *ExplorerNode
+	}{{"token transfers", transfers}, {"uniswap v4", v4}, {"logs", logs}} {
+		if len(sec.node.Children) == 0 {
+			continue
+		}
+		sec.node.Text = bsLabel(fmt.Sprintf("%s (%d)", sec.title, len(sec.node.Children)))
+		sec.node.Open = sec.node != logs
+		n.Children = append(n.Children, sec.node)
+	}
+	return n
+}
+
+// explorerTxIndex is the tx's position in its block.
+func explorerTxIndex(block *types.Block, tx *types.Transaction, receipt *types.Receipt) uint {
+	if receipt != nil {
+		return receipt.TransactionIndex
+	}
+	for i, t := range block.Transactions() {
+		if t.Hash() == tx.Hash() {
+			return uint(i)
+		}
+	}
+	return 0
+}
+
+// gasNode details the tx's gas; baseFee is its block's, nil before London.
+func (s *explorerScan) gasNode(baseFee *big.Int, tx *types.Transaction, receipt *types.Receipt) *ExplorerNode {
+	n := &ExplorerNode{}
+	n.field("type", bsValue(fmt.Sprint(tx.Type())))
+	n.field("gas limit", bsValue(fmt.Sprint(tx.Gas())))
+	summary := fmt.Sprintf("limit %d", tx.Gas())
+	if receipt != nil {
+		n.field("gas used", fmt.Sprintf("%s  %s", bsValue(fmt.Sprint(receipt.GasUsed)),
+			bsMuted.Render(fmt.Sprintf("(%.1f%% of limit)", percent(receipt.GasUsed, tx.Gas())))))
+		summary = fmt.Sprintf("used %d of %d", receipt.GasUsed, tx.Gas())
+		if price := receipt.EffectiveGasPrice; price != nil {
+			fee := new(big.Int).Mul(price, new(big.Int).SetUint64(receipt.GasUsed))
+			n.field("price", bsValue(explorerGwei(price)))
+			n.field("fee", bsValue(FormatETH(fee)))
+			summary += " · " + FormatETH(fee)
+			if baseFee != nil && price.Cmp(baseFee) >= 0 {
+				n.field("priority", bsValue(explorerGwei(new(big.Int).Sub(price, baseFee))))
+			}
+		}
+	}
+	if tx.Type() >= types.DynamicFeeTxType {
+		n.field("max fee", bsValue(explorerGwei(tx.GasFeeCap())))
+		n.field("max tip", bsValue(explorerGwei(tx.GasTipCap())))
+	} else {
+		n.field("gas price", bsValue(explorerGwei(tx.GasPrice())))
+	}
+	n.Text = bsLabel("gas") + "  " + bsMuted.Render(summary)
+	return n
+}
+
+// inputNode lists calldata as its selector and 32-byte words.
+func inputNode(data []byte) *ExplorerNode {
+	n := &ExplorerNode{Text: bsLabel("input") + "  " + bsMuted.Render(fmt.Sprintf("%d bytes", len(data)))}
+	if len(data) == 0 {
+		return n
+	}
+	rest := data
+	if len(data) >= 4 {
+		n.field("selector", FadeString("0x"+hex.EncodeToString(data[:4]), "#874BFD", "#79C0FF"))
+		rest = data[4:]
+	}
+	for i := 0; i < len(rest); i += 32 {
+		word := rest[i:min(i+32, len(rest))]
+		n.add(bsMuted.Render(fmt.Sprintf("[%d] ", i/32)) + bsValue(hex.EncodeToString(word)))
+	}
+	return n
+}
+
+// logNode shows a raw log: emitter, topics and data words.
+func logNode(lg *types.Log) *ExplorerNode {
+	text := fmt.Sprintf("%s  %s", bsMuted.Render(fmt.Sprintf("#%d", lg.Index)), HyperAddr(lg.Address))
+	if len(lg.Topics) > 0 {
+		text += "  " + bsMuted.Render(ShortenAddr(lg.Topics[0].Hex()))
+	}
+	n := &ExplorerNode{Text: text}
+	n.field("emitter", bsMuted.Render(lg.Address.Hex()))
+	for i, t := range lg.Topics {
+		n.field(fmt.Sprintf("topic[%d]", i), bsMuted.Render(t.Hex()))
+	}
+	for i := 0; i < len(lg.Data); i += 32 {
+		n.field(fmt.Sprintf("data[%d]", i/32), bsMuted.Render(hex.EncodeToString(lg.Data[i:min(i+32, len(lg.Data))])))
+	}
+	return n
+}
+
+// transferLine decodes an ERC-20 or ERC-721 Transfer, or returns "".
+func (s *explorerScan) transferLine(lg *types.Log) string {
+	if len(lg.Topics) == 0 || lg.Topics[0] != explorerTransferSig {
+		return ""
+	}
+	switch {
+	case len(lg.Topics) == 4:
+		return decodeERC721Line(lg)
+	case len(lg.Topics) == 3 && len(lg.Data) == 32:
+		from := common.BytesToAddress(lg.Topics[1].Bytes())
+		to := common.BytesToAddress(lg.Topics[2].Bytes())
+		symbol := s.syms.getOrFetch(s.ctx, s.client, lg.Address)
+		if symbol == "" {
+			symbol = "?"
+		}
+		amount := FormatToken(new(big.Int).SetBytes(lg.Data), s.decimals(lg.Address), symbol)
+		return fmt.Sprintf("[ERC-20 %s]  %s  %s → %s  %s", bsAccent.Render("Transfer"), bsValue(amount),
+			HyperAddr(from), HyperAddr(to), bsMuted.Render("token="+ShortenAddr(lg.Address.Hex())))
+	}
+	return ""
+}
+
+func (s *explorerScan) decimals(token common.Address) uint8 {
+	if d, ok := s.decs[token]; ok {
+		return d
+	}
+	d := v4ERC20Decimals(s.ctx, s.client, token)
+	s.decs[token] = d
+	return d
+}
+
+// v4Line decodes a PoolManager event or PositionManager IncreaseLiquidity,
+// or returns "".
+func (s *explorerScan) v4Line(lg *types.Log) string {
+	switch {
+	case lg.Address == s.addrs.V4PoolManager:
+		line, err := v4FormatLog(&s.pmABI, *lg, s.eventNames, &s.mu, s.poolKeys, s.ctx, s.client, s.syms)
+		if err != nil {
+			return bsError.Render("[V4 err] ") + err.Error()
+		}
+		return line
+	case lg.Address == s.addrs.V4PositionManager && len(lg.Topics) > 0 && lg.Topics[0] == explorerIncLiqSig:
+		return decodeIncLiqLine(lg)
+	}
+	return ""
+}
+
+// poolManagerNode lists every PoolManager event in the block, from any
+// sender, and returns the pools initialized in it.
+func (s *explorerScan) poolManagerNode(blockNum *big.Int) (*ExplorerNode, []common.Hash) {
+	n := &ExplorerNode{Open: true}
+	sigs := make([]common.Hash, 0, len(s.pmABI.Events))
+	for _, ev := range s.pmABI.Events {
+		sigs = append(sigs, ev.ID)
+	}
+	logs, err := s.client.FilterLogs(s.ctx, ethereum.FilterQuery{
+		FromBlock: blockNum,
+		ToBlock:   blockNum,
+		Addresses: []common.Address{s.addrs.V4PoolManager},
+		Topics:    [][]common.Hash{sigs},
+	})
+	n.Text = bsSection(fmt.Sprintf("Uniswap V4 PoolManager · %d events", len(logs))) + "  " + HyperAddr(s.addrs.V4PoolManager)
+	if err != nil {
+		n.add(bsError.Render("FilterLogs: ") + bsMuted.Render(err.Error()))
+		return n, nil
+	}
+	var poolIDs []common.Hash
+	for _, lg := range logs {
+		line := s.v4Line(&lg)
+		if line == "" {
+			continue
+		}
+		n.add(line + "  " + HyperTxHash(lg.TxHash))
+		if len(lg.Topics) > 1 && s.eventNames[lg.Topics[0]] == "Initialize" {
+			poolIDs = appendUniq(poolIDs, lg.Topics[1])
+		}
+	}
+	return n, poolIDs
+}
+
+// positionManagerNode lists the block's position NFT transfers and
+// liquidity increases.
+func (s *explorerScan) positionManagerNode(blockNum *big.Int) *ExplorerNode {
+	n := &ExplorerNode{Open: true}
+	logs, err := s.client.FilterLogs(s.ctx, ethereum.FilterQuery{
+		FromBlock: blockNum,
+		ToBlock:   blockNum,
+		Addresses: []common.Address{s.addrs.V4PositionManager},
+		Topics:    [][]common.Hash{{explorerTransferSig, explorerIncLiqSig}},
+	})
+	n.Text = bsSection(fmt.Sprintf("Uniswap V4 PositionManager · %d events", len(logs))) + "  " + HyperAddr(s.addrs.V4PositionManager)
+	if err != nil {
+		n.add(bsError.Render("FilterLogs: ") + bsMuted.Render(err.Error()))
+		return n
+	}
+	for _, lg := range logs {
+		line := decodeERC721Line(&lg)
+		if lg.Topics[0] == explorerIncLiqSig {
+			line = decodeIncLiqLine(&lg)
+		}
+		n.add(line + "  " + HyperTxHash(lg.TxHash))
+	}
+	return n
+}
+
+// poolStateNode reads the live state and key of the pools created in the
+// block.
+func (s *explorerScan) poolStateNode(poolIDs []common.Hash) *ExplorerNode {
+	n := &ExplorerNode{Open: true, Text: bsSection(fmt.Sprintf("Live Pool State · %d pool(s)", len(poolIDs)))}
+	for _, id := range poolIDs {
+		p := n.add(HyperPoolID(id) + "  " + bsMuted.Render(id.Hex()))
+		p.Open = true
+		if info, err := FetchPoolInfo(s.rpcURL, id); err != nil {
+			p.add(bsError.Render("FetchPoolInfo: ") + bsMuted.Render(err.Error()))
+		} else {
+			p.field("sqrtPriceX96", bsValue(info.SqrtPriceX96))
+			p.field("tick", bsValue(fmt.Sprint(info.Tick)))
+			p.field("protocolFee", bsValue(fmt.Sprint(info.ProtocolFee)))
+			p.field("lpFee", bsValue(fmt.Sprint(info.LpFee)))
+			p.field("liquidity", bsValue(info.Liquidity))
+		}
+		key, err := FetchPoolKey(s.rpcURL, id)
+		if err != nil {
+			p.add(bsError.Render("FetchPoolKey: ") + bsMuted.Render(err.Error()))
+			continue
+		}
+		currency := func(c string) string {
+			if c == "NATIVE" {
+				return bsAccent.Render("NATIVE (ETH)")
+			}
+			return HyperAddr(common.HexToAddress(c))
+		}
+		p.field("currency0", currency(key.Currency0))
+		p.field("currency1", currency(key.Currency1))
+		p.field("fee", bsValue(fmt.Sprint(key.Fee))+"  "+bsMuted.Render(fmt.Sprintf("(%.4f%%)", float64(key.Fee)/1e4)))
+		p.field("tickSpacing", bsValue(fmt.Sprint(key.TickSpacing)))
+		p.field("hooks", HyperAddr(common.HexToAddress(key.Hooks)))
+	}
+	return n
+}
+
+func percent(part, whole uint64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
+
+// explorerGwei formats wei as gwei.
+func explorerGwei(wei *big.Int) string {
+	if wei == nil {
+		return "0 gwei"
+	}
+	g, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9)).Float64()
+	return strconv.FormatFloat(g, 'f', -1, 64) + " gwei"
+}
+
+// explorerAge formats how long ago a block was mined.
+func explorerAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
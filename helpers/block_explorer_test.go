@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseExplorerQuery(t *testing.T) {
+	addr := "0x5857bCe5490545a89598b9992DD0D409C4C20d86"
+	hash := "0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"
+	cases := []struct {
+		in   string
+		want ExplorerQuery
+	}{
+		{"24686488", ExplorerQuery{Block: 24686488}},
+		{"#24,686,488", ExplorerQuery{Block: 24686488}},
+		{"latest", ExplorerQuery{Latest: true}},
+		{hash, ExplorerQuery{Tx: common.HexToHash(hash)}},
+		{addr, ExplorerQuery{Latest: true, Address: common.HexToAddress(addr)}},
+		{addr + "  24686488", ExplorerQuery{Block: 24686488, Address: common.HexToAddress(addr)}},
+	}
+	for _, c := range cases {
+		got, err := ParseExplorerQuery(c.in)
+		if err != nil || got != c.want {
+			t.Errorf("ParseExplorerQuery(%q) = %+v, %v; want %+v", c.in, got, err, c.want)
+		}
+	}
+
+	for _, bad := range []string{"", "vitalik.eth", "1 2", "latest 5", hash + " 1", hash + " " + addr, addr + " " + addr, "0x1234"} {
+		if _, err := ParseExplorerQuery(bad); err == nil {
+			t.Errorf("ParseExplorerQuery(%q) accepted", bad)
+		}
+	}
+}
+
+func TestTxTouches(t *testing.T) {
+	me := common.HexToAddress("0x1")
+	other := common.HexToAddress("0x2")
+	token := common.HexToAddress("0x3")
+	tx := types.NewTx(&types.LegacyTx{To: &other, Value: big.NewInt(0)})
+
+	if !txTouches(tx, me, nil, me) {
+		t.Error("sender not matched")
+	}
+	if !txTouches(tx, other, nil, other) || txTouches(tx, other, nil, me) {
+		t.Error("recipient match wrong")
+	}
+	receipt := &types.Receipt{Logs: []*types.Log{{
+		Address: token,
+		Topics:  []common.Hash{explorerTransferSig, common.BytesToHash(other.Bytes()), common.BytesToHash(me.Bytes())},
+	}}}
+	if !txTouches(tx, other, receipt, me) {
+		t.Error("token recipient not matched")
+	}
+	if !txTouches(tx, other, receipt, token) {
+		t.Error("log emitter not matched")
+	}
+}
+
+func TestInputNode(t *testing.T) {
+	data := append([]byte{0xa9, 0x05, 0x9c, 0xbb}, make([]byte, 40)...)
+	n := inputNode(data)
+	// selector, one full word and one partial word
+	if len(n.Children) != 3 {
+		t.Fatalf("input node has %d children, want 3", len(n.Children))
+	}
+	if n := inputNode(nil); len(n.Children) != 0 {
+		t.Errorf("empty input has %d children", len(n.Children))
+	}
+}
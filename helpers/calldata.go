@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ABIEncodeUint256 ABI-encodes a *big.Int as a 32-byte uint256.
+func ABIEncodeUint256(v *big.Int) []byte {
+	b := make([]byte, 32)
+	copy(b[32-len(v.Bytes()):], v.Bytes())
+	return b
+}
+
+// ABIEncodeAddress ABI-encodes a common.Address as a 32-byte padded value.
+func ABIEncodeAddress(addr common.Address) []byte {
+	b := make([]byte, 32)
+	copy(b[12:], addr[:])
+	return b
+}
+
+// BuildApproveCalldata ABI-encodes approve(spender, amount) for an ERC-20 token.
+// Selector 0x095ea7b3: approve(address,uint256)
+func BuildApproveCalldata(spender common.Address, amount *big.Int) []byte {
+	var d []byte
+	d = append(d, 0x09, 0x5e, 0xa7, 0xb3)
+	d = append(d, ABIEncodeAddress(spender)...)
+	d = append(d, ABIEncodeUint256(amount)...)
+	return d
+}
+
+// BuildTransferCalldata ABI-encodes transfer(to, amount) for an ERC-20 token.
+// Selector 0xa9059cbb: transfer(address,uint256)
+func BuildTransferCalldata(to common.Address, amount *big.Int) []byte {
+	var d []byte
+	d = append(d, 0xa9, 0x05, 0x9c, 0xbb)
+	d = append(d, ABIEncodeAddress(to)...)
+	d = append(d, ABIEncodeUint256(amount)...)
+	return d
+}
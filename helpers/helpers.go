@@ -64,8 +64,18 @@ func ShortenAddr(addr string) string {
 }
 
 // HyperAddr returns a FadeString-coloured shortened address hyperlinked to its Etherscan page.
+// Addresses the user labelled (see SetAddressLabels) show the label instead;
+// an ENS name (see SetENSLabels) is shown alongside the address, not in its place.
 func HyperAddr(a common.Address) string {
-	display := FadeString(ShortenAddr(a.Hex()), "#F25D94", "#EDFF82")
+	text := ShortenAddr(a.Hex())
+	if l, fromENS, ok := addressLabel(a); ok {
+		if fromENS {
+			text = l + " " + text
+		} else {
+			text = l
+		}
+	}
+	display := FadeString(text, "#F25D94", "#EDFF82")
 	return ansi.SetHyperlink("https://etherscan.io/address/"+a.Hex()) + display + ansi.ResetHyperlink()
 }
 
@@ -110,6 +120,34 @@ func FormatToken(balance *big.Int, decimals uint8, symbol string) string {
 	return amount.Text('f', 4) + " " + symbol
 }
 
+// ParseTokenAmount converts a human decimal amount (e.g. "12.5") into the
+// token's base units, exactly — amounts with more fractional digits than
+// decimals allows are rejected rather than silently rounded.
+func ParseTokenAmount(s string, decimals uint8) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("amount is required")
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > int(decimals) {
+		return nil, fmt.Errorf("at most %d decimal places", decimals)
+	}
+	digits := whole + frac + strings.Repeat("0", int(decimals)-len(frac))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid amount")
+		}
+	}
+	v, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount")
+	}
+	return v, nil
+}
+
 // LoadedAt formats the loaded timestamp
 func LoadedAt(t time.Time, loading bool) string {
 	if loading {
@@ -0,0 +1,40 @@
+package helpers
+
+import "testing"
+
+func TestParseTokenAmount(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals uint8
+		want     string
+		wantErr  bool
+	}{
+		{"1", 6, "1000000", false},
+		{"12.5", 6, "12500000", false},
+		{".25", 18, "250000000000000000", false},
+		{"0.000001", 6, "1", false},
+		{"  3.0  ", 0, "", true},
+		{"3", 0, "3", false},
+		{"0.0000001", 6, "", true},
+		{"", 6, "", true},
+		{"1e5", 6, "", true},
+		{"-1", 6, "", true},
+		{"1.2.3", 6, "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTokenAmount(tt.in, tt.decimals)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTokenAmount(%q, %d) = %s, want error", tt.in, tt.decimals, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTokenAmount(%q, %d) error: %v", tt.in, tt.decimals, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseTokenAmount(%q, %d) = %s, want %s", tt.in, tt.decimals, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// addressLabels is the process-wide address -> label table behind
+// HyperAddr and LabelAddr. The model rebuilds it from the address book,
+// wallet nicknames and cached ENS names whenever any of those change;
+// indexer and listener goroutines only read it.
+//
+// ENS names live in their own table: a primary name is whatever the
+// address's owner chose to claim, so it is only shown next to the address,
+// never in place of it, and a label the user set always wins.
+var (
+	addressLabelsMu sync.RWMutex
+	addressLabels   = map[common.Address]string{}
+	ensLabels       = map[common.Address]string{}
+)
+
+// SetAddressLabels replaces the table of labels the user set.
+func SetAddressLabels(labels map[common.Address]string) {
+	next := nonEmptyLabels(labels)
+	addressLabelsMu.Lock()
+	addressLabels = next
+	addressLabelsMu.Unlock()
+}
+
+// SetENSLabels replaces the table of forward-verified reverse-ENS names.
+func SetENSLabels(names map[common.Address]string) {
+	next := nonEmptyLabels(names)
+	addressLabelsMu.Lock()
+	ensLabels = next
+	addressLabelsMu.Unlock()
+}
+
+func nonEmptyLabels(labels map[common.Address]string) map[common.Address]string {
+	next := make(map[common.Address]string, len(labels))
+	for a, l := range labels {
+		if l != "" {
+			next[a] = l
+		}
+	}
+	return next
+}
+
+// AddressLabel returns the label for a, if one is known: the user's own
+// label, or else its ENS name.
+func AddressLabel(a common.Address) (string, bool) {
+	l, _, ok := addressLabel(a)
+	return l, ok
+}
+
+// addressLabel is AddressLabel, also reporting whether the label is an ENS
+// name rather than one the user set.
+func addressLabel(a common.Address) (label string, fromENS, ok bool) {
+	addressLabelsMu.RLock()
+	defer addressLabelsMu.RUnlock()
+	if l, ok := addressLabels[a]; ok {
+		return l, false, true
+	}
+	if l, ok := ensLabels[a]; ok {
+		return l, true, true
+	}
+	return "", false, false
+}
+
+// LabelAddr returns "label (0x1234…abcd)" for a labelled address and the
+// plain shortened address otherwise. Non-address input is returned as-is.
+func LabelAddr(addr string) string {
+	if !common.IsHexAddress(addr) {
+		return addr
+	}
+	short := ShortenAddr(common.HexToAddress(addr).Hex())
+	if l, ok := AddressLabel(common.HexToAddress(addr)); ok {
+		return l + " (" + short + ")"
+	}
+	return short
+}
+
+// AddrWithLabel returns the full address followed by its label in
+// parentheses, for previews where the complete address must stay visible.
+func AddrWithLabel(addr string) string {
+	if !common.IsHexAddress(addr) {
+		return addr
+	}
+	if l, ok := AddressLabel(common.HexToAddress(addr)); ok {
+		return addr + " (" + l + ")"
+	}
+	return addr
+}
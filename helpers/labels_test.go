@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLabelAddr(t *testing.T) {
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	SetAddressLabels(map[common.Address]string{alice: "alice", bob: ""})
+	defer SetAddressLabels(nil)
+
+	if got, want := LabelAddr(alice.Hex()), "alice (0x0000…11cE)"; got != want {
+		t.Errorf("LabelAddr(alice) = %q, want %q", got, want)
+	}
+	if got, want := LabelAddr("0x00000000000000000000000000000000000a11ce"), "alice (0x0000…11cE)"; got != want {
+		t.Errorf("LabelAddr(lowercase alice) = %q, want %q", got, want)
+	}
+	if got, want := LabelAddr(bob.Hex()), "0x0000…0B0b"; got != want {
+		t.Errorf("LabelAddr(bob) = %q, want %q (empty labels are dropped)", got, want)
+	}
+	if got := LabelAddr("vitalik.eth"); got != "vitalik.eth" {
+		t.Errorf("LabelAddr(non-address) = %q, want it unchanged", got)
+	}
+}
+
+func TestENSLabelsKeepAddress(t *testing.T) {
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	carol := common.HexToAddress("0x00000000000000000000000000000000000ca401")
+	SetAddressLabels(map[common.Address]string{alice: "alice"})
+	SetENSLabels(map[common.Address]string{alice: "alice.eth", carol: "vitalik.eth"})
+	defer SetAddressLabels(nil)
+	defer SetENSLabels(nil)
+
+	if got, want := LabelAddr(alice.Hex()), "alice (0x0000…11cE)"; got != want {
+		t.Errorf("LabelAddr(alice) = %q, want %q (user labels win over ENS)", got, want)
+	}
+	if got, want := LabelAddr(carol.Hex()), "vitalik.eth (0x0000…a401)"; got != want {
+		t.Errorf("LabelAddr(carol) = %q, want %q", got, want)
+	}
+	if _, fromENS, ok := addressLabel(carol); !ok || !fromENS {
+		t.Errorf("addressLabel(carol) fromENS = %v, ok = %v; want both true", fromENS, ok)
+	}
+	if got := ansi.Strip(HyperAddr(carol)); !strings.Contains(got, "vitalik.eth") || !strings.Contains(got, "0x0000…a401") {
+		t.Errorf("HyperAddr(carol) = %q, want the ENS name next to the address", got)
+	}
+	if got := ansi.Strip(HyperAddr(alice)); strings.Contains(got, "0x") {
+		t.Errorf("HyperAddr(alice) = %q, want the user label alone", got)
+	}
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import "sort"
+
+// NextFreeNonce returns the lowest nonce at or above the chain's pending
+// nonce that no local reservation holds. Gaps left by discarded packages are
+// filled first, so a discarded tx never strands the ones queued after it.
+func NextFreeNonce(pending uint64, reserved []uint64) uint64 {
+	taken := make(map[uint64]bool, len(reserved))
+	for _, n := range reserved {
+		taken[n] = true
+	}
+	n := pending
+	for taken[n] {
+		n++
+	}
+	return n
+}
+
+// NonceGaps returns every nonce between pending and the highest reserved
+// nonce that nothing holds. Any reserved tx above a gap cannot be mined
+// until a tx at the gap's nonce is broadcast.
+func NonceGaps(pending uint64, reserved []uint64) []uint64 {
+	if len(reserved) == 0 {
+		return nil
+	}
+	sorted := append([]uint64(nil), reserved...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var gaps []uint64
+	next := pending
+	for _, n := range sorted {
+		if n < next {
+			continue
+		}
+		for ; next < n; next++ {
+			gaps = append(gaps, next)
+		}
+		next = n + 1
+	}
+	return gaps
+}
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNextFreeNonce(t *testing.T) {
+	cases := []struct {
+		name     string
+		pending  uint64
+		reserved []uint64
+		want     uint64
+	}{
+		{"nothing reserved", 7, nil, 7},
+		{"consecutive", 7, []uint64{7, 8}, 9},
+		{"fills gap", 7, []uint64{8, 9}, 7},
+		{"fills middle gap", 7, []uint64{7, 9}, 8},
+		{"ignores below pending", 7, []uint64{3, 4}, 7},
+	}
+	for _, tc := range cases {
+		if got := NextFreeNonce(tc.pending, tc.reserved); got != tc.want {
+			t.Errorf("%s: NextFreeNonce(%d, %v) = %d, want %d", tc.name, tc.pending, tc.reserved, got, tc.want)
+		}
+	}
+}
+
+func TestNonceGaps(t *testing.T) {
+	cases := []struct {
+		name     string
+		pending  uint64
+		reserved []uint64
+		want     []uint64
+	}{
+		{"empty", 5, nil, nil},
+		{"contiguous", 5, []uint64{5, 6, 7}, nil},
+		{"leading gap", 5, []uint64{7}, []uint64{5, 6}},
+		{"middle gap unsorted", 5, []uint64{8, 5, 6}, []uint64{7}},
+		{"stale below pending", 5, []uint64{2, 5}, nil},
+	}
+	for _, tc := range cases {
+		if got := NonceGaps(tc.pending, tc.reserved); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: NonceGaps(%d, %v) = %v, want %v", tc.name, tc.pending, tc.reserved, got, tc.want)
+		}
+	}
+}
@@ -78,3 +78,16 @@ func ResolveOndoV4Pool(tokenA, tokenB common.Address) (OndoPoolEntry, bool) {
 	}
 	return OndoPoolEntry{}, false
 }
+
+// ResolveOndoV4Pools returns every vendored pool entry pairing tokenA and
+// tokenB (in either order): one pair can have several pool keys, differing
+// in fee, tick spacing or hook.
+func ResolveOndoV4Pools(tokenA, tokenB common.Address) []OndoPoolEntry {
+	var out []OndoPoolEntry
+	for _, p := range OndoV4Pools {
+		if (p.Currency0 == tokenA && p.Currency1 == tokenB) || (p.Currency0 == tokenB && p.Currency1 == tokenA) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
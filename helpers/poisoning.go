@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LookalikeMinMatch is how many leading and trailing hex digits an address
+// must share with a known counterparty to count as a lookalike. Poisoning
+// addresses are ground to match what wallets show of an address — its first
+// and last few characters — so 3 each side catches them while a chance match
+// between two unrelated addresses stays around one in 17 million.
+const LookalikeMinMatch = 3
+
+// Lookalike reports the known address that addr imitates: one that shares
+// at least LookalikeMinMatch leading and trailing hex digits with addr
+// (case-insensitively) but differs in the middle. When several match, the one
+// sharing the most characters wins. An exact match is never a lookalike.
+func Lookalike(addr common.Address, known []common.Address) (common.Address, bool) {
+	a := strings.ToLower(addr.Hex()[2:])
+	var (
+		best      common.Address
+		bestScore int
+	)
+	for _, k := range known {
+		if k == addr {
+			continue
+		}
+		b := strings.ToLower(k.Hex()[2:])
+		prefix := commonPrefixLen(a, b)
+		suffix := commonPrefixLen(reverse(a), reverse(b))
+		if prefix < LookalikeMinMatch || suffix < LookalikeMinMatch {
+			continue
+		}
+		if score := prefix + suffix; score > bestScore {
+			best, bestScore = k, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
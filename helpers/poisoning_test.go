@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLookalike(t *testing.T) {
+	payee := common.HexToAddress("0x1234a000000000000000000000000000000b5678")
+	other := common.HexToAddress("0x9999000000000000000000000000000000000001")
+	known := []common.Address{payee, other}
+
+	tests := []struct {
+		name string
+		addr string
+		want common.Address
+		ok   bool
+	}{
+		{"poisoned: same ends, different middle", "0x1234affffffffffffffffffffffffffffffb5678", payee, true},
+		{"exact match is not a lookalike", payee.Hex(), common.Address{}, false},
+		{"prefix only", "0x1234afffffffffffffffffffffffffffffffffff", common.Address{}, false},
+		{"suffix only", "0xfffffffffffffffffffffffffffffffffffb5678", common.Address{}, false},
+		{"too short a match", "0x12ffffffffffffffffffffffffffffffffffff78", common.Address{}, false},
+		{"unrelated", "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd", common.Address{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Lookalike(common.HexToAddress(tt.addr), known)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Lookalike(%s) = %s, %v; want %s, %v", tt.addr, got.Hex(), ok, tt.want.Hex(), tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,518 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/store"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ---- Transaction inspector ----
+//
+// The Transaction Inspector decodes one transaction in depth into an
+// ExplorerNode tree: a summary with the revert reason, the full receipt,
+// the decoded input, each address's net ETH and token balance change, every
+// log decoded with the ABIs the app knows, and the internal call tree when
+// the node serves debug_traceTransaction.
+
+// ParseTxHash parses the inspector's input, a 0x-prefixed 32-byte hash.
+func ParseTxHash(s string) (common.Hash, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return common.Hash{}, fmt.Errorf("enter a transaction hash")
+	}
+	if !isHexHash(s) {
+		return common.Hash{}, fmt.Errorf("%q: not a transaction hash (0x + 64 hex digits)", s)
+	}
+	return common.HexToHash(s), nil
+}
+
+// TxInspector inspects transactions. PoolLookup names V4 pools as in
+// BlockExplorer; ABIs are the Contract page's saved ABIs, used to decode
+// calls and logs of the contracts they were opened against, and any other
+// that matches.
+type TxInspector struct {
+	PoolLookup func(common.Hash) (V4PoolKey, bool)
+	ABIs       []store.SavedABI
+}
+
+// traceCall mirrors one frame of geth's "callTracer" output.
+type traceCall struct {
+	Type         string         `json:"type"`
+	From         common.Address `json:"from"`
+	To           common.Address `json:"to"`
+	Value        *hexutil.Big   `json:"value"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Input        hexutil.Bytes  `json:"input"`
+	Output       hexutil.Bytes  `json:"output"`
+	Error        string         `json:"error"`
+	RevertReason string         `json:"revertReason"`
+	Calls        []traceCall    `json:"calls"`
+}
+
+// movesValue reports whether the frame transfers its value; DELEGATECALL
+// and CALLCODE frames repeat their caller's value without moving it.
+func (f *traceCall) movesValue() bool {
+	switch f.Type {
+	case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+		return f.Value != nil && f.Value.ToInt().Sign() > 0
+	}
+	return false
+}
+
+var (
+	wethDepositSig    = crypto.Keccak256Hash([]byte("Deposit(address,uint256)"))
+	wethWithdrawalSig = crypto.Keccak256Hash([]byte("Withdrawal(address,uint256)"))
+)
+
+// txInspection is one Inspect call's state.
+type txInspection struct {
+	*explorerScan
+	dec      *abiDecoder
+	tx       *types.Transaction
+	from     common.Address
+	receipt  *types.Receipt
+	header   *types.Header
+	trace    *traceCall // nil when the node has no debug_traceTransaction
+	traceErr error
+}
+
+// Inspect decodes the transaction into a tree of top-level sections.
+func (t TxInspector) Inspect(ctx context.Context, rpcURL string, hash common.Hash) ([]*ExplorerNode, error) {
+	s, err := newExplorerScan(ctx, rpcURL, t.PoolLookup)
+	if err != nil {
+		return nil, err
+	}
+	defer s.client.Close()
+
+	tx, pending, err := s.client.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("transaction %s not found", ShortenAddr(hash.Hex()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("transaction: %w", err)
+	}
+	if pending {
+		return nil, fmt.Errorf("transaction %s is pending, not mined yet", ShortenAddr(hash.Hex()))
+	}
+	receipt, err := s.client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("receipt: %w", err)
+	}
+	header, err := s.client.HeaderByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("block %s: %w", receipt.BlockNumber, err)
+	}
+	from, err := s.client.TransactionSender(ctx, tx, receipt.BlockHash, receipt.TransactionIndex)
+	if err != nil {
+		from, _ = types.Sender(s.signer, tx)
+	}
+	s.loadPoolKeys(map[common.Hash]*types.Receipt{hash: receipt})
+
+	in := &txInspection{
+		explorerScan: s,
+		dec:          newABIDecoder(t.ABIs),
+		tx:           tx,
+		from:         from,
+		receipt:      receipt,
+		header:       header,
+	}
+	var trace traceCall
+	if err := s.client.Client().CallContext(ctx, &trace, "debug_traceTransaction", hash, map[string]string{"tracer": "callTracer"}); err != nil {
+		in.traceErr = err
+	} else {
+		in.trace = &trace
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return []*ExplorerNode{
+		in.summaryNode(),
+		in.receiptNode(),
+		in.inputNode(),
+		in.balanceNode(),
+		in.logsNode(),
+		in.traceNode(),
+	}, ctx.Err()
+}
+
+func (in *txInspection) summaryNode() *ExplorerNode {
+	tx, r := in.tx, in.receipt
+	ok := r.Status == types.ReceiptStatusSuccessful
+	status := bsAccent.Render("✓ SUCCESS")
+	if !ok {
+		status = bsError.Render("✗ FAILED")
+	}
+	n := &ExplorerNode{Open: true, Tx: tx.Hash(), Text: bsSection("Transaction ") + HyperTxHash(tx.Hash()) + "  " + status}
+	n.field("hash", bsMuted.Render(tx.Hash().Hex()))
+	n.field("status", status)
+	if !ok {
+		n.field("revert", bsError.Render(in.revertReason()))
+	}
+	at := time.Unix(int64(in.header.Time), 0)
+	n.field("block", bsHyperBlock(r.BlockNumber.Uint64())+"  "+
+		bsMuted.Render(fmt.Sprintf("#%d in block · %s · %s ago", r.TransactionIndex, at.UTC().Format("2006-01-02 15:04:05 UTC"), explorerAge(time.Since(at)))))
+	n.field("from", HyperAddr(in.from)+"  "+bsMuted.Render(in.from.Hex()))
+	if to := tx.To(); to != nil {
+		text := HyperAddr(*to) + "  " + bsMuted.Render(to.Hex())
+		if name := in.dec.contractName(*to); name != "" {
+			text += "  " + bsAccent2.Render(name)
+		}
+		n.field("to", text)
+	}
+	if r.ContractAddress != (common.Address{}) {
+		n.field("created", HyperAddr(r.ContractAddress)+"  "+bsMuted.Render(r.ContractAddress.Hex()))
+	}
+	n.field("value", bsValue(FormatETH(tx.Value())))
+	n.field("nonce", bsValue(fmt.Sprint(tx.Nonce())))
+	n.Children = append(n.Children, in.gasNode(in.header.BaseFee, tx, r))
+	return n
+}
+
+// revertReason asks the node to replay the failed call, falling back to
+// what the call tracer reported.
+func (in *txInspection) revertReason() string {
+	if reason := rpc.RevertReason(in.ctx, in.client, in.tx, in.receipt.BlockNumber); reason != "" {
+		return reason
+	}
+	if in.trace != nil {
+		if in.trace.RevertReason != "" {
+			return in.trace.RevertReason
+		}
+		if in.trace.Error != "" {
+			return in.trace.Error
+		}
+	}
+	return "no reason returned"
+}
+
+func (in *txInspection) receiptNode() *ExplorerNode {
+	r := in.receipt
+	n := &ExplorerNode{Open: true, Text: bsSection("Receipt")}
+	n.field("type", bsValue(fmt.Sprint(r.Type)))
+	n.field("status", bsValue(fmt.Sprint(r.Status)))
+	n.field("block hash", bsHyperHash(r.BlockHash, "block/")+"  "+bsMuted.Render(r.BlockHash.Hex()))
+	n.field("block", bsHyperBlock(r.BlockNumber.Uint64()))
+	n.field("index", bsValue(fmt.Sprint(r.TransactionIndex)))
+	n.field("gas used", bsValue(fmt.Sprint(r.GasUsed)))
+	n.field("cumulative", bsValue(fmt.Sprint(r.CumulativeGasUsed)))
+	if r.EffectiveGasPrice != nil {
+		n.field("gas price", bsValue(explorerGwei(r.EffectiveGasPrice)))
+	}
+	if r.BlobGasUsed > 0 {
+		n.field("blob gas", bsValue(fmt.Sprint(r.BlobGasUsed)))
+		n.field("blob price", bsValue(explorerGwei(r.BlobGasPrice)))
+	}
+	if r.ContractAddress != (common.Address{}) {
+		n.field("contract", HyperAddr(r.ContractAddress))
+	}
+	n.field("logs", bsValue(fmt.Sprint(len(r.Logs))))
+	bits := 0
+	for _, b := range r.Bloom {
+		for ; b != 0; b &= b - 1 {
+			bits++
+		}
+	}
+	n.field("logs bloom", bsMuted.Render(fmt.Sprintf("%d of 2048 bits set", bits)))
+	return n
+}
+
+func (in *txInspection) inputNode() *ExplorerNode {
+	data := in.tx.Data()
+	n := &ExplorerNode{Open: true, Text: bsSection("Input") + "  " + bsMuted.Render(fmt.Sprintf("%d bytes", len(data)))}
+	switch {
+	case in.tx.To() == nil:
+		n.add(bsMuted.Render("contract creation: the input is the init code"))
+	case len(data) == 0:
+		n.add(bsMuted.Render("no calldata: a plain ETH transfer"))
+	default:
+		in.callFields(n, *in.tx.To(), data)
+	}
+	if len(data) > 0 {
+		raw := inputNode(data)
+		raw.Text = bsLabel("raw") + "  " + bsMuted.Render(fmt.Sprintf("%d bytes", len(data)))
+		n.Children = append(n.Children, raw)
+	}
+	return n
+}
+
+// callFields adds the decoded method and arguments of calldata sent to
+// addr, or notes that no ABI or 4-byte entry knows its selector.
+func (in *txInspection) callFields(n *ExplorerNode, addr common.Address, data []byte) {
+	dc, ok := in.dec.decodeCall(addr, data)
+	if !ok {
+		n.field("method", bsWarn.Render("unknown selector "+selectorHex(data)))
+		return
+	}
+	n.field("method", bsAccent.Render(dc.sig)+"  "+bsMuted.Render(selectorHex(data)+" · via "+dc.source))
+	for _, a := range dc.args {
+		n.field(a.name, bsValue(a.value))
+	}
+}
+
+func (in *txInspection) balanceNode() *ExplorerNode {
+	deltas := balanceDeltas(in.tx, in.from, in.receipt, in.trace, in.addrs.WETH)
+	holders := make([]common.Address, 0, len(deltas))
+	for a := range deltas {
+		holders = append(holders, a)
+	}
+	to := addrOrZero(in.tx.To())
+	rank := func(a common.Address) int {
+		switch a {
+		case in.from:
+			return 0
+		case to:
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if ri, rj := rank(holders[i]), rank(holders[j]); ri != rj {
+			return ri < rj
+		}
+		return bytes.Compare(holders[i].Bytes(), holders[j].Bytes()) < 0
+	})
+
+	n := &ExplorerNode{Open: true, Text: bsSection(fmt.Sprintf("Balance Changes · %d addresses", len(holders)))}
+	for _, a := range holders {
+		text := HyperAddr(a) + "  " + bsMuted.Render(a.Hex())
+		switch {
+		case a == in.from:
+			text += "  " + bsAccent2.Render("sender")
+		case a == to:
+			text += "  " + bsAccent2.Render("recipient")
+		}
+		h := n.add(text)
+		h.Open = true
+		tokens := make([]common.Address, 0, len(deltas[a]))
+		for tok := range deltas[a] {
+			tokens = append(tokens, tok)
+		}
+		sort.Slice(tokens, func(i, j int) bool { return bytes.Compare(tokens[i].Bytes(), tokens[j].Bytes()) < 0 })
+		for _, tok := range tokens {
+			h.add(in.deltaLine(tok, deltas[a][tok]))
+		}
+	}
+	if len(holders) == 0 {
+		n.add(bsMuted.Render("no ETH or token balance changed"))
+	}
+	if in.trace == nil {
+		n.add(bsMuted.Render("ETH moved by internal calls is not shown: the node has no debug_traceTransaction"))
+	}
+	if price := in.receipt.EffectiveGasPrice; price != nil {
+		fee := new(big.Int).Mul(price, new(big.Int).SetUint64(in.receipt.GasUsed))
+		n.add(bsMuted.Render("excludes the sender's gas fee of " + FormatETH(fee)))
+	}
+	return n
+}
+
+// deltaLine formats a signed balance change; the zero token is ETH.
+func (in *txInspection) deltaLine(token common.Address, amount *big.Int) string {
+	abs := new(big.Int).Abs(amount)
+	var s string
+	if token == (common.Address{}) {
+		s = FormatETH(abs)
+	} else {
+		symbol := in.syms.getOrFetch(in.ctx, in.client, token)
+		if symbol == "" {
+			symbol = "?"
+		}
+		s = FormatToken(abs, in.decimals(token), symbol) + "  " + bsMuted.Render("token="+ShortenAddr(token.Hex()))
+	}
+	if amount.Sign() < 0 {
+		return bsError.Render("−" + s)
+	}
+	return bsAccent.Render("+" + s)
+}
+
+// balanceDeltas nets each address's ETH and token balance change in a
+// successful tx, keyed holder → token, with the zero token for ETH: ETH
+// from the call trace's value transfers (the top-level value alone without
+// a trace), tokens from ERC-20 Transfer logs and WETH Deposit and
+// Withdrawal. Gas fees are not included.
+func balanceDeltas(tx *types.Transaction, from common.Address, receipt *types.Receipt, trace *traceCall, weth common.Address) map[common.Address]map[common.Address]*big.Int {
+	out := make(map[common.Address]map[common.Address]*big.Int)
+	add := func(holder, token common.Address, amount *big.Int, sign int) {
+		if out[holder] == nil {
+			out[holder] = make(map[common.Address]*big.Int)
+		}
+		cur := out[holder][token]
+		if cur == nil {
+			cur = new(big.Int)
+			out[holder][token] = cur
+		}
+		if sign < 0 {
+			cur.Sub(cur, amount)
+		} else {
+			cur.Add(cur, amount)
+		}
+	}
+	move := func(src, dst, token common.Address, amount *big.Int) {
+		add(src, token, amount, -1)
+		add(dst, token, amount, 1)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return out
+	}
+
+	eth := common.Address{}
+	if trace != nil {
+		var walk func(f *traceCall)
+		walk = func(f *traceCall) {
+			if f.Error != "" {
+				return // reverted: nothing in this frame or below it moved
+			}
+			if f.movesValue() {
+				move(f.From, f.To, eth, f.Value.ToInt())
+			}
+			for i := range f.Calls {
+				walk(&f.Calls[i])
+			}
+		}
+		walk(trace)
+	} else if tx.Value().Sign() > 0 {
+		to := addrOrZero(tx.To())
+		if tx.To() == nil {
+			to = receipt.ContractAddress
+		}
+		move(from, to, eth, tx.Value())
+	}
+
+	for _, lg := range receipt.Logs {
+		if len(lg.Topics) == 0 || len(lg.Data) != 32 {
+			continue
+		}
+		amount := new(big.Int).SetBytes(lg.Data)
+		switch {
+		case lg.Topics[0] == explorerTransferSig && len(lg.Topics) == 3:
+			move(common.BytesToAddress(lg.Topics[1].Bytes()), common.BytesToAddress(lg.Topics[2].Bytes()), lg.Address, amount)
+		case lg.Address == weth && lg.Topics[0] == wethDepositSig && len(lg.Topics) == 2:
+			add(common.BytesToAddress(lg.Topics[1].Bytes()), weth, amount, 1)
+		case lg.Address == weth && lg.Topics[0] == wethWithdrawalSig && len(lg.Topics) == 2:
+			add(common.BytesToAddress(lg.Topics[1].Bytes()), weth, amount, -1)
+		}
+	}
+
+	for holder, tokens := range out {
+		for token, amount := range tokens {
+			if amount.Sign() == 0 {
+				delete(tokens, token)
+			}
+		}
+		if len(tokens) == 0 {
+			delete(out, holder)
+		}
+	}
+	return out
+}
+
+func (in *txInspection) logsNode() *ExplorerNode {
+	n := &ExplorerNode{Open: true, Text: bsSection(fmt.Sprintf("Logs · %d", len(in.receipt.Logs)))}
+	for _, lg := range in.receipt.Logs {
+		raw := logNode(lg)
+		raw.Text = bsLabel("raw")
+		text := fmt.Sprintf("%s  %s", bsMuted.Render(fmt.Sprintf("#%d", lg.Index)), HyperAddr(lg.Address))
+		dc, ok := in.dec.decodeLog(lg)
+		if !ok {
+			if len(lg.Topics) > 0 {
+				text += "  " + bsMuted.Render(ShortenAddr(lg.Topics[0].Hex()))
+			}
+			l := n.add(text + "  " + bsWarn.Render("unknown event"))
+			l.Children = raw.Children
+			continue
+		}
+		l := n.add(text + "  " + bsAccent.Render(dc.sig[:strings.IndexByte(dc.sig, '(')]) + "  " + bsMuted.Render("via "+dc.source))
+		if line := in.v4Line(lg); line != "" {
+			l.add(line)
+		}
+		for _, a := range dc.args {
+			l.field(a.name, bsValue(a.value))
+		}
+		l.Children = append(l.Children, raw)
+	}
+	if len(in.receipt.Logs) == 0 {
+		n.add(bsMuted.Render("no logs"))
+	}
+	return n
+}
+
+func (in *txInspection) traceNode() *ExplorerNode {
+	n := &ExplorerNode{Open: true, Text: bsSection("Call Trace")}
+	if in.trace == nil {
+		n.add(bsWarn.Render("debug_traceTransaction unavailable: ") + bsMuted.Render(in.traceErr.Error()))
+		return n
+	}
+	frames := 0
+	var count func(f *traceCall)
+	count = func(f *traceCall) {
+		frames++
+		for i := range f.Calls {
+			count(&f.Calls[i])
+		}
+	}
+	count(in.trace)
+	n.Text += "  " + bsMuted.Render(fmt.Sprintf("%d calls", frames))
+	n.Children = append(n.Children, in.frameNode(in.trace, 0))
+	return n
+}
+
+// frameNode shows one call frame: its decoded call and output, then its
+// subcalls. The first levels start open.
+func (in *txInspection) frameNode(f *traceCall, depth int) *ExplorerNode {
+	text := bsAccent2.Render(f.Type) + "  " + HyperAddr(f.From) + " → " + HyperAddr(f.To)
+	if len(f.Input) >= 4 && !strings.HasPrefix(f.Type, "CREATE") {
+		if dc, ok := in.dec.decodeCall(f.To, f.Input); ok {
+			text += "  " + bsAccent.Render(dc.sig[:strings.IndexByte(dc.sig, '(')])
+		} else {
+			text += "  " + bsMuted.Render(selectorHex(f.Input))
+		}
+	}
+	if f.movesValue() {
+		text += "  " + bsValue(FormatETH(f.Value.ToInt()))
+	}
+	text += "  " + bsMuted.Render(fmt.Sprintf("gas %d", uint64(f.GasUsed)))
+	if f.Error != "" {
+		reason := f.Error
+		if f.RevertReason != "" {
+			reason += ": " + f.RevertReason
+		}
+		text += "  " + bsError.Render("✗ "+reason)
+	}
+	n := &ExplorerNode{Text: text, Open: depth < 2}
+
+	if len(f.Input) > 0 {
+		call := n.add(bsLabel("call") + "  " + bsMuted.Render(fmt.Sprintf("%d bytes", len(f.Input))))
+		if strings.HasPrefix(f.Type, "CREATE") {
+			call.add(bsMuted.Render("init code"))
+		} else {
+			in.callFields(call, f.To, f.Input)
+		}
+	}
+	if len(f.Output) > 0 {
+		out := "0x" + hex.EncodeToString(f.Output[:min(len(f.Output), 64)])
+		if len(f.Output) > 64 {
+			out += fmt.Sprintf("… (%d bytes)", len(f.Output))
+		}
+		n.field("output", bsMuted.Render(out))
+	}
+	for i := range f.Calls {
+		n.Children = append(n.Children, in.frameNode(&f.Calls[i], depth+1))
+	}
+	return n
+}
@@ -0,0 +1,364 @@
+package helpers
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"charm-wallet-tui/store"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//go:embed data/function_signatures.json
+var functionSignaturesJSON []byte
+
+// functionSignatures maps a 4-byte selector to its text signature from the
+// vendored table, the Transaction Inspector's offline stand-in for a
+// 4byte.directory lookup when no ABI knows a call.
+var functionSignatures = map[[4]byte]abi.Method{}
+
+func init() {
+	var raw struct {
+		Signatures []string `json:"signatures"`
+	}
+	if err := json.Unmarshal(functionSignaturesJSON, &raw); err != nil {
+		panic(fmt.Sprintf("helpers: malformed embedded function_signatures.json: %v", err))
+	}
+	for _, sig := range raw.Signatures {
+		m, err := parseFunctionSignature(sig)
+		if err != nil {
+			panic(fmt.Sprintf("helpers: bad signature %q in embedded function_signatures.json: %v", sig, err))
+		}
+		functionSignatures[[4]byte(m.ID)] = m
+	}
+}
+
+// parseFunctionSignature builds an ABI method with unnamed inputs from a
+// canonical text signature such as "exactInput((bytes,address,uint256))".
+func parseFunctionSignature(sig string) (abi.Method, error) {
+	open := strings.IndexByte(sig, '(')
+	if open <= 0 || !strings.HasSuffix(sig, ")") {
+		return abi.Method{}, fmt.Errorf("want name(type,…)")
+	}
+	name := sig[:open]
+	parts, err := splitSignatureTypes(sig[open+1 : len(sig)-1])
+	if err != nil {
+		return abi.Method{}, err
+	}
+	inputs := make(abi.Arguments, len(parts))
+	for i, p := range parts {
+		m, err := signatureType(p)
+		if err != nil {
+			return abi.Method{}, err
+		}
+		t, err := abi.NewType(m.Type, "", m.Components)
+		if err != nil {
+			return abi.Method{}, fmt.Errorf("%s: %w", p, err)
+		}
+		inputs[i] = abi.Argument{Type: t}
+	}
+	m := abi.NewMethod(name, name, abi.Function, "", false, false, inputs, nil)
+	if m.Sig != sig {
+		return abi.Method{}, fmt.Errorf("not canonical, want %s", m.Sig)
+	}
+	return m, nil
+}
+
+// signatureType converts one type of a text signature to the form
+// abi.NewType takes; tuples get numbered component names.
+func signatureType(t string) (abi.ArgumentMarshaling, error) {
+	if !strings.HasPrefix(t, "(") {
+		return abi.ArgumentMarshaling{Type: t}, nil
+	}
+	end := strings.LastIndexByte(t, ')')
+	parts, err := splitSignatureTypes(t[1:end])
+	if err != nil {
+		return abi.ArgumentMarshaling{}, err
+	}
+	m := abi.ArgumentMarshaling{Type: "tuple" + t[end+1:]}
+	for i, p := range parts {
+		c, err := signatureType(p)
+		if err != nil {
+			return m, err
+		}
+		c.Name = fmt.Sprintf("field%d", i)
+		m.Components = append(m.Components, c)
+	}
+	return m, nil
+}
+
+// splitSignatureTypes splits a comma-separated type list at the top level,
+// keeping tuples whole.
+func splitSignatureTypes(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+	}
+	return append(parts, s[start:]), nil
+}
+
+// Built-in ABIs the inspector decodes with, alongside poolManagerEventsABI
+// and v3PositionManagerABI. Contracts sharing an event name (ERC-20 and
+// ERC-721 Transfer, V2 and V3 Swap) are kept apart so each parses.
+const (
+	inspectorERC20ABI = `[
+  {"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+  {"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+  {"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+  {"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+  {"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+	inspectorERC721ABI = `[
+  {"type":"function","name":"setApprovalForAll","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]},
+  {"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+  {"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"approved","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+  {"type":"event","name":"ApprovalForAll","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}]}
+]`
+	inspectorERC1155ABI = `[
+  {"type":"event","name":"TransferSingle","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"id","type":"uint256","indexed":false},{"name":"value","type":"uint256","indexed":false}]},
+  {"type":"event","name":"TransferBatch","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"ids","type":"uint256[]","indexed":false},{"name":"values","type":"uint256[]","indexed":false}]}
+]`
+	inspectorWETHABI = `[
+  {"type":"function","name":"deposit","inputs":[],"outputs":[]},
+  {"type":"function","name":"withdraw","inputs":[{"name":"wad","type":"uint256"}],"outputs":[]},
+  {"type":"event","name":"Deposit","inputs":[{"name":"dst","type":"address","indexed":true},{"name":"wad","type":"uint256","indexed":false}]},
+  {"type":"event","name":"Withdrawal","inputs":[{"name":"src","type":"address","indexed":true},{"name":"wad","type":"uint256","indexed":false}]}
+]`
+	inspectorV2PairABI = `[
+  {"type":"event","name":"Swap","inputs":[{"name":"sender","type":"address","indexed":true},{"name":"amount0In","type":"uint256","indexed":false},{"name":"amount1In","type":"uint256","indexed":false},{"name":"amount0Out","type":"uint256","indexed":false},{"name":"amount1Out","type":"uint256","indexed":false},{"name":"to","type":"address","indexed":true}]},
+  {"type":"event","name":"Sync","inputs":[{"name":"reserve0","type":"uint112","indexed":false},{"name":"reserve1","type":"uint112","indexed":false}]},
+  {"type":"event","name":"Mint","inputs":[{"name":"sender","type":"address","indexed":true},{"name":"amount0","type":"uint256","indexed":false},{"name":"amount1","type":"uint256","indexed":false}]},
+  {"type":"event","name":"Burn","inputs":[{"name":"sender","type":"address","indexed":true},{"name":"amount0","type":"uint256","indexed":false},{"name":"amount1","type":"uint256","indexed":false},{"name":"to","type":"address","indexed":true}]}
+]`
+	inspectorV3PoolABI = `[
+  {"type":"event","name":"Swap","inputs":[{"name":"sender","type":"address","indexed":true},{"name":"recipient","type":"address","indexed":true},{"name":"amount0","type":"int256","indexed":false},{"name":"amount1","type":"int256","indexed":false},{"name":"sqrtPriceX96","type":"uint160","indexed":false},{"name":"liquidity","type":"uint128","indexed":false},{"name":"tick","type":"int24","indexed":false}]}
+]`
+	inspectorV3PositionEventsABI = `[
+  {"type":"event","name":"IncreaseLiquidity","inputs":[{"name":"tokenId","type":"uint256","indexed":true},{"name":"liquidity","type":"uint128","indexed":false},{"name":"amount0","type":"uint256","indexed":false},{"name":"amount1","type":"uint256","indexed":false}]},
+  {"type":"event","name":"DecreaseLiquidity","inputs":[{"name":"tokenId","type":"uint256","indexed":true},{"name":"liquidity","type":"uint128","indexed":false},{"name":"amount0","type":"uint256","indexed":false},{"name":"amount1","type":"uint256","indexed":false}]},
+  {"type":"event","name":"Collect","inputs":[{"name":"tokenId","type":"uint256","indexed":true},{"name":"recipient","type":"address","indexed":false},{"name":"amount0","type":"uint256","indexed":false},{"name":"amount1","type":"uint256","indexed":false}]}
+]`
+	inspectorPermit2ABI = `[
+  {"type":"function","name":"approve","inputs":[{"name":"token","type":"address"},{"name":"spender","type":"address"},{"name":"amount","type":"uint160"},{"name":"expiration","type":"uint48"}],"outputs":[]},
+  {"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"token","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"amount","type":"uint160","indexed":false},{"name":"expiration","type":"uint48","indexed":false}]},
+  {"type":"event","name":"Permit","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"token","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"amount","type":"uint160","indexed":false},{"name":"expiration","type":"uint48","indexed":false},{"name":"nonce","type":"uint48","indexed":false}]}
+]`
+	inspectorSafeABI = `[
+  {"type":"event","name":"ExecutionSuccess","inputs":[{"name":"txHash","type":"bytes32","indexed":false},{"name":"payment","type":"uint256","indexed":false}]},
+  {"type":"event","name":"ExecutionFailure","inputs":[{"name":"txHash","type":"bytes32","indexed":false},{"name":"payment","type":"uint256","indexed":false}]}
+]`
+)
+
+var inspectorBuiltinABIs = []struct{ source, json string }{
+	{"ERC-20", inspectorERC20ABI},
+	{"ERC-721", inspectorERC721ABI},
+	{"ERC-1155", inspectorERC1155ABI},
+	{"WETH", inspectorWETHABI},
+	{"Uniswap V2 pair", inspectorV2PairABI},
+	{"Uniswap V3 pool", inspectorV3PoolABI},
+	{"Uniswap V3 PositionManager", v3PositionManagerABI},
+	{"Uniswap V3 PositionManager", inspectorV3PositionEventsABI},
+	{"Uniswap V4 PoolManager", poolManagerEventsABI},
+	{"Permit2", inspectorPermit2ABI},
+	{"Safe", inspectorSafeABI},
+}
+
+// sourcedABI is a parsed ABI and where it came from, shown next to what it
+// decodes.
+type sourcedABI struct {
+	source string
+	abi    abi.ABI
+}
+
+// abiDecoder names calls and logs from the ABIs the app knows: the
+// Contract page's saved ABIs, which win for the contract they were opened
+// against, then the built-in token, Uniswap, Permit2 and Safe ABIs, and for
+// calls last the vendored 4-byte table.
+type abiDecoder struct {
+	abis   []sourcedABI
+	byAddr map[common.Address]int // index into abis of a contract's saved ABI
+}
+
+func newABIDecoder(saved []store.SavedABI) *abiDecoder {
+	d := &abiDecoder{byAddr: make(map[common.Address]int)}
+	for _, sa := range saved {
+		parsed, err := abi.JSON(strings.NewReader(sa.ABI))
+		if err != nil {
+			continue
+		}
+		if common.IsHexAddress(sa.Address) {
+			d.byAddr[common.HexToAddress(sa.Address)] = len(d.abis)
+		}
+		d.abis = append(d.abis, sourcedABI{source: sa.Name, abi: parsed})
+	}
+	for _, b := range inspectorBuiltinABIs {
+		parsed, err := abi.JSON(strings.NewReader(b.json))
+		if err != nil {
+			panic(fmt.Sprintf("helpers: built-in %s ABI: %v", b.source, err))
+		}
+		d.abis = append(d.abis, sourcedABI{source: b.source, abi: parsed})
+	}
+	return d
+}
+
+// contractName is the saved ABI name for addr, or "".
+func (d *abiDecoder) contractName(addr common.Address) string {
+	if i, ok := d.byAddr[addr]; ok {
+		return d.abis[i].source
+	}
+	return ""
+}
+
+// candidates lists the ABIs to try for a contract, its own saved one first.
+func (d *abiDecoder) candidates(addr common.Address) []sourcedABI {
+	i, ok := d.byAddr[addr]
+	if !ok {
+		return d.abis
+	}
+	return append([]sourcedABI{d.abis[i]}, d.abis...)
+}
+
+// decodedArg is one named, formatted argument of a call or event.
+type decodedArg struct {
+	name, value string
+}
+
+// decodedCall is a call or event matched to an ABI.
+type decodedCall struct {
+	source string // ABI name, or "4-byte table"
+	sig    string // e.g. "transfer(address,uint256)"
+	args   []decodedArg
+}
+
+// decodeCall matches calldata sent to addr against the known ABIs, then
+// the 4-byte table.
+func (d *abiDecoder) decodeCall(addr common.Address, input []byte) (decodedCall, bool) {
+	if len(input) < 4 {
+		return decodedCall{}, false
+	}
+	for _, c := range d.candidates(addr) {
+		m, err := c.abi.MethodById(input[:4])
+		if err != nil {
+			continue
+		}
+		if dc, ok := unpackCall(c.source, m, input[4:]); ok {
+			return dc, true
+		}
+	}
+	if m, ok := functionSignatures[[4]byte(input[:4])]; ok {
+		return unpackCall("4-byte table", &m, input[4:])
+	}
+	return decodedCall{}, false
+}
+
+func unpackCall(source string, m *abi.Method, data []byte) (decodedCall, bool) {
+	vals, err := m.Inputs.Unpack(data)
+	if err != nil {
+		return decodedCall{}, false
+	}
+	dc := decodedCall{source: source, sig: m.Sig}
+	for i, arg := range m.Inputs {
+		dc.args = append(dc.args, decodedArg{argName(arg, i), FormatABIValue(vals[i])})
+	}
+	return dc, true
+}
+
+// decodeLog matches a log against the known event ABIs, checking the
+// indexed argument count so ERC-20 and ERC-721 Transfers are told apart.
+func (d *abiDecoder) decodeLog(lg *types.Log) (decodedCall, bool) {
+	if len(lg.Topics) == 0 {
+		return decodedCall{}, false
+	}
+	for _, c := range d.candidates(lg.Address) {
+		ev, err := c.abi.EventByID(lg.Topics[0])
+		if err != nil {
+			continue
+		}
+		if dc, ok := unpackLog(c.source, ev, lg); ok {
+			return dc, true
+		}
+	}
+	return decodedCall{}, false
+}
+
+func unpackLog(source string, ev *abi.Event, lg *types.Log) (decodedCall, bool) {
+	indexed := 0
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed++
+		}
+	}
+	if indexed != len(lg.Topics)-1 {
+		return decodedCall{}, false
+	}
+	data, err := ev.Inputs.NonIndexed().Unpack(lg.Data)
+	if err != nil {
+		return decodedCall{}, false
+	}
+	dc := decodedCall{source: source, sig: ev.Sig}
+	topic, value := 1, 0
+	for i, arg := range ev.Inputs {
+		var s string
+		if arg.Indexed {
+			s = indexedValue(arg.Type, lg.Topics[topic])
+			topic++
+		} else {
+			s = FormatABIValue(data[value])
+			value++
+		}
+		dc.args = append(dc.args, decodedArg{argName(arg, i), s})
+	}
+	return dc, true
+}
+
+// indexedValue decodes an indexed event argument; dynamic types are only
+// stored as their hash.
+func indexedValue(t abi.Type, topic common.Hash) string {
+	switch t.T {
+	case abi.IntTy, abi.UintTy, abi.BoolTy, abi.AddressTy, abi.FixedBytesTy:
+		if vals, err := (abi.Arguments{{Type: t}}).Unpack(topic.Bytes()); err == nil {
+			return FormatABIValue(vals[0])
+		}
+	}
+	return "hash " + topic.Hex()
+}
+
+func argName(arg abi.Argument, i int) string {
+	if arg.Name != "" {
+		return arg.Name
+	}
+	return fmt.Sprintf("arg%d", i)
+}
+
+// selectorHex formats calldata's selector, or "" when it has none.
+func selectorHex(input []byte) string {
+	if len(input) < 4 {
+		return ""
+	}
+	return "0x" + hex.EncodeToString(input[:4])
+}
@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"charm-wallet-tui/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseFunctionSignature(t *testing.T) {
+	cases := map[string]string{
+		"transfer(address,uint256)": "a9059cbb",
+		"exactInputSingle((address,address,uint24,address,uint256,uint256,uint160))": "04e45aaf",
+		"aggregate3((address,bool,bytes)[])":                                         "82ad56cb",
+		"deposit()":                                                                  "d0e30db0",
+	}
+	for sig, want := range cases {
+		m, err := parseFunctionSignature(sig)
+		if err != nil {
+			t.Errorf("parseFunctionSignature(%q): %v", sig, err)
+			continue
+		}
+		if got := hex.EncodeToString(m.ID); got != want {
+			t.Errorf("%s selector = %s, want %s", sig, got, want)
+		}
+	}
+	for _, bad := range []string{"", "transfer", "(uint256)", "f((uint256)", "f(uint)"} {
+		if _, err := parseFunctionSignature(bad); err == nil {
+			t.Errorf("parseFunctionSignature(%q) accepted", bad)
+		}
+	}
+}
+
+func TestABIDecoder(t *testing.T) {
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	from := common.HexToAddress("0x2")
+	to := common.HexToAddress("0x3")
+	d := newABIDecoder([]store.SavedABI{{
+		Name:    "Vault",
+		Address: token.Hex(),
+		ABI:     `[{"type":"function","name":"transfer","inputs":[{"name":"dst","type":"address"},{"name":"wad","type":"uint256"}],"outputs":[]}]`,
+	}})
+
+	word := func(b []byte) []byte { return common.LeftPadBytes(b, 32) }
+	input := append(common.FromHex("a9059cbb"), append(word(to.Bytes()), word(big.NewInt(5).Bytes())...)...)
+	dc, ok := d.decodeCall(token, input)
+	if !ok || dc.source != "Vault" || dc.args[0].name != "dst" || dc.args[1].value != "5" {
+		t.Errorf("call to the saved contract decoded as %+v, %v", dc, ok)
+	}
+	if dc, ok := d.decodeCall(to, input); !ok || dc.source == "4-byte table" {
+		t.Errorf("call to another contract decoded as %+v, %v; want an ABI match", dc, ok)
+	}
+	if dc, ok := d.decodeCall(to, common.FromHex("0x3593564c")); ok || dc.source != "" {
+		t.Errorf("truncated execute() decoded as %+v", dc)
+	}
+	if _, ok := d.decodeCall(to, common.FromHex("0xdeadbeef")); ok {
+		t.Error("unknown selector decoded")
+	}
+
+	erc20 := &types.Log{Address: token, Data: word(big.NewInt(7).Bytes()), Topics: []common.Hash{
+		explorerTransferSig, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()),
+	}}
+	if dc, ok := d.decodeLog(erc20); !ok || dc.source != "ERC-20" || dc.args[2].value != "7" {
+		t.Errorf("ERC-20 Transfer decoded as %+v, %v", dc, ok)
+	}
+	erc721 := &types.Log{Address: token, Topics: []common.Hash{
+		explorerTransferSig, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(42)),
+	}}
+	if dc, ok := d.decodeLog(erc721); !ok || dc.source != "ERC-721" || dc.args[2].value != "42" || dc.args[1].value != to.Hex() {
+		t.Errorf("ERC-721 Transfer decoded as %+v, %v", dc, ok)
+	}
+}
+
+func TestBalanceDeltas(t *testing.T) {
+	sender := common.HexToAddress("0xa")
+	router := common.HexToAddress("0xb")
+	pool := common.HexToAddress("0xc")
+	impl := common.HexToAddress("0xd")
+	token := common.HexToAddress("0xe")
+	weth := common.HexToAddress("0xf")
+	eth := common.Address{}
+	wei := func(n int64) *hexutil.Big { return (*hexutil.Big)(big.NewInt(n)) }
+
+	trace := &traceCall{Type: "CALL", From: sender, To: router, Value: wei(100), Calls: []traceCall{
+		{Type: "CALL", From: router, To: weth, Value: wei(60)},
+		{Type: "DELEGATECALL", From: router, To: impl, Value: wei(100)},
+		{Type: "CALL", From: router, To: pool, Value: wei(40), Error: "execution reverted"},
+	}}
+	word := func(n int64) []byte { return common.LeftPadBytes(big.NewInt(n).Bytes(), 32) }
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{
+		{Address: weth, Topics: []common.Hash{wethDepositSig, common.BytesToHash(router.Bytes())}, Data: word(60)},
+		{Address: token, Topics: []common.Hash{explorerTransferSig, common.BytesToHash(pool.Bytes()), common.BytesToHash(sender.Bytes())}, Data: word(9)},
+	}}
+	tx := types.NewTx(&types.LegacyTx{To: &router, Value: big.NewInt(100)})
+
+	got := balanceDeltas(tx, sender, receipt, trace, weth)
+	want := map[common.Address]map[common.Address]int64{
+		sender: {eth: -100, token: 9},
+		router: {eth: 40, weth: 60},
+		weth:   {eth: 60},
+		pool:   {token: -9},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("deltas for %d holders, want %d: %v", len(got), len(want), got)
+	}
+	for holder, tokens := range want {
+		if len(got[holder]) != len(tokens) {
+			t.Errorf("%s: %v, want %v", holder.Hex(), got[holder], tokens)
+			continue
+		}
+		for tok, n := range tokens {
+			if got[holder][tok] == nil || got[holder][tok].Int64() != n {
+				t.Errorf("%s %s delta = %v, want %d", holder.Hex(), tok.Hex(), got[holder][tok], n)
+			}
+		}
+	}
+
+	// Without a trace only the top-level value is known.
+	got = balanceDeltas(tx, sender, receipt, nil, weth)
+	if got[sender][eth].Int64() != -100 || got[router][eth].Int64() != 100 {
+		t.Errorf("untraced ETH deltas = %v / %v", got[sender][eth], got[router][eth])
+	}
+
+	receipt.Status = types.ReceiptStatusFailed
+	if got := balanceDeltas(tx, sender, receipt, trace, weth); len(got) != 0 {
+		t.Errorf("failed tx has deltas %v", got)
+	}
+}
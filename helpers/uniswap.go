@@ -71,12 +71,14 @@ type UniswapNetworkAddresses struct {
 	USDC   common.Address
 	USDT   common.Address
 	DAI    common.Address
+	WBTC   common.Address // router hub token — mainnet only
 	SPCXon common.Address // SpaceX (Ondo Tokenized) — mainnet only
 
 	// Uniswap V3
-	FactoryV3    common.Address // V3 factory, for on-chain getPool() lookups
-	QuoterV2     common.Address // QuoterV2 for off-chain quote simulation
-	SwapRouterV3 common.Address // SwapRouter02
+	FactoryV3         common.Address // V3 factory, for on-chain getPool() lookups
+	QuoterV2          common.Address // QuoterV2 for off-chain quote simulation
+	SwapRouterV3      common.Address // SwapRouter02
+	V3PositionManager common.Address // NonfungiblePositionManager (V3 liquidity position NFTs)
 
 	// Uniswap V4. Zero on networks where V4 isn't deployed/supported — callers
 	// gate on V4PoolManager != (common.Address{}) before trying the V4 tier.
@@ -99,11 +101,13 @@ var mainnetUniswapAddresses = UniswapNetworkAddresses{
 	USDC:   USDCAddress,
 	USDT:   common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
 	DAI:    DAIAddress,
+	WBTC:   common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599"),
 	SPCXon: common.HexToAddress("0xc9eef266834730340A55B6CC24621B31BAF55581"),
 
-	FactoryV3:    common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"),
-	QuoterV2:     common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e"),
-	SwapRouterV3: common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"),
+	FactoryV3:         common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"),
+	QuoterV2:          common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e"),
+	SwapRouterV3:      common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"),
+	V3PositionManager: common.HexToAddress("0xC36442b4a4522E871399CD717aBDD847Ab11FE88"),
 
 	// V4 addresses reuse the constants/vars already defined for the existing
 	// V4 read-side code (event listener, NFT positions) rather than being
@@ -134,9 +138,10 @@ var sepoliaUniswapAddresses = UniswapNetworkAddresses{
 	USDT: common.HexToAddress("0xaa8E23Fb1079EA71e0a56F48a2aa51851D8433D0"),
 	DAI:  common.HexToAddress("0xB4F1737Af37711e9A5890D9510c9bB60e170CB0D"),
 
-	FactoryV3:    common.HexToAddress("0x0227628f3F023bb0B980b67D528571c95c6DaC1c"),
-	QuoterV2:     common.HexToAddress("0xEd1f6473345F45b75F8179591dd5bA1888cf2FB3"),
-	SwapRouterV3: common.HexToAddress("0x3bFA4769FB09eefC5a80d6E87c3B9C650f7Ae48E"),
+	FactoryV3:         common.HexToAddress("0x0227628f3F023bb0B980b67D528571c95c6DaC1c"),
+	QuoterV2:          common.HexToAddress("0xEd1f6473345F45b75F8179591dd5bA1888cf2FB3"),
+	SwapRouterV3:      common.HexToAddress("0x3bFA4769FB09eefC5a80d6E87c3B9C650f7Ae48E"),
+	V3PositionManager: common.HexToAddress("0x1238536071E1c677A632429e3655c799b22cDA52"),
 
 	V4PoolManager:     common.HexToAddress("0xE03A1074c86CFeDd5C142C4F04F1a1536e203543"),
 	V4StateView:       common.HexToAddress("0xe1dd9c3fa50edb962e442f60dfbc432e24537e4c"),
@@ -406,23 +411,14 @@ func v3PoolLiquidity(ctx context.Context, client *ethclient.Client, pool common.
 	return new(big.Int).SetBytes(out), nil
 }
 
-// ResolvePairOnChain finds the best Uniswap pool for tokenA/tokenB by
-// querying the V2 and V3 factories directly, instead of relying on a
-// hardcoded pair-address table. It only considers a candidate "real" if it
-// has actual liquidity — a pool can exist on-chain but be empty (just
-// deployed, never seeded), and naively taking the first non-zero address
-// risks picking a dead pool over the one that's actually tradable.
-//
-// V3 is preferred over V2 when both have liquidity (matching this app's
-// prior hand-picked behavior for SPCXon, whose deepest liquidity is on V3).
-// Among V3 fee tiers, the one with the highest liquidity() wins. V4 is tried
-// last, only when neither V2 nor V3 has liquidity — V4 has no factory to
-// query live, so this tier costs more (see resolveV4Pool) and is only worth
-// paying when the established versions come up empty.
-func ResolvePairOnChain(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, tokenA, tokenB common.Address) (ResolvedPool, error) {
-	var bestV3Addr common.Address
-	var bestV3Fee uint32
-	var bestV3Liquidity *big.Int
+// LiquidPools returns every Uniswap pool between tokenA and tokenB that
+// holds liquidity: each V3 fee tier, the V2 pair and every V4 pool key. A pool can
+// exist on-chain but be empty (just deployed, never seeded), so a candidate
+// only counts once it has non-zero liquidity()/reserves. liveScanV4 enables
+// resolveV4Pool's recent-block log scan; without it only the vendored V4
+// index is consulted.
+func LiquidPools(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, tokenA, tokenB common.Address, liveScanV4 bool) []ResolvedPool {
+	var pools []ResolvedPool
 
 	if addrs.FactoryV3 != (common.Address{}) {
 		for _, f := range v3FeeTiers {
@@ -434,33 +430,66 @@ func ResolvePairOnChain(ctx context.Context, client *ethclient.Client, addrs Uni
 			if lerr != nil || liq.Sign() <= 0 {
 				continue
 			}
-			if bestV3Liquidity == nil || liq.Cmp(bestV3Liquidity) > 0 {
-				bestV3Addr = pool
-				bestV3Fee = f
-				bestV3Liquidity = liq
-			}
+			pools = append(pools, ResolvedPool{Version: PoolVersionV3, PairAddr: pool, V3Fee: f, Liquidity: liq})
 		}
 	}
-	if bestV3Liquidity != nil {
-		return ResolvedPool{Version: PoolVersionV3, PairAddr: bestV3Addr, V3Fee: bestV3Fee}, nil
-	}
 
 	if addrs.Factory != (common.Address{}) {
 		pair, perr := v2FactoryGetPair(ctx, client, addrs.Factory, tokenA, tokenB)
 		if perr == nil && pair != (common.Address{}) {
 			r0, r1, rerr := fetchReserves(ctx, client, pair)
 			if rerr == nil && r0.Sign() > 0 && r1.Sign() > 0 {
-				return ResolvedPool{Version: PoolVersionV2, PairAddr: pair}, nil
+				pools = append(pools, ResolvedPool{Version: PoolVersionV2, PairAddr: pair})
 			}
 		}
 	}
 
 	if addrs.V4PoolManager != (common.Address{}) {
-		if pool, ok := resolveV4Pool(ctx, client, addrs, tokenA, tokenB); ok {
-			return pool, nil
+		pools = append(pools, resolveV4Pools(ctx, client, addrs, tokenA, tokenB, liveScanV4)...)
+	}
+	return pools
+}
+
+// PreferredPool picks the single pool ResolvePairOnChain settles on from a
+// LiquidPools result. V3 is preferred over V2 when both have liquidity
+// (matching this app's prior hand-picked behavior for SPCXon, whose deepest
+// liquidity is on V3), and among V3 fee tiers the one with the highest
+// liquidity() wins. V4 comes last.
+func PreferredPool(pools []ResolvedPool) (ResolvedPool, bool) {
+	var best *ResolvedPool
+	for i := range pools {
+		p := &pools[i]
+		if p.Version == PoolVersionV3 && (best == nil || p.Liquidity.Cmp(best.Liquidity) > 0) {
+			best = p
+		}
+	}
+	if best != nil {
+		return *best, true
+	}
+	for _, v := range []PoolVersion{PoolVersionV2, PoolVersionV4} {
+		for _, p := range pools {
+			if p.Version == v {
+				return p, true
+			}
 		}
 	}
+	return ResolvedPool{}, false
+}
 
+// ResolvePairOnChain finds the best Uniswap pool for tokenA/tokenB by
+// querying the V2 and V3 factories directly, instead of relying on a
+// hardcoded pair-address table (see LiquidPools and PreferredPool). V4 has no
+// factory to query live, so its log-scan fallback (see resolveV4Pool) costs
+// more and only runs when neither V2, V3 nor the vendored V4 index has a pool.
+func ResolvePairOnChain(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, tokenA, tokenB common.Address) (ResolvedPool, error) {
+	if pool, ok := PreferredPool(LiquidPools(ctx, client, addrs, tokenA, tokenB, false)); ok {
+		return pool, nil
+	}
+	if addrs.V4PoolManager != (common.Address{}) {
+		if pool, ok := resolveV4Pool(ctx, client, addrs, tokenA, tokenB, true); ok {
+			return pool, nil
+		}
+	}
 	return ResolvedPool{}, fmt.Errorf("no Uniswap V2, V3, or V4 pool found")
 }
 
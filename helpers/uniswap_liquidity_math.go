@@ -0,0 +1,225 @@
+package helpers
+
+import (
+	"math"
+	"math/big"
+)
+
+// ---- Concentrated-liquidity math ----
+//
+// The standard V3/V4 LiquidityAmounts formulas, in float precision. They
+// size and preview positions. The deposit maximums that reach calldata come
+// from MaxAmountsForLiquidity instead, which repeats TickMath and
+// SqrtPriceMath exactly so a zero-slippage deposit still covers what the
+// contract will pull.
+
+// MinTick and MaxTick are TickMath's bounds; a position's ticks must also be
+// multiples of its pool's tick spacing (see UsableTick).
+const (
+	MinTick int32 = -887272
+	MaxTick int32 = 887272
+)
+
+// q96 is 2^96, the fixed-point scale of sqrtPriceX96.
+var q96 = new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))
+
+// sqrtAtTick returns sqrt(1.0001^tick).
+func sqrtAtTick(tick int32) float64 {
+	return math.Pow(1.0001, float64(tick)/2)
+}
+
+// tickRatios are TickMath's Q128 factors sqrt(1.0001)^-(2^i), one per bit
+// of |tick|.
+var tickRatios = func() []*big.Int {
+	hex := []string{
+		"fffcb933bd6fad37aa2d162d1a594001", "fff97272373d413259a46990580e213a",
+		"fff2e50f5f656932ef12357cf3c7fdcc", "ffe5caca7e10e4e61c3624eaa0941cd0",
+		"ffcb9843d60f6159c9db58835c926644", "ff973b41fa98c081472e6896dfb254c0",
+		"ff2ea16466c96a3843ec78b326b52861", "fe5dee046a99a2a811c461f1969c3053",
+		"fcbe86c7900a88aedcffc83b479aa3a4", "f987a7253ac413176f2b074cf7815e54",
+		"f3392b0822b70005940c7a398e4b70f3", "e7159475a2c29b7443b29c7fa6e889d9",
+		"d097f3bdfd2022b8845ad8f792aa5825", "a9f746462d870fdf8a65dc1f90e061e5",
+		"70d869a156d2a1b890bb3df62baf32f7", "31be135f97d08fd981231505542fcfa6",
+		"9aa508b5b7a84e1c677de54f3e99bc9", "5d6af8dedb81196699c329225ee604",
+		"2216e584f5fa1ea926041bedfe98", "48a170391f7dc42444e8fa2",
+	}
+	out := make([]*big.Int, len(hex))
+	for i, h := range hex {
+		out[i], _ = new(big.Int).SetString(h, 16)
+	}
+	return out
+}()
+
+// SqrtPriceAtTick returns the sqrtPriceX96 at tick, exactly as
+// TickMath.getSqrtPriceAtTick computes it. tick must lie within
+// [MinTick, MaxTick].
+func SqrtPriceAtTick(tick int32) *big.Int {
+	abs := tick
+	if abs < 0 {
+		abs = -abs
+	}
+	ratio := new(big.Int).Lsh(big.NewInt(1), 128)
+	if abs&1 != 0 {
+		ratio.Set(tickRatios[0])
+	}
+	for i := 1; i < len(tickRatios); i++ {
+		if abs&(1<<i) != 0 {
+			ratio.Mul(ratio, tickRatios[i]).Rsh(ratio, 128)
+		}
+	}
+	if tick > 0 {
+		maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+		ratio.Quo(maxU256, ratio)
+	}
+	// Round up from Q128.128 to Q64.96.
+	rem := new(big.Int).And(ratio, big.NewInt(1<<32-1))
+	ratio.Rsh(ratio, 32)
+	if rem.Sign() != 0 {
+		ratio.Add(ratio, big.NewInt(1))
+	}
+	return ratio
+}
+
+// sqrtPriceFloat converts sqrtPriceX96 to a plain float sqrt price.
+func sqrtPriceFloat(sqrtPriceX96 *big.Int) float64 {
+	sp, _ := new(big.Float).Quo(new(big.Float).SetInt(sqrtPriceX96), q96).Float64()
+	return sp
+}
+
+// AmountsForLiquidity returns the token0/token1 amounts liquidity is worth
+// between tickLower and tickUpper at sqrtPriceX96.
+func AmountsForLiquidity(liquidity, sqrtPriceX96 *big.Int, tickLower, tickUpper int32) (amount0, amount1 *big.Int) {
+	if liquidity == nil || sqrtPriceX96 == nil || liquidity.Sign() == 0 {
+		return big.NewInt(0), big.NewInt(0)
+	}
+	l, _ := new(big.Float).SetInt(liquidity).Float64()
+	sp := sqrtPriceFloat(sqrtPriceX96)
+	sa, sb := sqrtAtTick(tickLower), sqrtAtTick(tickUpper)
+
+	var a0, a1 float64
+	switch {
+	case sp <= sa:
+		a0 = l * (sb - sa) / (sa * sb)
+	case sp >= sb:
+		a1 = l * (sb - sa)
+	default:
+		a0 = l * (sb - sp) / (sp * sb)
+		a1 = l * (sp - sa)
+	}
+	amount0, _ = big.NewFloat(a0).Int(nil)
+	amount1, _ = big.NewFloat(a1).Int(nil)
+	return amount0, amount1
+}
+
+// MaxAmountsForLiquidity returns the token0/token1 amounts the pool
+// charges for adding liquidity between tickLower and tickUpper at
+// sqrtPriceX96, rounded up in integer math the way SqrtPriceMath's
+// getAmount0Delta/getAmount1Delta round for a deposit.
+func MaxAmountsForLiquidity(liquidity, sqrtPriceX96 *big.Int, tickLower, tickUpper int32) (amount0, amount1 *big.Int) {
+	amount0, amount1 = big.NewInt(0), big.NewInt(0)
+	if liquidity == nil || sqrtPriceX96 == nil || liquidity.Sign() == 0 || tickLower >= tickUpper {
+		return amount0, amount1
+	}
+	sa, sb := SqrtPriceAtTick(tickLower), SqrtPriceAtTick(tickUpper)
+	sp := sqrtPriceX96
+	switch {
+	case sp.Cmp(sa) <= 0:
+		amount0 = amount0Delta(liquidity, sa, sb)
+	case sp.Cmp(sb) >= 0:
+		amount1 = amount1Delta(liquidity, sa, sb)
+	default:
+		amount0 = amount0Delta(liquidity, sp, sb)
+		amount1 = amount1Delta(liquidity, sa, sp)
+	}
+	return amount0, amount1
+}
+
+// amount0Delta is getAmount0Delta(lo, hi, liquidity, roundUp=true):
+// ceil(ceil((liquidity << 96) * (hi - lo) / hi) / lo).
+func amount0Delta(liquidity, lo, hi *big.Int) *big.Int {
+	num := new(big.Int).Lsh(liquidity, 96)
+	num.Mul(num, new(big.Int).Sub(hi, lo))
+	return divRoundUp(divRoundUp(num, hi), lo)
+}
+
+// amount1Delta is getAmount1Delta(lo, hi, liquidity, roundUp=true):
+// ceil(liquidity * (hi - lo) / 2^96).
+func amount1Delta(liquidity, lo, hi *big.Int) *big.Int {
+	num := new(big.Int).Mul(liquidity, new(big.Int).Sub(hi, lo))
+	return divRoundUp(num, new(big.Int).Lsh(big.NewInt(1), 96))
+}
+
+// divRoundUp returns ceil(a / b) for non-negative a and positive b.
+func divRoundUp(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// LiquidityForAmount returns the liquidity a deposit of amount of token0
+// (isToken0) or token1 buys between tickLower and tickUpper at
+// sqrtPriceX96. It is zero when the range takes none of that token at the
+// current price: token0 only below the range's upper bound, token1 only
+// above its lower bound.
+func LiquidityForAmount(sqrtPriceX96 *big.Int, tickLower, tickUpper int32, amount *big.Int, isToken0 bool) *big.Int {
+	if sqrtPriceX96 == nil || amount == nil || amount.Sign() <= 0 || tickLower >= tickUpper {
+		return big.NewInt(0)
+	}
+	a, _ := new(big.Float).SetInt(amount).Float64()
+	sp := sqrtPriceFloat(sqrtPriceX96)
+	sa, sb := sqrtAtTick(tickLower), sqrtAtTick(tickUpper)
+
+	var l float64
+	if isToken0 {
+		if sp >= sb {
+			return big.NewInt(0)
+		}
+		lo := math.Max(sp, sa)
+		l = a * lo * sb / (sb - lo)
+	} else {
+		if sp <= sa {
+			return big.NewInt(0)
+		}
+		hi := math.Min(sp, sb)
+		l = a / (hi - sa)
+	}
+	liquidity, _ := big.NewFloat(l).Int(nil)
+	return liquidity
+}
+
+// TickToPrice converts a tick to a human-readable price (token1 per token0).
+func TickToPrice(tick int32, decimals0, decimals1 uint8) float64 {
+	return v4TickToPrice(tick, decimals0, decimals1)
+}
+
+// PriceToTick converts a human-readable price (token1 per token0) to the
+// nearest tick usable with tickSpacing.
+func PriceToTick(price float64, decimals0, decimals1 uint8, tickSpacing int32) int32 {
+	if price <= 0 || math.IsNaN(price) {
+		return UsableTick(MinTick, tickSpacing)
+	}
+	if math.IsInf(price, 1) {
+		return UsableTick(MaxTick, tickSpacing)
+	}
+	raw := price / math.Pow(10, float64(int(decimals0)-int(decimals1)))
+	t := math.Round(math.Log(raw) / math.Log(1.0001))
+	t = math.Max(float64(MinTick), math.Min(float64(MaxTick), t))
+	return UsableTick(int32(t), tickSpacing)
+}
+
+// UsableTick rounds tick to the nearest multiple of tickSpacing that lies
+// within [MinTick, MaxTick].
+func UsableTick(tick, tickSpacing int32) int32 {
+	if tickSpacing <= 0 {
+		return tick
+	}
+	rounded := int32(math.Round(float64(tick)/float64(tickSpacing))) * tickSpacing
+	if rounded < MinTick {
+		rounded += tickSpacing
+	} else if rounded > MaxTick {
+		rounded -= tickSpacing
+	}
+	return rounded
+}
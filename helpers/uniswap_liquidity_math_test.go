@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestLiquidityForAmountRoundTrip(t *testing.T) {
+	atTick0 := new(big.Int).Lsh(big.NewInt(1), 96) // sqrtPriceX96 at tick 0
+	deposit := big.NewInt(1e18)
+
+	for _, isToken0 := range []bool{true, false} {
+		l := LiquidityForAmount(atTick0, -600, 600, deposit, isToken0)
+		if l.Sign() <= 0 {
+			t.Fatalf("isToken0=%v: liquidity = %s, want positive", isToken0, l)
+		}
+		a0, a1 := AmountsForLiquidity(l, atTick0, -600, 600)
+		got := a1
+		if isToken0 {
+			got = a0
+		}
+		diff := new(big.Int).Sub(got, deposit)
+		if diff.Abs(diff).Cmp(big.NewInt(1e6)) > 0 {
+			t.Errorf("isToken0=%v: round trip gave %s, want ~%s", isToken0, got, deposit)
+		}
+	}
+
+	// Above the range the position holds only token1, so token0 buys nothing.
+	if l := LiquidityForAmount(atTick0, -1200, -600, deposit, true); l.Sign() != 0 {
+		t.Errorf("token0 above range: liquidity = %s, want 0", l)
+	}
+}
+
+func TestPriceToTick(t *testing.T) {
+	tests := []struct {
+		price    float64
+		d0, d1   uint8
+		spacing  int32
+		wantTick int32
+	}{
+		{1, 18, 18, 60, 0},
+		{1.0001, 18, 18, 1, 1},
+		{2, 18, 18, 60, 6960},      // ln2/ln1.0001 ≈ 6931.8, nearest multiple of 60
+		{2000, 18, 6, 10, -200310}, // 18- vs 6-decimal pair, e.g. WETH priced in USDC
+		{0, 18, 18, 60, -887220},
+	}
+	for _, tt := range tests {
+		if got := PriceToTick(tt.price, tt.d0, tt.d1, tt.spacing); got != tt.wantTick {
+			t.Errorf("PriceToTick(%v, %d, %d, %d) = %d, want %d", tt.price, tt.d0, tt.d1, tt.spacing, got, tt.wantTick)
+		}
+	}
+}
+
+func TestSqrtPriceAtTickMatchesTickMath(t *testing.T) {
+	maxSqrt, _ := new(big.Int).SetString("1461446703485210103287273052203988822378723970342", 10)
+	for _, tc := range []struct {
+		tick int32
+		want *big.Int
+	}{
+		{MinTick, big.NewInt(4295128739)},
+		{0, new(big.Int).Lsh(big.NewInt(1), 96)},
+		{MaxTick, maxSqrt},
+	} {
+		if got := SqrtPriceAtTick(tc.tick); got.Cmp(tc.want) != 0 {
+			t.Errorf("SqrtPriceAtTick(%d) = %s, want %s", tc.tick, got, tc.want)
+		}
+	}
+}
+
+func TestMaxAmountsForLiquidityCoversDeposit(t *testing.T) {
+	atTick0 := new(big.Int).Lsh(big.NewInt(1), 96)
+	l := LiquidityForAmount(atTick0, -600, 600, big.NewInt(1e18), true)
+	a0, a1 := AmountsForLiquidity(l, atTick0, -600, 600)
+	m0, m1 := MaxAmountsForLiquidity(l, atTick0, -600, 600)
+	// The exact amounts round up, so they never fall below the float
+	// estimate by more than its own error, and both sides are charged.
+	for _, p := range []struct{ est, max *big.Int }{{a0, m0}, {a1, m1}} {
+		diff := new(big.Int).Sub(p.max, p.est)
+		if p.max.Sign() <= 0 || diff.Abs(diff).Cmp(big.NewInt(1e6)) > 0 {
+			t.Errorf("max %s, float estimate %s", p.max, p.est)
+		}
+	}
+
+	// One unit of liquidity across a range holding only token1 costs a
+	// rounded-up wei, not zero.
+	if m0, m1 := MaxAmountsForLiquidity(big.NewInt(1), atTick0, -1200, -600); m0.Sign() != 0 || m1.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("above range: max = %s, %s; want 0, 1", m0, m1)
+	}
+}
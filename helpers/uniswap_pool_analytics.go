@@ -0,0 +1,242 @@
+package helpers
+
+import (
+	"math"
+	"math/big"
+	"sort"
+
+	"charm-wallet-tui/store"
+)
+
+// ---- V4 pool analytics from indexed events ----
+//
+// Everything here is derived from the event store's v4_swaps and
+// v4_modify_liquidity rows, so it covers only the blocks the indexer has
+// seen: liquidity added before indexing started is missing from the depth
+// histogram and the TVL behind the fee APR.
+
+// secondsPerBlock is the post-merge mainnet slot time, used to turn block
+// spans into durations.
+const secondsPerBlock = 12
+
+// Candle is one OHLC interval of a pool's price (token1 per token0).
+type Candle struct {
+	StartBlock uint64
+	Open       float64
+	High       float64
+	Low        float64
+	Close      float64
+	Volume     float64 // token1 swapped, whole tokens
+	Swaps      int
+}
+
+// BuildCandles buckets swaps (in chain order) into intervalBlocks-wide
+// candles from each swap's post-swap sqrtPrice. Intervals without a swap
+// between the first and last are filled flat at the previous close so the
+// x-axis stays linear in blocks.
+func BuildCandles(swaps []store.V4SwapRow, intervalBlocks uint64, decimals0, decimals1 uint8) []Candle {
+	if len(swaps) == 0 || intervalBlocks == 0 {
+		return nil
+	}
+	var candles []Candle
+	for _, s := range swaps {
+		if s.SqrtPrice == nil || s.SqrtPrice.Sign() == 0 {
+			continue
+		}
+		price := sqrtPriceToPrice(s.SqrtPrice, decimals0, decimals1)
+		start := s.Block - s.Block%intervalBlocks
+		if n := len(candles); n > 0 {
+			last := &candles[n-1]
+			if last.StartBlock == start {
+				last.High = math.Max(last.High, price)
+				last.Low = math.Min(last.Low, price)
+				last.Close = price
+				last.Volume += wholeTokens(new(big.Int).Abs(s.Amount1), decimals1)
+				last.Swaps++
+				continue
+			}
+			for gap := last.StartBlock + intervalBlocks; gap < start; gap += intervalBlocks {
+				c := candles[len(candles)-1].Close
+				candles = append(candles, Candle{StartBlock: gap, Open: c, High: c, Low: c, Close: c})
+			}
+		}
+		open := price
+		if n := len(candles); n > 0 {
+			open = candles[n-1].Close
+		}
+		candles = append(candles, Candle{
+			StartBlock: start,
+			Open:       open,
+			High:       math.Max(open, price),
+			Low:        math.Min(open, price),
+			Close:      price,
+			Volume:     wholeTokens(new(big.Int).Abs(s.Amount1), decimals1),
+			Swaps:      1,
+		})
+	}
+	return candles
+}
+
+// DepthBucket is one bar of the liquidity-depth histogram: the liquidity
+// active at the bucket's lower tick.
+type DepthBucket struct {
+	TickLower int32
+	TickUpper int32
+	Liquidity *big.Int
+}
+
+// LiquidityDepth reconstructs active liquidity by tick from ModifyLiquidity
+// events and samples it into buckets of ticksPerBucket ticks centred on
+// centerTick. A tick's active liquidity is the sum of the deltas of every
+// range containing it; negative sums (removals of liquidity added before
+// indexing began) read as zero.
+func LiquidityDepth(events []store.V4LiquidityRow, centerTick int32, ticksPerBucket int32, buckets int) []DepthBucket {
+	if buckets <= 0 || ticksPerBucket <= 0 {
+		return nil
+	}
+	net := make(map[int64]*big.Int)
+	add := func(tick int64, d *big.Int) {
+		if net[tick] == nil {
+			net[tick] = new(big.Int)
+		}
+		net[tick].Add(net[tick], d)
+	}
+	for _, e := range events {
+		if e.Delta == nil || e.Delta.Sign() == 0 || e.TickLower >= e.TickUpper {
+			continue
+		}
+		add(e.TickLower, e.Delta)
+		add(e.TickUpper, new(big.Int).Neg(e.Delta))
+	}
+	ticks := make([]int64, 0, len(net))
+	for t := range net {
+		ticks = append(ticks, t)
+	}
+	sort.Slice(ticks, func(i, j int) bool { return ticks[i] < ticks[j] })
+
+	first := int64(centerTick) - int64(ticksPerBucket)*int64(buckets/2)
+	first -= first % int64(ticksPerBucket)
+	out := make([]DepthBucket, buckets)
+	active := new(big.Int)
+	next := 0
+	for i := range out {
+		lower := first + int64(i)*int64(ticksPerBucket)
+		for next < len(ticks) && ticks[next] <= lower {
+			active.Add(active, net[ticks[next]])
+			next++
+		}
+		l := new(big.Int).Set(active)
+		if l.Sign() < 0 {
+			l.SetInt64(0)
+		}
+		out[i] = DepthBucket{TickLower: int32(lower), TickUpper: int32(lower + int64(ticksPerBucket)), Liquidity: l}
+	}
+	return out
+}
+
+// FeeEstimate is a pool's fee income over the indexed swaps and the APR it
+// implies for the indexed liquidity.
+type FeeEstimate struct {
+	Fees0       float64 // whole token0 taken as fees
+	Fees1       float64 // whole token1 taken as fees
+	FeesValue   float64 // both, in token1 at the current price
+	TVL         float64 // indexed liquidity's holdings, in token1 at the current price
+	Blocks      uint64  // span of the swaps the fees were earned over
+	APR         float64 // FeesValue/TVL annualised; 0 when either is unknown
+	SwapVolume1 float64 // whole token1 swapped
+}
+
+// EstimateFeeAPR sums each swap's fee (its input amount times its fee in
+// pips) and annualises it against the token1 value, at sqrtPriceX96, of
+// every range reconstructed from events.
+func EstimateFeeAPR(swaps []store.V4SwapRow, events []store.V4LiquidityRow, sqrtPriceX96 *big.Int, decimals0, decimals1 uint8) FeeEstimate {
+	var est FeeEstimate
+	if len(swaps) == 0 || sqrtPriceX96 == nil || sqrtPriceX96.Sign() == 0 {
+		return est
+	}
+	price := sqrtPriceToPrice(sqrtPriceX96, decimals0, decimals1)
+	for _, s := range swaps {
+		fee := float64(s.Fee) / 1e6
+		if s.Amount0 != nil && s.Amount0.Sign() < 0 {
+			est.Fees0 += wholeTokens(new(big.Int).Neg(s.Amount0), decimals0) * fee
+		}
+		if s.Amount1 != nil {
+			if s.Amount1.Sign() < 0 {
+				est.Fees1 += wholeTokens(new(big.Int).Neg(s.Amount1), decimals1) * fee
+			}
+			est.SwapVolume1 += wholeTokens(new(big.Int).Abs(s.Amount1), decimals1)
+		}
+	}
+	est.FeesValue = est.Fees0*price + est.Fees1
+	est.Blocks = swaps[len(swaps)-1].Block - swaps[0].Block
+
+	type rangeKey struct{ lower, upper int64 }
+	ranges := make(map[rangeKey]*big.Int)
+	for _, e := range events {
+		k := rangeKey{e.TickLower, e.TickUpper}
+		if ranges[k] == nil {
+			ranges[k] = new(big.Int)
+		}
+		if e.Delta != nil {
+			ranges[k].Add(ranges[k], e.Delta)
+		}
+	}
+	for k, l := range ranges {
+		if l.Sign() <= 0 || k.lower >= k.upper {
+			continue
+		}
+		a0, a1 := AmountsForLiquidity(l, sqrtPriceX96, int32(k.lower), int32(k.upper))
+		est.TVL += wholeTokens(a0, decimals0)*price + wholeTokens(a1, decimals1)
+	}
+
+	if est.TVL > 0 && est.Blocks > 0 {
+		years := float64(est.Blocks*secondsPerBlock) / (365 * 24 * 3600)
+		est.APR = est.FeesValue / est.TVL / years
+	}
+	return est
+}
+
+// Trader is one swap sender's activity in a pool. The sender is the
+// contract that called the PoolManager — usually a router, not the EOA
+// behind it.
+type Trader struct {
+	Sender string
+	Swaps  int
+	Volume float64 // whole token1 swapped
+}
+
+// TopTraders ranks swaps' senders by token1 volume, returning at most n.
+func TopTraders(swaps []store.V4SwapRow, decimals1 uint8, n int) []Trader {
+	bySender := make(map[string]*Trader)
+	for _, s := range swaps {
+		t := bySender[s.Sender]
+		if t == nil {
+			t = &Trader{Sender: s.Sender}
+			bySender[s.Sender] = t
+		}
+		t.Swaps++
+		if s.Amount1 != nil {
+			t.Volume += wholeTokens(new(big.Int).Abs(s.Amount1), decimals1)
+		}
+	}
+	out := make([]Trader, 0, len(bySender))
+	for _, t := range bySender {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Volume != out[j].Volume {
+			return out[i].Volume > out[j].Volume
+		}
+		return out[i].Sender < out[j].Sender
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// SqrtPriceToPrice converts sqrtPriceX96 to a human-readable price of
+// token0 in token1.
+func SqrtPriceToPrice(sqrtPriceX96 *big.Int, decimals0, decimals1 uint8) float64 {
+	return sqrtPriceToPrice(sqrtPriceX96, decimals0, decimals1)
+}
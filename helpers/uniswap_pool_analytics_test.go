@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"charm-wallet-tui/store"
+)
+
+// sqrtAt returns sqrtPriceX96 for a raw price of p (decimals 0/0).
+func sqrtAt(p float64) *big.Int {
+	f := new(big.Float).SetFloat64(math.Sqrt(p))
+	f.Mul(f, q96)
+	v, _ := f.Int(nil)
+	return v
+}
+
+func TestBuildCandles(t *testing.T) {
+	swaps := []store.V4SwapRow{
+		{Block: 100, Amount1: big.NewInt(-10), SqrtPrice: sqrtAt(4)},
+		{Block: 105, Amount1: big.NewInt(5), SqrtPrice: sqrtAt(9)},
+		{Block: 109, Amount1: big.NewInt(1), SqrtPrice: sqrtAt(1)},
+		{Block: 131, Amount1: big.NewInt(2), SqrtPrice: sqrtAt(16)},
+	}
+	candles := BuildCandles(swaps, 10, 0, 0)
+	if len(candles) != 4 {
+		t.Fatalf("got %d candles, want 4 (two filled, one gap, one filled)", len(candles))
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+
+	c := candles[0]
+	if c.StartBlock != 100 || !near(c.Open, 4) || !near(c.High, 9) || !near(c.Low, 1) || !near(c.Close, 1) {
+		t.Errorf("candle 0 = %+v, want 100 O4 H9 L1 C1", c)
+	}
+	if c.Swaps != 3 || c.Volume != 16 {
+		t.Errorf("candle 0 swaps/volume = %d/%v, want 3/16", c.Swaps, c.Volume)
+	}
+	if gap := candles[1]; gap.StartBlock != 110 || gap.Swaps != 0 || !near(gap.Close, 1) {
+		t.Errorf("gap candle = %+v, want flat at 1 from block 110", gap)
+	}
+	if last := candles[3]; last.StartBlock != 130 || !near(last.Open, 1) || !near(last.Close, 16) {
+		t.Errorf("last candle = %+v, want 130 O1 C16", last)
+	}
+}
+
+func TestLiquidityDepth(t *testing.T) {
+	events := []store.V4LiquidityRow{
+		{TickLower: -100, TickUpper: 100, Delta: big.NewInt(1000)},
+		{TickLower: 0, TickUpper: 200, Delta: big.NewInt(500)},
+		{TickLower: -100, TickUpper: 100, Delta: big.NewInt(-400)},
+	}
+	depth := LiquidityDepth(events, 0, 100, 4) // buckets at -200, -100, 0, 100
+	want := []int64{0, 600, 1100, 500}
+	if len(depth) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(depth), len(want))
+	}
+	for i, b := range depth {
+		if b.TickLower != int32(-200+100*i) || b.Liquidity.Int64() != want[i] {
+			t.Errorf("bucket %d = [%d,%d) %s, want [%d,…) %d", i, b.TickLower, b.TickUpper, b.Liquidity, -200+100*i, want[i])
+		}
+	}
+}
+
+func TestTopTraders(t *testing.T) {
+	swaps := []store.V4SwapRow{
+		{Sender: "0xa", Amount1: big.NewInt(-3)},
+		{Sender: "0xb", Amount1: big.NewInt(10)},
+		{Sender: "0xa", Amount1: big.NewInt(4)},
+		{Sender: "0xc", Amount1: big.NewInt(1)},
+	}
+	top := TopTraders(swaps, 0, 2)
+	if len(top) != 2 || top[0].Sender != "0xb" || top[1].Sender != "0xa" || top[1].Swaps != 2 || top[1].Volume != 7 {
+		t.Errorf("TopTraders = %+v, want 0xb then 0xa (2 swaps, volume 7)", top)
+	}
+}
@@ -0,0 +1,412 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/store"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ---- Liquidity position PnL and impermanent loss ----
+//
+// A position's history is the liquidity added to and removed from it. V4
+// positions take it from the event store's ModifyLiquidity rows, converted
+// to token amounts at the pool price just before each event; V3 positions
+// read the NonfungiblePositionManager's IncreaseLiquidity/DecreaseLiquidity/
+// Collect logs, which carry exact amounts. There are no historical USD
+// prices, so deposits are valued at the pool price of the time in the
+// position's anchor token (a stablecoin when it has one) at today's USD
+// price.
+
+// v3NpmDeployBlock is the mainnet NonfungiblePositionManager's deploy block,
+// the start of its log scan.
+const v3NpmDeployBlock = 12369651
+
+// The V3 log scan runs in v3PnLLogRange-block eth_getLogs calls, halving
+// the range down to monitorLogRange when a provider rejects it, and covers
+// at most the last v3PnLMaxBlocks: the full history since deploy is more
+// calls than a PnL refresh can afford.
+const (
+	v3PnLLogRange  = 10000
+	v3PnLMaxBlocks = 2000000
+)
+
+var (
+	v3IncreaseLiquiditySig = crypto.Keccak256Hash([]byte("IncreaseLiquidity(uint256,uint128,uint256,uint256)"))
+	v3DecreaseLiquiditySig = crypto.Keccak256Hash([]byte("DecreaseLiquidity(uint256,uint128,uint256,uint256)"))
+	v3CollectSig           = crypto.Keccak256Hash([]byte("Collect(uint256,address,uint256,uint256)"))
+)
+
+// v4StateViewFeesABI holds the StateView reads behind a V4 position's
+// uncollected fees.
+const v4StateViewFeesABI = `[
+  {
+    "inputs": [{"name": "poolId", "type": "bytes32"}, {"name": "positionKey", "type": "bytes32"}],
+    "name": "getPositionInfo",
+    "outputs": [
+      {"name": "liquidity",                "type": "uint128"},
+      {"name": "feeGrowthInside0LastX128", "type": "uint256"},
+      {"name": "feeGrowthInside1LastX128", "type": "uint256"}
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {"name": "poolId",    "type": "bytes32"},
+      {"name": "tickLower", "type": "int24"},
+      {"name": "tickUpper", "type": "int24"}
+    ],
+    "name": "getFeeGrowthInside",
+    "outputs": [
+      {"name": "feeGrowthInside0X128", "type": "uint256"},
+      {"name": "feeGrowthInside1X128", "type": "uint256"}
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+// PositionFlow is one deposit into or withdrawal from a position.
+type PositionFlow struct {
+	Block   uint64
+	Deposit bool
+	Amount0 *big.Int
+	Amount1 *big.Int
+	Price   float64 // token0 in token1 when it happened; 0 when unknown
+}
+
+// PositionPnL is a position's performance since it was opened. USD values
+// use today's token prices; DepositUSD converts each deposit at its own
+// pool price first.
+type PositionPnL struct {
+	FirstBlock   uint64 // block of the first deposit found
+	Deposited0   *big.Int
+	Deposited1   *big.Int
+	Withdrawn0   *big.Int
+	Withdrawn1   *big.Int
+	Fees0        *big.Int // collected plus uncollected
+	Fees1        *big.Int
+	DepositUSD   float64 // deposits at their time's price
+	HoldUSD      float64 // deposits at today's prices
+	PositionUSD  float64 // the liquidity now, fees excluded
+	WithdrawnUSD float64 // withdrawals at today's prices
+	FeesUSD      float64
+	ILUSD        float64 // position plus withdrawals against holding; negative is a loss
+	ILPct        float64 // ILUSD as a percentage of HoldUSD
+	NetUSD       float64 // position, withdrawals and fees against DepositUSD
+	Priced       bool    // false when neither token has a USD price
+	Notes        []string
+}
+
+// V4PositionFlows converts a V4 position's indexed ModifyLiquidity events to
+// token flows. An event whose price is unknown is converted at
+// currentSqrtPrice. Fee collections (zero deltas) are skipped.
+func V4PositionFlows(events []store.V4PositionEvent, tickLower, tickUpper int32, d0, d1 uint8, currentSqrtPrice *big.Int) []PositionFlow {
+	var flows []PositionFlow
+	for _, e := range events {
+		if e.Delta == nil || e.Delta.Sign() == 0 {
+			continue
+		}
+		sp, price := e.SqrtPrice, 0.0
+		if sp == nil || sp.Sign() == 0 {
+			sp = currentSqrtPrice
+		} else {
+			price = sqrtPriceToPrice(sp, d0, d1)
+		}
+		if sp == nil {
+			continue
+		}
+		a0, a1 := AmountsForLiquidity(new(big.Int).Abs(e.Delta), sp, tickLower, tickUpper)
+		flows = append(flows, PositionFlow{Block: e.Block, Deposit: e.Delta.Sign() > 0, Amount0: a0, Amount1: a1, Price: price})
+	}
+	return flows
+}
+
+// ComputePositionPnL values pos's flows against its current amounts.
+// collected0/1 are fees already taken out of the position; they are added
+// to its uncollected TokensOwed0/1.
+func ComputePositionPnL(pos LiquidityPosition, flows []PositionFlow, collected0, collected1 *big.Int) *PositionPnL {
+	r := &PositionPnL{
+		Deposited0: new(big.Int), Deposited1: new(big.Int),
+		Withdrawn0: new(big.Int), Withdrawn1: new(big.Int),
+		Fees0: new(big.Int), Fees1: new(big.Int),
+	}
+	for _, f := range []struct{ dst, a, b *big.Int }{
+		{r.Fees0, collected0, pos.TokensOwed0},
+		{r.Fees1, collected1, pos.TokensOwed1},
+	} {
+		if f.a != nil {
+			f.dst.Add(f.dst, f.a)
+		}
+		if f.b != nil {
+			f.dst.Add(f.dst, f.b)
+		}
+		if f.dst.Sign() < 0 {
+			f.dst.SetInt64(0)
+		}
+	}
+
+	current := 0.0
+	if pos.SqrtPriceX96 != nil {
+		current = sqrtPriceToPrice(pos.SqrtPriceX96, pos.Token0Decimals, pos.Token1Decimals)
+	}
+	usd := func(a0, a1 *big.Int) float64 {
+		return wholeTokens(a0, pos.Token0Decimals)*pos.Token0USD + wholeTokens(a1, pos.Token1Decimals)*pos.Token1USD
+	}
+
+	unpriced := 0
+	for _, f := range flows {
+		if !f.Deposit {
+			r.Withdrawn0.Add(r.Withdrawn0, f.Amount0)
+			r.Withdrawn1.Add(r.Withdrawn1, f.Amount1)
+			continue
+		}
+		if r.FirstBlock == 0 {
+			r.FirstBlock = f.Block
+		}
+		r.Deposited0.Add(r.Deposited0, f.Amount0)
+		r.Deposited1.Add(r.Deposited1, f.Amount1)
+
+		price := f.Price
+		if price <= 0 {
+			price = current
+			unpriced++
+		}
+		w0, w1 := wholeTokens(f.Amount0, pos.Token0Decimals), wholeTokens(f.Amount1, pos.Token1Decimals)
+		switch {
+		case pos.AnchorToken1:
+			r.DepositUSD += (w0*price + w1) * pos.Token1USD
+		case price > 0:
+			r.DepositUSD += (w0 + w1/price) * pos.Token0USD
+		default:
+			r.DepositUSD += w0 * pos.Token0USD
+		}
+	}
+
+	r.Priced = pos.Token0USD > 0 || pos.Token1USD > 0
+	if pos.Amount0 != nil && pos.Amount1 != nil {
+		r.PositionUSD = usd(pos.Amount0, pos.Amount1)
+	}
+	r.HoldUSD = usd(r.Deposited0, r.Deposited1)
+	r.WithdrawnUSD = usd(r.Withdrawn0, r.Withdrawn1)
+	r.FeesUSD = usd(r.Fees0, r.Fees1)
+	r.ILUSD = r.PositionUSD + r.WithdrawnUSD - r.HoldUSD
+	if r.HoldUSD > 0 {
+		r.ILPct = r.ILUSD / r.HoldUSD * 100
+	}
+	r.NetUSD = r.PositionUSD + r.WithdrawnUSD + r.FeesUSD - r.DepositUSD
+
+	if len(flows) == 0 {
+		r.Notes = append(r.Notes, "no deposits found")
+	}
+	if unpriced > 0 {
+		r.Notes = append(r.Notes, fmt.Sprintf("%d deposit(s) valued at today's price", unpriced))
+	}
+	if !r.Priced {
+		r.Notes = append(r.Notes, "neither token has a USD price")
+	}
+	return r
+}
+
+// LoadPositionPnL builds pos's PnL: V3 positions from the position
+// manager's logs over rpcURL, V4 positions from st's indexed events. V4
+// fees collected before now are not in the store, so only uncollected fees
+// count.
+func LoadPositionPnL(rpcURL string, st *store.Store, pos LiquidityPosition) (*PositionPnL, error) {
+	if pos.Stub {
+		return nil, fmt.Errorf("position %s was not read", pos.TokenID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+	defer client.Close()
+	addrs := addressesForClient(ctx, client)
+
+	if pos.Version == PoolVersionV3 {
+		chainID, _ := client.ChainID(ctx)
+		from := uint64(0)
+		if isMainnet(chainID) {
+			from = v3NpmDeployBlock
+		}
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("block number: %w", err)
+		}
+		partial := head > from+v3PnLMaxBlocks
+		if partial {
+			from = head - v3PnLMaxBlocks
+		}
+		flows, collected0, collected1, err := v3PositionFlows(ctx, client, addrs.V3PositionManager, pos, from, head)
+		if err != nil {
+			return nil, err
+		}
+		r := ComputePositionPnL(pos, flows, collected0, collected1)
+		if partial {
+			r.Notes = append(r.Notes, fmt.Sprintf("partial: only the last %d blocks scanned, older deposits and collects are missing", v3PnLMaxBlocks))
+		}
+		return r, nil
+	}
+
+	if st == nil {
+		return nil, fmt.Errorf("event store unavailable")
+	}
+	poolID := ComputePoolId(pos.Token0, pos.Token1, pos.Hooks, pos.Fee, pos.TickSpacing)
+	salt := common.BigToHash(pos.TokenID)
+	events, err := st.V4PositionEvents(poolID.Hex(), addrs.V4PositionManager.Hex(), salt.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("read position events: %w", err)
+	}
+	flows := V4PositionFlows(events, pos.TickLower, pos.TickUpper, pos.Token0Decimals, pos.Token1Decimals, pos.SqrtPriceX96)
+	r := ComputePositionPnL(pos, flows, nil, nil)
+	if len(events) == 0 {
+		r.Notes = append(r.Notes, "pool events not indexed for this position")
+	}
+	return r, nil
+}
+
+// v3PositionFlows reads pos's IncreaseLiquidity and DecreaseLiquidity logs
+// in fromBlock..toBlock (see v3PositionLogs). The collected fees it returns are Collect amounts less the
+// principal decreased; they go negative while decreased principal is still
+// uncollected, which its TokensOwed then makes up.
+func v3PositionFlows(ctx context.Context, client *ethclient.Client, npm common.Address, pos LiquidityPosition, fromBlock, toBlock uint64) ([]PositionFlow, *big.Int, *big.Int, error) {
+	logs, err := v3PositionLogs(ctx, client, ethereum.FilterQuery{
+		Addresses: []common.Address{npm},
+		Topics: [][]common.Hash{
+			{v3IncreaseLiquiditySig, v3DecreaseLiquiditySig, v3CollectSig},
+			{common.BigToHash(pos.TokenID)},
+		},
+	}, fromBlock, toBlock)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("position manager logs: %w", err)
+	}
+
+	collected0, collected1 := new(big.Int), new(big.Int)
+	var flows []PositionFlow
+	for _, l := range logs {
+		if len(l.Topics) < 2 || len(l.Data) < 96 {
+			continue
+		}
+		w := func(i int) *big.Int { return new(big.Int).SetBytes(l.Data[i*32 : (i+1)*32]) }
+		switch l.Topics[0] {
+		case v3CollectSig:
+			// Data: recipient, amount0, amount1.
+			collected0.Add(collected0, w(1))
+			collected1.Add(collected1, w(2))
+		case v3IncreaseLiquiditySig, v3DecreaseLiquiditySig:
+			// Data: liquidity, amount0, amount1.
+			f := PositionFlow{Block: l.BlockNumber, Deposit: l.Topics[0] == v3IncreaseLiquiditySig, Amount0: w(1), Amount1: w(2)}
+			f.Price = v3FlowPrice(w(0), f.Amount0, f.Amount1, pos)
+			if !f.Deposit {
+				collected0.Sub(collected0, f.Amount0)
+				collected1.Sub(collected1, f.Amount1)
+			}
+			flows = append(flows, f)
+		}
+	}
+	return flows, collected0, collected1, nil
+}
+
+// v3PositionLogs runs query over fromBlock..toBlock in ranged eth_getLogs
+// calls, like the pool monitor's catchUp.
+func v3PositionLogs(ctx context.Context, client *ethclient.Client, query ethereum.FilterQuery, fromBlock, toBlock uint64) ([]types.Log, error) {
+	var all []types.Log
+	span := uint64(v3PnLLogRange)
+	for from := fromBlock; from <= toBlock; {
+		to := from + span - 1
+		if to > toBlock {
+			to = toBlock
+		}
+		q := query
+		q.FromBlock, q.ToBlock = new(big.Int).SetUint64(from), new(big.Int).SetUint64(to)
+		logs, err := client.FilterLogs(ctx, q)
+		if err != nil {
+			if span > monitorLogRange && ctx.Err() == nil {
+				span /= 2
+				continue
+			}
+			return nil, fmt.Errorf("blocks %d–%d: %w", from, to, err)
+		}
+		all = append(all, logs...)
+		from = to + 1
+	}
+	return all, nil
+}
+
+// v3FlowPrice recovers the pool price (token0 in token1) a liquidity change
+// happened at from its amounts: in range, amount1 = L·(√P − √Pa). A one-sided
+// change only bounds the price, so the nearer range edge is used.
+func v3FlowPrice(liquidity, amount0, amount1 *big.Int, pos LiquidityPosition) float64 {
+	switch {
+	case liquidity.Sign() == 0:
+		return 0
+	case amount1.Sign() == 0:
+		return pos.MinPrice
+	case amount0.Sign() == 0:
+		return pos.MaxPrice
+	}
+	l, _ := new(big.Float).SetInt(liquidity).Float64()
+	a1, _ := new(big.Float).SetInt(amount1).Float64()
+	sp := a1/l + sqrtAtTick(pos.TickLower)
+	return sp * sp * math.Pow(10, float64(int(pos.Token0Decimals)-int(pos.Token1Decimals)))
+}
+
+// v4UncollectedFees computes a V4 position's fees since its last checkpoint:
+// the growth inside its range since then, times its liquidity, over 2^128.
+// Growth counters wrap, so the difference is taken mod 2^256.
+func v4UncollectedFees(ctx context.Context, client *ethclient.Client, stateView common.Address, poolId, posKey common.Hash, tickLower, tickUpper int32) (fees0, fees1 *big.Int, err error) {
+	parsed, err := abi.JSON(strings.NewReader(v4StateViewFeesABI))
+	if err != nil {
+		return nil, nil, err
+	}
+	call := func(method string, args ...interface{}) ([]interface{}, error) {
+		data, err := parsed.Pack(method, args...)
+		if err != nil {
+			return nil, err
+		}
+		out, err := client.CallContract(ctx, ethereum.CallMsg{To: &stateView, Data: data}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return parsed.Unpack(method, out)
+	}
+	info, err := call("getPositionInfo", poolId, posKey)
+	if err != nil || len(info) < 3 {
+		return nil, nil, fmt.Errorf("getPositionInfo: %w", err)
+	}
+	inside, err := call("getFeeGrowthInside", poolId, big.NewInt(int64(tickLower)), big.NewInt(int64(tickUpper)))
+	if err != nil || len(inside) < 2 {
+		return nil, nil, fmt.Errorf("getFeeGrowthInside: %w", err)
+	}
+	liquidity, _ := info[0].(*big.Int)
+	if liquidity == nil {
+		return nil, nil, fmt.Errorf("getPositionInfo: no liquidity")
+	}
+	mod := new(big.Int).Lsh(big.NewInt(1), 256)
+	owed := func(now, last interface{}) *big.Int {
+		n, _ := now.(*big.Int)
+		l, _ := last.(*big.Int)
+		if n == nil || l == nil {
+			return new(big.Int)
+		}
+		d := new(big.Int).Sub(n, l)
+		d.Mod(d, mod)
+		return d.Rsh(d.Mul(d, liquidity), 128)
+	}
+	return owed(inside[0], info[1]), owed(inside[1], info[2]), nil
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"charm-wallet-tui/store"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPositionPnL(t *testing.T) {
+	usdc := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	liquidity := big.NewInt(1e18)
+
+	// Deposited at price 1, now at tick 0 too: no IL, PnL is the fees.
+	pos := LiquidityPosition{
+		Token0: other, Token1: usdc, Token0Decimals: 18, Token1Decimals: 18,
+		TickLower: -600, TickUpper: 600, Liquidity: liquidity,
+		SqrtPriceX96: SqrtPriceAtTick(0), TokensOwed0: big.NewInt(0), TokensOwed1: big.NewInt(5e17),
+	}
+	positions := []LiquidityPosition{pos}
+	valuePositions(positions, map[common.Address]float64{usdc: 1})
+	pos = positions[0]
+	if !pos.AnchorToken1 || pos.Token1USD != 1 {
+		t.Fatalf("anchor = token1 %v at $%f, want USDC at $1", pos.AnchorToken1, pos.Token1USD)
+	}
+
+	events := []store.V4PositionEvent{
+		{Block: 10, Delta: liquidity, SqrtPrice: SqrtPriceAtTick(0)},
+		{Block: 11, Delta: big.NewInt(0), SqrtPrice: SqrtPriceAtTick(0)}, // fee collection
+	}
+	flows := V4PositionFlows(events, pos.TickLower, pos.TickUpper, 18, 18, pos.SqrtPriceX96)
+	if len(flows) != 1 || !flows[0].Deposit {
+		t.Fatalf("flows = %+v, want one deposit", flows)
+	}
+	r := ComputePositionPnL(pos, flows, nil, nil)
+	if math.Abs(r.ILUSD) > 1e-6 {
+		t.Errorf("IL at unchanged price = %f, want 0", r.ILUSD)
+	}
+	if math.Abs(r.NetUSD-0.5) > 1e-6 || math.Abs(r.FeesUSD-0.5) > 1e-6 {
+		t.Errorf("net = %f, fees = %f; want both 0.5", r.NetUSD, r.FeesUSD)
+	}
+
+	// Price moved up ~3%, still in range: the position sold token0 on the
+	// way and lags holding.
+	moved := pos
+	moved.SqrtPriceX96 = SqrtPriceAtTick(300)
+	moved.TokensOwed1 = big.NewInt(0)
+	positions = []LiquidityPosition{moved}
+	valuePositions(positions, map[common.Address]float64{usdc: 1})
+	r = ComputePositionPnL(positions[0], flows, nil, nil)
+	if r.ILUSD >= 0 || r.ILPct >= 0 || r.ILPct < -2 {
+		t.Errorf("IL after +3%% = %f (%.3f%%), want a small loss", r.ILUSD, r.ILPct)
+	}
+	if r.NetUSD <= 0 {
+		t.Errorf("net after +3%% = %f, want a gain from the token0 held", r.NetUSD)
+	}
+}
+
+func TestV3FlowPrice(t *testing.T) {
+	pos := LiquidityPosition{TickLower: -600, TickUpper: 600, MinPrice: 0.94, MaxPrice: 1.06}
+	liquidity := big.NewInt(1e18)
+	a0, a1 := AmountsForLiquidity(liquidity, SqrtPriceAtTick(100), pos.TickLower, pos.TickUpper)
+	want := math.Pow(1.0001, 100)
+	if got := v3FlowPrice(liquidity, a0, a1, pos); math.Abs(got-want) > 1e-6 {
+		t.Errorf("recovered price = %f, want %f", got, want)
+	}
+	if got := v3FlowPrice(liquidity, a0, big.NewInt(0), pos); got != pos.MinPrice {
+		t.Errorf("token0-only price = %f, want the range minimum", got)
+	}
+}
@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ---- Liquidity position valuation ----
+
+// InRange reports whether the pool's current tick lies inside the position's
+// range, i.e. the position is earning fees. False when the tick is unknown.
+func (p LiquidityPosition) InRange() bool {
+	return p.PoolTickKnown && p.TickLower <= p.PoolTick && p.PoolTick < p.TickUpper
+}
+
+// positionUSDPrices returns the USD price of one whole token for the tokens
+// the app can price without an oracle: the chain's stablecoins at $1, and
+// ETH/WETH from the WETH/USDC 0.05% V3 pool.
+func positionUSDPrices(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses) map[common.Address]float64 {
+	prices := make(map[common.Address]float64)
+	for _, stable := range []common.Address{addrs.USDC, addrs.USDT, addrs.DAI} {
+		if stable != (common.Address{}) {
+			prices[stable] = 1
+		}
+	}
+	if addrs.FactoryV3 == (common.Address{}) || addrs.USDC == (common.Address{}) {
+		return prices
+	}
+	pool, err := v3FactoryGetPool(ctx, client, addrs.FactoryV3, addrs.WETH, addrs.USDC, 500)
+	if err != nil || pool == (common.Address{}) {
+		return prices
+	}
+	sqrtPrice, _, err := v3ReadSlot0(ctx, client, pool)
+	if err != nil {
+		return prices
+	}
+	token0, token1 := addrs.WETH, addrs.USDC
+	if bytes.Compare(token1.Bytes(), token0.Bytes()) < 0 {
+		token0, token1 = token1, token0
+	}
+	p := sqrtPriceToPrice(sqrtPrice, v4ERC20Decimals(ctx, client, token0), v4ERC20Decimals(ctx, client, token1))
+	if token0 == addrs.USDC && p > 0 {
+		p = 1 / p
+	}
+	if p > 0 {
+		prices[addrs.WETH] = p
+		prices[common.Address{}] = p
+	}
+	return prices
+}
+
+// sqrtPriceToPrice converts sqrtPriceX96 to a human-readable price of
+// token0 in token1.
+func sqrtPriceToPrice(sqrtPriceX96 *big.Int, decimals0, decimals1 uint8) float64 {
+	sp, _ := new(big.Float).Quo(new(big.Float).SetInt(sqrtPriceX96), q96).Float64()
+	return sp * sp * math.Pow(10, float64(int(decimals0)-int(decimals1)))
+}
+
+// valuePositions fills in each position's token amounts, token prices and
+// USD value (liquidity plus uncollected fees). A token without a known price is valued
+// through the position's own pool from the other side; a pair with neither
+// side priced is left at 0.
+func valuePositions(positions []LiquidityPosition, prices map[common.Address]float64) {
+	for i := range positions {
+		p := &positions[i]
+		if p.Stub || p.SqrtPriceX96 == nil {
+			continue
+		}
+		p.Amount0, p.Amount1 = AmountsForLiquidity(p.Liquidity, p.SqrtPriceX96, p.TickLower, p.TickUpper)
+
+		price := sqrtPriceToPrice(p.SqrtPriceX96, p.Token0Decimals, p.Token1Decimals)
+		p0, ok0 := prices[p.Token0]
+		p1, ok1 := prices[p.Token1]
+		switch {
+		case ok0 && !ok1 && price > 0:
+			p1 = p0 / price
+		case ok1 && !ok0:
+			p0 = p1 * price
+		case !ok0 && !ok1:
+			continue
+		}
+		// Stablecoins are priced at exactly $1; prefer converting through
+		// one, so deposit-time values need no historical USD price.
+		p.Token0USD, p.Token1USD = p0, p1
+		p.AnchorToken1 = ok1 && (p1 == 1 || p0 != 1)
+		total0 := new(big.Int).Set(p.Amount0)
+		total1 := new(big.Int).Set(p.Amount1)
+		if p.TokensOwed0 != nil {
+			total0.Add(total0, p.TokensOwed0)
+		}
+		if p.TokensOwed1 != nil {
+			total1.Add(total1, p.TokensOwed1)
+		}
+		p.USDValue = wholeTokens(total0, p.Token0Decimals)*p0 + wholeTokens(total1, p.Token1Decimals)*p1
+	}
+}
+
+// wholeTokens converts a base-unit amount to whole tokens.
+func wholeTokens(amount *big.Int, decimals uint8) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(math.Pow(10, float64(decimals)))).Float64()
+	return f
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAmountsForLiquidity(t *testing.T) {
+	liquidity := big.NewInt(1e18)
+	atTick0 := new(big.Int).Lsh(big.NewInt(1), 96) // sqrtPriceX96 at tick 0
+
+	// In range: both tokens, roughly equal around price 1.
+	a0, a1 := AmountsForLiquidity(liquidity, atTick0, -60, 60)
+	if a0.Sign() <= 0 || a1.Sign() <= 0 {
+		t.Fatalf("in range: amounts = %s, %s; want both positive", a0, a1)
+	}
+	if r := new(big.Float).Quo(new(big.Float).SetInt(a0), new(big.Float).SetInt(a1)); r.Cmp(big.NewFloat(0.99)) < 0 || r.Cmp(big.NewFloat(1.01)) > 0 {
+		t.Errorf("in range: amount0/amount1 = %s, want ~1", r.Text('f', 4))
+	}
+
+	// Price below the range: all token0. Above: all token1.
+	if a0, a1 := AmountsForLiquidity(liquidity, atTick0, 60, 120); a0.Sign() <= 0 || a1.Sign() != 0 {
+		t.Errorf("below range: amounts = %s, %s; want token0 only", a0, a1)
+	}
+	if a0, a1 := AmountsForLiquidity(liquidity, atTick0, -120, -60); a0.Sign() != 0 || a1.Sign() <= 0 {
+		t.Errorf("above range: amounts = %s, %s; want token1 only", a0, a1)
+	}
+}
+
+func TestValuePositions(t *testing.T) {
+	usdc := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	prices := map[common.Address]float64{usdc: 1}
+
+	// other/USDC at price 1 (both 18 decimals here), in range, plus fees.
+	pos := LiquidityPosition{
+		Token0: other, Token1: usdc, Token0Decimals: 18, Token1Decimals: 18,
+		TickLower: -60, TickUpper: 60, Liquidity: big.NewInt(1e18),
+		SqrtPriceX96: new(big.Int).Lsh(big.NewInt(1), 96), PoolTick: 0, PoolTickKnown: true,
+		TokensOwed0: big.NewInt(1e18), TokensOwed1: big.NewInt(0),
+	}
+	positions := []LiquidityPosition{pos, {Stub: true}}
+	valuePositions(positions, prices)
+
+	got := positions[0]
+	if !got.InRange() {
+		t.Error("position at tick 0 in [-60, 60) should be in range")
+	}
+	want := wholeTokens(got.Amount0, 18) + wholeTokens(got.Amount1, 18) + 1
+	if math.Abs(got.USDValue-want) > 1e-6 {
+		t.Errorf("USDValue = %f, want %f", got.USDValue, want)
+	}
+	if positions[1].USDValue != 0 || positions[1].Amount0 != nil {
+		t.Error("stub position should be left unvalued")
+	}
+}
@@ -0,0 +1,397 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// RouteHop is one pool traversal within a Route.
+type RouteHop struct {
+	Pool     ResolvedPool
+	TokenIn  common.Address
+	TokenOut common.Address
+	Quote    *SwapQuote // this hop's own quote within the route
+}
+
+// Route is a quoted exact-input swap path of one to three hops.
+type Route struct {
+	Hops        []RouteHop
+	AmountIn    *big.Int
+	AmountOut   *big.Int
+	PriceImpact float64  // compounded across hops, in percent
+	GasUnits    uint64   // rough estimate, see routeGasUnits
+	GasCostOut  *big.Int // GasUnits at the current gas price, in output-token units; nil when it couldn't be priced
+}
+
+// NetOut returns AmountOut less the estimated gas cost, the figure
+// QuoteRoutes ranks routes by.
+func (r Route) NetOut() *big.Int {
+	if r.GasCostOut == nil {
+		return r.AmountOut
+	}
+	return new(big.Int).Sub(r.AmountOut, r.GasCostOut)
+}
+
+// Tokens returns the route's token path, input first.
+func (r Route) Tokens() []common.Address {
+	if len(r.Hops) == 0 {
+		return nil
+	}
+	out := []common.Address{r.Hops[0].TokenIn}
+	for _, h := range r.Hops {
+		out = append(out, h.TokenOut)
+	}
+	return out
+}
+
+// RouteGraph holds the liquid pools found between a swap's two tokens and
+// the hub tokens, keyed by routeEdgeKey. Built once per token pair by
+// DiscoverRouteGraph and re-quoted by QuoteRoutes as the amount changes.
+type RouteGraph struct {
+	TokenIn  common.Address
+	TokenOut common.Address
+	Hubs     []common.Address
+	Pools    map[string][]ResolvedPool
+}
+
+// PoolsFor returns the liquid pools between a and b.
+func (g RouteGraph) PoolsFor(a, b common.Address) []ResolvedPool {
+	return g.Pools[routeEdgeKey(a, b)]
+}
+
+// Direct returns the liquid pools pairing the swap's two tokens directly.
+func (g RouteGraph) Direct() []ResolvedPool {
+	return g.PoolsFor(g.TokenIn, g.TokenOut)
+}
+
+// Paths returns every token path of at most maxHops hops from TokenIn to
+// TokenOut whose edges all have a liquid pool.
+func (g RouteGraph) Paths(maxHops int) [][]common.Address {
+	var mids []common.Address
+	for _, h := range g.Hubs {
+		if h != g.TokenIn && h != g.TokenOut {
+			mids = append(mids, h)
+		}
+	}
+	var candidates [][]common.Address
+	candidates = append(candidates, []common.Address{g.TokenIn, g.TokenOut})
+	if maxHops >= 2 {
+		for _, h := range mids {
+			candidates = append(candidates, []common.Address{g.TokenIn, h, g.TokenOut})
+		}
+	}
+	if maxHops >= 3 {
+		for _, h1 := range mids {
+			for _, h2 := range mids {
+				if h1 != h2 {
+					candidates = append(candidates, []common.Address{g.TokenIn, h1, h2, g.TokenOut})
+				}
+			}
+		}
+	}
+
+	var paths [][]common.Address
+	for _, p := range candidates {
+		ok := true
+		for i := 0; i+1 < len(p); i++ {
+			if len(g.PoolsFor(p[i], p[i+1])) == 0 {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// RouteHubs returns the intermediate tokens the router may route through on
+// the network addrs describes.
+func RouteHubs(addrs UniswapNetworkAddresses) []common.Address {
+	var hubs []common.Address
+	for _, a := range []common.Address{addrs.WETH, addrs.USDC, addrs.USDT, addrs.DAI, addrs.WBTC} {
+		if a != (common.Address{}) {
+			hubs = append(hubs, a)
+		}
+	}
+	return hubs
+}
+
+// routeEdgeKey normalizes two token addresses into an order-independent key.
+func routeEdgeKey(a, b common.Address) string {
+	ah, bh := strings.ToLower(a.Hex()), strings.ToLower(b.Hex())
+	if ah > bh {
+		ah, bh = bh, ah
+	}
+	return ah + "_" + bh
+}
+
+// DiscoverRouteGraph looks up the liquid pools on every edge between
+// tokenIn, tokenOut and the hub tokens, one goroutine per edge. Only the
+// direct edge pays for resolveV4Pool's live log scan; hub edges use the
+// vendored V4 index.
+func DiscoverRouteGraph(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, tokenIn, tokenOut common.Address) RouteGraph {
+	g := RouteGraph{TokenIn: tokenIn, TokenOut: tokenOut, Hubs: RouteHubs(addrs), Pools: make(map[string][]ResolvedPool)}
+
+	nodes := []common.Address{tokenIn, tokenOut}
+	for _, h := range g.Hubs {
+		if h != tokenIn && h != tokenOut {
+			nodes = append(nodes, h)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < len(nodes); i++ {
+		for j := i + 1; j < len(nodes); j++ {
+			a, b := nodes[i], nodes[j]
+			direct := i == 0 && j == 1
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				pools := LiquidPools(ctx, client, addrs, a, b, direct)
+				if len(pools) == 0 {
+					return
+				}
+				mu.Lock()
+				g.Pools[routeEdgeKey(a, b)] = pools
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+	return g
+}
+
+// Rough per-hop gas costs for ranking routes against each other. They only
+// need to be in proportion; the packaged transaction's gas limit is set
+// separately.
+const (
+	routeBaseGas  = 100000
+	routeV2HopGas = 60000
+	routeV3HopGas = 80000
+	routeV4HopGas = 90000
+)
+
+// routeGasUnits estimates the gas a route's swap transaction uses.
+func routeGasUnits(hops []RouteHop) uint64 {
+	gas := uint64(routeBaseGas)
+	for _, h := range hops {
+		switch h.Pool.Version {
+		case PoolVersionV2:
+			gas += routeV2HopGas
+		case PoolVersionV3:
+			gas += routeV3HopGas
+		default:
+			gas += routeV4HopGas
+		}
+	}
+	return gas
+}
+
+// QuoteHop quotes amountIn of tokenIn through a single pool.
+func QuoteHop(client *ethclient.Client, addrs UniswapNetworkAddresses, pool ResolvedPool, tokenIn, tokenOut common.Address, amountIn *big.Int) (*SwapQuote, error) {
+	switch pool.Version {
+	case PoolVersionV3:
+		return GetV3SwapQuote(client, addrs.QuoterV2, pool.PairAddr, tokenIn, tokenOut, pool.V3Fee, amountIn)
+	case PoolVersionV4:
+		return GetV4SwapQuote(client, addrs, pool.V4Key, pool.V4PoolID, tokenIn, amountIn)
+	default:
+		return GetSwapQuote(client, pool.PairAddr, tokenIn, amountIn)
+	}
+}
+
+// routeQuoter memoizes per-hop quotes for one QuoteRoutes pass: routes that
+// share a prefix (every path through the same first hub) share its quotes.
+type routeQuoter struct {
+	client *ethclient.Client
+	addrs  UniswapNetworkAddresses
+	graph  RouteGraph
+
+	mu   sync.Mutex
+	memo map[string]hopResult
+}
+
+type hopResult struct {
+	hop RouteHop
+	ok  bool
+}
+
+// bestHop quotes amountIn across every pool on the a→b edge and returns the
+// one paying out the most.
+func (q *routeQuoter) bestHop(a, b common.Address, amountIn *big.Int, multiHop bool) hopResult {
+	key := fmt.Sprintf("%s>%s:%s:%t", a.Hex(), b.Hex(), amountIn, multiHop)
+	q.mu.Lock()
+	if r, ok := q.memo[key]; ok {
+		q.mu.Unlock()
+		return r
+	}
+	q.mu.Unlock()
+
+	var best hopResult
+	for _, pool := range q.graph.PoolsFor(a, b) {
+		if multiHop && pool.Version == PoolVersionV4 {
+			// The Universal Router's multi-hop V4 input layout is not
+			// pinned against the deployed router, so V4 pools are only
+			// swapped through directly.
+			continue
+		}
+		quote, err := QuoteHop(q.client, q.addrs, pool, a, b, amountIn)
+		if err != nil || quote.AmountOut == nil || quote.AmountOut.Sign() <= 0 {
+			continue
+		}
+		if !best.ok || quote.AmountOut.Cmp(best.hop.Quote.AmountOut) > 0 {
+			best = hopResult{hop: RouteHop{Pool: pool, TokenIn: a, TokenOut: b, Quote: quote}, ok: true}
+		}
+	}
+
+	q.mu.Lock()
+	q.memo[key] = best
+	q.mu.Unlock()
+	return best
+}
+
+// quotePath walks path hop by hop, feeding each hop's output into the next.
+func (q *routeQuoter) quotePath(path []common.Address, amountIn *big.Int) (Route, bool) {
+	r := Route{AmountIn: amountIn}
+	amount := amountIn
+	keep := 1.0
+	for i := 0; i+1 < len(path); i++ {
+		res := q.bestHop(path[i], path[i+1], amount, len(path) > 2)
+		if !res.ok {
+			return Route{}, false
+		}
+		r.Hops = append(r.Hops, res.hop)
+		amount = res.hop.Quote.AmountOut
+		if res.hop.Quote.PriceImpact > 0 {
+			keep *= 1 - res.hop.Quote.PriceImpact/100
+		}
+	}
+	r.AmountOut = amount
+	r.PriceImpact = (1 - keep) * 100
+	r.GasUnits = routeGasUnits(r.Hops)
+	return r, true
+}
+
+// routeQuoteWorkers bounds how many paths QuoteRoutes quotes at once.
+const routeQuoteWorkers = 8
+
+// QuoteRoutes quotes amountIn along every path in g of at most maxHops hops
+// and returns the routes ranked by output after estimated gas, best first.
+// Gas is priced in the output token through the graph's WETH→TokenOut pools;
+// when the output token has no WETH pool, routes are ranked on raw output.
+func QuoteRoutes(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, g RouteGraph, amountIn *big.Int, maxHops int) ([]Route, error) {
+	paths := g.Paths(maxHops)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no Uniswap route found")
+	}
+
+	q := &routeQuoter{client: client, addrs: addrs, graph: g, memo: make(map[string]hopResult)}
+	results := make([]Route, len(paths))
+	found := make([]bool, len(paths))
+	sem := make(chan struct{}, routeQuoteWorkers)
+	var wg sync.WaitGroup
+	for i, p := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], found[i] = q.quotePath(p, amountIn)
+		}()
+	}
+	wg.Wait()
+
+	var routes []Route
+	for i, r := range results {
+		if found[i] {
+			routes = append(routes, r)
+		}
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("every Uniswap route failed to quote")
+	}
+
+	if gasPrice, err := client.SuggestGasPrice(ctx); err == nil && gasPrice.Sign() > 0 {
+		q.priceGas(routes, gasPrice)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].NetOut().Cmp(routes[j].NetOut()) > 0
+	})
+	return routes, nil
+}
+
+// priceGas fills in GasCostOut for each route. The ETH→TokenOut rate is
+// quoted once, at a two-hop route's gas cost so the reference trade is the
+// same order of size as what it prices.
+func (q *routeQuoter) priceGas(routes []Route, gasPrice *big.Int) {
+	refGas := new(big.Int).Mul(gasPrice, big.NewInt(routeBaseGas+2*routeV3HopGas))
+	weth, out := q.addrs.WETH, q.graph.TokenOut
+
+	refOut := refGas
+	if out != weth {
+		res := q.bestHop(weth, out, refGas, false)
+		if !res.ok {
+			return
+		}
+		refOut = res.hop.Quote.AmountOut
+	}
+	for i := range routes {
+		wei := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(routes[i].GasUnits))
+		routes[i].GasCostOut = new(big.Int).Div(new(big.Int).Mul(wei, refOut), refGas)
+	}
+}
+
+// PoolQuote is one pool's quote in a side-by-side comparison.
+type PoolQuote struct {
+	Pool  ResolvedPool
+	Quote *SwapQuote
+	Err   error
+}
+
+// QuotePools quotes amountIn of tokenIn through each of pools in parallel,
+// in the order given. V4 pools found through the vendored index carry no
+// liquidity figure, so it is read from the StateView alongside the quote.
+func QuotePools(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, pools []ResolvedPool, tokenIn, tokenOut common.Address, amountIn *big.Int) []PoolQuote {
+	stateViewABI, abiErr := abi.JSON(strings.NewReader(poolManagerViewABI))
+	out := make([]PoolQuote, len(pools))
+	var wg sync.WaitGroup
+	for i, pool := range pools {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if pool.Version == PoolVersionV4 && pool.Liquidity == nil && abiErr == nil && addrs.V4StateView != (common.Address{}) {
+				if _, _, _, _, liq, err := v4GetSlot0(ctx, client, &stateViewABI, addrs.V4StateView, pool.V4PoolID); err == nil {
+					pool.Liquidity = liq
+				}
+			}
+			quote, err := QuoteHop(client, addrs, pool, tokenIn, tokenOut, amountIn)
+			out[i] = PoolQuote{Pool: pool, Quote: quote, Err: err}
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+// SingleHopRoute wraps one pool's quote as a Route, for swapping through a
+// pool the user picked over the router's choice.
+func SingleHopRoute(pq PoolQuote, tokenIn, tokenOut common.Address) Route {
+	hops := []RouteHop{{Pool: pq.Pool, TokenIn: tokenIn, TokenOut: tokenOut, Quote: pq.Quote}}
+	return Route{
+		Hops:        hops,
+		AmountIn:    pq.Quote.AmountIn,
+		AmountOut:   pq.Quote.AmountOut,
+		PriceImpact: pq.Quote.PriceImpact,
+		GasUnits:    routeGasUnits(hops),
+	}
+}
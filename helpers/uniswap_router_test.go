@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRouteGraphPaths(t *testing.T) {
+	in := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	out := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	weth := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	usdc := common.HexToAddress("0x00000000000000000000000000000000000000d4")
+	pool := []ResolvedPool{{Version: PoolVersionV2}}
+
+	// in—weth, weth—usdc, usdc—out: only the two-hub path connects.
+	g := RouteGraph{TokenIn: in, TokenOut: out, Hubs: []common.Address{weth, usdc}, Pools: map[string][]ResolvedPool{
+		routeEdgeKey(in, weth):   pool,
+		routeEdgeKey(usdc, weth): pool,
+		routeEdgeKey(out, usdc):  pool,
+	}}
+	if got := g.Paths(2); len(got) != 0 {
+		t.Fatalf("Paths(2) = %v, want none", got)
+	}
+	got := g.Paths(3)
+	if len(got) != 1 || len(got[0]) != 4 || got[0][1] != weth || got[0][2] != usdc {
+		t.Fatalf("Paths(3) = %v, want [in weth usdc out]", got)
+	}
+
+	// A direct pool is always tried first.
+	g.Pools[routeEdgeKey(out, in)] = pool
+	if got := g.Paths(3); len(got) != 2 || len(got[0]) != 2 {
+		t.Fatalf("Paths(3) with direct pool = %v", got)
+	}
+}
+
+func TestRouteNetOut(t *testing.T) {
+	hops := []RouteHop{{Pool: ResolvedPool{Version: PoolVersionV3}}, {Pool: ResolvedPool{Version: PoolVersionV2}}}
+	if got, want := routeGasUnits(hops), uint64(routeBaseGas+routeV3HopGas+routeV2HopGas); got != want {
+		t.Fatalf("routeGasUnits = %d, want %d", got, want)
+	}
+
+	r := Route{AmountOut: big.NewInt(1000)}
+	if r.NetOut().Int64() != 1000 {
+		t.Fatalf("NetOut without gas cost = %s", r.NetOut())
+	}
+	r.GasCostOut = big.NewInt(150)
+	if r.NetOut().Int64() != 850 {
+		t.Fatalf("NetOut = %s, want 850", r.NetOut())
+	}
+}
+
+func TestResolveV4PoolsListsEveryKey(t *testing.T) {
+	if len(OndoV4Pools) == 0 {
+		t.Skip("no vendored V4 pools")
+	}
+	a, b := OndoV4Pools[0].Currency0, OndoV4Pools[0].Currency1
+	want := 0
+	for _, p := range OndoV4Pools {
+		if (p.Currency0 == a && p.Currency1 == b) || (p.Currency0 == b && p.Currency1 == a) {
+			want++
+		}
+	}
+	got := resolveV4Pools(context.Background(), nil, UniswapNetworkAddresses{}, b, a, false)
+	if len(got) != want {
+		t.Fatalf("resolveV4Pools = %d pools, want every vendored key (%d)", len(got), want)
+	}
+	seen := map[common.Hash]bool{}
+	for _, p := range got {
+		if p.Version != PoolVersionV4 || seen[p.V4PoolID] {
+			t.Errorf("unexpected pool %+v", p)
+		}
+		seen[p.V4PoolID] = true
+	}
+}
@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ---- Uniswap V3 NonfungiblePositionManager positions ----
+//
+// V3 positions live on the NonfungiblePositionManager (an ERC-721 like V4's
+// PositionManager, so the v4 enumeration helpers serve both). Unlike V4, the
+// manager keeps the position's fee accounting itself: positions() reports
+// only fees already checkpointed into tokensOwed, so the uncollected total is
+// read by simulating collect() from the owner, which pokes the pool first.
+
+const v3PositionManagerABI = `[
+  {
+    "inputs": [{"name": "tokenId", "type": "uint256"}],
+    "name": "positions",
+    "outputs": [
+      {"name": "nonce",                    "type": "uint96"},
+      {"name": "operator",                 "type": "address"},
+      {"name": "token0",                   "type": "address"},
+      {"name": "token1",                   "type": "address"},
+      {"name": "fee",                      "type": "uint24"},
+      {"name": "tickLower",                "type": "int24"},
+      {"name": "tickUpper",                "type": "int24"},
+      {"name": "liquidity",                "type": "uint128"},
+      {"name": "feeGrowthInside0LastX128", "type": "uint256"},
+      {"name": "feeGrowthInside1LastX128", "type": "uint256"},
+      {"name": "tokensOwed0",              "type": "uint128"},
+      {"name": "tokensOwed1",              "type": "uint128"}
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [{"name": "params", "type": "tuple", "components": [
+      {"name": "tokenId",    "type": "uint256"},
+      {"name": "recipient",  "type": "address"},
+      {"name": "amount0Max", "type": "uint128"},
+      {"name": "amount1Max", "type": "uint128"}
+    ]}],
+    "name": "collect",
+    "outputs": [{"name": "amount0", "type": "uint256"}, {"name": "amount1", "type": "uint256"}],
+    "stateMutability": "payable",
+    "type": "function"
+  }
+]`
+
+// maxUint128 is collect()'s "everything owed" amount cap.
+var maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// getV3LiquidityPositions enumerates owner's V3 position NFTs (up to 50) and
+// reads each one's range, liquidity, uncollected fees and pool tick.
+func getV3LiquidityPositions(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, owner common.Address, syms *v4SymbolCache) ([]LiquidityPosition, uint64, []string, error) {
+	npm := addrs.V3PositionManager
+	if npm == (common.Address{}) {
+		return nil, 0, nil, nil
+	}
+	balance, err := v4BalanceOf(ctx, client, npm, owner)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("V3 balanceOf: %w", err)
+	}
+	diags := []string{fmt.Sprintf("V3 balanceOf: %d NFT(s)", balance)}
+	if balance == 0 {
+		return nil, 0, diags, nil
+	}
+
+	npmABI, err := abi.JSON(strings.NewReader(v3PositionManagerABI))
+	if err != nil {
+		return nil, balance, diags, fmt.Errorf("parse V3 PositionManager ABI: %w", err)
+	}
+	tokenIds, enumDiags, err := v4EnumerateOwnedTokenIds(ctx, client, npm, owner, balance)
+	diags = append(diags, enumDiags...)
+	if err != nil {
+		return nil, balance, diags, fmt.Errorf("enumerate V3 token IDs: %w", err)
+	}
+
+	positions := make([]LiquidityPosition, 0, len(tokenIds))
+	for _, tokenId := range tokenIds {
+		pos, err := v3FetchPosition(ctx, client, &npmABI, npm, tokenId)
+		if err != nil {
+			diags = append(diags, fmt.Sprintf("V3 tokenId %s: positions() error: %v", tokenId, err))
+			positions = append(positions, LiquidityPosition{TokenID: tokenId, Version: PoolVersionV3, Stub: true})
+			continue
+		}
+
+		if owed0, owed1, err := v3SimulateCollect(ctx, client, &npmABI, npm, owner, tokenId); err == nil {
+			pos.TokensOwed0, pos.TokensOwed1 = owed0, owed1
+		} else {
+			diags = append(diags, fmt.Sprintf("V3 tokenId %s: collect() simulation failed, showing checkpointed fees: %v", tokenId, err))
+		}
+
+		if pool, err := v3FactoryGetPool(ctx, client, addrs.FactoryV3, pos.Token0, pos.Token1, pos.Fee); err == nil && pool != (common.Address{}) {
+			if sqrtPrice, tick, err := v3ReadSlot0(ctx, client, pool); err == nil {
+				pos.SqrtPriceX96, pos.PoolTick, pos.PoolTickKnown = sqrtPrice, tick, true
+			}
+		}
+
+		pos.Token0Symbol = syms.getOrFetch(ctx, client, pos.Token0)
+		if pos.Token0Symbol == "" {
+			pos.Token0Symbol = pos.Token0.Hex()[:10]
+		}
+		pos.Token1Symbol = syms.getOrFetch(ctx, client, pos.Token1)
+		if pos.Token1Symbol == "" {
+			pos.Token1Symbol = pos.Token1.Hex()[:10]
+		}
+		pos.Token0Decimals = v4ERC20Decimals(ctx, client, pos.Token0)
+		pos.Token1Decimals = v4ERC20Decimals(ctx, client, pos.Token1)
+		pos.MinPrice = v4TickToPrice(pos.TickLower, pos.Token0Decimals, pos.Token1Decimals)
+		pos.MaxPrice = v4TickToPrice(pos.TickUpper, pos.Token0Decimals, pos.Token1Decimals)
+
+		positions = append(positions, pos)
+	}
+	return positions, balance, diags, nil
+}
+
+// v3FetchPosition decodes NonfungiblePositionManager.positions(tokenId).
+// TokensOwed0/1 hold the checkpointed fees until v3SimulateCollect replaces
+// them with the full uncollected amount.
+func v3FetchPosition(ctx context.Context, client *ethclient.Client, npmABI *abi.ABI, npm common.Address, tokenId *big.Int) (LiquidityPosition, error) {
+	pos := LiquidityPosition{TokenID: tokenId, Version: PoolVersionV3}
+	data, err := npmABI.Pack("positions", tokenId)
+	if err != nil {
+		return pos, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &npm, Data: data}, nil)
+	if err != nil {
+		return pos, err
+	}
+	vals, err := npmABI.Unpack("positions", out)
+	if err != nil || len(vals) < 12 {
+		return pos, fmt.Errorf("unpack positions: %w", err)
+	}
+	pos.Token0, _ = vals[2].(common.Address)
+	pos.Token1, _ = vals[3].(common.Address)
+	if fee, ok := vals[4].(*big.Int); ok {
+		pos.Fee = uint32(fee.Uint64())
+	}
+	if tl, ok := vals[5].(*big.Int); ok {
+		pos.TickLower = int32(tl.Int64())
+	}
+	if tu, ok := vals[6].(*big.Int); ok {
+		pos.TickUpper = int32(tu.Int64())
+	}
+	pos.Liquidity, _ = vals[7].(*big.Int)
+	pos.TokensOwed0, _ = vals[10].(*big.Int)
+	pos.TokensOwed1, _ = vals[11].(*big.Int)
+	return pos, nil
+}
+
+// v3SimulateCollect static-calls collect(tokenId, owner, max, max) from
+// owner, returning every fee the position could collect right now.
+func v3SimulateCollect(ctx context.Context, client *ethclient.Client, npmABI *abi.ABI, npm, owner common.Address, tokenId *big.Int) (amount0, amount1 *big.Int, err error) {
+	data, err := npmABI.Pack("collect", struct {
+		TokenId    *big.Int
+		Recipient  common.Address
+		Amount0Max *big.Int
+		Amount1Max *big.Int
+	}{tokenId, owner, maxUint128, maxUint128})
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{From: owner, To: &npm, Data: data}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	vals, err := npmABI.Unpack("collect", out)
+	if err != nil || len(vals) < 2 {
+		return nil, nil, fmt.Errorf("unpack collect: %w", err)
+	}
+	amount0, _ = vals[0].(*big.Int)
+	amount1, _ = vals[1].(*big.Int)
+	return amount0, amount1, nil
+}
+
+// v3ReadSlot0 reads a V3 pool's sqrtPriceX96 and current tick.
+func v3ReadSlot0(ctx context.Context, client *ethclient.Client, pool common.Address) (*big.Int, int32, error) {
+	data, err := client.CallContract(ctx, ethereum.CallMsg{To: &pool, Data: v3Slot0Selector}, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("slot0 call failed: %w", err)
+	}
+	if len(data) < 64 {
+		return nil, 0, fmt.Errorf("slot0 returned short data: %d bytes", len(data))
+	}
+	return new(big.Int).SetBytes(data[0:32]), v4DecodeInt24Slot(data[32:64]), nil
+}
@@ -36,10 +36,11 @@ const v4PositionManagerViewABI = `[
   }
 ]`
 
-// LiquidityPosition represents a Uniswap V4 NFT liquidity position.
+// LiquidityPosition represents a Uniswap V3 or V4 NFT liquidity position.
 type LiquidityPosition struct {
 	TokenID        *big.Int
-	Stub           bool           // true when positions() call failed; only TokenID is populated
+	Version        PoolVersion    // PoolVersionV3 or PoolVersionV4
+	Stub           bool           // true when positions() call failed; only TokenID and Version are populated
 	Token0         common.Address // currency0
 	Token1         common.Address // currency1
 	Token0Symbol   string
@@ -47,22 +48,39 @@ type LiquidityPosition struct {
 	Token0Decimals uint8
 	Token1Decimals uint8
 	Fee            uint32  // fee tier in hundredths of a bip (3000 = 0.3%)
-	TickSpacing    int32   // V4 tick spacing
+	TickSpacing    int32   // V4 tick spacing (0 for V3)
 	Hooks          common.Address
 	TickLower      int32
 	TickUpper      int32
 	Liquidity      *big.Int
-	TokensOwed0    *big.Int // nil for V4 (different fee model)
-	TokensOwed1    *big.Int // nil for V4 (different fee model)
+	TokensOwed0    *big.Int // uncollected fees: V3 collect() simulation, V4 StateView fee growth; nil when unread
+	TokensOwed1    *big.Int // uncollected fees: V3 collect() simulation, V4 StateView fee growth; nil when unread
 	MinPrice       float64  // human-readable price of token0 in token1 at tickLower
 	MaxPrice       float64  // human-readable price of token0 in token1 at tickUpper
+
+	// Pool state and valuation (see uniswap_position_value.go); zero when
+	// the pool's slot0 could not be read.
+	SqrtPriceX96  *big.Int
+	PoolTick      int32
+	PoolTickKnown bool
+	Amount0       *big.Int // token0 the liquidity is worth at the current price
+	Amount1       *big.Int // token1 the liquidity is worth at the current price
+	USDValue      float64  // liquidity plus uncollected fees; 0 when neither token can be priced
+	Token0USD     float64  // USD price of one whole token0; 0 when unpriced
+	Token1USD     float64  // USD price of one whole token1; 0 when unpriced
+	AnchorToken1  bool     // deposit-time values convert through token1 rather than token0
+
+	// PnL is filled in later by LoadPositionPnL (see uniswap_position_pnl.go).
+	PnL *PositionPnL
 }
 
-// GetLiquidityPositions fetches all Uniswap V4 NFT positions held by ownerAddr.
-// Caps at 50 to avoid excessive RPC calls.
-// Returns positions, total NFT balance, diagnostic log lines, and any error.
+// GetLiquidityPositions fetches the Uniswap V3 and V4 NFT positions held by
+// ownerAddr, V3 first, each valued at its pool's current price.
+// Caps at 50 per version to avoid excessive RPC calls.
+// Returns positions, total NFT balance, diagnostic log lines, and any error;
+// the error is only returned when neither version could be read.
 func GetLiquidityPositions(rpcURL string, ownerAddr common.Address) ([]LiquidityPosition, uint64, []string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
 	defer cancel()
 
 	client, err := ethclient.DialContext(ctx, rpcURL)
@@ -72,6 +90,28 @@ func GetLiquidityPositions(rpcURL string, ownerAddr common.Address) ([]Liquidity
 	defer client.Close()
 
 	addrs := addressesForClient(ctx, client)
+	syms := newV4SymbolCache()
+
+	v3Positions, v3Balance, diags, v3Err := getV3LiquidityPositions(ctx, client, addrs, ownerAddr, syms)
+	v4Positions, v4Balance, v4Diags, v4Err := getV4LiquidityPositions(ctx, client, addrs, ownerAddr, syms)
+	diags = append(diags, v4Diags...)
+	if v3Err != nil && v4Err != nil {
+		return nil, v3Balance + v4Balance, diags, fmt.Errorf("%v; %v", v3Err, v4Err)
+	}
+	for _, err := range []error{v3Err, v4Err} {
+		if err != nil {
+			diags = append(diags, "error: "+err.Error())
+		}
+	}
+
+	positions := append(v3Positions, v4Positions...)
+	valuePositions(positions, positionUSDPrices(ctx, client, addrs))
+	return positions, v3Balance + v4Balance, diags, nil
+}
+
+// getV4LiquidityPositions fetches the V4 PositionManager NFTs held by
+// ownerAddr (up to 50), with each pool's current tick from StateView.
+func getV4LiquidityPositions(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, ownerAddr common.Address, syms *v4SymbolCache) ([]LiquidityPosition, uint64, []string, error) {
 	posManager := addrs.V4PositionManager
 	stateView := addrs.V4StateView
 
@@ -87,15 +127,18 @@ func GetLiquidityPositions(rpcURL string, ownerAddr common.Address) ([]Liquidity
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("parse StateView ABI: %w", err)
 	}
+	poolViewABI, err := abi.JSON(strings.NewReader(poolManagerViewABI))
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("parse pool view ABI: %w", err)
+	}
 
-	diags := []string{fmt.Sprintf("balanceOf: %d NFT(s)", balance)}
+	diags := []string{fmt.Sprintf("V4 balanceOf: %d NFT(s)", balance)}
 	tokenIds, enumDiags, enumErr := v4EnumerateOwnedTokenIds(ctx, client, posManager, ownerAddr, balance)
 	diags = append(diags, enumDiags...)
 	if enumErr != nil {
 		return nil, balance, diags, fmt.Errorf("enumerate token IDs: %w", enumErr)
 	}
 
-	syms := newV4SymbolCache()
 	positions := make([]LiquidityPosition, 0, len(tokenIds))
 
 	for _, tokenId := range tokenIds {
@@ -103,14 +146,23 @@ func GetLiquidityPositions(rpcURL string, ownerAddr common.Address) ([]Liquidity
 		if err != nil {
 			diags = append(diags, fmt.Sprintf("tokenId %s: positions() error: %v", tokenId, err))
 			// Still include a stub so the token ID is visible in the panel.
-			positions = append(positions, LiquidityPosition{TokenID: tokenId, Stub: true})
+			positions = append(positions, LiquidityPosition{TokenID: tokenId, Version: PoolVersionV4, Stub: true})
 			continue
 		}
+		pos.Version = PoolVersionV4
 
 		// Fetch per-position liquidity from StateView.
 		poolId := v4ComputePoolId(pos.Token0, pos.Token1, pos.Hooks, pos.Fee, pos.TickSpacing)
 		posKey := v4ComputePositionKey(posManager, tokenId, pos.TickLower, pos.TickUpper)
 		pos.Liquidity = v4FetchPositionLiquidity(ctx, client, stateView, &stateViewABI, poolId, posKey)
+		if fees0, fees1, err := v4UncollectedFees(ctx, client, stateView, poolId, posKey, pos.TickLower, pos.TickUpper); err == nil {
+			pos.TokensOwed0, pos.TokensOwed1 = fees0, fees1
+		} else {
+			diags = append(diags, fmt.Sprintf("tokenId %s: uncollected fees not read: %v", tokenId, err))
+		}
+		if sqrtPrice, tick, _, _, _, err := v4GetSlot0(ctx, client, &poolViewABI, stateView, poolId); err == nil {
+			pos.SqrtPriceX96, pos.PoolTick, pos.PoolTickKnown = sqrtPrice, tick, true
+		}
 
 		if pos.Liquidity == nil || pos.Liquidity.Sign() == 0 {
 			diags = append(diags, fmt.Sprintf("tokenId %s: liquidity=0", tokenId))
@@ -0,0 +1,356 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ---- Pool event monitor filters ----
+//
+// A filter expression is a space-separated list of terms, all of which an
+// event must match; a term's comma-separated values are alternatives:
+//
+//	pool:0x<pool id>        kind:swap,liquidity
+//	token:USDC,0x<address>  sender:0x<address>
+//	min:25k                 (minimum swap size in USD)
+//
+// Pools, senders and kinds narrow the log subscription itself where the
+// event's indexed topics allow it; tokens and swap size can only be checked
+// once an event is decoded.
+
+// poolEventKinds maps the kind: names to PoolManager event names.
+var poolEventKinds = map[string]string{
+	"init":       "Initialize",
+	"initialize": "Initialize",
+	"liquidity":  "ModifyLiquidity",
+	"modify":     "ModifyLiquidity",
+	"swap":       "Swap",
+	"donate":     "Donate",
+	"transfer":   "Transfer",
+	"operator":   "OperatorSet",
+	"fee":        "ProtocolFeeUpdated",
+	"controller": "ProtocolFeeControllerUpdated",
+	"owner":      "OwnershipTransferred",
+}
+
+// poolEventKindNames is the kind: name String writes for each event.
+var poolEventKindNames = map[string]string{
+	"Initialize":                   "init",
+	"ModifyLiquidity":              "liquidity",
+	"Swap":                         "swap",
+	"Donate":                       "donate",
+	"Transfer":                     "transfer",
+	"OperatorSet":                  "operator",
+	"ProtocolFeeUpdated":           "fee",
+	"ProtocolFeeControllerUpdated": "controller",
+	"OwnershipTransferred":         "owner",
+}
+
+// poolIndexedEvents carry the pool id as their first indexed topic;
+// senderIndexedEvents carry the PoolManager caller as their second.
+var (
+	poolIndexedEvents   = map[string]bool{"Initialize": true, "ModifyLiquidity": true, "Swap": true, "Donate": true, "ProtocolFeeUpdated": true}
+	senderIndexedEvents = map[string]bool{"ModifyLiquidity": true, "Swap": true, "Donate": true}
+)
+
+// PoolEventFilter selects which PoolManager events the monitor shows and
+// stores. The zero value passes everything.
+type PoolEventFilter struct {
+	Pools      []common.Hash
+	Currencies []common.Address
+	Symbols    []string // upper-cased
+	Senders    []common.Address
+	MinSwapUSD float64  // swaps below this, or that cannot be priced, are dropped; 0 = off
+	Kinds      []string // PoolManager event names; empty = all
+}
+
+// ParsePoolEventFilter parses a filter expression (see above). An empty
+// expression is the empty filter.
+func ParsePoolEventFilter(expr string) (PoolEventFilter, error) {
+	var f PoolEventFilter
+	for _, term := range strings.Fields(expr) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			return f, fmt.Errorf("%q: expected key:value (pool, token, sender, min or kind)", term)
+		}
+		for _, v := range strings.Split(value, ",") {
+			if v == "" {
+				continue
+			}
+			switch strings.ToLower(key) {
+			case "pool":
+				if !common.IsHexAddress(v) && len(strings.TrimPrefix(v, "0x")) == 64 {
+					f.Pools = append(f.Pools, common.HexToHash(v))
+					continue
+				}
+				return f, fmt.Errorf("pool:%s: expected a 32-byte pool id", v)
+			case "token":
+				switch {
+				case common.IsHexAddress(v):
+					f.Currencies = append(f.Currencies, common.HexToAddress(v))
+				case strings.EqualFold(v, "ETH"):
+					f.Currencies = append(f.Currencies, common.Address{})
+				default:
+					f.Symbols = append(f.Symbols, strings.ToUpper(v))
+				}
+			case "sender":
+				if !common.IsHexAddress(v) {
+					return f, fmt.Errorf("sender:%s: expected an address", v)
+				}
+				f.Senders = append(f.Senders, common.HexToAddress(v))
+			case "min":
+				usd, err := parseAmount(v)
+				if err != nil {
+					return f, fmt.Errorf("min:%s: %w", v, err)
+				}
+				f.MinSwapUSD = usd
+			case "kind":
+				name, ok := poolEventKinds[strings.ToLower(v)]
+				if !ok {
+					return f, fmt.Errorf("kind:%s: expected init, liquidity, swap, donate, transfer, operator, fee, controller or owner", v)
+				}
+				f.Kinds = append(f.Kinds, name)
+			default:
+				return f, fmt.Errorf("%q: unknown key %q (pool, token, sender, min or kind)", term, key)
+			}
+		}
+	}
+	return f, nil
+}
+
+// parseAmount parses "25000", "$25000", "25_000", "25k" or "1.5m".
+func parseAmount(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(s), "$"), "_", "")
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"):
+		mult, s = 1e3, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		mult, s = 1e6, strings.TrimSuffix(s, "m")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("expected an amount like 25000 or 25k")
+	}
+	return v * mult, nil
+}
+
+// Empty reports whether f passes every event.
+func (f PoolEventFilter) Empty() bool {
+	return len(f.Pools) == 0 && len(f.Currencies) == 0 && len(f.Symbols) == 0 &&
+		len(f.Senders) == 0 && f.MinSwapUSD == 0 && len(f.Kinds) == 0
+}
+
+// kinds is the set of event names f can pass: its Kinds, narrowed to the
+// events that carry a pool (or sender) when it filters on one.
+func (f PoolEventFilter) kinds(all []string) []string {
+	var out []string
+	for _, name := range all {
+		switch {
+		case len(f.Kinds) > 0 && !containsString(f.Kinds, name):
+		case (len(f.Pools) > 0 || len(f.Currencies) > 0 || len(f.Symbols) > 0) && !poolIndexedEvents[name]:
+		case len(f.Senders) > 0 && !senderIndexedEvents[name]:
+		case f.MinSwapUSD > 0 && len(f.Kinds) == 0 && name != "Swap":
+			// A size floor with no kinds given means "big swaps".
+		default:
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// topics builds the subscription's topic filter from f's kinds, pools and
+// senders. eventIDs maps event names to their signature hashes.
+func (f PoolEventFilter) topics(eventIDs map[string]common.Hash) [][]common.Hash {
+	names := make([]string, 0, len(eventIDs))
+	for name := range eventIDs {
+		names = append(names, name)
+	}
+	kinds := f.kinds(names)
+	sigs := make([]common.Hash, 0, len(kinds))
+	for _, name := range kinds {
+		sigs = append(sigs, eventIDs[name])
+	}
+	if len(sigs) == 0 {
+		// Nothing can match; a topic no event has keeps the query valid.
+		sigs = []common.Hash{{}}
+	}
+	topics := [][]common.Hash{sigs}
+	if len(f.Pools) > 0 {
+		topics = append(topics, f.Pools)
+	}
+	if len(f.Senders) > 0 {
+		if len(topics) == 1 {
+			topics = append(topics, nil)
+		}
+		senders := make([]common.Hash, len(f.Senders))
+		for i, s := range f.Senders {
+			senders[i] = common.BytesToHash(s.Bytes())
+		}
+		topics = append(topics, senders)
+	}
+	return topics
+}
+
+// MatchPool reports whether a pool with these currencies passes f's pool and
+// token terms. Symbols are compared case-insensitively.
+func (f PoolEventFilter) MatchPool(id common.Hash, currency0, currency1 common.Address, sym0, sym1 string) bool {
+	if len(f.Pools) > 0 && !containsHash(f.Pools, id) {
+		return false
+	}
+	if len(f.Currencies) == 0 && len(f.Symbols) == 0 {
+		return true
+	}
+	for _, c := range []common.Address{currency0, currency1} {
+		for _, want := range f.Currencies {
+			if c == want {
+				return true
+			}
+		}
+	}
+	for _, s := range []string{sym0, sym1} {
+		if s != "" && containsString(f.Symbols, strings.ToUpper(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders f back as an expression.
+func (f PoolEventFilter) String() string {
+	var terms []string
+	join := func(key string, vals []string) {
+		if len(vals) > 0 {
+			terms = append(terms, key+":"+strings.Join(vals, ","))
+		}
+	}
+	var pools, tokens, senders, kinds []string
+	for _, p := range f.Pools {
+		pools = append(pools, p.Hex())
+	}
+	for _, c := range f.Currencies {
+		if c == (common.Address{}) {
+			tokens = append(tokens, "ETH")
+		} else {
+			tokens = append(tokens, c.Hex())
+		}
+	}
+	tokens = append(tokens, f.Symbols...)
+	for _, s := range f.Senders {
+		senders = append(senders, s.Hex())
+	}
+	for _, k := range f.Kinds {
+		kinds = append(kinds, poolEventKindNames[k])
+	}
+	join("pool", pools)
+	join("token", tokens)
+	join("sender", senders)
+	if f.MinSwapUSD > 0 {
+		terms = append(terms, "min:"+strconv.FormatFloat(f.MinSwapUSD, 'f', -1, 64))
+	}
+	join("kind", kinds)
+	return strings.Join(terms, " ")
+}
+
+// passes checks a log against f's terms, including the ones the
+// subscription could not apply. Token terms need the pool's key, from its
+// Initialize event or st.poolKey; events of unknown pools fail them.
+func (f PoolEventFilter) passes(ctx context.Context, client *ethclient.Client, st *monitorState, name string, lg types.Log) bool {
+	if !containsString(f.kinds([]string{name}), name) {
+		return false
+	}
+	if len(f.Senders) > 0 && (len(lg.Topics) < 3 || !containsAddress(f.Senders, common.BytesToAddress(lg.Topics[2].Bytes()))) {
+		return false
+	}
+	if len(f.Pools) == 0 && len(f.Currencies) == 0 && len(f.Symbols) == 0 && f.MinSwapUSD == 0 {
+		return true
+	}
+	if len(lg.Topics) < 2 {
+		return false
+	}
+	id := lg.Topics[1]
+	key, ok := st.poolKey(id)
+	if name == "Initialize" && len(lg.Topics) >= 4 {
+		key = v4PoolKey{
+			Currency0: common.BytesToAddress(lg.Topics[2].Bytes()),
+			Currency1: common.BytesToAddress(lg.Topics[3].Bytes()),
+		}
+		ok = true
+	}
+	if len(f.Currencies) > 0 || len(f.Symbols) > 0 {
+		if !ok {
+			return false
+		}
+		var sym0, sym1 string
+		if len(f.Symbols) > 0 {
+			sym0, sym1 = st.syms.getOrFetch(ctx, client, key.Currency0), st.syms.getOrFetch(ctx, client, key.Currency1)
+		}
+		if !f.MatchPool(id, key.Currency0, key.Currency1, sym0, sym1) {
+			return false
+		}
+	} else if len(f.Pools) > 0 && !containsHash(f.Pools, id) {
+		return false
+	}
+	if f.MinSwapUSD > 0 && name == "Swap" {
+		return ok && st.swapUSD(ctx, client, key, lg) >= f.MinSwapUSD
+	}
+	return true
+}
+
+// swapUSD sizes a Swap log in USD from whichever side has a price; 0 when
+// neither does.
+func (st *monitorState) swapUSD(ctx context.Context, client *ethclient.Client, key v4PoolKey, lg types.Log) float64 {
+	var ev v4SwapEvent
+	if err := st.parsedABI.UnpackIntoInterface(&ev, "Swap", lg.Data); err != nil {
+		return 0
+	}
+	size := 0.0
+	for _, side := range []struct {
+		currency common.Address
+		amount   *big.Int
+	}{{key.Currency0, ev.Amount0}, {key.Currency1, ev.Amount1}} {
+		price, ok := st.prices[side.currency]
+		if !ok || side.amount == nil {
+			continue
+		}
+		usd := wholeTokens(new(big.Int).Abs(side.amount), st.decimals(ctx, client, side.currency)) * price
+		if usd > size {
+			size = usd
+		}
+	}
+	return size
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(list []common.Hash, h common.Hash) bool {
+	for _, v := range list {
+		if v == h {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(list []common.Address, a common.Address) bool {
+	for _, v := range list {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
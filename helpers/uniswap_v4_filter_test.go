@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParsePoolEventFilter(t *testing.T) {
+	pool := "0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"
+	usdc := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	f, err := ParsePoolEventFilter("pool:" + pool + " token:" + usdc + ",weth,ETH min:25k kind:swap,liquidity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Pools) != 1 || f.Pools[0] != common.HexToHash(pool) {
+		t.Errorf("Pools = %v", f.Pools)
+	}
+	if len(f.Currencies) != 2 || f.Currencies[0] != common.HexToAddress(usdc) || f.Currencies[1] != (common.Address{}) {
+		t.Errorf("Currencies = %v", f.Currencies)
+	}
+	if len(f.Symbols) != 1 || f.Symbols[0] != "WETH" {
+		t.Errorf("Symbols = %v", f.Symbols)
+	}
+	if f.MinSwapUSD != 25000 {
+		t.Errorf("MinSwapUSD = %v", f.MinSwapUSD)
+	}
+	if strings.Join(f.Kinds, ",") != "Swap,ModifyLiquidity" {
+		t.Errorf("Kinds = %v", f.Kinds)
+	}
+
+	again, err := ParsePoolEventFilter(f.String())
+	if err != nil || again.String() != f.String() {
+		t.Errorf("round trip %q → %q (%v)", f.String(), again.String(), err)
+	}
+
+	for _, bad := range []string{"pool:0x1234", "sender:bob", "min:lots", "kind:mint", "usdc", "chain:1"} {
+		if _, err := ParsePoolEventFilter(bad); err == nil {
+			t.Errorf("ParsePoolEventFilter(%q) accepted", bad)
+		}
+	}
+	if f, err := ParsePoolEventFilter("  "); err != nil || !f.Empty() {
+		t.Errorf("blank expression = %+v, %v", f, err)
+	}
+}
+
+func TestPoolEventFilterTopics(t *testing.T) {
+	st, err := newMonitorState(PoolEventFilter{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topics := (PoolEventFilter{}).topics(st.eventIDs); len(topics) != 1 || len(topics[0]) != len(st.eventIDs) {
+		t.Errorf("empty filter topics = %v", topics)
+	}
+
+	sender := common.HexToAddress("0x66a9893cC07D91D95644AEDD05D03f95e1dBA8Af")
+	f := PoolEventFilter{Senders: []common.Address{sender}}
+	topics := f.topics(st.eventIDs)
+	if len(topics) != 3 || topics[1] != nil || topics[2][0] != common.BytesToHash(sender.Bytes()) {
+		t.Fatalf("sender topics = %v", topics)
+	}
+	if len(topics[0]) != 3 {
+		t.Errorf("sender filter kinds = %d events, want ModifyLiquidity, Swap and Donate", len(topics[0]))
+	}
+
+	// A swap size floor with no kinds means swaps only.
+	if topics := (PoolEventFilter{MinSwapUSD: 1}).topics(st.eventIDs); len(topics[0]) != 1 || topics[0][0] != st.eventIDs["Swap"] {
+		t.Errorf("min filter topics = %v", topics)
+	}
+	// Kinds that cannot carry a pool id match nothing once pools are set.
+	f = PoolEventFilter{Pools: []common.Hash{{1}}, Kinds: []string{"Transfer"}}
+	if topics := f.topics(st.eventIDs); topics[0][0] != (common.Hash{}) {
+		t.Errorf("impossible filter topics = %v", topics)
+	}
+}
+
+func TestPoolEventFilterMatchPool(t *testing.T) {
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	f, _ := ParsePoolEventFilter("token:eth,usdt")
+	if !f.MatchPool(common.Hash{}, common.Address{}, usdc, "ETH", "USDC") {
+		t.Error("native ETH pool rejected")
+	}
+	if !f.MatchPool(common.Hash{}, usdc, common.HexToAddress("0x1"), "USDC", "usdt") {
+		t.Error("symbol match is not case-insensitive")
+	}
+	if f.MatchPool(common.Hash{}, usdc, common.HexToAddress("0x1"), "USDC", "DAI") {
+		t.Error("unrelated pool matched")
+	}
+}
@@ -0,0 +1,295 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ---- V4 hook inspection ----
+//
+// A V4 hook's permissions are encoded in the low 14 bits of its address
+// (the PoolManager calls exactly the callbacks whose bits are set), so they
+// can be read without touching the chain. InspectHook adds what the chain
+// can tell: whether there is code, whether that code is an upgradeable
+// proxy, and who deployed it. Source verification needs a block explorer
+// and is not checked.
+
+// HookRisk grades how much a hook can interfere with a swap or position.
+type HookRisk int
+
+const (
+	HookRiskLow HookRisk = iota
+	HookRiskMedium
+	HookRiskHigh
+)
+
+func (r HookRisk) String() string {
+	switch r {
+	case HookRiskHigh:
+		return "HIGH"
+	case HookRiskMedium:
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
+
+// HookPermission is one callback a hook address enables.
+type HookPermission struct {
+	Flag uint16
+	Name string
+	Note string // what the callback lets the hook do
+	Risk HookRisk
+}
+
+// hookPermissions lists the V4 Hooks library's permission bits, highest
+// first.
+var hookPermissions = []HookPermission{
+	{1 << 13, "beforeInitialize", "runs when the pool is created", HookRiskLow},
+	{1 << 12, "afterInitialize", "runs after the pool is created", HookRiskLow},
+	{1 << 11, "beforeAddLiquidity", "can gate or revert deposits", HookRiskLow},
+	{1 << 10, "afterAddLiquidity", "runs after deposits", HookRiskLow},
+	{1 << 9, "beforeRemoveLiquidity", "can revert withdrawals, locking LPs in", HookRiskMedium},
+	{1 << 8, "afterRemoveLiquidity", "runs after withdrawals", HookRiskLow},
+	{1 << 7, "beforeSwap", "runs on every swap; can revert it or set its fee", HookRiskMedium},
+	{1 << 6, "afterSwap", "runs after every swap; can revert it", HookRiskMedium},
+	{1 << 5, "beforeDonate", "runs before donations", HookRiskLow},
+	{1 << 4, "afterDonate", "runs after donations", HookRiskLow},
+	{1 << 3, "beforeSwapReturnDelta", "can take or replace the swap's input and output", HookRiskHigh},
+	{1 << 2, "afterSwapReturnDelta", "can take a cut of the swap's output", HookRiskHigh},
+	{1 << 1, "afterAddLiquidityReturnDelta", "can change what a deposit costs", HookRiskHigh},
+	{1 << 0, "afterRemoveLiquidityReturnDelta", "can take a cut of withdrawals", HookRiskHigh},
+}
+
+// dynamicFeeFlag is the pool-key fee value marking an LP fee the hook sets.
+const dynamicFeeFlag = 0x800000
+
+// HookPermissions decodes the callbacks hook's address enables.
+func HookPermissions(hook common.Address) []HookPermission {
+	flags := (uint16(hook[18])<<8 | uint16(hook[19])) & 0x3FFF
+	var out []HookPermission
+	for _, p := range hookPermissions {
+		if flags&p.Flag != 0 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// EIP-1967 proxy storage slots: keccak256 of the slot name, minus one.
+var (
+	eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	eip1967BeaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	eip1967AdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+// EIP-1167 minimal proxy bytecode around the 20-byte target.
+var (
+	eip1167Prefix = common.FromHex("0x363d3d373d3d3d363d73")
+	eip1167Suffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+// create2Deployer is the deterministic CREATE2 deployer hook miners
+// conventionally deploy through; its calldata is salt ‖ initcode.
+var create2Deployer = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+// HookReport is what InspectHook learned about a hook contract.
+type HookReport struct {
+	Address        common.Address
+	Permissions    []HookPermission
+	DynamicFee     bool // the pool's LP fee is set by the hook
+	CodeSize       int
+	Implementation common.Address // EIP-1967 implementation; zero if not a proxy
+	Beacon         common.Address // EIP-1967 beacon; zero if none
+	Admin          common.Address // EIP-1967 admin; zero if none
+	CloneOf        common.Address // EIP-1167 minimal-proxy target; zero if not a clone
+	DeployBlock    uint64         // 0 when not found
+	DeployTx       common.Hash    // zero when not found
+	Deployer       common.Address // zero when not found
+	DeployNote     string         // why the deployment is incomplete, if it is
+	Risk           HookRisk
+	Findings       []string // one line per risk, worst first
+}
+
+// Upgradeable reports whether the hook's logic can be swapped out.
+func (r *HookReport) Upgradeable() bool {
+	return r.Implementation != (common.Address{}) || r.Beacon != (common.Address{})
+}
+
+// Summary is a one-line risk summary for warnings and logs.
+func (r *HookReport) Summary() string {
+	s := fmt.Sprintf("Hook %s — risk %s", ShortenAddr(r.Address.Hex()), r.Risk)
+	if len(r.Findings) > 0 {
+		s += ": " + r.Findings[0]
+		if n := len(r.Findings) - 1; n > 0 {
+			s += fmt.Sprintf(" (+%d more)", n)
+		}
+	}
+	return s
+}
+
+// InspectHook decodes hook's permissions and reads its code, EIP-1967
+// slots and deployment from rpcURL. fee is the pool key's fee, used to flag
+// dynamic-fee pools. A failed deployment lookup (it needs historical state)
+// is recorded in DeployNote rather than returned.
+func InspectHook(rpcURL string, hook common.Address, fee uint32) (*HookReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+	defer client.Close()
+
+	r := &HookReport{
+		Address:     hook,
+		Permissions: HookPermissions(hook),
+		DynamicFee:  fee == dynamicFeeFlag,
+	}
+
+	code, err := client.CodeAt(ctx, hook, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get code: %w", err)
+	}
+	r.CodeSize = len(code)
+	if len(code) == 45 && bytes.HasPrefix(code, eip1167Prefix) && bytes.HasSuffix(code, eip1167Suffix) {
+		r.CloneOf = common.BytesToAddress(code[10:30])
+	}
+	for slot, dst := range map[common.Hash]*common.Address{
+		eip1967ImplementationSlot: &r.Implementation,
+		eip1967BeaconSlot:         &r.Beacon,
+		eip1967AdminSlot:          &r.Admin,
+	} {
+		v, err := client.StorageAt(ctx, hook, slot, nil)
+		if err != nil {
+			return nil, fmt.Errorf("read EIP-1967 slot: %w", err)
+		}
+		*dst = common.BytesToAddress(v)
+	}
+
+	if r.CodeSize > 0 {
+		findHookDeployment(ctx, client, r)
+	}
+	r.assess()
+	return r, nil
+}
+
+// findHookDeployment binary-searches for the first block with code at the
+// hook, then looks in that block for a direct CREATE or a deterministic
+// CREATE2 deployer call that produced it.
+func findHookDeployment(ctx context.Context, client *ethclient.Client, r *HookReport) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		r.DeployNote = "deployment not looked up: " + err.Error()
+		return
+	}
+	chainID, _ := client.ChainID(ctx)
+	lo, hi := uint64(0), header.Number.Uint64()
+	if isMainnet(chainID) {
+		lo = v4PoolManagerDeployBlock // a hook can only be used after the PoolManager exists
+	}
+	hasCode := func(block uint64) (bool, error) {
+		code, err := client.CodeAt(ctx, r.Address, new(big.Int).SetUint64(block))
+		return len(code) > 0, err
+	}
+	if ok, err := hasCode(lo); err != nil {
+		r.DeployNote = "deployment lookup needs an archive node"
+		return
+	} else if ok {
+		r.DeployNote = fmt.Sprintf("deployed before block %d", lo)
+		return
+	}
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		ok, err := hasCode(mid)
+		if err != nil {
+			r.DeployNote = "deployment lookup failed: " + err.Error()
+			return
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	r.DeployBlock = hi
+
+	block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(hi))
+	if err != nil {
+		r.DeployNote = "deployment block not fetched: " + err.Error()
+		return
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	for _, tx := range block.Transactions() {
+		var created bool
+		switch to := tx.To(); {
+		case to == nil:
+			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+			created = err == nil && receipt.ContractAddress == r.Address
+		case *to == create2Deployer && len(tx.Data()) > 32:
+			created = crypto.CreateAddress2(create2Deployer, common.BytesToHash(tx.Data()[:32]), crypto.Keccak256(tx.Data()[32:])) == r.Address
+		}
+		if !created {
+			continue
+		}
+		r.DeployTx = tx.Hash()
+		if from, err := types.Sender(signer, tx); err == nil {
+			r.Deployer = from
+		}
+		return
+	}
+	r.DeployNote = "created by a factory contract; deploying transaction not identified"
+}
+
+// assess turns the report's facts into findings, worst first, and an
+// overall risk.
+func (r *HookReport) assess() {
+	add := func(risk HookRisk, finding string) {
+		if risk > r.Risk {
+			r.Risk = risk
+		}
+		r.Findings = append(r.Findings, finding)
+	}
+	if r.CodeSize == 0 {
+		add(HookRiskHigh, "no contract code at the hook address")
+	}
+	if r.Upgradeable() {
+		target := r.Implementation
+		if target == (common.Address{}) {
+			target = r.Beacon
+		}
+		finding := "upgradeable EIP-1967 proxy (logic at " + ShortenAddr(target.Hex())
+		if r.Admin != (common.Address{}) {
+			finding += ", admin " + ShortenAddr(r.Admin.Hex())
+		}
+		add(HookRiskHigh, finding+") — its behaviour can change after you check it")
+	}
+	for _, p := range r.Permissions {
+		if p.Risk == HookRiskHigh {
+			add(HookRiskHigh, p.Name+": "+p.Note)
+		}
+	}
+	if r.DynamicFee {
+		add(HookRiskMedium, "dynamic LP fee set by the hook on each swap")
+	}
+	for _, p := range r.Permissions {
+		if p.Risk == HookRiskMedium {
+			add(HookRiskMedium, p.Name+": "+p.Note)
+		}
+	}
+	if r.CloneOf != (common.Address{}) {
+		add(HookRiskLow, "EIP-1167 clone of "+ShortenAddr(r.CloneOf.Hex()))
+	}
+	if r.CodeSize > 0 && len(r.Permissions) == 0 {
+		add(HookRiskLow, "no callbacks enabled")
+	}
+}
@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestHookPermissions(t *testing.T) {
+	// ...0x00C8 = beforeSwap (1<<7) | afterSwap (1<<6) | beforeSwapReturnDelta (1<<3).
+	hook := common.HexToAddress("0x1234567890abcdef1234567890abcdef000000c8")
+	perms := HookPermissions(hook)
+	want := []string{"beforeSwap", "afterSwap", "beforeSwapReturnDelta"}
+	if len(perms) != len(want) {
+		t.Fatalf("got %d permissions, want %d", len(perms), len(want))
+	}
+	for i, p := range perms {
+		if p.Name != want[i] {
+			t.Errorf("permission %d = %s, want %s", i, p.Name, want[i])
+		}
+	}
+
+	// Bits above the low 14 are part of the address, not permissions.
+	if p := HookPermissions(common.HexToAddress("0x000000000000000000000000000000000000c000")); len(p) != 0 {
+		t.Errorf("high bits decoded as %v, want none", p)
+	}
+}
+
+func TestHookReportAssess(t *testing.T) {
+	r := &HookReport{
+		Address:        common.HexToAddress("0x00000000000000000000000000000000000000c0"),
+		Permissions:    HookPermissions(common.HexToAddress("0x00000000000000000000000000000000000000c0")),
+		CodeSize:       100,
+		Implementation: common.HexToAddress("0x1111111111111111111111111111111111111111"),
+	}
+	r.assess()
+	if r.Risk != HookRiskHigh {
+		t.Errorf("upgradeable swap hook risk = %s, want HIGH", r.Risk)
+	}
+	if len(r.Findings) != 3 {
+		t.Fatalf("findings = %q, want proxy, beforeSwap and afterSwap", r.Findings)
+	}
+
+	plain := &HookReport{Permissions: HookPermissions(common.HexToAddress("0x0000000000000000000000000000000000002000")), CodeSize: 100}
+	plain.assess()
+	if plain.Risk != HookRiskLow || len(plain.Findings) != 0 {
+		t.Errorf("beforeInitialize-only hook = %s %q, want LOW with no findings", plain.Risk, plain.Findings)
+	}
+}
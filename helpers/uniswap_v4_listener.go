@@ -519,10 +519,14 @@ func v4FormatLog(
 
 // ---- PoolEventMonitor ----
 
-// PoolEventMonitor subscribes to Uniswap V4 PoolManager events and streams
+// PoolEventMonitor follows Uniswap V4 PoolManager events and streams
 // formatted event lines through Lines() for display and structured events
 // through Events() for storage.
 type PoolEventMonitor struct {
+	// PoolLookup, when set before Start, resolves the key of a pool the
+	// monitor has not seen initialized, so token filters can match it.
+	PoolLookup func(common.Hash) (V4PoolKey, bool)
+
 	lines  chan string
 	events chan indexer.V4PoolEvent
 	cancel context.CancelFunc
@@ -536,14 +540,16 @@ func NewPoolEventMonitor() *PoolEventMonitor {
 	}
 }
 
-// Start dials the given WebSocket RPC URL and begins streaming pool events.
-// The WebSocket URL must use the wss:// or ws:// scheme for live subscriptions.
-// Events are available via Lines(). The goroutine exits when Stop() is called
-// or a fatal error occurs, after which the Lines() channel is closed.
-func (m *PoolEventMonitor) Start(wsURL string) {
+// Start begins streaming pool events from rpcURL: over a WebSocket
+// subscription when the endpoint (or its ws:// counterpart) offers one, else
+// by polling over HTTP. Dropped connections are retried with backoff. Only
+// events passing filter are delivered; the zero filter passes all.
+// Events are available via Lines() and Events(); both channels close once
+// Stop() is called.
+func (m *PoolEventMonitor) Start(rpcURL string, filter PoolEventFilter) {
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancel = cancel
-	go m.run(ctx, wsURL)
+	go m.run(ctx, rpcURL, filter)
 }
 
 // Stop cancels the monitor's context, causing the background goroutine to exit.
@@ -580,96 +586,6 @@ func (m *PoolEventMonitor) emitEvent(ev indexer.V4PoolEvent) {
 	}
 }
 
-func (m *PoolEventMonitor) run(ctx context.Context, wsURL string) {
-	defer close(m.lines)
-	defer close(m.events)
-
-	if wsURL == "" {
-		m.emit("[PoolMonitor] ERROR: no RPC URL configured")
-		return
-	}
-	// Convert HTTP scheme to WebSocket scheme.
-	if strings.HasPrefix(wsURL, "https://") {
-		wsURL = "wss://" + wsURL[len("https://"):]
-	} else if strings.HasPrefix(wsURL, "http://") {
-		wsURL = "ws://" + wsURL[len("http://"):]
-	}
-	// Replace port 8545 with 8546 (standard WebSocket port for Ethereum nodes).
-	wsURL = strings.ReplaceAll(wsURL, ":8545", ":8546")
-	if !strings.HasPrefix(wsURL, "ws://") && !strings.HasPrefix(wsURL, "wss://") {
-		m.emit(fmt.Sprintf("[PoolMonitor] ERROR: WebSocket URL required (got %q). Set a wss:// RPC endpoint.", wsURL))
-		return
-	}
-
-	parsedABI, err := abi.JSON(strings.NewReader(poolManagerEventsABI))
-	if err != nil {
-		m.emit(fmt.Sprintf("[PoolMonitor] ERROR: parse ABI: %v", err))
-		return
-	}
-
-	eventNames := make(map[common.Hash]string, len(parsedABI.Events))
-	allSigs := make([]common.Hash, 0, len(parsedABI.Events))
-	for name, ev := range parsedABI.Events {
-		eventNames[ev.ID] = name
-		allSigs = append(allSigs, ev.ID)
-	}
-
-	client, err := ethclient.DialContext(ctx, wsURL)
-	if err != nil {
-		m.emit(fmt.Sprintf("[PoolMonitor] ERROR: dial %s: %v", wsURL, err))
-		return
-	}
-	defer client.Close()
-
-	poolManager := addressesForClient(ctx, client).V4PoolManager
-
-	var (
-		mu       sync.RWMutex
-		poolKeys = make(map[common.Hash]v4PoolKey)
-		syms     = newV4SymbolCache()
-	)
-
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{poolManager},
-		Topics:    [][]common.Hash{allSigs},
-	}
-
-	logCh := make(chan types.Log, 1024)
-	sub, err := client.SubscribeFilterLogs(ctx, query, logCh)
-	if err != nil {
-		m.emit(fmt.Sprintf("[PoolMonitor] ERROR: subscribe: %v", err))
-		return
-	}
-
-	m.emit(fmt.Sprintf("[PoolMonitor] Listening… PoolManager=%s", poolManager.Hex()))
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-
-		case subErr, ok := <-sub.Err():
-			if !ok {
-				return
-			}
-			m.emit(fmt.Sprintf("[PoolMonitor] ERROR: subscription: %v", subErr))
-			return
-
-		case lg := <-logCh:
-			line, fmtErr := v4FormatLog(&parsedABI, lg, eventNames, &mu, poolKeys, ctx, client, syms)
-			if fmtErr != nil {
-				m.emit(fmt.Sprintf("[PoolMonitor] decode error: %v", fmtErr))
-			} else if line != "" {
-				m.emit(line)
-			}
-			// Emit the structured event for SQLite indexing regardless of display outcome.
-			if ev := indexer.DecodeV4PoolEvent(lg, &parsedABI); ev != nil {
-				m.emitEvent(*ev)
-			}
-		}
-	}
-}
-
 // ---- Pool Info (on-demand contract reads) ----
 
 const poolManagerViewABI = `[
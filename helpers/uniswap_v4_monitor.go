@@ -0,0 +1,371 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"charm-wallet-tui/indexer"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ---- PoolEventMonitor transports ----
+//
+// The monitor prefers an eth_subscribe("logs") WebSocket subscription. When
+// none can be established and the endpoint also speaks HTTP, it polls
+// instead: an eth_newFilter filter read with eth_getFilterChanges, or, where
+// the node does not keep filters, ranged eth_getLogs from the last block
+// seen. A dropped connection of either kind is retried with exponential
+// backoff, and the blocks missed meanwhile are fetched with eth_getLogs
+// before live events resume.
+
+const (
+	monitorPollInterval = 4 * time.Second
+	monitorMinBackoff   = 2 * time.Second
+	monitorMaxBackoff   = time.Minute
+	monitorLogRange     = 500  // blocks per eth_getLogs call
+	monitorCatchUpLimit = 5000 // most blocks fetched after a reconnect
+)
+
+// monitorState is what the monitor keeps across reconnects: the filter, the
+// decoded pool keys, symbols and decimals, and how far it has read.
+type monitorState struct {
+	parsedABI  abi.ABI
+	eventNames map[common.Hash]string
+	eventIDs   map[string]common.Hash
+	mu         sync.RWMutex
+	poolKeys   map[common.Hash]v4PoolKey
+	syms       *v4SymbolCache
+	decs       map[common.Address]uint8
+
+	filter PoolEventFilter
+	lookup func(common.Hash) (V4PoolKey, bool)
+	prices map[common.Address]float64 // USD per whole token, for min: filters
+
+	// cursor is the first block whose logs may not all have been handled;
+	// 0 until the first connection. lastBlock/lastIndex is the newest log
+	// handled, so logs delivered twice across a catch-up are skipped.
+	cursor    uint64
+	lastBlock uint64
+	lastIndex uint
+	handled   bool
+}
+
+func newMonitorState(filter PoolEventFilter, lookup func(common.Hash) (V4PoolKey, bool)) (*monitorState, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(poolManagerEventsABI))
+	if err != nil {
+		return nil, err
+	}
+	st := &monitorState{
+		parsedABI:  parsedABI,
+		eventNames: make(map[common.Hash]string, len(parsedABI.Events)),
+		eventIDs:   make(map[string]common.Hash, len(parsedABI.Events)),
+		poolKeys:   make(map[common.Hash]v4PoolKey),
+		syms:       newV4SymbolCache(),
+		decs:       make(map[common.Address]uint8),
+		filter:     filter,
+		lookup:     lookup,
+	}
+	for name, ev := range parsedABI.Events {
+		st.eventNames[ev.ID] = name
+		st.eventIDs[name] = ev.ID
+	}
+	return st, nil
+}
+
+// connect prepares st for a new connection and returns the log query for
+// the chain's PoolManager, narrowed by the filter's topics.
+func (st *monitorState) connect(ctx context.Context, client *ethclient.Client) ethereum.FilterQuery {
+	addrs := addressesForClient(ctx, client)
+	if st.filter.MinSwapUSD > 0 {
+		st.prices = positionUSDPrices(ctx, client, addrs)
+	}
+	return ethereum.FilterQuery{
+		Addresses: []common.Address{addrs.V4PoolManager},
+		Topics:    st.filter.topics(st.eventIDs),
+	}
+}
+
+// poolKey returns the key of a pool seen initialized this session, else
+// the one st.lookup knows (caching it so event lines can name the pair).
+func (st *monitorState) poolKey(id common.Hash) (v4PoolKey, bool) {
+	st.mu.RLock()
+	key, ok := st.poolKeys[id]
+	st.mu.RUnlock()
+	if ok || st.lookup == nil {
+		return key, ok
+	}
+	k, ok := st.lookup(id)
+	if !ok {
+		return key, false
+	}
+	key = v4PoolKey{Currency0: k.Currency0, Currency1: k.Currency1, Fee: k.Fee, TickSpacing: k.TickSpacing, Hooks: k.Hooks}
+	st.mu.Lock()
+	st.poolKeys[id] = key
+	st.mu.Unlock()
+	return key, true
+}
+
+// decimals caches token decimals for swap sizing; native ETH has 18.
+func (st *monitorState) decimals(ctx context.Context, client *ethclient.Client, token common.Address) uint8 {
+	if token == (common.Address{}) {
+		return 18
+	}
+	st.mu.RLock()
+	d, ok := st.decs[token]
+	st.mu.RUnlock()
+	if !ok {
+		d = v4ERC20Decimals(ctx, client, token)
+		st.mu.Lock()
+		st.decs[token] = d
+		st.mu.Unlock()
+	}
+	return d
+}
+
+// monitorURLs derives the WebSocket and HTTP forms of rpcURL. An http(s) URL
+// maps to ws(s) with a node's default port 8545 swapped for 8546, and back.
+// Other URLs (e.g. an IPC path) are only tried for subscriptions.
+func monitorURLs(rpcURL string) (wsURL, httpURL string) {
+	switch {
+	case strings.HasPrefix(rpcURL, "https://"):
+		wsURL = "wss://" + rpcURL[len("https://"):]
+	case strings.HasPrefix(rpcURL, "http://"):
+		wsURL = "ws://" + rpcURL[len("http://"):]
+	case strings.HasPrefix(rpcURL, "wss://"):
+		httpURL = "https://" + rpcURL[len("wss://"):]
+	case strings.HasPrefix(rpcURL, "ws://"):
+		httpURL = "http://" + rpcURL[len("ws://"):]
+	default:
+		return rpcURL, ""
+	}
+	if wsURL != "" {
+		return strings.ReplaceAll(wsURL, ":8545", ":8546"), rpcURL
+	}
+	return rpcURL, strings.ReplaceAll(httpURL, ":8546", ":8545")
+}
+
+func (m *PoolEventMonitor) run(ctx context.Context, rpcURL string, filter PoolEventFilter) {
+	defer close(m.lines)
+	defer close(m.events)
+
+	if rpcURL == "" {
+		m.emit("[PoolMonitor] ERROR: no RPC URL configured")
+		return
+	}
+	st, err := newMonitorState(filter, m.PoolLookup)
+	if err != nil {
+		m.emit(fmt.Sprintf("[PoolMonitor] ERROR: parse ABI: %v", err))
+		return
+	}
+
+	wsURL, httpURL := monitorURLs(rpcURL)
+	useWS, wsConnected := true, false
+	backoff := monitorMinBackoff
+	for {
+		var connected bool
+		if useWS {
+			connected, err = m.stream(ctx, wsURL, st)
+			wsConnected = wsConnected || connected
+			// Fall back to polling only when WebSocket never worked; a
+			// subscription that dropped is worth reconnecting.
+			if !wsConnected && httpURL != "" && ctx.Err() == nil {
+				m.emit(fmt.Sprintf("[PoolMonitor] WebSocket unavailable (%v) — polling over HTTP", err))
+				useWS = false
+				continue
+			}
+		} else {
+			connected, err = m.poll(ctx, httpURL, st)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = monitorMinBackoff
+		}
+		m.emit(fmt.Sprintf("[PoolMonitor] %v — reconnecting in %s", err, backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > monitorMaxBackoff {
+			backoff = monitorMaxBackoff
+		}
+	}
+}
+
+// stream follows a WebSocket log subscription until it drops. connected
+// reports whether the subscription was established.
+func (m *PoolEventMonitor) stream(ctx context.Context, wsURL string, st *monitorState) (connected bool, err error) {
+	client, err := ethclient.DialContext(ctx, wsURL)
+	if err != nil {
+		return false, fmt.Errorf("dial %s: %w", wsURL, err)
+	}
+	defer client.Close()
+
+	query := st.connect(ctx, client)
+	poolManager := query.Addresses[0]
+	logCh := make(chan types.Log, 1024)
+	sub, err := client.SubscribeFilterLogs(ctx, query, logCh)
+	if err != nil {
+		return false, fmt.Errorf("subscribe: %w", err)
+	}
+	defer sub.Unsubscribe()
+	m.emit(fmt.Sprintf("[PoolMonitor] Listening over WebSocket… PoolManager=%s", poolManager.Hex()))
+
+	// Logs the subscription also delivers are skipped as already handled.
+	if err := m.catchUp(ctx, client, query, st); err != nil {
+		m.emit(fmt.Sprintf("[PoolMonitor] catch-up failed, some events were missed: %v", err))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case subErr, ok := <-sub.Err():
+			if !ok || subErr == nil {
+				return true, errors.New("subscription closed")
+			}
+			return true, fmt.Errorf("subscription dropped: %w", subErr)
+		case lg := <-logCh:
+			m.handleLog(ctx, client, st, lg)
+			st.cursor = lg.BlockNumber
+		}
+	}
+}
+
+// poll reads logs over HTTP every monitorPollInterval, through a node filter
+// when the node keeps one and ranged eth_getLogs otherwise, until a request
+// fails. connected reports whether the endpoint answered at all.
+func (m *PoolEventMonitor) poll(ctx context.Context, httpURL string, st *monitorState) (connected bool, err error) {
+	client, err := ethclient.DialContext(ctx, httpURL)
+	if err != nil {
+		return false, fmt.Errorf("dial %s: %w", httpURL, err)
+	}
+	defer client.Close()
+
+	if _, err := client.BlockNumber(ctx); err != nil {
+		return false, fmt.Errorf("block number: %w", err)
+	}
+	query := st.connect(ctx, client)
+	poolManager := query.Addresses[0]
+
+	// The filter is installed before catching up, so nothing falls between
+	// the two; logs both return are skipped as already handled.
+	var filterID string
+	err = client.Client().CallContext(ctx, &filterID, "eth_newFilter", map[string]interface{}{
+		"address": query.Addresses,
+		"topics":  query.Topics,
+	})
+	if err == nil {
+		defer func() {
+			uctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			client.Client().CallContext(uctx, nil, "eth_uninstallFilter", filterID)
+		}()
+		m.emit(fmt.Sprintf("[PoolMonitor] Polling eth_getFilterChanges every %s… PoolManager=%s", monitorPollInterval, poolManager.Hex()))
+	} else {
+		m.emit(fmt.Sprintf("[PoolMonitor] Polling eth_getLogs every %s (node filters unavailable: %v)… PoolManager=%s", monitorPollInterval, err, poolManager.Hex()))
+	}
+	if err := m.catchUp(ctx, client, query, st); err != nil {
+		return true, err
+	}
+
+	ticker := time.NewTicker(monitorPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case <-ticker.C:
+		}
+		if filterID == "" {
+			if err := m.catchUp(ctx, client, query, st); err != nil {
+				return true, err
+			}
+			continue
+		}
+		var logs []types.Log
+		if err := client.Client().CallContext(ctx, &logs, "eth_getFilterChanges", filterID); err != nil {
+			// Nodes drop idle or expired filters; a fresh connection
+			// catches up and installs a new one.
+			return true, fmt.Errorf("filter changes: %w", err)
+		}
+		for _, lg := range logs {
+			m.handleLog(ctx, client, st, lg)
+			st.cursor = lg.BlockNumber
+		}
+	}
+}
+
+// catchUp handles the logs from st.cursor to the chain head with ranged
+// eth_getLogs, at most monitorCatchUpLimit blocks back. On the first
+// connection there is nothing to catch up, so it only sets the cursor.
+func (m *PoolEventMonitor) catchUp(ctx context.Context, client *ethclient.Client, query ethereum.FilterQuery, st *monitorState) error {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("block number: %w", err)
+	}
+	if st.cursor == 0 {
+		st.cursor = head + 1
+		return nil
+	}
+	if head+1 > st.cursor+monitorCatchUpLimit {
+		m.emit(fmt.Sprintf("[PoolMonitor] %d blocks behind — skipping to the last %d", head+1-st.cursor, monitorCatchUpLimit))
+		st.cursor = head + 1 - monitorCatchUpLimit
+	}
+	for st.cursor <= head {
+		to := st.cursor + monitorLogRange - 1
+		if to > head {
+			to = head
+		}
+		q := query
+		q.FromBlock, q.ToBlock = new(big.Int).SetUint64(st.cursor), new(big.Int).SetUint64(to)
+		logs, err := client.FilterLogs(ctx, q)
+		if err != nil {
+			return fmt.Errorf("get logs %d–%d: %w", st.cursor, to, err)
+		}
+		for _, lg := range logs {
+			m.handleLog(ctx, client, st, lg)
+		}
+		st.cursor = to + 1
+	}
+	return nil
+}
+
+// handleLog formats and emits lg unless it was already handled, has been
+// reorged out or fails the filter.
+func (m *PoolEventMonitor) handleLog(ctx context.Context, client *ethclient.Client, st *monitorState, lg types.Log) {
+	if lg.Removed {
+		return
+	}
+	if st.handled && (lg.BlockNumber < st.lastBlock || (lg.BlockNumber == st.lastBlock && lg.Index <= st.lastIndex)) {
+		return
+	}
+	st.lastBlock, st.lastIndex, st.handled = lg.BlockNumber, lg.Index, true
+	if !st.filter.Empty() && len(lg.Topics) > 0 && !st.filter.passes(ctx, client, st, st.eventNames[lg.Topics[0]], lg) {
+		return
+	}
+
+	line, err := v4FormatLog(&st.parsedABI, lg, st.eventNames, &st.mu, st.poolKeys, ctx, client, st.syms)
+	if err != nil {
+		m.emit(fmt.Sprintf("[PoolMonitor] decode error: %v", err))
+	} else if line != "" {
+		m.emit(line)
+	}
+	// Emit the structured event for SQLite indexing regardless of display outcome.
+	if ev := indexer.DecodeV4PoolEvent(lg, &st.parsedABI); ev != nil {
+		m.emitEvent(*ev)
+	}
+}
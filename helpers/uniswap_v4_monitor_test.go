@@ -0,0 +1,19 @@
+package helpers
+
+import "testing"
+
+func TestMonitorURLs(t *testing.T) {
+	cases := []struct{ in, ws, http string }{
+		{"http://localhost:8545", "ws://localhost:8546", "http://localhost:8545"},
+		{"https://eth.example.com/v2/key", "wss://eth.example.com/v2/key", "https://eth.example.com/v2/key"},
+		{"wss://eth.example.com", "wss://eth.example.com", "https://eth.example.com"},
+		{"ws://node:8546", "ws://node:8546", "http://node:8545"},
+		{"/tmp/geth.ipc", "/tmp/geth.ipc", ""},
+	}
+	for _, c := range cases {
+		ws, http := monitorURLs(c.in)
+		if ws != c.ws || http != c.http {
+			t.Errorf("monitorURLs(%q) = %q, %q; want %q, %q", c.in, ws, http, c.ws, c.http)
+		}
+	}
+}
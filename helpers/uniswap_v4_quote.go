@@ -33,8 +33,8 @@ type V4PoolKey struct {
 	TickSpacing int32
 }
 
-// ResolvedPool carries the routing metadata for whichever Uniswap version
-// ResolvePairOnChain found liquidity on. Exactly one of {PairAddr+V3Fee,
+// ResolvedPool carries the routing metadata for a Uniswap pool found by
+// LiquidPools / ResolvePairOnChain. Exactly one of {PairAddr+V3Fee,
 // V4Key+V4PoolID} is populated, selected by Version.
 type ResolvedPool struct {
 	Version  PoolVersion
@@ -42,6 +42,10 @@ type ResolvedPool struct {
 	V3Fee    uint32
 	V4Key    V4PoolKey
 	V4PoolID common.Hash
+
+	// Liquidity is the pool's in-range liquidity() for V3 and live-scanned
+	// V4 pools; nil for V2 pairs and vendored-index V4 pools.
+	Liquidity *big.Int
 }
 
 // v4LiveFallbackWindowBlocks bounds the live-scan fallback in resolveV4Pool
@@ -49,21 +53,39 @@ type ResolvedPool struct {
 // package, staying under the common ~100k-block eth_getLogs range limit.
 const v4LiveFallbackWindowBlocks = 99000
 
-// resolveV4Pool finds a V4 pool for tokenA/tokenB. V4 has no on-chain
+// resolveV4Pool finds a V4 pool for tokenA/tokenB: the first of
+// resolveV4Pools.
+func resolveV4Pool(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, tokenA, tokenB common.Address, liveScan bool) (ResolvedPool, bool) {
+	pools := resolveV4Pools(ctx, client, addrs, tokenA, tokenB, liveScan)
+	if len(pools) == 0 {
+		return ResolvedPool{}, false
+	}
+	return pools[0], true
+}
+
+// resolveV4Pools finds every V4 pool key for tokenA/tokenB — a pair can
+// have several, e.g. a hooked and an unhooked pool. V4 has no on-chain
 // factory/registry to query live the way V2's getPair/V3's getPool do — a
 // pool's existence is only knowable from having observed its Initialize
 // event — so this uses a two-tier approach:
 //
-//  1. The vendored discovery index (ResolveOndoV4Pool), built offline by
+//  1. The vendored discovery index (ResolveOndoV4Pools), built offline by
 //     cmd/discoverondopools. Instant, zero RPC calls, covers this feature's
 //     actual scope (Ondo Global Markets tokens).
-//  2. A bounded live fallback scanning the most recent ~99,000 blocks for an
-//     Initialize event matching tokenA/tokenB, for pools created after the
-//     last index rebuild. NOT exhaustive — a matching pool older than the
-//     window and missing from the vendored index will not be found.
-func resolveV4Pool(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, tokenA, tokenB common.Address) (ResolvedPool, bool) {
-	if entry, ok := ResolveOndoV4Pool(tokenA, tokenB); ok {
-		return ResolvedPool{
+//  2. A bounded live scan of the most recent ~99,000 blocks for Initialize
+//     events matching tokenA/tokenB, for pools created after the last index
+//     rebuild. NOT exhaustive — a matching pool older than the window and
+//     missing from the vendored index will not be found. Skipped when
+//     liveScan is false (the router's hub edges, where one log scan per
+//     edge would dwarf every other lookup).
+//
+// Pools found by both tiers are listed once, vendored entries first.
+func resolveV4Pools(ctx context.Context, client *ethclient.Client, addrs UniswapNetworkAddresses, tokenA, tokenB common.Address, liveScan bool) []ResolvedPool {
+	var pools []ResolvedPool
+	seen := make(map[common.Hash]bool)
+	for _, entry := range ResolveOndoV4Pools(tokenA, tokenB) {
+		seen[entry.PoolID] = true
+		pools = append(pools, ResolvedPool{
 			Version: PoolVersionV4,
 			V4Key: V4PoolKey{
 				Currency0:   entry.Currency0,
@@ -73,12 +95,15 @@ func resolveV4Pool(ctx context.Context, client *ethclient.Client, addrs UniswapN
 				TickSpacing: entry.TickSpacing,
 			},
 			V4PoolID: entry.PoolID,
-		}, true
+		})
+	}
+	if !liveScan {
+		return pools
 	}
 
 	header, err := client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return ResolvedPool{}, false
+		return pools
 	}
 	toBlock := header.Number.Uint64()
 	fromBlock := uint64(0)
@@ -91,13 +116,13 @@ func resolveV4Pool(ctx context.Context, client *ethclient.Client, addrs UniswapN
 
 	events, err := indexer.FetchAllInitializeEvents(ctx, client, fromBlock, toBlock)
 	if err != nil {
-		return ResolvedPool{}, false
+		return pools
 	}
 
 	stateViewABI, abiErr := abi.JSON(strings.NewReader(poolManagerViewABI))
 	for _, ev := range events {
 		matches := (ev.Currency0 == tokenA && ev.Currency1 == tokenB) || (ev.Currency0 == tokenB && ev.Currency1 == tokenA)
-		if !matches {
+		if !matches || seen[ev.PoolID] {
 			continue
 		}
 		// Only accept if the pool has liquidity, mirroring the V3 tier's
@@ -117,7 +142,8 @@ func resolveV4Pool(ctx context.Context, client *ethclient.Client, addrs UniswapN
 		if ev.TickSpacing != nil {
 			tickSpacing = int32(ev.TickSpacing.Int64())
 		}
-		return ResolvedPool{
+		seen[ev.PoolID] = true
+		pools = append(pools, ResolvedPool{
 			Version: PoolVersionV4,
 			V4Key: V4PoolKey{
 				Currency0:   ev.Currency0,
@@ -126,11 +152,11 @@ func resolveV4Pool(ctx context.Context, client *ethclient.Client, addrs UniswapN
 				Fee:         fee,
 				TickSpacing: tickSpacing,
 			},
-			V4PoolID: ev.PoolID,
-		}, true
+			V4PoolID:  ev.PoolID,
+			Liquidity: liquidity,
+		})
 	}
-
-	return ResolvedPool{}, false
+	return pools
 }
 
 // ---- V4Quoter ----
@@ -0,0 +1,175 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"charm-wallet-tui/rpc"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	erc20ApprovalSig   = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	permit2ApprovalSig = crypto.Keccak256Hash([]byte("Approval(address,address,address,uint160,uint48)"))
+	permit2PermitSig   = crypto.Keccak256Hash([]byte("Permit(address,address,address,uint160,uint48,uint48)"))
+	permit2LockdownSig = crypto.Keccak256Hash([]byte("Lockdown(address,address,address)"))
+)
+
+// ApprovalKind identifies which allowance ledger an ApprovalEvent belongs to.
+type ApprovalKind uint8
+
+const (
+	ApprovalERC20   ApprovalKind = iota // token.approve(spender, amount)
+	ApprovalPermit2                     // Permit2 Approval / Permit / Lockdown
+)
+
+func (k ApprovalKind) String() string {
+	switch k {
+	case ApprovalERC20:
+		return "erc20"
+	case ApprovalPermit2:
+		return "permit2"
+	default:
+		return "unknown"
+	}
+}
+
+// ApprovalEvent is one allowance change granted by a watched owner, either an
+// ERC-20 Approval emitted by the token itself or a Permit2 Approval, Permit
+// or Lockdown emitted by the Permit2 contract (a lockdown is an amount of 0).
+type ApprovalEvent struct {
+	Kind       ApprovalKind
+	Block      uint64
+	TxHash     common.Hash
+	LogIndex   uint
+	Owner      common.Address
+	Token      common.Address
+	Spender    common.Address
+	Amount     *big.Int
+	Expiration uint64 // Permit2 only; 0 for ERC-20
+}
+
+// FetchApprovalEvents returns every ERC-20 and Permit2 allowance change made
+// by owners in [fromBlock, toBlock]. ERC-20 Approval logs are matched on any
+// contract by their owner topic; ERC-721 Approval logs (same topic0, tokenId
+// indexed as a fourth topic) are skipped. Ranges the node refuses to serve in
+// one eth_getLogs call are split in half until it accepts them.
+func FetchApprovalEvents(ctx context.Context, client *ethclient.Client, owners []common.Address, fromBlock, toBlock uint64) ([]ApprovalEvent, error) {
+	ownerTopics := make([]common.Hash, len(owners))
+	for i, a := range owners {
+		ownerTopics[i] = common.BytesToHash(a.Bytes())
+	}
+
+	erc20Logs, err := filterLogsSplit(ctx, client, ethereum.FilterQuery{
+		Topics: [][]common.Hash{{erc20ApprovalSig}, ownerTopics},
+	}, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	permit2Logs, err := filterLogsSplit(ctx, client, ethereum.FilterQuery{
+		Addresses: []common.Address{rpc.Permit2Address},
+		Topics:    [][]common.Hash{{permit2ApprovalSig, permit2PermitSig, permit2LockdownSig}, ownerTopics},
+	}, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ApprovalEvent
+	for _, l := range append(erc20Logs, permit2Logs...) {
+		if ev := decodeApproval(l); ev != nil {
+			events = append(events, *ev)
+		}
+	}
+	return events, nil
+}
+
+// filterLogsSplit runs q over [from, to], bisecting the range whenever the
+// node rejects it (too many results, range limit, timeout).
+func filterLogsSplit(ctx context.Context, client *ethclient.Client, q ethereum.FilterQuery, from, to uint64) ([]types.Log, error) {
+	q.FromBlock = new(big.Int).SetUint64(from)
+	q.ToBlock = new(big.Int).SetUint64(to)
+
+	fCtx, fCancel := context.WithTimeout(ctx, 15*time.Second)
+	logs, err := client.FilterLogs(fCtx, q)
+	fCancel()
+	if err == nil {
+		return logs, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if from == to {
+		return nil, fmt.Errorf("eth_getLogs block %d: %w", from, err)
+	}
+
+	mid := from + (to-from)/2
+	low, err := filterLogsSplit(ctx, client, q, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	high, err := filterLogsSplit(ctx, client, q, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(low, high...), nil
+}
+
+func decodeApproval(l types.Log) *ApprovalEvent {
+	if len(l.Topics) < 2 {
+		return nil
+	}
+	ev := &ApprovalEvent{
+		Block:    l.BlockNumber,
+		TxHash:   l.TxHash,
+		LogIndex: uint(l.Index),
+		Owner:    common.BytesToAddress(l.Topics[1].Bytes()),
+		Amount:   new(big.Int),
+	}
+	word := func(i int) []byte {
+		if len(l.Data) < (i+1)*32 {
+			return nil
+		}
+		return l.Data[i*32 : (i+1)*32]
+	}
+
+	switch {
+	case l.Topics[0] == erc20ApprovalSig && l.Address != rpc.Permit2Address:
+		// ERC-721 indexes tokenId as a fourth topic and carries no data.
+		if len(l.Topics) != 3 || word(0) == nil {
+			return nil
+		}
+		ev.Kind = ApprovalERC20
+		ev.Token = l.Address
+		ev.Spender = common.BytesToAddress(l.Topics[2].Bytes())
+		ev.Amount.SetBytes(word(0))
+
+	case l.Address == rpc.Permit2Address && (l.Topics[0] == permit2ApprovalSig || l.Topics[0] == permit2PermitSig):
+		if len(l.Topics) != 4 || word(1) == nil {
+			return nil
+		}
+		ev.Kind = ApprovalPermit2
+		ev.Token = common.BytesToAddress(l.Topics[2].Bytes())
+		ev.Spender = common.BytesToAddress(l.Topics[3].Bytes())
+		ev.Amount.SetBytes(word(0))
+		ev.Expiration = new(big.Int).SetBytes(word(1)).Uint64()
+
+	case l.Address == rpc.Permit2Address && l.Topics[0] == permit2LockdownSig:
+		if word(1) == nil {
+			return nil
+		}
+		ev.Kind = ApprovalPermit2
+		ev.Token = common.BytesToAddress(word(0))
+		ev.Spender = common.BytesToAddress(word(1))
+
+	default:
+		return nil
+	}
+	return ev
+}
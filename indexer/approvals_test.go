@@ -0,0 +1,115 @@
+package indexer
+
+import (
+	"math/big"
+	"testing"
+
+	"charm-wallet-tui/rpc"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDecodeApproval(t *testing.T) {
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	token := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	topic := func(a common.Address) common.Hash { return common.BytesToHash(a.Bytes()) }
+	word := func(x int64) []byte { return common.LeftPadBytes(big.NewInt(x).Bytes(), 32) }
+
+	tests := []struct {
+		name       string
+		log        types.Log
+		wantNil    bool
+		wantKind   ApprovalKind
+		wantAmount int64
+		wantExp    uint64
+	}{
+		{
+			name: "erc20 approval",
+			log: types.Log{
+				Address: token,
+				Topics:  []common.Hash{erc20ApprovalSig, topic(owner), topic(spender)},
+				Data:    word(500),
+			},
+			wantKind:   ApprovalERC20,
+			wantAmount: 500,
+		},
+		{
+			name: "erc721 approval skipped",
+			log: types.Log{
+				Address: token,
+				Topics:  []common.Hash{erc20ApprovalSig, topic(owner), topic(spender), common.BigToHash(big.NewInt(7))},
+			},
+			wantNil: true,
+		},
+		{
+			name: "permit2 approval",
+			log: types.Log{
+				Address: rpc.Permit2Address,
+				Topics:  []common.Hash{permit2ApprovalSig, topic(owner), topic(token), topic(spender)},
+				Data:    append(word(42), word(1_900_000_000)...),
+			},
+			wantKind:   ApprovalPermit2,
+			wantAmount: 42,
+			wantExp:    1_900_000_000,
+		},
+		{
+			name: "permit2 permit",
+			log: types.Log{
+				Address: rpc.Permit2Address,
+				Topics:  []common.Hash{permit2PermitSig, topic(owner), topic(token), topic(spender)},
+				Data:    append(append(word(9), word(1_800_000_000)...), word(3)...),
+			},
+			wantKind:   ApprovalPermit2,
+			wantAmount: 9,
+			wantExp:    1_800_000_000,
+		},
+		{
+			name: "permit2 lockdown zeroes the allowance",
+			log: types.Log{
+				Address: rpc.Permit2Address,
+				Topics:  []common.Hash{permit2LockdownSig, topic(owner)},
+				Data:    append(common.LeftPadBytes(token.Bytes(), 32), common.LeftPadBytes(spender.Bytes(), 32)...),
+			},
+			wantKind:   ApprovalPermit2,
+			wantAmount: 0,
+		},
+		{
+			name: "permit2 event from another contract ignored",
+			log: types.Log{
+				Address: token,
+				Topics:  []common.Hash{permit2ApprovalSig, topic(owner), topic(token), topic(spender)},
+				Data:    append(word(1), word(1)...),
+			},
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := decodeApproval(tt.log)
+			if tt.wantNil {
+				if ev != nil {
+					t.Fatalf("expected nil, got %+v", ev)
+				}
+				return
+			}
+			if ev == nil {
+				t.Fatal("expected an event, got nil")
+			}
+			if ev.Kind != tt.wantKind {
+				t.Errorf("kind = %v, want %v", ev.Kind, tt.wantKind)
+			}
+			if ev.Owner != owner || ev.Token != token || ev.Spender != spender {
+				t.Errorf("owner/token/spender = %s/%s/%s", ev.Owner.Hex(), ev.Token.Hex(), ev.Spender.Hex())
+			}
+			if ev.Amount.Int64() != tt.wantAmount {
+				t.Errorf("amount = %s, want %d", ev.Amount, tt.wantAmount)
+			}
+			if ev.Expiration != tt.wantExp {
+				t.Errorf("expiration = %d, want %d", ev.Expiration, tt.wantExp)
+			}
+		})
+	}
+}
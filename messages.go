@@ -8,6 +8,7 @@ import (
 	"charm-wallet-tui/indexer"
 	"charm-wallet-tui/rpc"
 	"charm-wallet-tui/store"
+	"charm-wallet-tui/views/approvals"
 	"charm-wallet-tui/webcam/capture"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -44,20 +45,12 @@ type ensForwardResolveMsg struct {
 	debugInfo string
 }
 
-// uniswapQuoteMsg contains result of Uniswap price quote fetch
-type uniswapQuoteMsg struct {
-	quote *helpers.SwapQuote
-	err   error
-}
-
-// pairLookupResultMsg carries the result of an on-chain Uniswap V2/V3
-// factory lookup for a token pair (see resolvePairOnChain in model_uniswap.go).
-type pairLookupResultMsg struct {
-	cacheKey       string
-	fromIdx, toIdx int  // token indices active when the lookup was dispatched
-	reverse        bool // which quote direction triggered this lookup
-	resolution     pairResolution
-	ok             bool
+// contactENSResolvedMsg carries the forward resolution of a .eth name entered
+// in the address book form.
+type contactENSResolvedMsg struct {
+	name    string
+	address string
+	err     error
 }
 
 // logInitMsg signals that log viewport should be initialized
@@ -86,29 +79,40 @@ type packageTransactionMsg struct {
 	err          error
 }
 
-// terraNullClaimsCountMsg contains the result of a number_of_claims() call
-type terraNullClaimsCountMsg struct {
-	count *big.Int
-	err   error
+// safeProposalMsg replaces packageTransactionMsg when the sender is a Gnosis
+// Safe: the calls the send/swap flow built, to be wrapped into one Safe
+// transaction and signed by the owners.
+type safeProposalMsg struct {
+	info    *rpc.SafeInfo
+	chainID *big.Int
+	calls   []rpc.SafeCallSpec
+	summary string
 }
 
-// terraNullClaimQueryMsg contains the result of a claims(uint256) call
-type terraNullClaimQueryMsg struct {
-	result *helpers.TerraClaimResult
+// contractCallResultMsg carries the formatted outputs (or error) of a read
+// function called from the Contract page.
+type contractCallResultMsg struct {
+	name   string // abi.ABI method key
+	result string
 	err    error
 }
 
 // poolEventLineMsg carries a single formatted pool event line for the log panel
 type poolEventLineMsg struct {
-	line string
+	line    string
+	monitor *helpers.PoolEventMonitor
 }
 
-// poolEventMonitorStoppedMsg signals that the pool event monitor has stopped
-type poolEventMonitorStoppedMsg struct{}
+// poolEventMonitorStoppedMsg signals that a pool event monitor has stopped;
+// it may be one already replaced by a restart.
+type poolEventMonitorStoppedMsg struct {
+	monitor *helpers.PoolEventMonitor
+}
 
 // poolMonitorEventMsg carries a structured V4PoolEvent from the live monitor for SQLite indexing
 type poolMonitorEventMsg struct {
-	event indexer.V4PoolEvent
+	event   indexer.V4PoolEvent
+	monitor *helpers.PoolEventMonitor
 }
 
 // poolInfoResultMsg carries the result of a FetchPoolInfo call
@@ -125,6 +129,14 @@ type poolKeyResultMsg struct {
 	err    error
 }
 
+// poolHookReportMsg carries the result of an InspectHook call for the pool
+// info popup
+type poolHookReportMsg struct {
+	poolID string
+	report *helpers.HookReport
+	err    error
+}
+
 // indexedEventMsg carries a single ERC-20 Transfer event from the address indexer
 type indexedEventMsg struct {
 	event indexer.IndexedEvent
@@ -153,20 +165,20 @@ type recentEventsMsg struct {
 	err    error
 }
 
-// v4BlockScanLineMsg carries a single formatted line from V4BlockScanner.
-type v4BlockScanLineMsg struct {
-	line string
+// explorerResultMsg carries a Block Explorer query's decoded tree.
+type explorerResultMsg struct {
+	gen   int
+	query helpers.ExplorerQuery
+	roots []*helpers.ExplorerNode
+	err   error
 }
 
-// v4BlockScanDoneMsg signals that the V4BlockScanner has finished.
-type v4BlockScanDoneMsg struct{}
-
-// liquidityPositionsMsg carries the result of a V4 liquidity position lookup
-type liquidityPositionsMsg struct {
-	positions   []helpers.LiquidityPosition
-	nftCount    uint64    // total NFTs reported by balanceOf before filtering
-	diagnostics []string  // per-step diagnostic lines for the logger
-	err         error
+// inspectorResultMsg carries a Transaction Inspector lookup's decoded tree.
+type inspectorResultMsg struct {
+	gen   int
+	hash  common.Hash
+	roots []*helpers.ExplorerNode
+	err   error
 }
 
 // v4PoolTableMsg carries a freshly-queried snapshot of indexed V4 pools for the events panel
@@ -222,3 +234,56 @@ type tokenMetadataMsg struct {
 	totalSupply *big.Int
 	err         error
 }
+
+// approvalsLoadedMsg carries the active wallet's allowances with their live
+// on-chain values; scanned is how many new approval events were indexed.
+type approvalsLoadedMsg struct {
+	rows    []approvals.Row
+	scanned int
+	err     error
+}
+
+// txHistoryLoadedMsg carries the Transactions page's history rows, newest
+// first.
+type txHistoryLoadedMsg struct {
+	records []store.TxRecord
+	err     error
+}
+
+// outboxLoadedMsg carries the active wallet's nonce reservations after they
+// were reconciled against the chain's pending nonce.
+type outboxLoadedMsg struct {
+	entries []store.NonceReservation
+	pending uint64
+	gaps    []uint64
+	err     error
+}
+
+// alertTickMsg schedules the next alert snapshot for poll loop gen.
+type alertTickMsg struct {
+	gen int
+}
+
+// alertSnapshotMsg carries a fresh alert snapshot for poll loop gen; err
+// reports what could not be read, the rest of the snapshot still counts.
+type alertSnapshotMsg struct {
+	gen  int
+	snap *helpers.AlertSnapshot
+	err  error
+}
+
+// alertFiredMsg carries alerts raised off the UI goroutine.
+type alertFiredMsg struct {
+	firings []alertFiring
+}
+
+// alertSignalSentMsg clears a terminal alert's sequences from the frame
+// once the renderer has had time to write them.
+type alertSignalSentMsg struct {
+	seq string
+}
+
+// alertDeliveredMsg reports alert deliveries that failed.
+type alertDeliveredMsg struct {
+	errs []string
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"image"
 	"math/big"
 	"os"
@@ -12,11 +14,14 @@ import (
 
 	"charm-wallet-tui/anim"
 	"charm-wallet-tui/config"
+	"charm-wallet-tui/dapp"
 	"charm-wallet-tui/helpers"
 	"charm-wallet-tui/indexer"
 	"charm-wallet-tui/rpc"
 	"charm-wallet-tui/store"
 	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/approvals"
+	"charm-wallet-tui/views/contract"
 	"charm-wallet-tui/views/scrollbar"
 	"charm-wallet-tui/webcam/capture"
 
@@ -26,6 +31,7 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -50,7 +56,6 @@ const (
 	dialogTxResult                 // transaction result / QR panel
 	dialogPoolInfo                 // Uniswap pool info popup
 	dialogAccountList              // account selector popup
-	dialogTerraClaim               // Terra Nullius claim form
 	dialogScanTx                   // webcam scan for signed transaction
 	dialogPasteSignedTx            // paste + broadcast a signed transaction
 	dialogEditWallet               // edit address + nickname for an existing wallet
@@ -58,6 +63,11 @@ const (
 	dialogSendTx                   // send transaction form
 	dialogDeleteToken              // watched token delete confirmation
 	dialogOndoPicker               // Ondo Global Markets token picker (Watched Tokens page)
+	dialogOutbox                   // queued (packaged, unbroadcast) transactions for the active wallet
+	dialogDeleteContact            // address book entry delete confirmation
+	dialogDeleteDapp               // user-defined dApp delete confirmation
+	dialogPoolFilter               // pool event monitor filter and presets
+	dialogAlerts                   // alert rules
 )
 
 // pasteTxPhaseKind identifies which step of the paste-signed-transaction
@@ -147,8 +157,15 @@ type model struct {
 	configPath     string
 
 	// dApp browser state
-	dapps           []config.DApp
-	selectedDappIdx int
+	dapps                       []config.DApp // registered modules, config.DefaultDapps, then the config file's own
+	selectedDappIdx             int
+	dappFormMode                string // "list", "add", "edit"
+	dappForm                    *huh.Form
+	dappFormFields              []huh.Field
+	dappFormButtonFocused       bool
+	dappFormError               string
+	editingDappIdx              int
+	deleteDappDialogYesSelected bool
 
 	// home form
 	homeForm *huh.Form
@@ -221,64 +238,70 @@ type model struct {
 	txSwapSummary      string   // human-readable swap summary for step-2 content
 	txSwapStep         bool     // false=showing approve (step 1), true=showing swap (step 2)
 
-	// Uniswap swap state
-	uniswapFromTokenIdx    int    // index in available tokens
-	uniswapToTokenIdx      int    // index in available tokens
-	uniswapFromAmount      string // user input amount
-	uniswapToAmount        string // estimated output amount
-	uniswapFocusedField    int    // 0=from, 1=to, 2=swap button
-	uniswapShowingSelector bool   // true when showing token selector popup
-	uniswapSelectorFor     int    // 0=from, 1=to
-	uniswapSelectorIdx     int    // selected index in token selector
-	uniswapEstimating      bool   // true when estimating swap output
-	uniswapQuote           *helpers.SwapQuote // current swap quote
-	uniswapQuoteError      string             // error from quote fetch
-	uniswapPriceImpactWarn string             // warning message for high price impact
-	uniswapHookWarn        string             // warning shown when the resolved V4 pool has a non-zero hook address
-	uniswapEditingFrom     bool               // true if user has been editing From field
-	uniswapEditingTo       bool               // true if user has been editing To field
-	// Track last quote parameters to avoid unnecessary fetches
-	lastQuoteFromAmount   string              // last amount used for forward quote
-	lastQuoteToAmount     string              // last amount used for reverse quote
-	lastQuoteFromTokenIdx int                 // last from token index used for quote
-	lastQuoteToTokenIdx   int                 // last to token index used for quote
-	uniswapLastVersion    helpers.PoolVersion // which Uniswap version the last resolved pair used
-	uniswapLastFee        uint32              // valid when uniswapLastVersion == PoolVersionV3
-	uniswapLastV4Key      helpers.V4PoolKey   // valid when uniswapLastVersion == PoolVersionV4
-	uniswapLastV4PoolID   common.Hash         // valid when uniswapLastVersion == PoolVersionV4
-
-	// On-chain pair/pool resolution (replaces the old hardcoded pair table)
-	pairCache            map[string]pairCacheEntry
-	uniswapResolvingPair bool // true while an on-chain factory lookup is in flight
-
-	// Liquidity positions view (within Uniswap page)
-	uniswapShowingLiquidity bool
-	liquidityPositions      []helpers.LiquidityPosition
-	liquidityLoading        bool
-	liquidityFocusedIdx     int
-	liquidityErr            string
-
-	// Terra Nullius dapp state
-	terraNullFocusedField   int                    // 1=Claims, 2=Claim
-	terraNullClaimsCount    string                 // display value from number_of_claims()
-	terraNullClaimsLoading  bool
-	terraNullClaimInput     string                 // typed index for claims() query
-	terraNullClaimResult    *helpers.TerraClaimResult
-	terraNullClaimQuerying  bool
-	terraNullLastQueriedIdx string // index used for the current/last result
-	terraNullClaimResultErr string
-	// Terra Nullius claim popup state
-	terraNullMsgInput      textinput.Model
-	terraNullFormFocused   int    // 0=message input, 1=submit button
-	terraNullMsgError      string
+	// dApp modules' own settings (dapp.Host.LoadSettings), persisted to config
+	dappSettings map[string]json.RawMessage
+
+	// Active dApp module (see dapp.Module and dapp_host.go)
+	activeModule dapp.Module
+	moduleFrame  *dapp.View // the active module's View for the current frame
+	v4PanelShown bool       // a module rendered the V4 events panel this frame
 
 	// Pool Event Monitor state
 	poolEventMonitorActive bool
 	poolEventMonitor       *helpers.PoolEventMonitor
 
-	// V4 Block Scanner state (one-shot historical scan)
-	v4BlockScanActive  bool
-	v4BlockScanner     *helpers.V4BlockScanner
+	// Pool event monitor filter (dialogPoolFilter, "f" on the V4 events
+	// panel): the filter in force and its expression, the dialog's edit
+	// buffers, and the saved presets.
+	poolFilter        helpers.PoolEventFilter
+	poolFilterExpr    string
+	poolFilterInput   string
+	poolFilterName    string
+	poolFilterNaming  bool // typing a preset name rather than the expression
+	poolFilterErr     string
+	poolFilterIdx     int
+	poolFilterPresets []config.PoolMonitorPreset
+
+	// Alerts (dialogAlerts, "!" on the Wallets page): the rules and hooks
+	// from the config, the last snapshot periodic rules were checked
+	// against, the poll loop's generation (a restart orphans the old
+	// loop), and the dialog's state.
+	alertRules     []config.AlertRule
+	alertWebhook   string
+	alertCommand   string
+	alertSnapshot  *helpers.AlertSnapshot
+	alertFromBlock uint64 // events before this block are history, not alerts
+	alertGen       int
+	alertFired     map[string]time.Time // last firing by rule expression
+	alertInput     string
+	alertErr       string
+	alertIdx       int
+	alertSeenSwaps map[string]bool // swaps already checked, by tx:logIndex
+	alertSignal    string          // bell/OSC sequences prepended to the next frame
+
+	// Block Explorer page state (PageBlockExplorer): the decoded tree of the
+	// last query, navigated through its visible rows.
+	explorerInput   string
+	explorerEditing bool // the query line has the keyboard
+	explorerQuery   helpers.ExplorerQuery
+	explorerTree    resultTree
+	explorerLoading bool
+	explorerErr     string
+	explorerGen     int                // drops results of superseded queries
+	explorerCancel  context.CancelFunc // cancels the query in flight
+	explorerReturn  config.Page        // page Esc goes back to
+
+	// Transaction Inspector page state (PageTxInspector): one transaction
+	// decoded in depth, in the same tree view as the Block Explorer.
+	inspectorInput   string
+	inspectorEditing bool // the hash line has the keyboard
+	inspectorHash    common.Hash
+	inspectorTree    resultTree
+	inspectorLoading bool
+	inspectorErr     string
+	inspectorGen     int                // drops results of superseded lookups
+	inspectorCancel  context.CancelFunc // cancels the lookup in flight
+	inspectorReturn  config.Page        // page Esc goes back to
 
 	// Address indexer state (toggleable via "i")
 	txIndexerActive bool
@@ -291,6 +314,7 @@ type model struct {
 	// V4 Events panel (shown when pool event monitor is active)
 	v4PoolRows       []store.PoolRow
 	v4EventsViewport viewport.Model
+	v4SelectedPool   string           // pool ID of the highlighted panel row; "" selects the first
 	focusedPanel     focusedPanelKind // which panel (V4 events or log) has scroll focus
 	v4Scroll         scrollbar.State  // scrollbar state for the V4 events panel
 
@@ -302,6 +326,9 @@ type model struct {
 	poolInfoKeyLoading bool
 	poolInfoKeyErr     string
 	poolInfoCopied   bool // true briefly after a successful copy
+	poolInfoHook        *helpers.HookReport // risk report for the pool's hook, once inspected
+	poolInfoHookLoading bool
+	poolInfoHookErr     string
 
 	logScroll   scrollbar.State // scrollbar state for the log panel
 	txQRScroll  scrollbar.State // scrollbar state for the txQR result dialog
@@ -339,6 +366,71 @@ type model struct {
 	pasteTxPollErr   string
 	pasteTxOnChainInfo *rpc.TxOnChainInfo
 	pasteTxChainID   *big.Int // captured at submit time, picks the Etherscan subdomain
+	pasteTxFrom      string   // sender recovered at submit time, for the nonce ledger
+	pasteTxNonce     uint64   // nonce of the submitted tx, for the nonce ledger
+	pasteTxRaw       string   // submitted signed hex, for the transaction history
+
+	// Outbox dialog state (dialogOutbox): the active wallet's locally
+	// reserved nonces, see nonceBatch.
+	outboxEntries []store.NonceReservation
+	outboxIdx     int
+	outboxPending uint64   // chain's pending nonce at the last refresh
+	outboxGaps    []uint64 // unreserved nonces below the highest reservation
+	outboxLoading bool
+	outboxErr     string
+
+	// Transactions page state (PageTransactions): the persistent history of
+	// every packaged, signed and broadcast tx, newest first.
+	txHistory        []store.TxRecord
+	txHistoryIdx     int
+	txHistoryLoading bool
+	txHistoryErr     string
+
+	// Approvals page state (PageApprovals): every spender the active wallet
+	// ever approved, with live allowances.
+	approvalRows     []approvals.Row
+	approvalIdx      int
+	approvalsLoading bool
+	approvalsErr     string
+	approvalsShowAll bool // include revoked/expired rows
+
+	// Address book (persisted to config) and its page state (PageAddressBook)
+	addressBook                    []config.Contact
+	ensNames                       map[string]string // cached reverse-ENS names, refreshed by syncAddressLabels
+	contactIdx                     int
+	contactFormMode                string // "list", "add", "edit"
+	contactForm                    *huh.Form
+	contactFormFields              []huh.Field
+	contactFormButtonFocused       bool
+	contactFormError               string
+	contactResolving               bool // .eth name lookup in flight for the form
+	editingContactIdx              int
+	deleteContactDialogYesSelected bool
+
+	// Address-poisoning checks for the send and paste-tx flows
+	counterparties   []store.Counterparty // indexed transfer counterparties, loaded when either dialog opens
+	sendRecipientAck string               // flagged recipient the user chose to send to anyway
+	pasteTxWarnAck   string               // flagged signed tx the user chose to broadcast anyway
+
+	// Gnosis Safe transaction awaiting owner signatures (shown in dialogTxResult)
+	safeSession *safeSession
+
+	// EIP-712 message a dApp module asked the wallet to sign (shown in dialogTxResult)
+	typedSign *typedSignSession
+
+	// Contract page (any contract, driven by a user-supplied ABI)
+	contractMode              string // "library", "open", "functions" or "call"
+	contractLibrary           []store.SavedABI
+	contractLibIdx            int
+	contractName              string
+	contractAddress           common.Address
+	contractABI               abi.ABI
+	contractFuncs             []contract.Function
+	contractFuncIdx           int
+	contractForm              *huh.Form
+	contractFormFields        []huh.Field
+	contractFormButtonFocused bool
+	contractFormError         string
 
 	// Tx hash hit-test (clickable in the polling phase — opens Etherscan)
 	pasteTxHashLineY  int
@@ -437,20 +529,11 @@ func newModel() model {
 	if len(cfg.WatchedTokens) == 0 {
 		watch = buildTokenWatchlist()
 		cfg.WatchedTokens = tokenWatchToConfigList(watch)
-		config.Save(configPath, cfg)
+		_ = config.Save(configPath, cfg)
 	} else {
 		watch = configListToTokenWatch(cfg.WatchedTokens)
 	}
 
-	// Terra Nullius message input (for claim popup)
-	terraNullInput := textinput.New()
-	terraNullInput.Placeholder = "Enter your message…"
-	terraNullInput.Prompt = ""
-	terraNullInput.TextStyle = lipgloss.NewStyle().Foreground(styles.CText)
-	terraNullInput.Cursor.Style = lipgloss.NewStyle().Foreground(styles.CAccent2)
-	terraNullInput.CharLimit = 256
-	terraNullInput.Width = 44
-
 	// Initialize log viewport
 	vp := viewport.New(0, 20) // Will be resized in Update on first WindowSizeMsg
 	vp.Style = lipgloss.NewStyle().
@@ -509,16 +592,23 @@ func newModel() model {
 		logBuffer:          &strings.Builder{},
 		logSpinner:         logSpin,
 		detailsCache:       make(map[string]rpc.WalletDetails),
-		pairCache:          make(map[string]pairCacheEntry),
-		dapps:           config.DefaultDapps(),
+		dapps:           append(append(dapp.Cards(), config.DefaultDapps()...), cfg.DApps...),
 		selectedDappIdx: 0,
+		dappFormMode:    "list",
 		detailsInWallets:   true, // Enable split panel view by default
-		terraNullFocusedField: 1,
-		terraNullClaimInput:   "0",
-		terraNullMsgInput:     terraNullInput,
 		eventStore:            eventStore,
 		eventStoreErr:         eventStoreErrMsg,
+		addressBook:           cfg.AddressBook,
+		dappSettings:          cfg.DappSettings,
+		poolFilterPresets:     cfg.PoolMonitorPresets,
+		alertRules:            cfg.AlertRules,
+		alertWebhook:          cfg.AlertWebhook,
+		alertCommand:          cfg.AlertCommand,
+		alertFired:            make(map[string]time.Time),
+		contactFormMode:       "list",
+		contractMode:          "library",
 	}
+	m.syncAddressLabels()
 
 	return m
 }
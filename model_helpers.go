@@ -70,6 +70,54 @@ func configListToTokenWatch(entries []config.WatchedToken) []rpc.WatchedToken {
 	return out
 }
 
+// saveConfig persists everything the config file holds. Every settings
+// change goes through here so that saving one section can never drop
+// another.
+func (m *model) saveConfig() error {
+	return config.Save(m.configPath, config.Config{
+		RPCURLs:            m.rpcURLs,
+		Wallets:            m.accounts,
+		Logger:             m.logEnabled,
+		WatchedTokens:      tokenWatchToConfigList(m.tokenWatch),
+		AddressBook:        m.addressBook,
+		DApps:              m.customDapps(),
+		DappSettings:       m.dappSettings,
+		PoolMonitorPresets: m.poolFilterPresets,
+		AlertRules:         m.alertRules,
+		AlertWebhook:       m.alertWebhook,
+		AlertCommand:       m.alertCommand,
+	})
+}
+
+// syncAddressLabels rebuilds the shared address label tables (used by
+// helpers.HyperAddr and helpers.LabelAddr): wallet nicknames and address
+// book labels, the latter winning, plus cached reverse-ENS names that
+// forward-resolve back to their address, kept apart as helpers.SetENSLabels.
+func (m *model) syncAddressLabels() {
+	if m.eventStore != nil {
+		if names, err := m.eventStore.ENSReverseNames(); err == nil {
+			m.ensNames = names
+			ens := make(map[common.Address]string, len(names))
+			for addr, name := range names {
+				ens[common.HexToAddress(addr)] = name
+			}
+			helpers.SetENSLabels(ens)
+		}
+	}
+	labels := make(map[common.Address]string)
+	for _, w := range m.accounts {
+		if w.Name != "" && common.IsHexAddress(w.Address) {
+			labels[common.HexToAddress(w.Address)] = w.Name
+		}
+	}
+	for _, c := range m.addressBook {
+		if c.Label != "" && common.IsHexAddress(c.Address) {
+			labels[common.HexToAddress(c.Address)] = c.Label
+		}
+	}
+	helpers.SetAddressLabels(labels)
+}
+
 // chainIDOrMainnet treats a nil chain ID (undetected connection, or a
 // pre-chain-aware saved entry) as mainnet, matching
 // helpers.UniswapAddressesForChain's existing nil-defaults-to-mainnet rule.
@@ -143,7 +191,9 @@ func (m model) textInputActive() bool {
 		((m.settingsMode == "add" || m.settingsMode == "edit") && m.form != nil) ||
 		((m.tokenFormMode == "add" || m.tokenFormMode == "edit") && m.tokenForm != nil) ||
 		(m.activeDialog == dialogPasteSignedTx && m.pasteTxPhase == pasteTxPhaseForm && m.pasteTxForm != nil) ||
-		m.activeDialog == dialogTerraClaim
+		(m.activePage == config.PageBlockExplorer && m.explorerEditing && m.activeDialog == dialogNone) ||
+		(m.activePage == config.PageTxInspector && m.inspectorEditing && m.activeDialog == dialogNone) ||
+		m.moduleCapturesInput()
 }
 
 // loadSelectedWalletDetails loads details for the currently selected wallet,
@@ -186,37 +236,86 @@ func (m *model) navigateTo(page config.Page) tea.Cmd {
 	case config.PageWatchedTokens:
 		m.tokenFormMode = "list"
 		m.selectedTokenIdx = 0
-	case config.PageUniswap:
-		m.uniswapFromTokenIdx = 0
-		m.uniswapToTokenIdx = 1
-		m.uniswapFromAmount = ""
-		m.uniswapToAmount = ""
-		m.uniswapFocusedField = 0
-		m.uniswapShowingSelector = false
-		m.uniswapSelectorFor = 0
-		m.uniswapSelectorIdx = 0
-		m.uniswapEstimating = false
-		m.uniswapQuote = nil
-		m.uniswapQuoteError = ""
-		m.uniswapPriceImpactWarn = ""
-		m.lastQuoteFromAmount = ""
-		m.lastQuoteFromTokenIdx = -1
-		m.lastQuoteToTokenIdx = -1
-	case config.PageTerraNullius:
-		m.terraNullFocusedField = 1
-		m.terraNullClaimsCount = ""
-		m.terraNullClaimsLoading = true
-		m.terraNullClaimInput = "0"
-		m.terraNullClaimResult = nil
-		m.terraNullClaimQuerying = false
-		m.terraNullClaimResultErr = ""
-		m.activeDialog = dialogNone
-		m.logInfo("Terra Nullius: loading number of claims…")
-		return fetchTerraNumberOfClaims(m.ethClient)
+	case config.PageTransactions:
+		m.txHistoryIdx = 0
+		m.txHistoryErr = ""
+		m.txHistoryLoading = true
+		return loadTxHistory(m.ethClient, m.eventStore)
+	case config.PageApprovals:
+		return m.refreshApprovals()
+	case config.PageAddressBook:
+		m.contactFormMode = "list"
+		m.syncAddressLabels()
+	case config.PageDappBrowser:
+		m.dappFormMode = "list"
+		m.dappForm = nil
+	case config.PageContract:
+		m.contractMode = "library"
+		m.contractForm = nil
+		m.refreshABILibrary()
+	case config.PageBlockExplorer:
+		m.explorerEditing = m.explorerTree.roots == nil && !m.explorerLoading
+	case config.PageTxInspector:
+		m.inspectorEditing = m.inspectorTree.roots == nil && !m.inspectorLoading
+	case config.PageDapp:
+		if m.activeModule != nil {
+			return m.activeModule.Init(m.host())
+		}
 	}
 	return nil
 }
 
+// chainID returns the connected chain's ID, or nil if there is no connection
+// or the lookup failed at connect time. helpers.UniswapAddressesForChain treats
+// nil as "assume mainnet", matching the app's existing default network.
+func (m *model) chainID() *big.Int {
+	if m.ethClient == nil {
+		return nil
+	}
+	return m.ethClient.DetectedChainID
+}
+
+// tokenWatchForActiveChain returns m.tokenWatch filtered to the connected
+// chain, so balance loads, the tx indexer, the Watched Tokens page, and
+// dApp modules only ever see the current network's addresses.
+func (m *model) tokenWatchForActiveChain() []rpc.WatchedToken {
+	return tokensForChain(m.tokenWatch, m.chainID())
+}
+
+// togglePoolMonitor starts or stops the live V4 pool event monitor.
+func (m *model) togglePoolMonitor() tea.Cmd {
+	if m.poolEventMonitorActive {
+		if m.poolEventMonitor != nil {
+			m.poolEventMonitor.Stop()
+			m.poolEventMonitor = nil
+		}
+		m.poolEventMonitorActive = false
+		m.logInfo("Pool Event Monitor stopped")
+		return nil
+	}
+	m.focusedPanel = focusedPanelV4Events
+	m.logInfo("Pool Event Monitor starting… (WebSocket subscription, else HTTP polling)")
+	cmds := []tea.Cmd{m.startPoolMonitor()}
+	if m.eventStore != nil {
+		cmds = append(cmds, loadV4PoolTableCmd(m.eventStore))
+	}
+	return tea.Batch(cmds...)
+}
+
+// startPoolMonitor starts a pool event monitor with the filter in force,
+// replacing any previous one.
+func (m *model) startPoolMonitor() tea.Cmd {
+	monitor := helpers.NewPoolEventMonitor()
+	monitor.PoolLookup = storePoolLookup(m.eventStore)
+	monitor.Start(m.rpcURL, m.poolFilter)
+	m.poolEventMonitor = monitor
+	m.poolEventMonitorActive = true
+	if !m.poolFilter.Empty() {
+		m.logInfo("Pool Event Monitor filter: " + m.poolFilter.String())
+	}
+	return tea.Batch(waitForPoolEvent(monitor), waitForPoolEventData(monitor))
+}
+
 // loadDetails fetches ETH and token balances for an address.
 func loadDetails(client *rpc.Client, addr common.Address, watch []rpc.WatchedToken) tea.Cmd {
 	return func() tea.Msg {
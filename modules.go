@@ -0,0 +1,14 @@
+package main
+
+import (
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/dapp/terra"
+	"charm-wallet-tui/dapp/uniswap"
+)
+
+// The built-in dApp modules, in dApp Browser order. A new module only needs
+// a dapp.Module implementation and a line here.
+func init() {
+	dapp.Register(uniswap.New())
+	dapp.Register(terra.New())
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nonceBatch hands out nonces for one packaging pass (e.g. approve + swap).
+// It starts from the chain's pending nonce, skips every nonce already held
+// by a queued/broadcast reservation in the local store, and records each tx
+// it builds as a new reservation — so packaging twice in a row without
+// broadcasting in between no longer reuses the same pending nonce.
+//
+// A nil store degrades to plain pending-nonce sequencing within the batch.
+//
+// When from is a Gnosis Safe there is nothing to sign with its own key, so
+// build only collects the calls and done turns the batch into a
+// safeProposalMsg: one Safe transaction (MultiSend for approve + swap) for
+// the owners to sign.
+type nonceBatch struct {
+	st       *store.Store
+	from     common.Address
+	params   rpc.TxParams
+	reserved []uint64
+
+	// reservations are the outbox rows this batch created, discarded again
+	// by release when packaging fails part-way.
+	reservations []int64
+
+	safe      *rpc.SafeInfo
+	safeCalls []rpc.SafeCallSpec
+}
+
+// safeSource tells newNonceBatch whether the packaging account is a Safe
+// without dialing a fresh client: the account's loaded details answer
+// "is it a Safe", and the model's client reads what may have changed since.
+type safeSource struct {
+	client *rpc.Client
+	info   *rpc.SafeInfo
+	known  bool
+}
+
+// safeSourceFor resolves from's Safe info from the details cache. Details
+// that were never loaded, or whose Safe check failed, are re-checked live.
+func (m *model) safeSourceFor(from common.Address) safeSource {
+	if d, ok := m.detailsCache[strings.ToLower(from.Hex())]; ok && d.SafeErr == "" {
+		return safeSource{client: m.ethClient, info: d.Safe, known: true}
+	}
+	return safeSource{client: m.ethClient}
+}
+
+// load returns from's Safe info, or nil for an ordinary account. A known
+// Safe's nonce is always read live: owners must sign the nonce the Safe
+// will execute at, not the one cached when its details loaded. A failed
+// check is an error — packaging from a Safe as if it were an EOA yields a
+// tx nobody can sign.
+func (s safeSource) load(from common.Address) (*rpc.SafeInfo, error) {
+	if s.known && s.info == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+	defer cancel()
+	if !s.known {
+		info, err := rpc.LoadSafeInfo(ctx, s.client, from)
+		if err != nil {
+			return nil, fmt.Errorf("checking whether %s is a Safe: %w", helpers.ShortenAddr(from.Hex()), err)
+		}
+		return info, nil
+	}
+	nonce, err := rpc.SafeNonce(ctx, s.client, from)
+	if err != nil {
+		return nil, err
+	}
+	info := *s.info
+	info.Nonce = nonce
+	return &info, nil
+}
+
+// newNonceBatch fetches live tx params for from and loads its outstanding
+// local reservations, marking any the chain has already moved past.
+func newNonceBatch(st *store.Store, rpcURL string, from common.Address, safe safeSource) (*nonceBatch, error) {
+	p, err := rpc.FetchTxParams(rpcURL, from)
+	if err != nil {
+		return nil, err
+	}
+	info, err := safe.load(from)
+	if err != nil {
+		return nil, err
+	}
+	b := &nonceBatch{st: st, from: from, params: p, safe: info}
+	if st != nil {
+		// Without the outbox's view of held nonces the batch would fall back
+		// to the pending nonce and collide with queued txs, so refuse.
+		chain := p.ChainID.Uint64()
+		if err := st.SyncNonces(chain, from, p.Nonce); err != nil {
+			return nil, fmt.Errorf("syncing outbox nonces: %w", err)
+		}
+		if b.reserved, err = st.ActiveNonces(chain, from); err != nil {
+			return nil, fmt.Errorf("reading outbox nonces: %w", err)
+		}
+	}
+	return b, nil
+}
+
+// build packages one unsigned EIP-1559 tx at the next free nonce, reserves
+// that nonce in the outbox under summary and starts its transaction history
+// row.
+func (b *nonceBatch) build(to common.Address, value *big.Int, gasLimit uint64, data []byte, summary string) (urStr, txJSON string, err error) {
+	if b.safe != nil {
+		b.safeCalls = append(b.safeCalls, rpc.SafeCallSpec{To: to, Value: value, Data: data})
+		return "", "", nil
+	}
+	nonce := helpers.NextFreeNonce(b.params.Nonce, b.reserved)
+	urStr, txJSON, err = rpc.BuildUnsignedTxEIP4527(b.from, to, value, gasLimit, data, nonce, b.params.Tip, b.params.MaxFee, b.params.ChainID)
+	if err != nil {
+		return "", "", err
+	}
+	if b.st != nil {
+		id, err := b.st.ReserveNonce(store.NonceReservation{
+			ChainID:   b.params.ChainID.Uint64(),
+			Address:   b.from,
+			Nonce:     nonce,
+			RequestID: packagedRequestID(txJSON),
+			Summary:   summary,
+			UR:        urStr,
+			TxJSON:    txJSON,
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("reserving nonce %d: %w", nonce, err)
+		}
+		b.reservations = append(b.reservations, id)
+		if _, err := b.st.SavePackagedTx(store.TxRecord{
+			ChainID:   b.params.ChainID.Uint64(),
+			From:      b.from,
+			Nonce:     nonce,
+			RequestID: packagedRequestID(txJSON),
+			Summary:   summary,
+			UR:        urStr,
+			TxJSON:    txJSON,
+		}); err != nil {
+			return "", "", fmt.Errorf("recording packaged tx: %w", err)
+		}
+	}
+	b.reserved = append(b.reserved, nonce)
+	return urStr, txJSON, nil
+}
+
+// release discards every reservation this batch made, so a pass that fails
+// after reserving a nonce (approve built, swap not) leaves no gap behind.
+func (b *nonceBatch) release() {
+	for _, id := range b.reservations {
+		_ = b.st.SetNonceStatus(id, store.NonceDiscarded)
+	}
+	b.reservations = nil
+}
+
+// done is the batch's final message: msg itself for an ordinary account,
+// or a safeProposalMsg carrying the collected calls when from is a Safe.
+// A failed msg releases the batch's reservations.
+func (b *nonceBatch) done(msg packageTransactionMsg) tea.Msg {
+	if msg.err != nil {
+		b.release()
+		return msg
+	}
+	if b.safe == nil {
+		return msg
+	}
+	return safeProposalMsg{info: b.safe, chainID: b.params.ChainID, calls: b.safeCalls, summary: msg.txDisplay}
+}
+
+// packagedRequestID extracts the EIP-4527 request-id from a packaged tx's
+// JSON (see rpc.BuildUnsignedTxEIP4527), or "" if it has none.
+func packagedRequestID(txJSON string) string {
+	var fields struct {
+		RequestID string `json:"requestId"`
+	}
+	if json.Unmarshal([]byte(txJSON), &fields) != nil {
+		return ""
+	}
+	return fields.RequestID
+}
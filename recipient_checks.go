@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"charm-wallet-tui/helpers"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// loadRecipientChecks refreshes the indexed counterparties the send and
+// paste-tx flows check recipients against. It reads only the local store, so
+// it runs synchronously when either dialog opens.
+func (m *model) loadRecipientChecks() {
+	m.counterparties = nil
+	if m.eventStore == nil {
+		return
+	}
+	wallets := make([]common.Address, 0, len(m.accounts))
+	for _, w := range m.accounts {
+		wallets = append(wallets, common.HexToAddress(w.Address))
+	}
+	cps, err := m.eventStore.Counterparties(wallets)
+	if err != nil {
+		m.logWarn("Recipient checks: could not read indexed transfers: " + err.Error())
+		return
+	}
+	m.counterparties = cps
+}
+
+// recipientWarning returns why sending to addr looks like address poisoning,
+// or "" if it does not. Wallets and address book entries are trusted as-is.
+// Otherwise addr is flagged if it imitates the first and last characters of
+// a trusted address or of a counterparty that has actually moved value with
+// one of the wallets, or if it first appeared in a zero-value transfer.
+func (m *model) recipientWarning(addr string) string {
+	if !common.IsHexAddress(addr) {
+		return ""
+	}
+	a := common.HexToAddress(addr)
+
+	var known []common.Address
+	for _, w := range m.accounts {
+		known = append(known, common.HexToAddress(w.Address))
+	}
+	for _, c := range m.addressBook {
+		known = append(known, common.HexToAddress(c.Address))
+	}
+	for _, k := range known {
+		if k == a {
+			return ""
+		}
+	}
+	for _, cp := range m.counterparties {
+		if cp.ValueTransfers > 0 {
+			known = append(known, cp.Address)
+		}
+	}
+
+	if k, ok := helpers.Lookalike(a, known); ok {
+		return fmt.Sprintf("Looks like %s but differs in the middle — possible address poisoning", helpers.LabelAddr(k.Hex()))
+	}
+	for _, cp := range m.counterparties {
+		if cp.Address == a && cp.FirstZeroValue {
+			return fmt.Sprintf("First seen in a zero-value transfer (block %d) — a common address-poisoning pattern", cp.FirstBlock)
+		}
+	}
+	return ""
+}
+
+// confirmSendRecipient gates the send form on recipientWarning: the first
+// submit to a flagged address is refused with the warning, a second submit
+// to the same address goes through.
+func (m *model) confirmSendRecipient(addr string) error {
+	warning := m.recipientWarning(addr)
+	if warning == "" || m.sendRecipientAck == addr {
+		return nil
+	}
+	m.sendRecipientAck = addr
+	m.logWarn(fmt.Sprintf("Recipient %s: %s", helpers.LabelAddr(addr), warning))
+	return fmt.Errorf("⚠ %s. Submit again to send anyway", warning)
+}
@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CallMethod runs a view/pure method of contract via eth_call (from is the
+// msg.sender the call sees, zero for none) and returns its decoded outputs.
+// Reverts come back with their reason when the node supplies one.
+func CallMethod(ctx context.Context, client *Client, contract, from common.Address, parsed abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	if client == nil || client.Client == nil {
+		return nil, fmt.Errorf("no RPC client")
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{From: from, To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 && len(parsed.Methods[method].Outputs) > 0 {
+		return nil, fmt.Errorf("empty return data — is %s a contract on this network?", contract.Hex())
+	}
+	return parsed.Unpack(method, out)
+}
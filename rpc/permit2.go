@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// PermitSingle is Permit2's signed one-token allowance: spender may move up
+// to Amount of Token until Expiration, and the signature itself must be
+// used by SigDeadline. Nonce is the allowance's current nonce (see
+// Permit2AllowanceWithNonce); Permit2 bumps it on every permit.
+type PermitSingle struct {
+	Token       common.Address
+	Amount      *big.Int // uint160
+	Expiration  uint64   // uint48, unix seconds
+	Nonce       uint64   // uint48
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// Permit2TypedData returns the EIP-712 typed data an owner signs for p,
+// under Permit2's domain (name "Permit2", no version).
+func Permit2TypedData(chainID *big.Int, p PermitSingle) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"PermitSingle": {
+				{Name: "details", Type: "PermitDetails"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint160"},
+				{Name: "expiration", Type: "uint48"},
+				{Name: "nonce", Type: "uint48"},
+			},
+		},
+		PrimaryType: "PermitSingle",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Permit2",
+			ChainId:           (*math.HexOrDecimal256)(chainID),
+			VerifyingContract: Permit2Address.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"details": map[string]interface{}{
+				"token":      p.Token.Hex(),
+				"amount":     bigOrZero(p.Amount).String(),
+				"expiration": new(big.Int).SetUint64(p.Expiration).String(),
+				"nonce":      new(big.Int).SetUint64(p.Nonce).String(),
+			},
+			"spender":     p.Spender.Hex(),
+			"sigDeadline": bigOrZero(p.SigDeadline).String(),
+		},
+	}
+}
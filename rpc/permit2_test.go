@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Type hashes from Permit2's PermitHash.sol.
+var (
+	permitDetailsTypeHash = common.HexToHash("0x65626cad6cb96493bf6f5ebea28756c966f023ab9e8a83a7101849d5573b3678")
+	permitSingleTypeHash  = common.HexToHash("0xf3841cd1ff0085026a6327b620b67997ce40f282c88a8e905a7a5626e310f3d0")
+)
+
+func TestPermit2TypedData(t *testing.T) {
+	p := PermitSingle{
+		Token:       common.HexToAddress("0x00000000000000000000000000000000000000a1"),
+		Amount:      big.NewInt(5_000_000),
+		Expiration:  1_900_000_000,
+		Nonce:       3,
+		Spender:     common.HexToAddress("0x00000000000000000000000000000000000000b2"),
+		SigDeadline: big.NewInt(1_800_000_000),
+	}
+	chainID := big.NewInt(1)
+	typed := Permit2TypedData(chainID, p)
+
+	if got := common.BytesToHash(typed.TypeHash("PermitDetails")); got != permitDetailsTypeHash {
+		t.Errorf("PermitDetails type hash = %s", got.Hex())
+	}
+	if got := common.BytesToHash(typed.TypeHash("PermitSingle")); got != permitSingleTypeHash {
+		t.Errorf("PermitSingle type hash = %s", got.Hex())
+	}
+
+	// Independent encoding, the way PermitHash.hash does it on-chain.
+	word := func(x *big.Int) []byte { return common.LeftPadBytes(x.Bytes(), 32) }
+	addr := func(a common.Address) []byte { return common.LeftPadBytes(a.Bytes(), 32) }
+	u := func(x uint64) []byte { return word(new(big.Int).SetUint64(x)) }
+	domain := crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte("Permit2")), word(chainID), addr(Permit2Address))
+	details := crypto.Keccak256(permitDetailsTypeHash.Bytes(), addr(p.Token), word(p.Amount), u(p.Expiration), u(p.Nonce))
+	structHash := crypto.Keccak256(permitSingleTypeHash.Bytes(), details, addr(p.Spender), word(p.SigDeadline))
+	want := crypto.Keccak256([]byte{0x19, 0x01}, domain, structHash)
+
+	got, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToHash(got) != common.BytesToHash(want) {
+		t.Fatalf("permit hash = %x, want %x", got, want)
+	}
+
+	key, _ := crypto.GenerateKey()
+	raw, err := crypto.Sign(want, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig [65]byte
+	copy(sig[:], raw)
+	sig[64] += 27
+	signer, err := RecoverTypedDataSigner(typed, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("recovered %s, want %s", signer.Hex(), crypto.PubkeyToAddress(key.PublicKey).Hex())
+	}
+}
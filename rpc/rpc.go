@@ -94,6 +94,8 @@ type WalletDetails struct {
 	Address    string
 	EthWei     *big.Int
 	Tokens     []TokenBalance
+	Safe       *SafeInfo // non-nil when the address is a Gnosis Safe
+	SafeErr    string    // Safe detection failed: the address may still be a Safe
 	LoadedAt   time.Time
 	ErrMessage string
 }
@@ -151,6 +153,14 @@ func LoadWalletDetailsWithTimeout(client *Client, addr common.Address, watch []W
 	})
 	d.Tokens = toks
 
+	// A failed Safe read is kept apart from "not a Safe", so packaging can
+	// refuse instead of treating a Safe as a plain account.
+	if safe, err := LoadSafeInfo(ctx, client, addr); err != nil {
+		d.SafeErr = err.Error()
+	} else {
+		d.Safe = safe
+	}
+
 	return d
 }
 
@@ -233,31 +243,43 @@ const permit2AllowanceABI = `[{"inputs":[{"name":"user","type":"address"},{"name
 // Permit2Allowance reads Permit2's allowance(owner, token, spender), mirroring
 // ERC20Allowance's shape for the Universal Router's Permit2-based approval flow.
 func Permit2Allowance(ctx context.Context, client *Client, token, owner, spender common.Address) (amount *big.Int, expiration uint64, err error) {
+	amount, expiration, _, err = Permit2AllowanceWithNonce(ctx, client, token, owner, spender)
+	return amount, expiration, err
+}
+
+// Permit2AllowanceWithNonce is Permit2Allowance plus the allowance's current
+// nonce, which a signed PermitSingle for the same (owner, token, spender)
+// must carry.
+func Permit2AllowanceWithNonce(ctx context.Context, client *Client, token, owner, spender common.Address) (amount *big.Int, expiration, nonce uint64, err error) {
 	parsed, err := abi.JSON(strings.NewReader(permit2AllowanceABI))
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
 	data, err := parsed.Pack("allowance", owner, token, spender)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
 	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &Permit2Address, Data: data}, nil)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
 	vals, err := parsed.Unpack("allowance", out)
-	if err != nil || len(vals) < 2 {
-		return nil, 0, fmt.Errorf("unpack Permit2 allowance: %w", err)
+	if err != nil || len(vals) < 3 {
+		return nil, 0, 0, fmt.Errorf("unpack Permit2 allowance: %w", err)
 	}
 	amount, _ = vals[0].(*big.Int)
 	expBig, _ := vals[1].(*big.Int)
+	nonceBig, _ := vals[2].(*big.Int)
 	if amount == nil {
 		amount = big.NewInt(0)
 	}
 	if expBig != nil {
 		expiration = expBig.Uint64()
 	}
-	return amount, expiration, nil
+	if nonceBig != nil {
+		nonce = nonceBig.Uint64()
+	}
+	return amount, expiration, nonce, nil
 }
 
 // erc20SymbolABI describes the standard ERC-20 symbol() view function, used to
@@ -440,15 +462,21 @@ type rlpEIP1559UnsignedTx struct {
 	AccessList           []struct{}
 }
 
+// EIP-4527 eth-sign-request data types (CBOR key 3).
+const (
+	signDataTransaction = 1 // RLP-encoded transaction
+	signDataTypedData   = 2 // EIP-712 typed data as UTF-8 JSON
+)
+
 // buildEthSignRequestCBOR builds the CBOR payload for an EIP-4527 eth-sign-request.
 // Map structure:
 //
 //	1: tag(37, bytes(16)) — request-id UUID
-//	2: bytes             — RLP-encoded unsigned transaction
-//	3: uint(1)           — data-type = transaction
+//	2: bytes             — sign data (RLP-encoded unsigned tx, or typed-data JSON)
+//	3: uint              — data-type (signDataTransaction / signDataTypedData)
 //	4: uint              — chain-id
 //	6: bytes(20)         — from address
-func buildEthSignRequestCBOR(requestID [16]byte, signData []byte, chainID uint64, fromAddr common.Address) []byte {
+func buildEthSignRequestCBOR(requestID [16]byte, signData []byte, dataType uint64, chainID uint64, fromAddr common.Address) []byte {
 	var buf []byte
 	buf = append(buf, 0xA5) // map(5)
 
@@ -461,7 +489,7 @@ func buildEthSignRequestCBOR(requestID [16]byte, signData []byte, chainID uint64
 	buf = append(buf, cborBytesField(signData)...)
 
 	buf = append(buf, 0x03) // key 3
-	buf = append(buf, 0x01) // uint(1) — transaction type
+	buf = append(buf, cborUintField(dataType)...)
 
 	buf = append(buf, 0x04) // key 4
 	buf = append(buf, cborUintField(chainID)...)
@@ -472,6 +500,25 @@ func buildEthSignRequestCBOR(requestID [16]byte, signData []byte, chainID uint64
 	return buf
 }
 
+// newRequestID returns a random (version 4) UUID for an eth-sign-request.
+func newRequestID() ([16]byte, error) {
+	var requestID [16]byte
+	if _, err := rand.Read(requestID[:]); err != nil {
+		return requestID, err
+	}
+	requestID[6] = (requestID[6] & 0x0F) | 0x40
+	requestID[8] = (requestID[8] & 0x3F) | 0x80
+	return requestID, nil
+}
+
+// ethSignRequestUR appends the CRC32 checksum to an eth-sign-request CBOR
+// payload and bytewords-encodes it as a single-part UR string.
+func ethSignRequestUR(cborData []byte) string {
+	checksum := crc32.ChecksumIEEE(cborData)
+	payload := append(cborData, byte(checksum>>24), byte(checksum>>16), byte(checksum>>8), byte(checksum))
+	return "ur:eth-sign-request/" + encodeBytewordsMinimal(payload)
+}
+
 // BuildUnsignedTxEIP4527 assembles an EIP-4527 UR from already-known transaction
 // parameters. Unlike PackUnsignedTxEIP4527 it does not require an RPC connection,
 // making it suitable for offline testing and batch tooling.
@@ -492,17 +539,11 @@ func BuildUnsignedTxEIP4527(from, to common.Address, value *big.Int, gasLimit ui
 	}
 	signData := append([]byte{0x02}, rlpBytes...)
 
-	var requestID [16]byte
-	if _, err := rand.Read(requestID[:]); err != nil {
+	requestID, err := newRequestID()
+	if err != nil {
 		return "", "", err
 	}
-	requestID[6] = (requestID[6] & 0x0F) | 0x40
-	requestID[8] = (requestID[8] & 0x3F) | 0x80
-
-	cborData := buildEthSignRequestCBOR(requestID, signData, chainID.Uint64(), from)
-	checksum := crc32.ChecksumIEEE(cborData)
-	payload := append(cborData, byte(checksum>>24), byte(checksum>>16), byte(checksum>>8), byte(checksum))
-	urStr := "ur:eth-sign-request/" + encodeBytewordsMinimal(payload)
+	urStr := ethSignRequestUR(buildEthSignRequestCBOR(requestID, signData, signDataTransaction, chainID.Uint64(), from))
 
 	txFields := map[string]interface{}{
 		"from":                 from.Hex(),
@@ -670,6 +711,9 @@ func decodeRawTx(rawHex string) (*types.Transaction, error) {
 	return tx, nil
 }
 
+// erc20TransferSelector is the 4-byte selector of transfer(address,uint256).
+var erc20TransferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+
 // DecodedSignedTx holds human-readable fields extracted from a pasted signed
 // transaction. The sender is recovered from the transaction's own signature —
 // no private key is ever read or required.
@@ -691,6 +735,10 @@ type DecodedSignedTx struct {
 	PriorityFeeHuman string
 	ChainID          *big.Int
 	JSON             string // pretty-printed transaction JSON
+	// TransferTo is the recipient of an ERC-20 transfer(address,uint256)
+	// call, "" for any other calldata. For a token send To is the token
+	// contract, so this is the address the funds actually go to.
+	TransferTo string
 }
 
 // DecodeSignedRawTx parses a "0x..." pre-signed raw transaction and extracts
@@ -729,6 +777,9 @@ func DecodeSignedRawTx(rawHex string) (DecodedSignedTx, error) {
 		ChainID:    tx.ChainId(),
 		JSON:       prettyJSON,
 	}
+	if data := tx.Data(); len(data) == 68 && bytes.Equal(data[:4], erc20TransferSelector) {
+		decoded.TransferTo = common.BytesToAddress(data[4:36]).Hex()
+	}
 	if tx.Type() == types.DynamicFeeTxType {
 		decoded.IsEIP1559 = true
 		decoded.MaxFeeHuman = weiToGweiStr(tx.GasFeeCap())
@@ -779,11 +830,11 @@ type TxOnChainInfo struct {
 	RevertReason      string // best-effort explanation when Status == "Failed"
 }
 
-// revertReason re-simulates a failed transaction via eth_call at its parent
+// RevertReason re-simulates a failed transaction via eth_call at its parent
 // block to recover the revert reason the receipt itself doesn't carry.
 // Best-effort: returns "" if the sender can't be recovered or the call
 // doesn't yield a usable error.
-func revertReason(ctx context.Context, client *Client, tx *types.Transaction, blockNumber *big.Int) string {
+func RevertReason(ctx context.Context, client ethereum.ContractCaller, tx *types.Transaction, blockNumber *big.Int) string {
 	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
 	if err != nil {
 		return ""
@@ -872,7 +923,7 @@ func GetTransactionOnChain(client *Client, txHash common.Hash) (*TxOnChainInfo,
 
 	reason := ""
 	if status == "Failed" {
-		reason = revertReason(ctx, client, tx, receipt.BlockNumber)
+		reason = RevertReason(ctx, client, tx, receipt.BlockNumber)
 	}
 
 	return &TxOnChainInfo{
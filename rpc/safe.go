@@ -0,0 +1,354 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Safe operation values for SafeTx.Operation.
+const (
+	SafeCall         uint8 = 0
+	SafeDelegateCall uint8 = 1
+)
+
+// MultiSendCallOnlyAddress is the canonical Safe v1.3.0 MultiSendCallOnly
+// deployment, used (via delegatecall) to bundle several calls — e.g. an
+// approve and a swap — into one Safe transaction.
+var MultiSendCallOnlyAddress = common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D")
+
+// safeSentinel is the linked-list head Safe uses for owners and modules.
+var safeSentinel = common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+const safeABI = `[
+{"inputs":[],"name":"getOwners","outputs":[{"type":"address[]"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"getThreshold","outputs":[{"type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"nonce","outputs":[{"type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"VERSION","outputs":[{"type":"string"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"start","type":"address"},{"name":"pageSize","type":"uint256"}],"name":"getModulesPaginated","outputs":[{"name":"array","type":"address[]"},{"name":"next","type":"address"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],"name":"execTransaction","outputs":[{"name":"success","type":"bool"}],"stateMutability":"payable","type":"function"},
+{"inputs":[{"name":"transactions","type":"bytes"}],"name":"multiSend","outputs":[],"stateMutability":"payable","type":"function"}
+]`
+
+var parsedSafeABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(safeABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// SafeInfo is the on-chain configuration of a Gnosis Safe.
+type SafeInfo struct {
+	Address   common.Address
+	Version   string
+	Owners    []common.Address
+	Threshold uint64
+	Nonce     uint64
+	Modules   []common.Address
+}
+
+// IsOwner reports whether a is one of the Safe's owners.
+func (s *SafeInfo) IsOwner(a common.Address) bool {
+	for _, o := range s.Owners {
+		if o == a {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadSafeInfo reads owners, threshold, nonce, version and enabled modules
+// from addr. It returns (nil, nil) when addr is not a Safe: an EOA, or a
+// contract that rejects getThreshold/getOwners. A failed read (timeout,
+// dropped connection) is an error, since addr may still be a Safe.
+func LoadSafeInfo(ctx context.Context, client *Client, addr common.Address) (*SafeInfo, error) {
+	if client == nil || client.Client == nil {
+		return nil, fmt.Errorf("no RPC client")
+	}
+	code, err := client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, nil
+	}
+
+	call := func(method string, args ...interface{}) ([]interface{}, error) {
+		data, err := parsedSafeABI.Pack(method, args...)
+		if err != nil {
+			return nil, err
+		}
+		out, err := client.CallContract(ctx, ethereum.CallMsg{To: &addr, Data: data}, nil)
+		if err != nil {
+			return nil, err
+		}
+		// Output that does not decode is a contract without the method.
+		vals, _ := parsedSafeABI.Unpack(method, out)
+		return vals, nil
+	}
+
+	thresholdOut, err := call("getThreshold")
+	if err != nil && !isCallRejection(err) {
+		return nil, fmt.Errorf("getThreshold: %w", err)
+	}
+	if err != nil || len(thresholdOut) == 0 {
+		return nil, nil
+	}
+	ownersOut, err := call("getOwners")
+	if err != nil && !isCallRejection(err) {
+		return nil, fmt.Errorf("getOwners: %w", err)
+	}
+	if err != nil || len(ownersOut) == 0 {
+		return nil, nil
+	}
+	threshold, _ := thresholdOut[0].(*big.Int)
+	owners, _ := ownersOut[0].([]common.Address)
+	if threshold == nil || threshold.Sign() == 0 || len(owners) == 0 {
+		return nil, nil
+	}
+
+	info := &SafeInfo{Address: addr, Owners: owners, Threshold: threshold.Uint64()}
+	if info.Nonce, err = SafeNonce(ctx, client, addr); err != nil {
+		return nil, err
+	}
+	if out, err := call("VERSION"); err == nil && len(out) > 0 {
+		info.Version, _ = out[0].(string)
+	}
+	if out, err := call("getModulesPaginated", safeSentinel, big.NewInt(10)); err == nil && len(out) > 0 {
+		info.Modules, _ = out[0].([]common.Address)
+	}
+	return info, nil
+}
+
+// SafeNonce reads a Safe's current nonce, the one the next SafeTx must use.
+func SafeNonce(ctx context.Context, client *Client, safe common.Address) (uint64, error) {
+	if client == nil || client.Client == nil {
+		return 0, fmt.Errorf("no RPC client")
+	}
+	data, err := parsedSafeABI.Pack("nonce")
+	if err != nil {
+		return 0, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &safe, Data: data}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("Safe nonce: %w", err)
+	}
+	vals, err := parsedSafeABI.Unpack("nonce", out)
+	if err != nil || len(vals) == 0 {
+		return 0, fmt.Errorf("Safe nonce: unexpected response")
+	}
+	n, ok := vals[0].(*big.Int)
+	if !ok {
+		return 0, fmt.Errorf("Safe nonce: unexpected response")
+	}
+	return n.Uint64(), nil
+}
+
+// isCallRejection reports whether err is the contract turning a call down
+// (a revert) rather than the read failing.
+// Nodes report reverts as JSON-RPC errors; transport failures carry no code.
+func isCallRejection(err error) bool {
+	var rpcErr gethrpc.Error
+	return errors.As(err, &rpcErr)
+}
+
+// SafeTx is the payload a Safe's owners sign: the call the Safe makes plus
+// its refund parameters. The app always leaves the refund fields zero (the
+// submitting owner pays gas), which is also what Safe{Wallet} does.
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          uint64
+}
+
+func bigOrZero(x *big.Int) *big.Int {
+	if x == nil {
+		return new(big.Int)
+	}
+	return x
+}
+
+// SafeTypedData returns the EIP-712 typed data owners sign for tx. Safes
+// before v1.3.0 leave chainId out of their domain separator.
+func SafeTypedData(chainID *big.Int, safe common.Address, version string, tx SafeTx) apitypes.TypedData {
+	domainTypes := []apitypes.Type{{Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}}
+	domain := apitypes.TypedDataDomain{ChainId: (*math.HexOrDecimal256)(chainID), VerifyingContract: safe.Hex()}
+	if safeLegacyDomain(version) {
+		domainTypes = domainTypes[1:]
+		domain.ChainId = nil
+	}
+
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domainTypes,
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"to":             tx.To.Hex(),
+			"value":          bigOrZero(tx.Value).String(),
+			"data":           hexutil.Encode(tx.Data),
+			"operation":      fmt.Sprintf("%d", tx.Operation),
+			"safeTxGas":      bigOrZero(tx.SafeTxGas).String(),
+			"baseGas":        bigOrZero(tx.BaseGas).String(),
+			"gasPrice":       bigOrZero(tx.GasPrice).String(),
+			"gasToken":       tx.GasToken.Hex(),
+			"refundReceiver": tx.RefundReceiver.Hex(),
+			"nonce":          fmt.Sprintf("%d", tx.Nonce),
+		},
+	}
+}
+
+func safeLegacyDomain(version string) bool {
+	for _, v := range []string{"0.", "1.0.", "1.1.", "1.2."} {
+		if strings.HasPrefix(version, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// SafeTxHash computes the EIP-712 safeTxHash owners sign for tx.
+func SafeTxHash(chainID *big.Int, safe common.Address, version string, tx SafeTx) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(SafeTypedData(chainID, safe, version, tx))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// BuildTypedDataSignRequestEIP4527 packages EIP-712 typed data as an
+// EIP-4527 eth-sign-request (data-type 2) for signer, returning the UR and
+// its request-id so the scanned-back eth-signature can be matched to it.
+func BuildTypedDataSignRequestEIP4527(signer common.Address, chainID *big.Int, typedData apitypes.TypedData) (urString string, requestID [16]byte, err error) {
+	jsonBytes, err := json.Marshal(typedData)
+	if err != nil {
+		return "", requestID, err
+	}
+	requestID, err = newRequestID()
+	if err != nil {
+		return "", requestID, err
+	}
+	return ethSignRequestUR(buildEthSignRequestCBOR(requestID, jsonBytes, signDataTypedData, chainID.Uint64(), signer)), requestID, nil
+}
+
+// RecoverSafeSigner returns the owner whose EIP-712 signature over
+// safeTxHash sig is. Both v conventions (0/1 and 27/28) are accepted.
+func RecoverSafeSigner(safeTxHash common.Hash, sig [65]byte) (common.Address, error) {
+	s := make([]byte, 65)
+	copy(s, sig[:])
+	if s[64] >= 27 {
+		s[64] -= 27
+	}
+	pub, err := crypto.SigToPub(safeTxHash.Bytes(), s)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// RecoverTypedDataSigner returns the account whose EIP-712 signature over
+// typedData sig is, for checking a scanned eth-signature before using it.
+func RecoverTypedDataSigner(typedData apitypes.TypedData, sig [65]byte) (common.Address, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverSafeSigner(common.BytesToHash(hash), sig)
+}
+
+// PackSafeSignatures concatenates owner signatures in the ascending-owner
+// order execTransaction's checkSignatures requires, with v normalised to
+// 27/28 (a plain ECDSA signature of the safeTxHash).
+func PackSafeSignatures(sigs map[common.Address][65]byte) []byte {
+	owners := make([]common.Address, 0, len(sigs))
+	for o := range sigs {
+		owners = append(owners, o)
+	}
+	sort.Slice(owners, func(i, j int) bool { return bytes.Compare(owners[i].Bytes(), owners[j].Bytes()) < 0 })
+
+	out := make([]byte, 0, 65*len(owners))
+	for _, o := range owners {
+		sig := sigs[o]
+		if sig[64] < 27 {
+			sig[64] += 27
+		}
+		out = append(out, sig[:]...)
+	}
+	return out
+}
+
+// BuildSafeExecCalldata encodes execTransaction(tx..., signatures).
+func BuildSafeExecCalldata(tx SafeTx, signatures []byte) ([]byte, error) {
+	return parsedSafeABI.Pack("execTransaction",
+		tx.To, bigOrZero(tx.Value), tx.Data, tx.Operation,
+		bigOrZero(tx.SafeTxGas), bigOrZero(tx.BaseGas), bigOrZero(tx.GasPrice),
+		tx.GasToken, tx.RefundReceiver, signatures)
+}
+
+// SafeCallSpec is one call bundled into a Safe transaction.
+type SafeCallSpec struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// BuildSafeTx turns calls into the single SafeTx the Safe executes at
+// nonce: the call itself when there is one, otherwise a delegatecall to
+// MultiSendCallOnly with the calls packed back to back.
+func BuildSafeTx(calls []SafeCallSpec, nonce uint64) (SafeTx, error) {
+	switch len(calls) {
+	case 0:
+		return SafeTx{}, fmt.Errorf("no calls to execute")
+	case 1:
+		return SafeTx{To: calls[0].To, Value: bigOrZero(calls[0].Value), Data: calls[0].Data, Operation: SafeCall, Nonce: nonce}, nil
+	}
+
+	var packed []byte
+	for _, c := range calls {
+		packed = append(packed, SafeCall)
+		packed = append(packed, c.To.Bytes()...)
+		packed = append(packed, common.LeftPadBytes(bigOrZero(c.Value).Bytes(), 32)...)
+		packed = append(packed, common.LeftPadBytes(big.NewInt(int64(len(c.Data))).Bytes(), 32)...)
+		packed = append(packed, c.Data...)
+	}
+	data, err := parsedSafeABI.Pack("multiSend", packed)
+	if err != nil {
+		return SafeTx{}, err
+	}
+	return SafeTx{To: MultiSendCallOnlyAddress, Value: new(big.Int), Data: data, Operation: SafeDelegateCall, Nonce: nonce}, nil
+}
@@ -0,0 +1,180 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// manualSafeTxHash computes safeTxHash the way Safe's getTransactionHash
+// does on-chain, as an independent check of the apitypes-based encoding.
+func manualSafeTxHash(chainID *big.Int, safe common.Address, legacy bool, tx SafeTx) common.Hash {
+	word := func(x *big.Int) []byte { return common.LeftPadBytes(bigOrZero(x).Bytes(), 32) }
+	addr := func(a common.Address) []byte { return common.LeftPadBytes(a.Bytes(), 32) }
+
+	var domain []byte
+	if legacy {
+		domain = crypto.Keccak256(crypto.Keccak256([]byte("EIP712Domain(address verifyingContract)")), addr(safe))
+	} else {
+		domain = crypto.Keccak256(crypto.Keccak256([]byte("EIP712Domain(uint256 chainId,address verifyingContract)")), word(chainID), addr(safe))
+	}
+	typeHash := crypto.Keccak256([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+	structHash := crypto.Keccak256(typeHash,
+		addr(tx.To), word(tx.Value), crypto.Keccak256(tx.Data), word(big.NewInt(int64(tx.Operation))),
+		word(tx.SafeTxGas), word(tx.BaseGas), word(tx.GasPrice), addr(tx.GasToken), addr(tx.RefundReceiver),
+		word(new(big.Int).SetUint64(tx.Nonce)))
+	return common.BytesToHash(crypto.Keccak256([]byte{0x19, 0x01}, domain, structHash))
+}
+
+func TestSafeTxHash(t *testing.T) {
+	safe := common.HexToAddress("0x00000000000000000000000000000000000005af")
+	tx := SafeTx{
+		To:    common.HexToAddress("0x00000000000000000000000000000000000000b2"),
+		Value: big.NewInt(1_000_000),
+		Data:  []byte{0xa9, 0x05, 0x9c, 0xbb, 0x01},
+		Nonce: 7,
+	}
+	chainID := big.NewInt(1)
+
+	for _, tc := range []struct {
+		version string
+		legacy  bool
+	}{{"1.3.0", false}, {"1.4.1", false}, {"1.1.1", true}, {"1.2.0", true}} {
+		got, err := SafeTxHash(chainID, safe, tc.version, tx)
+		if err != nil {
+			t.Fatalf("v%s: %v", tc.version, err)
+		}
+		if want := manualSafeTxHash(chainID, safe, tc.legacy, tx); got != want {
+			t.Errorf("v%s: safeTxHash = %s, want %s", tc.version, got.Hex(), want.Hex())
+		}
+	}
+}
+
+func TestPackSafeSignatures(t *testing.T) {
+	hash := common.HexToHash("0x1234")
+	sigs := map[common.Address][65]byte{}
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := crypto.Sign(hash.Bytes(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sig [65]byte
+		copy(sig[:], raw) // v is 0/1 here, as some signers return it
+		owner := crypto.PubkeyToAddress(key.PublicKey)
+
+		recovered, err := RecoverSafeSigner(hash, sig)
+		if err != nil || recovered != owner {
+			t.Fatalf("RecoverSafeSigner = %s, %v; want %s", recovered.Hex(), err, owner.Hex())
+		}
+		sigs[owner] = sig
+	}
+
+	packed := PackSafeSignatures(sigs)
+	if len(packed) != 65*3 {
+		t.Fatalf("packed length = %d, want %d", len(packed), 65*3)
+	}
+	var prev common.Address
+	for i := 0; i < 3; i++ {
+		var sig [65]byte
+		copy(sig[:], packed[i*65:(i+1)*65])
+		if sig[64] != 27 && sig[64] != 28 {
+			t.Errorf("signature %d: v = %d, want 27 or 28", i, sig[64])
+		}
+		signer, err := RecoverSafeSigner(hash, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && bytes.Compare(prev.Bytes(), signer.Bytes()) >= 0 {
+			t.Errorf("signatures not in ascending owner order: %s before %s", prev.Hex(), signer.Hex())
+		}
+		prev = signer
+	}
+}
+
+func TestBuildSafeTx(t *testing.T) {
+	a := SafeCallSpec{To: common.HexToAddress("0x00000000000000000000000000000000000000a1"), Data: []byte{1, 2, 3}}
+	b := SafeCallSpec{To: common.HexToAddress("0x00000000000000000000000000000000000000b2"), Value: big.NewInt(5)}
+
+	single, err := BuildSafeTx([]SafeCallSpec{a}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single.To != a.To || single.Operation != SafeCall || single.Nonce != 3 || !bytes.Equal(single.Data, a.Data) {
+		t.Errorf("single call = %+v", single)
+	}
+
+	multi, err := BuildSafeTx([]SafeCallSpec{a, b}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if multi.To != MultiSendCallOnlyAddress || multi.Operation != SafeDelegateCall {
+		t.Errorf("multi call to %s op %d, want MultiSendCallOnly delegatecall", multi.To.Hex(), multi.Operation)
+	}
+	if !bytes.Equal(multi.Data[:4], []byte{0x8d, 0x80, 0xff, 0x0a}) {
+		t.Errorf("selector = %x, want multiSend(bytes)", multi.Data[:4])
+	}
+	args, err := parsedSafeABI.Methods["multiSend"].Inputs.Unpack(multi.Data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed := args[0].([]byte)
+	if want := (1 + 20 + 32 + 32 + 3) + (1 + 20 + 32 + 32); len(packed) != want {
+		t.Fatalf("packed transactions length = %d, want %d", len(packed), want)
+	}
+	if !bytes.Equal(packed[1:21], a.To.Bytes()) || !bytes.Equal(packed[85:88], a.Data) {
+		t.Errorf("first packed call malformed: %x", packed[:88])
+	}
+}
+
+func TestTypedDataSignRequestDataType(t *testing.T) {
+	safe := common.HexToAddress("0x00000000000000000000000000000000000005af")
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	typed := SafeTypedData(big.NewInt(1), safe, "1.3.0", SafeTx{To: owner})
+
+	ur, _, err := BuildTypedDataSignRequestEIP4527(owner, big.NewInt(1), typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ur, "ur:eth-sign-request/") {
+		t.Fatalf("unexpected UR prefix: %.30s", ur)
+	}
+
+	var reqID [16]byte
+	cbor := buildEthSignRequestCBOR(reqID, []byte("{}"), signDataTypedData, 1, owner)
+	// map(5), key 1 tag(37) bytes(16) id, key 2 bytes(2) "{}", key 3 data-type
+	if i := 1 + 1 + 2 + 1 + 16 + 1 + 1 + 2; cbor[i] != 0x03 || cbor[i+1] != 0x02 {
+		t.Errorf("data-type field = %x %x, want key 3 = 2 (typed data)", cbor[i], cbor[i+1])
+	}
+}
+
+type codedErr struct{ code int }
+
+func (e codedErr) Error() string  { return "execution reverted" }
+func (e codedErr) ErrorCode() int { return e.code }
+
+func TestIsCallRejection(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{codedErr{3}, true},
+		{fmt.Errorf("eth_call: %w", codedErr{-32000}), true},
+		{errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"), false},
+		{context.DeadlineExceeded, false},
+	} {
+		if got := isCallRejection(tc.err); got != tc.want {
+			t.Errorf("isCallRejection(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package store
+
+import "time"
+
+// SavedABI is one entry of the Contract page's ABI library.
+type SavedABI struct {
+	Name    string
+	Address string // contract the ABI was last opened against, "" if none
+	ABI     string // raw ABI JSON
+	SavedAt time.Time
+}
+
+// SaveABI stores abiJSON under name, replacing any earlier entry.
+func (s *Store) SaveABI(name, address, abiJSON string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO abi_library (name, address, abi, saved_at) VALUES (?,?,?,?)
+		ON CONFLICT(name) DO UPDATE SET
+			address  = excluded.address,
+			abi      = excluded.abi,
+			saved_at = excluded.saved_at`,
+		name, address, abiJSON, time.Now().UTC(),
+	)
+	return err
+}
+
+// DeleteABI removes name from the library.
+func (s *Store) DeleteABI(name string) error {
+	_, err := s.db.Exec(`DELETE FROM abi_library WHERE name = ?`, name)
+	return err
+}
+
+// ABIs returns the whole library, most recently saved first.
+func (s *Store) ABIs() ([]SavedABI, error) {
+	rows, err := s.db.Query(`SELECT name, address, abi, saved_at FROM abi_library ORDER BY saved_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []SavedABI
+	for rows.Next() {
+		var a SavedABI
+		if err := rows.Scan(&a.Name, &a.Address, &a.ABI, &a.SavedAt); err != nil {
+			continue
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
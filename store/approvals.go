@@ -0,0 +1,152 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+
+	"charm-wallet-tui/indexer"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// approvalScanChunk is how many blocks IndexApprovals asks for per step; the
+// cursor is persisted after each one so an interrupted first scan resumes.
+const approvalScanChunk = uint64(1_000_000)
+
+// Approval is one row of the approvals table: the most recent allowance an
+// owner granted spender on token, on either the ERC-20 ("erc20") or Permit2
+// ("permit2") ledger, joined with the token's cached metadata.
+type Approval struct {
+	ChainID    uint64
+	Owner      common.Address
+	Token      common.Address
+	Spender    common.Address
+	Kind       string
+	Amount     *big.Int // as of the last event; the live value may differ
+	Expiration uint64   // Permit2 only
+	Block      uint64
+	TxHash     string
+	Symbol     string
+	Decimals   uint8
+}
+
+// SaveApproval upserts ev as the latest allowance change for its (owner,
+// token, spender, ledger), ignoring it if a later event is already stored.
+func (s *Store) SaveApproval(chainID uint64, ev indexer.ApprovalEvent) error {
+	_, err := s.db.Exec(`
+		INSERT INTO approvals
+			(chain_id, owner, token, spender, kind, amount, expiration, block, tx_hash, log_index)
+		VALUES (?,?,?,?,?,?,?,?,?,?)
+		ON CONFLICT(chain_id, owner, token, spender, kind) DO UPDATE SET
+			amount     = excluded.amount,
+			expiration = excluded.expiration,
+			block      = excluded.block,
+			tx_hash    = excluded.tx_hash,
+			log_index  = excluded.log_index
+		WHERE excluded.block > approvals.block
+		   OR (excluded.block = approvals.block AND excluded.log_index > approvals.log_index)`,
+		chainID, ev.Owner.Hex(), ev.Token.Hex(), ev.Spender.Hex(), ev.Kind.String(),
+		bigText(ev.Amount), ev.Expiration, ev.Block, ev.TxHash.Hex(), ev.LogIndex,
+	)
+	return err
+}
+
+// approvalScanCursor returns the highest block already scanned for owner, or
+// ok=false if it has never been scanned.
+func (s *Store) approvalScanCursor(chainID uint64, owner common.Address) (last uint64, ok bool, err error) {
+	err = s.db.QueryRow(`SELECT last_block FROM approval_scans WHERE chain_id = ? AND owner = ?`,
+		chainID, owner.Hex()).Scan(&last)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return last, err == nil, err
+}
+
+func (s *Store) setApprovalScanCursor(chainID uint64, owner common.Address, last uint64) error {
+	_, err := s.db.Exec(`
+		INSERT INTO approval_scans (chain_id, owner, last_block) VALUES (?,?,?)
+		ON CONFLICT(chain_id, owner) DO UPDATE SET last_block = excluded.last_block`,
+		chainID, owner.Hex(), last,
+	)
+	return err
+}
+
+// IndexApprovals scans owner's allowance history from just past its stored
+// cursor (genesis on first use) up to toBlock, saving every ERC-20 and
+// Permit2 approval change and caching each token's metadata. Returns the
+// number of events saved.
+func (s *Store) IndexApprovals(ctx context.Context, client *ethclient.Client, chainID uint64, owner common.Address, toBlock uint64) (int, error) {
+	from := uint64(0)
+	if last, ok, err := s.approvalScanCursor(chainID, owner); err != nil {
+		return 0, err
+	} else if ok {
+		from = last + 1
+	}
+
+	saved := 0
+	for chunkStart := from; chunkStart <= toBlock; {
+		chunkEnd := chunkStart + approvalScanChunk - 1
+		if chunkEnd > toBlock {
+			chunkEnd = toBlock
+		}
+		events, err := indexer.FetchApprovalEvents(ctx, client, []common.Address{owner}, chunkStart, chunkEnd)
+		if err != nil {
+			return saved, err
+		}
+		for _, ev := range events {
+			if err := s.SaveApproval(chainID, ev); err != nil {
+				return saved, err
+			}
+			_ = s.EnsureERC20TokenWithClient(ctx, client, ev.Token)
+			saved++
+		}
+		if err := s.setApprovalScanCursor(chainID, owner, chunkEnd); err != nil {
+			return saved, err
+		}
+		chunkStart = chunkEnd + 1
+	}
+	return saved, nil
+}
+
+// Approvals returns every spender owner has ever approved on chainID, grouped
+// by token symbol then ledger.
+func (s *Store) Approvals(chainID uint64, owner common.Address) ([]Approval, error) {
+	rows, err := s.db.Query(`
+		SELECT a.token, a.spender, a.kind, a.amount, a.expiration, a.block, a.tx_hash,
+		       COALESCE(t.symbol, ''), COALESCE(t.decimals, 0)
+		FROM approvals a
+		LEFT JOIN erc20_tokens t ON t.address = a.token
+		WHERE a.chain_id = ? AND a.owner = ?
+		ORDER BY COALESCE(t.symbol, a.token), a.kind, a.spender`,
+		chainID, owner.Hex())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Approval
+	for rows.Next() {
+		var (
+			a                      Approval
+			token, spender, amount string
+			decimals               int
+		)
+		if err := rows.Scan(&token, &spender, &a.Kind, &amount, &a.Expiration,
+			&a.Block, &a.TxHash, &a.Symbol, &decimals); err != nil {
+			continue
+		}
+		a.ChainID = chainID
+		a.Owner = owner
+		a.Token = common.HexToAddress(token)
+		a.Spender = common.HexToAddress(spender)
+		a.Amount, _ = new(big.Int).SetString(amount, 10)
+		if a.Amount == nil {
+			a.Amount = new(big.Int)
+		}
+		a.Decimals = uint8(decimals)
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
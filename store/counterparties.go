@@ -0,0 +1,80 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Counterparty is an address seen on the other side of an indexed ERC-20
+// transfer with one of the watched wallets.
+type Counterparty struct {
+	Address        common.Address
+	FirstBlock     uint64
+	FirstZeroValue bool // its earliest transfer with a wallet moved nothing
+	ValueTransfers int  // transfers that moved a non-zero amount
+}
+
+// Counterparties returns every address that has sent to or received from
+// one of wallets in indexed_events, with how it was first seen. Zero-value
+// transfers are the usual address-poisoning vector: anyone can make a token
+// emit Transfer(victim, lookalike, 0), planting the lookalike in the
+// victim's history.
+func (s *Store) Counterparties(wallets []common.Address) ([]Counterparty, error) {
+	if len(wallets) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(wallets)), ",")
+	args := make([]any, 0, 2*len(wallets))
+	for _, w := range wallets {
+		args = append(args, w.Hex())
+	}
+	args = append(args, args...)
+
+	rows, err := s.db.Query(`
+		SELECT from_addr, to_addr, value_hex, block FROM indexed_events
+		WHERE from_addr IN (`+placeholders+`) OR to_addr IN (`+placeholders+`)
+		ORDER BY block, log_index`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	own := make(map[common.Address]bool, len(wallets))
+	for _, w := range wallets {
+		own[w] = true
+	}
+	byAddr := make(map[common.Address]*Counterparty)
+	var order []common.Address
+	for rows.Next() {
+		var from, to, valueHex string
+		var block uint64
+		if err := rows.Scan(&from, &to, &valueHex, &block); err != nil {
+			continue
+		}
+		zero := strings.TrimLeft(strings.TrimPrefix(valueHex, "0x"), "0") == ""
+		for _, addr := range []common.Address{common.HexToAddress(from), common.HexToAddress(to)} {
+			if own[addr] {
+				continue
+			}
+			cp, ok := byAddr[addr]
+			if !ok {
+				cp = &Counterparty{Address: addr, FirstBlock: block, FirstZeroValue: zero}
+				byAddr[addr] = cp
+				order = append(order, addr)
+			}
+			if !zero {
+				cp.ValueTransfers++
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]Counterparty, len(order))
+	for i, a := range order {
+		result[i] = *byAddr[a]
+	}
+	return result, nil
+}
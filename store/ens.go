@@ -0,0 +1,74 @@
+package store
+
+import (
+	"strings"
+	"time"
+)
+
+// ENS cache kinds.
+const (
+	ENSForward = "forward" // .eth name -> checksummed address
+	ENSReverse = "reverse" // checksummed address -> primary name
+)
+
+// CachedENS returns the cached result for key if it was resolved less than
+// maxAge ago. A hit with an empty value means the name (or address) was
+// looked up and found nothing. Forward keys are case-insensitive.
+func (s *Store) CachedENS(kind, key string, maxAge time.Duration) (value string, ok bool) {
+	var resolvedAt time.Time
+	err := s.db.QueryRow(`SELECT value, resolved_at FROM ens_cache WHERE kind = ? AND key = ?`,
+		kind, ensKey(kind, key)).Scan(&value, &resolvedAt)
+	if err != nil {
+		return "", false
+	}
+	if time.Since(resolvedAt) > maxAge {
+		return "", false
+	}
+	return value, true
+}
+
+// SaveENS records a lookup result, replacing any earlier one for key.
+func (s *Store) SaveENS(kind, key, value string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO ens_cache (kind, key, value, resolved_at) VALUES (?,?,?,?)
+		ON CONFLICT(kind, key) DO UPDATE SET
+			value       = excluded.value,
+			resolved_at = excluded.resolved_at`,
+		kind, ensKey(kind, key), value, time.Now().UTC(),
+	)
+	return err
+}
+
+// ENSReverseNames returns every cached address -> primary name pair whose
+// name also forward-resolves, in the cache, back to that address, for
+// labelling addresses that are in neither the address book nor the wallet
+// list. A reverse record alone is only the address owner's claim: anyone
+// can set their primary name to someone else's.
+func (s *Store) ENSReverseNames() (map[string]string, error) {
+	rows, err := s.db.Query(`
+		SELECT r.key, r.value FROM ens_cache r
+		JOIN ens_cache f ON f.kind = ? AND f.key = lower(r.value) AND lower(f.value) = lower(r.key)
+		WHERE r.kind = ? AND r.value != ''`, ENSForward, ENSReverse)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var addr, name string
+		if err := rows.Scan(&addr, &name); err != nil {
+			continue
+		}
+		names[addr] = name
+	}
+	return names, rows.Err()
+}
+
+func ensKey(kind, key string) string {
+	key = strings.TrimSpace(key)
+	if kind == ENSForward {
+		return strings.ToLower(key)
+	}
+	return key
+}
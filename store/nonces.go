@@ -0,0 +1,142 @@
+package store
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Nonce reservation statuses. A reservation starts out queued when its
+// unsigned tx is packaged, becomes broadcast once a signed tx with the same
+// (address, nonce) is relayed, and mined once the chain's pending nonce has
+// moved past it. A queued reservation the chain moved past without us ever
+// broadcasting it is stale — some other tx consumed that nonce, so its
+// packaged tx can no longer be mined.
+const (
+	NonceQueued    = "queued"
+	NonceBroadcast = "broadcast"
+	NonceMined     = "mined"
+	NonceStale     = "stale"
+	NonceDiscarded = "discarded"
+)
+
+// NonceReservation is one row of nonce_reservations: a packaged unsigned tx
+// holding a nonce for a wallet on a chain.
+type NonceReservation struct {
+	ID        int64
+	ChainID   uint64
+	Address   common.Address
+	Nonce     uint64
+	RequestID string // EIP-4527 request-id (hex), matches the packaged tx JSON
+	Summary   string
+	UR        string
+	TxJSON    string
+	Status    string
+	CreatedAt string
+}
+
+// ReserveNonce records a freshly packaged tx as queued at r.Nonce and
+// returns the new row's ID.
+func (s *Store) ReserveNonce(r NonceReservation) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO nonce_reservations
+			(chain_id, address, nonce, request_id, summary, ur, tx_json, status)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		r.ChainID, r.Address.Hex(), r.Nonce, r.RequestID,
+		r.Summary, r.UR, r.TxJSON, NonceQueued,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// SyncNonces reconciles a wallet's reservations against the chain's pending
+// nonce: anything broadcast below pending is mined, anything still queued
+// below pending is stale.
+func (s *Store) SyncNonces(chainID uint64, addr common.Address, pending uint64) error {
+	if _, err := s.db.Exec(`
+		UPDATE nonce_reservations SET status = ?
+		WHERE chain_id = ? AND address = ? AND nonce < ? AND status = ?`,
+		NonceMined, chainID, addr.Hex(), pending, NonceBroadcast,
+	); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`
+		UPDATE nonce_reservations SET status = ?
+		WHERE chain_id = ? AND address = ? AND nonce < ? AND status = ?`,
+		NonceStale, chainID, addr.Hex(), pending, NonceQueued,
+	)
+	return err
+}
+
+// ActiveNonces returns the nonces currently held by queued or broadcast
+// reservations for a wallet, ascending.
+func (s *Store) ActiveNonces(chainID uint64, addr common.Address) ([]uint64, error) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT nonce FROM nonce_reservations
+		WHERE chain_id = ? AND address = ? AND status IN (?, ?)
+		ORDER BY nonce`,
+		chainID, addr.Hex(), NonceQueued, NonceBroadcast)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nonces []uint64
+	for rows.Next() {
+		var n uint64
+		if err := rows.Scan(&n); err != nil {
+			continue
+		}
+		nonces = append(nonces, n)
+	}
+	return nonces, rows.Err()
+}
+
+// Outbox returns a wallet's queued, broadcast and stale reservations ordered
+// by nonce — everything the user may still want to re-show, re-sign or
+// discard. Mined and discarded rows are omitted.
+func (s *Store) Outbox(chainID uint64, addr common.Address) ([]NonceReservation, error) {
+	rows, err := s.db.Query(`
+		SELECT id, chain_id, address, nonce, request_id, summary, ur, tx_json, status, created_at
+		FROM nonce_reservations
+		WHERE chain_id = ? AND address = ? AND status IN (?, ?, ?)
+		ORDER BY nonce, id`,
+		chainID, addr.Hex(), NonceQueued, NonceBroadcast, NonceStale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []NonceReservation
+	for rows.Next() {
+		var (
+			r    NonceReservation
+			addr string
+		)
+		if err := rows.Scan(&r.ID, &r.ChainID, &addr, &r.Nonce, &r.RequestID,
+			&r.Summary, &r.UR, &r.TxJSON, &r.Status, &r.CreatedAt); err != nil {
+			continue
+		}
+		r.Address = common.HexToAddress(addr)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// SetNonceStatus overwrites one reservation's status by row ID.
+func (s *Store) SetNonceStatus(id int64, status string) error {
+	_, err := s.db.Exec(`UPDATE nonce_reservations SET status = ? WHERE id = ?`, status, id)
+	return err
+}
+
+// MarkNonceBroadcast flags the queued reservation(s) at (chainID, addr, nonce)
+// as broadcast. Called after a signed tx is relayed — the signed payload
+// carries no request-id, so the nonce is the only link back to its package.
+func (s *Store) MarkNonceBroadcast(chainID uint64, addr common.Address, nonce uint64) error {
+	_, err := s.db.Exec(`
+		UPDATE nonce_reservations SET status = ?
+		WHERE chain_id = ? AND address = ? AND nonce = ? AND status = ?`,
+		NonceBroadcast, chainID, addr.Hex(), nonce, NonceQueued,
+	)
+	return err
+}
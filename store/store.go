@@ -146,6 +146,119 @@ CREATE INDEX IF NOT EXISTS idx_v4xfer_token_id ON v4_transfers(token_id);
 CREATE INDEX IF NOT EXISTS idx_v4xfer_block    ON v4_transfers(block);
 `
 
+// v2Migration adds the local nonce ledger used to package several unsigned
+// transactions for one wallet before any of them are broadcast:
+//
+//   nonce_reservations — one row per packaged tx, keyed by (chain_id, address, nonce)
+//
+// Rows are never deleted; status moves queued → broadcast → mined, or to
+// stale/discarded (see the Nonce* status constants in nonces.go).
+const v2Migration = `
+CREATE TABLE IF NOT EXISTS nonce_reservations (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	chain_id   INTEGER NOT NULL,
+	address    TEXT    NOT NULL,
+	nonce      INTEGER NOT NULL,
+	request_id TEXT    NOT NULL DEFAULT '',
+	summary    TEXT    NOT NULL DEFAULT '',
+	ur         TEXT    NOT NULL DEFAULT '',
+	tx_json    TEXT    NOT NULL DEFAULT '',
+	status     TEXT    NOT NULL DEFAULT 'queued',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_nonce_wallet ON nonce_reservations(chain_id, address, status);
+CREATE INDEX IF NOT EXISTS idx_nonce_req    ON nonce_reservations(request_id);
+`
+
+// v3Migration adds a permanent record of every transaction the app packaged
+// or relayed:
+//
+//   transactions — one row per packaged (or externally signed and pasted) tx,
+//                  updated in place as it is signed, broadcast and mined
+//
+// Unlike nonce_reservations, rows here are history: nothing ever moves them
+// out of view, so a dismissed QR or broadcast result can always be revisited.
+const v3Migration = `
+CREATE TABLE IF NOT EXISTS transactions (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	chain_id      INTEGER NOT NULL,
+	from_addr     TEXT    NOT NULL,
+	nonce         INTEGER NOT NULL,
+	request_id    TEXT    NOT NULL DEFAULT '',
+	summary       TEXT    NOT NULL DEFAULT '',
+	ur            TEXT    NOT NULL DEFAULT '',
+	tx_json       TEXT    NOT NULL DEFAULT '',
+	signature     TEXT    NOT NULL DEFAULT '',
+	signed_raw    TEXT    NOT NULL DEFAULT '',
+	tx_hash       TEXT    NOT NULL DEFAULT '',
+	status        TEXT    NOT NULL DEFAULT 'packaged',
+	block         INTEGER NOT NULL DEFAULT 0,
+	gas_used      INTEGER NOT NULL DEFAULT 0,
+	revert_reason TEXT    NOT NULL DEFAULT '',
+	onchain_json  TEXT    NOT NULL DEFAULT '',
+	created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_tx_wallet ON transactions(chain_id, from_addr, nonce);
+CREATE INDEX IF NOT EXISTS idx_tx_req    ON transactions(request_id);
+CREATE INDEX IF NOT EXISTS idx_tx_hash   ON transactions(tx_hash);
+`
+
+// v4Migration adds the allowance index behind the Approvals page:
+//
+//   approvals       — latest allowance change per (chain, owner, token,
+//                     spender, ledger), from ERC-20 and Permit2 events
+//   approval_scans  — per-owner cursor: highest block already scanned
+const v4Migration = `
+CREATE TABLE IF NOT EXISTS approvals (
+	chain_id   INTEGER NOT NULL,
+	owner      TEXT    NOT NULL,
+	token      TEXT    NOT NULL,
+	spender    TEXT    NOT NULL,
+	kind       TEXT    NOT NULL,
+	amount     TEXT    NOT NULL,
+	expiration INTEGER NOT NULL DEFAULT 0,
+	block      INTEGER NOT NULL,
+	tx_hash    TEXT    NOT NULL,
+	log_index  INTEGER NOT NULL,
+	seen_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE(chain_id, owner, token, spender, kind)
+);
+CREATE INDEX IF NOT EXISTS idx_approvals_owner ON approvals(chain_id, owner);
+
+CREATE TABLE IF NOT EXISTS approval_scans (
+	chain_id   INTEGER NOT NULL,
+	owner      TEXT    NOT NULL,
+	last_block INTEGER NOT NULL,
+	PRIMARY KEY(chain_id, owner)
+);
+`
+
+// v5Migration adds the ENS cache shared by the wallet form, the address book
+// and the send form. kind is "forward" (name -> address) or "reverse"
+// (address -> name); an empty value records a lookup that found nothing.
+const v5Migration = `
+CREATE TABLE IF NOT EXISTS ens_cache (
+	kind        TEXT     NOT NULL,
+	key         TEXT     NOT NULL,
+	value       TEXT     NOT NULL,
+	resolved_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY(kind, key)
+);
+`
+
+// v6Migration adds the ABI library used by the Contract page: one saved ABI
+// per name, with the contract address it was last used against ("" when the
+// ABI is a reusable interface such as ERC-20).
+const v6Migration = `
+CREATE TABLE IF NOT EXISTS abi_library (
+	name     TEXT     NOT NULL PRIMARY KEY,
+	address  TEXT     NOT NULL DEFAULT '',
+	abi      TEXT     NOT NULL,
+	saved_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
 // Store wraps a SQLite database for persisting indexed events.
 type Store struct {
 	db *sql.DB
@@ -166,6 +279,26 @@ func Open(path string) (*Store, error) {
 		db.Close()
 		return nil, err
 	}
+	if err := migrateToV2(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrateToV3(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrateToV4(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrateToV5(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrateToV6(db); err != nil {
+		db.Close()
+		return nil, err
+	}
 	return &Store{db: db}, nil
 }
 
@@ -184,6 +317,81 @@ func migrateToV1(db *sql.DB) error {
 	return err
 }
 
+func migrateToV2(db *sql.DB) error {
+	var ver int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&ver); err != nil {
+		return err
+	}
+	if ver >= 2 {
+		return nil
+	}
+	if _, err := db.Exec(v2Migration); err != nil {
+		return err
+	}
+	_, err := db.Exec("PRAGMA user_version = 2")
+	return err
+}
+
+func migrateToV3(db *sql.DB) error {
+	var ver int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&ver); err != nil {
+		return err
+	}
+	if ver >= 3 {
+		return nil
+	}
+	if _, err := db.Exec(v3Migration); err != nil {
+		return err
+	}
+	_, err := db.Exec("PRAGMA user_version = 3")
+	return err
+}
+
+func migrateToV4(db *sql.DB) error {
+	var ver int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&ver); err != nil {
+		return err
+	}
+	if ver >= 4 {
+		return nil
+	}
+	if _, err := db.Exec(v4Migration); err != nil {
+		return err
+	}
+	_, err := db.Exec("PRAGMA user_version = 4")
+	return err
+}
+
+func migrateToV5(db *sql.DB) error {
+	var ver int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&ver); err != nil {
+		return err
+	}
+	if ver >= 5 {
+		return nil
+	}
+	if _, err := db.Exec(v5Migration); err != nil {
+		return err
+	}
+	_, err := db.Exec("PRAGMA user_version = 5")
+	return err
+}
+
+func migrateToV6(db *sql.DB) error {
+	var ver int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&ver); err != nil {
+		return err
+	}
+	if ver >= 6 {
+		return nil
+	}
+	if _, err := db.Exec(v6Migration); err != nil {
+		return err
+	}
+	_, err := db.Exec("PRAGMA user_version = 6")
+	return err
+}
+
 // Close closes the underlying database connection.
 func (s *Store) Close() error {
 	return s.db.Close()
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Transaction history statuses. A row starts out packaged when the app builds
+// an unsigned tx, becomes signed once the signer's QR reply is scanned,
+// broadcast once relayed, and finally confirmed or failed from its receipt.
+const (
+	TxPackaged  = "packaged"
+	TxSigned    = "signed"
+	TxBroadcast = "broadcast"
+	TxConfirmed = "confirmed"
+	TxFailed    = "failed"
+)
+
+// TxRecord is one row of the transactions table.
+type TxRecord struct {
+	ID           int64
+	ChainID      uint64
+	From         common.Address
+	Nonce        uint64
+	RequestID    string // EIP-4527 request-id (hex), empty for pasted txs
+	Summary      string
+	UR           string // unsigned eth-sign-request UR
+	TxJSON       string
+	Signature    string // 0x-prefixed 65-byte signature, once scanned
+	SignedRaw    string // 0x-prefixed signed RLP, once known
+	TxHash       string
+	Status       string
+	Block        uint64
+	GasUsed      uint64
+	RevertReason string
+	OnChainJSON  string // receipt summary as returned by the poller
+	CreatedAt    string
+	UpdatedAt    string
+}
+
+// SavePackagedTx records a freshly packaged unsigned tx and returns the new
+// row's ID.
+func (s *Store) SavePackagedTx(r TxRecord) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO transactions
+			(chain_id, from_addr, nonce, request_id, summary, ur, tx_json, status)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		r.ChainID, r.From.Hex(), r.Nonce, r.RequestID,
+		r.Summary, r.UR, r.TxJSON, TxPackaged,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// RecordTxSignature attaches a scanned signature and the assembled signed tx
+// to the packaged row with the given request-id. Rows that are already past
+// the signed stage are left alone.
+func (s *Store) RecordTxSignature(requestID, signature, signedRaw string) error {
+	if requestID == "" {
+		return nil
+	}
+	_, err := s.db.Exec(`
+		UPDATE transactions
+		SET signature = ?, signed_raw = ?, status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE request_id = ? AND status IN (?, ?)`,
+		signature, signedRaw, TxSigned, requestID, TxPackaged, TxSigned,
+	)
+	return err
+}
+
+// RecordTxBroadcast marks the tx at (chainID, from, nonce) as broadcast under
+// hash. A row already holding the same signed payload wins; otherwise the
+// newest packaged/signed row at that nonce is used. A signed tx that was never
+// packaged here (pasted from elsewhere) gets a fresh row of its own.
+func (s *Store) RecordTxBroadcast(chainID uint64, from common.Address, nonce uint64, signedRaw, hash string) error {
+	res, err := s.db.Exec(`
+		UPDATE transactions
+		SET tx_hash = ?, status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE signed_raw = ? AND signed_raw != ''`,
+		hash, TxBroadcast, signedRaw,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	res, err = s.db.Exec(`
+		UPDATE transactions
+		SET signed_raw = ?, tx_hash = ?, status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM transactions
+			WHERE chain_id = ? AND from_addr = ? AND nonce = ? AND status IN (?, ?)
+			ORDER BY id DESC LIMIT 1)`,
+		signedRaw, hash, TxBroadcast,
+		chainID, from.Hex(), nonce, TxPackaged, TxSigned,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO transactions
+			(chain_id, from_addr, nonce, summary, signed_raw, tx_hash, status)
+		VALUES (?,?,?,?,?,?,?)`,
+		chainID, from.Hex(), nonce, "Pasted signed transaction", signedRaw, hash, TxBroadcast,
+	)
+	return err
+}
+
+// RecordTxOnChain stores the mined outcome for the row(s) broadcast under hash.
+func (s *Store) RecordTxOnChain(hash string, success bool, block, gasUsed uint64, revertReason, infoJSON string) error {
+	status := TxConfirmed
+	if !success {
+		status = TxFailed
+	}
+	_, err := s.db.Exec(`
+		UPDATE transactions
+		SET status = ?, block = ?, gas_used = ?, revert_reason = ?, onchain_json = ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE tx_hash = ?`,
+		status, block, gasUsed, revertReason, infoJSON, hash,
+	)
+	return err
+}
+
+// RecentTransactions returns up to limit history rows, newest first.
+func (s *Store) RecentTransactions(limit int) ([]TxRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT id, chain_id, from_addr, nonce, request_id, summary, ur, tx_json,
+		       signature, signed_raw, tx_hash, status, block, gas_used,
+		       revert_reason, onchain_json, created_at, updated_at
+		FROM transactions
+		ORDER BY id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTxRecords(rows)
+}
+
+func scanTxRecords(rows *sql.Rows) ([]TxRecord, error) {
+	var result []TxRecord
+	for rows.Next() {
+		var (
+			r    TxRecord
+			from string
+		)
+		if err := rows.Scan(&r.ID, &r.ChainID, &from, &r.Nonce, &r.RequestID,
+			&r.Summary, &r.UR, &r.TxJSON, &r.Signature, &r.SignedRaw, &r.TxHash,
+			&r.Status, &r.Block, &r.GasUsed, &r.RevertReason, &r.OnChainJSON,
+			&r.CreatedAt, &r.UpdatedAt); err != nil {
+			continue
+		}
+		r.From = common.HexToAddress(from)
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
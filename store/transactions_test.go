@@ -0,0 +1,145 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func TestTxHistoryLifecycle(t *testing.T) {
+	from := common.HexToAddress("0x00000000000000000000000000000000000000f1")
+
+	for _, tc := range []struct {
+		name string
+		// steps run against a store holding one packaged tx (request "r1",
+		// nonce 5); the want fields describe the newest row afterwards.
+		steps      func(t *testing.T, st *Store)
+		wantRows   int
+		wantStatus string
+		wantRaw    string
+		wantHash   string
+	}{
+		{
+			name:       "packaged only",
+			steps:      func(*testing.T, *Store) {},
+			wantRows:   1,
+			wantStatus: TxPackaged,
+		},
+		{
+			name: "signed",
+			steps: func(t *testing.T, st *Store) {
+				mustDo(t, st.RecordTxSignature("r1", "0xsig", "0xraw"))
+			},
+			wantRows: 1, wantStatus: TxSigned, wantRaw: "0xraw",
+		},
+		{
+			name: "signed then broadcast",
+			steps: func(t *testing.T, st *Store) {
+				mustDo(t, st.RecordTxSignature("r1", "0xsig", "0xraw"))
+				mustDo(t, st.RecordTxBroadcast(1, from, 5, "0xraw", "0xh1"))
+			},
+			wantRows: 1, wantStatus: TxBroadcast, wantRaw: "0xraw", wantHash: "0xh1",
+		},
+		{
+			name: "broadcast without a scanned signature matches by nonce",
+			steps: func(t *testing.T, st *Store) {
+				mustDo(t, st.RecordTxBroadcast(1, from, 5, "0xraw", "0xh1"))
+			},
+			wantRows: 1, wantStatus: TxBroadcast, wantRaw: "0xraw", wantHash: "0xh1",
+		},
+		{
+			name: "re-broadcast of the same payload reuses the row",
+			steps: func(t *testing.T, st *Store) {
+				mustDo(t, st.RecordTxSignature("r1", "0xsig", "0xraw"))
+				mustDo(t, st.RecordTxBroadcast(1, from, 5, "0xraw", "0xh1"))
+				mustDo(t, st.RecordTxBroadcast(1, from, 5, "0xraw", "0xh1"))
+			},
+			wantRows: 1, wantStatus: TxBroadcast, wantRaw: "0xraw", wantHash: "0xh1",
+		},
+		{
+			name: "confirmed",
+			steps: func(t *testing.T, st *Store) {
+				mustDo(t, st.RecordTxBroadcast(1, from, 5, "0xraw", "0xh1"))
+				mustDo(t, st.RecordTxOnChain("0xh1", true, 100, 21000, "", "{}"))
+			},
+			wantRows: 1, wantStatus: TxConfirmed, wantRaw: "0xraw", wantHash: "0xh1",
+		},
+		{
+			name: "failed",
+			steps: func(t *testing.T, st *Store) {
+				mustDo(t, st.RecordTxBroadcast(1, from, 5, "0xraw", "0xh1"))
+				mustDo(t, st.RecordTxOnChain("0xh1", false, 100, 21000, "out of gas", "{}"))
+			},
+			wantRows: 1, wantStatus: TxFailed, wantRaw: "0xraw", wantHash: "0xh1",
+		},
+		{
+			name: "pasted tx at another nonce gets its own row",
+			steps: func(t *testing.T, st *Store) {
+				mustDo(t, st.RecordTxBroadcast(1, from, 6, "0xother", "0xh2"))
+			},
+			wantRows: 2, wantStatus: TxBroadcast, wantRaw: "0xother", wantHash: "0xh2",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			st := openTestStore(t)
+			if _, err := st.SavePackagedTx(TxRecord{ChainID: 1, From: from, Nonce: 5, RequestID: "r1", Summary: "Send 1 ETH", UR: "ur:eth-sign-request/x", TxJSON: "{}"}); err != nil {
+				t.Fatalf("SavePackagedTx: %v", err)
+			}
+			tc.steps(t, st)
+
+			rows, err := st.RecentTransactions(10)
+			if err != nil {
+				t.Fatalf("RecentTransactions: %v", err)
+			}
+			if len(rows) != tc.wantRows {
+				t.Fatalf("got %d rows, want %d", len(rows), tc.wantRows)
+			}
+			r := rows[0] // newest first
+			if r.Status != tc.wantStatus || r.SignedRaw != tc.wantRaw || r.TxHash != tc.wantHash {
+				t.Errorf("row = status %q raw %q hash %q; want %q %q %q",
+					r.Status, r.SignedRaw, r.TxHash, tc.wantStatus, tc.wantRaw, tc.wantHash)
+			}
+			if r.From != from {
+				t.Errorf("from = %s, want %s", r.From.Hex(), from.Hex())
+			}
+		})
+	}
+}
+
+func TestRecordTxSignatureLeavesBroadcastRows(t *testing.T) {
+	st := openTestStore(t)
+	from := common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	if _, err := st.SavePackagedTx(TxRecord{ChainID: 1, From: from, Nonce: 5, RequestID: "r1"}); err != nil {
+		t.Fatal(err)
+	}
+	mustDo(t, st.RecordTxSignature("r1", "0xsig", "0xraw"))
+	mustDo(t, st.RecordTxBroadcast(1, from, 5, "0xraw", "0xh1"))
+	// A late rescan of the signer's QR must not pull the row back to signed.
+	mustDo(t, st.RecordTxSignature("r1", "0xsig2", "0xraw2"))
+
+	rows, err := st.RecentTransactions(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := rows[0]; r.Status != TxBroadcast || r.SignedRaw != "0xraw" {
+		t.Errorf("row = status %q raw %q; want broadcast with the original payload", r.Status, r.SignedRaw)
+	}
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"database/sql"
+	"math/big"
+)
+
+// ---- Uniswap V4 pool analytics ----------------------------------------------
+
+// V4PoolMeta is a pool's key and its currencies' token metadata.
+type V4PoolMeta struct {
+	PoolID      string
+	Block       uint64
+	Currency0   string
+	Currency1   string
+	Token0Sym   string
+	Token1Sym   string
+	Decimals0   uint8
+	Decimals1   uint8
+	Fee         int64
+	TickSpacing int64
+	Hooks       string
+	InitTick    int64
+}
+
+// V4SwapRow is one indexed Swap event. Amounts are the swapper's deltas:
+// negative for the currency paid in, positive for the one received.
+type V4SwapRow struct {
+	Block     uint64
+	LogIndex  uint
+	Sender    string
+	Amount0   *big.Int
+	Amount1   *big.Int
+	SqrtPrice *big.Int
+	Liquidity *big.Int
+	Tick      int64
+	Fee       int64 // pips, i.e. hundredths of a bip
+}
+
+// V4LiquidityRow is one indexed ModifyLiquidity event.
+type V4LiquidityRow struct {
+	Block     uint64
+	TickLower int64
+	TickUpper int64
+	Delta     *big.Int // signed liquidity delta
+}
+
+// V4Pool returns poolID's key and token metadata; ok is false when the pool
+// has not been indexed. Currencies without erc20_tokens metadata fall back
+// to 18 decimals and an empty symbol, as in V4PoolStats.
+func (s *Store) V4Pool(poolID string) (meta V4PoolMeta, ok bool, err error) {
+	var dec0, dec1 int64
+	err = s.db.QueryRow(`
+		SELECT
+			p.pool_id, p.block, p.currency0, p.currency1,
+			COALESCE(t0.symbol, ''), COALESCE(t1.symbol, ''),
+			COALESCE(t0.decimals, 18), COALESCE(t1.decimals, 18),
+			p.fee, p.tick_spacing, p.hooks, p.init_tick
+		FROM v4_pools p
+		LEFT JOIN erc20_tokens t0 ON t0.address = p.currency0
+		LEFT JOIN erc20_tokens t1 ON t1.address = p.currency1
+		WHERE p.pool_id = ?`, poolID).Scan(
+		&meta.PoolID, &meta.Block, &meta.Currency0, &meta.Currency1,
+		&meta.Token0Sym, &meta.Token1Sym, &dec0, &dec1,
+		&meta.Fee, &meta.TickSpacing, &meta.Hooks, &meta.InitTick,
+	)
+	if err == sql.ErrNoRows {
+		return meta, false, nil
+	}
+	if err != nil {
+		return meta, false, err
+	}
+	meta.Decimals0, meta.Decimals1 = uint8(dec0), uint8(dec1)
+	return meta, true, nil
+}
+
+// V4PoolSwaps returns poolID's indexed swaps in chain order.
+func (s *Store) V4PoolSwaps(poolID string) ([]V4SwapRow, error) {
+	rows, err := s.db.Query(`
+		SELECT block, log_index, sender, amount0, amount1, sqrt_price, liquidity, tick, fee
+		FROM v4_swaps
+		WHERE pool_id = ?
+		ORDER BY block, log_index`, poolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []V4SwapRow
+	for rows.Next() {
+		var r V4SwapRow
+		var a0, a1, sp, liq string
+		if err := rows.Scan(&r.Block, &r.LogIndex, &r.Sender, &a0, &a1, &sp, &liq, &r.Tick, &r.Fee); err != nil {
+			continue
+		}
+		r.Amount0, r.Amount1 = parseBig(a0), parseBig(a1)
+		r.SqrtPrice, r.Liquidity = parseBig(sp), parseBig(liq)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// V4PoolLiquidityEvents returns poolID's indexed ModifyLiquidity events in
+// chain order.
+func (s *Store) V4PoolLiquidityEvents(poolID string) ([]V4LiquidityRow, error) {
+	rows, err := s.db.Query(`
+		SELECT block, tick_lower, tick_upper, liq_delta
+		FROM v4_modify_liquidity
+		WHERE pool_id = ?
+		ORDER BY block, log_index`, poolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []V4LiquidityRow
+	for rows.Next() {
+		var r V4LiquidityRow
+		var delta string
+		if err := rows.Scan(&r.Block, &r.TickLower, &r.TickUpper, &delta); err != nil {
+			continue
+		}
+		r.Delta = parseBig(delta)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// parseBig parses a decimal (or 0x-prefixed) integer column, as written by
+// bigText; malformed values read as 0.
+func parseBig(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return new(big.Int)
+	}
+	return v
+}
+
+// V4PositionEvent is one ModifyLiquidity event of a single position, with
+// the pool's price when it happened.
+type V4PositionEvent struct {
+	Block     uint64
+	Delta     *big.Int // signed liquidity delta; 0 for a fee collection
+	SqrtPrice *big.Int // last indexed swap's price before the event, else the pool's initial price; 0 when unknown
+}
+
+// V4PositionEvents returns the indexed ModifyLiquidity events of the
+// position owned by sender (e.g. the PositionManager) with salt in poolID,
+// in chain order.
+func (s *Store) V4PositionEvents(poolID, sender, salt string) ([]V4PositionEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT ml.block, ml.liq_delta,
+			COALESCE(
+				(SELECT sw.sqrt_price FROM v4_swaps sw
+				 WHERE sw.pool_id = ml.pool_id
+				   AND (sw.block < ml.block OR (sw.block = ml.block AND sw.log_index < ml.log_index))
+				 ORDER BY sw.block DESC, sw.log_index DESC
+				 LIMIT 1),
+				p.sqrt_price, '0')
+		FROM v4_modify_liquidity ml
+		LEFT JOIN v4_pools p ON p.pool_id = ml.pool_id
+		WHERE ml.pool_id = ? AND ml.sender = ? COLLATE NOCASE AND ml.salt = ? COLLATE NOCASE
+		ORDER BY ml.block, ml.log_index`, poolID, sender, salt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []V4PositionEvent
+	for rows.Next() {
+		var e V4PositionEvent
+		var delta, sp string
+		if err := rows.Scan(&e.Block, &delta, &sp); err != nil {
+			continue
+		}
+		e.Delta, e.SqrtPrice = parseBig(delta), parseBig(sp)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
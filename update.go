@@ -24,7 +24,23 @@ var (
 	tempRPCFormURL    string
 	tempSendToAddr    string
 	tempSendAmount    string
+	tempSendAsset     string // "" for ETH, else the ERC-20 contract address
 	tempTokenFormAddr string
+	tempContactAddr   string
+	tempContactLabel  string
+	tempContactNotes  string
+	tempContactTags   string
+	tempContractAddr  string
+	tempContractName  string
+	tempContractABI   string
+	tempContractValue string
+	tempContractArgs  []string // one per input of the function being called
+	tempDappName      string
+	tempDappAddr      string
+	tempDappIcon      string
+	tempDappChainID   string
+	tempDappABI       string
+	tempDappDesc      string
 )
 
 // -------------------- UPDATE --------------------
@@ -86,6 +102,11 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return updated, cmd
 	}
 
+	// Alert polls, like spinner ticks, must survive an open form or dialog.
+	if updated, cmd, handled := m.handleAlertMsg(msg); handled {
+		return updated, cmd
+	}
+
 	// Spinner ticks must be handled before any dialog-specific early return;
 	// those handlers don't forward anim.StepMsg to handleSpinnerTick, so the
 	// tick chain silently dies whenever a form or dialog is open.
@@ -103,8 +124,8 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.activePage == config.PageHome {
 		return m, m.navigateTo(config.PageWallets)
 	}
-	if m.activePage == config.PageTerraNullius && m.activeDialog == dialogTerraClaim {
-		return m.handleTerraClaimPopupMsg(msg)
+	if m.moduleCapturesInput() {
+		return m, m.activeModule.Update(m.host(), msg)
 	}
 	if m.activePage == config.PageSettings && (m.settingsMode == "add" || m.settingsMode == "edit") && m.form != nil {
 		return m.handleSettingsFormMsg(msg)
@@ -112,6 +133,15 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.activePage == config.PageWatchedTokens && (m.tokenFormMode == "add" || m.tokenFormMode == "edit") && m.tokenForm != nil && !m.tokenLookupActive {
 		return m.handleWatchedTokensFormMsg(msg)
 	}
+	if m.activePage == config.PageAddressBook && (m.contactFormMode == "add" || m.contactFormMode == "edit") && m.contactForm != nil && !m.contactResolving {
+		return m.handleContactFormMsg(msg)
+	}
+	if m.activePage == config.PageContract && (m.contractMode == "open" || m.contractMode == "call") && m.contractForm != nil {
+		return m.handleContractFormMsg(msg)
+	}
+	if m.activePage == config.PageDappBrowser && (m.dappFormMode == "add" || m.dappFormMode == "edit") && m.dappForm != nil {
+		return m.handleDappFormMsg(msg)
+	}
 
 	switch msg := msg.(type) {
 	case logInitMsg:
@@ -126,12 +156,12 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleDetailsLoaded(msg)
 	case packageTransactionMsg:
 		return m.handlePackageTransaction(msg)
+	case safeProposalMsg:
+		return m.handleSafeProposal(msg)
+	case contractCallResultMsg:
+		return m.handleContractCallResult(msg)
 	case txQRAnimTickMsg:
 		return m.handleQRAnimTick()
-	case terraNullClaimsCountMsg:
-		return m.handleTerraClaimsCount(msg)
-	case terraNullClaimQueryMsg:
-		return m.handleTerraClaimQuery(msg)
 	case poolEventLineMsg:
 		return m.handlePoolEventLine(msg)
 	case poolMonitorEventMsg:
@@ -139,11 +169,11 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case v4PoolTableMsg:
 		return m.handleV4PoolTable(msg)
 	case poolEventMonitorStoppedMsg:
-		return m.handlePoolMonitorStopped()
-	case v4BlockScanLineMsg:
-		return m.handleV4BlockScanLine(msg)
-	case v4BlockScanDoneMsg:
-		return m.handleV4BlockScanDone()
+		return m.handlePoolMonitorStopped(msg)
+	case explorerResultMsg:
+		return m.handleExplorerResult(msg)
+	case inspectorResultMsg:
+		return m.handleInspectorResult(msg)
 	case indexedEventMsg:
 		return m.handleIndexedEvent(msg)
 	case indexerStoppedMsg:
@@ -162,6 +192,14 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handlePoolInfoResult(msg)
 	case poolKeyResultMsg:
 		return m.handlePoolKeyResult(msg)
+	case poolHookReportMsg:
+		return m.handlePoolHookReport(msg)
+	case approvalsLoadedMsg:
+		return m.handleApprovalsLoaded(msg)
+	case txHistoryLoadedMsg:
+		return m.handleTxHistoryLoaded(msg)
+	case outboxLoadedMsg:
+		return m.handleOutboxLoaded(msg)
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 	case tea.MouseMsg:
@@ -180,16 +218,12 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.txCopiedMsg = "✓ Copied to clipboard"
 		m.txCopiedMsgTime = time.Now()
 		return m, clearClipboardMsg()
-	case uniswapQuoteMsg:
-		return m.handleUniswapQuote(msg)
-	case pairLookupResultMsg:
-		return m.handlePairLookupResult(msg)
-	case liquidityPositionsMsg:
-		return m.handleLiquidityPositions(msg)
 	case ensLookupResultMsg:
 		return m.handleENSLookupResult(msg)
 	case ensForwardResolveMsg:
 		return m.handleENSForwardResolve(msg)
+	case contactENSResolvedMsg:
+		return m.handleContactENSResolved(msg)
 	default:
 		if msg, ok := msg.(struct{ clearClipboard bool }); ok && msg.clearClipboard {
 			if time.Since(m.copiedMsgTime) >= 2*time.Second {
@@ -198,9 +232,10 @@ func (m *model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if time.Since(m.txCopiedMsgTime) >= 2*time.Second {
 				m.txCopiedMsg = ""
 			}
+			return m, nil
 		}
 	}
-	return m, nil
+	return m, m.broadcastToModules(msg)
 }
 
 // -------------------- MOUSE MOTION --------------------
@@ -238,6 +273,9 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.poolInfoID = ""
 			m.poolInfoKeyLoading = false
 			m.poolInfoKeyErr = ""
+			m.poolInfoHook = nil
+			m.poolInfoHookLoading = false
+			m.poolInfoHookErr = ""
 		}
 		return m, nil
 	}
@@ -253,6 +291,9 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, vpCmd
 		case "tab":
+			if m.safeSession != nil {
+				return m, tea.Batch(vpCmd, m.cycleSafeSigner())
+			}
 			if m.txApproveQRFrames != nil {
 				m.txSwapStep = !m.txSwapStep
 				m.txQRFrameIdx = 0
@@ -282,6 +323,8 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.txSwapQRFrames = nil
 			m.txSwapJSON = ""
 			m.txSwapStep = false
+			m.safeSession = nil
+			m.typedSign = nil
 			// Refresh watched-token balances — this is the shared dismiss path
 			// for swaps, ETH sends, and Terra claims, any of which can have
 			// just changed the active wallet's on-chain balances.
@@ -294,6 +337,18 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleScanTxKey(msg)
 	}
 
+	if m.activeDialog == dialogOutbox {
+		return m.handleOutboxKey(msg)
+	}
+
+	if m.activeDialog == dialogPoolFilter {
+		return m.handlePoolFilterKey(msg)
+	}
+
+	if m.activeDialog == dialogAlerts {
+		return m.handleAlertsKey(msg)
+	}
+
 	if m.activeDialog == dialogAccountList {
 		switch msg.String() {
 		case "up", "k":
@@ -313,7 +368,7 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.activeAddress = selectedAddr
 				m.highlightedAddress = selectedAddr
 				m.selectedWallet = m.accountListSelectedIdx
-				config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+				m.saveConfig()
 				m.logSuccess(fmt.Sprintf("Activated account: %s", helpers.ShortenAddr(selectedAddr)))
 				m.activeDialog = dialogNone
 				return m, m.loadSelectedWalletDetailsFresh()
@@ -336,7 +391,7 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.logViewport.Width = m.w - 6
 				}
 				m.logReady = false
-				config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+				m.saveConfig()
 				return m, tea.Batch(initLogViewport(), m.logSpinner.Start())
 			}
 			if m.logBuffer != nil {
@@ -344,14 +399,36 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			m.logger = nil
 			m.logReady = false
-			config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+			m.saveConfig()
 			return m, nil
 
 		case "i", "I":
 			return m.handleIndexerToggle()
 
+		case "f", "F":
+			if m.activeDialog == dialogNone && m.v4PanelTargeted() {
+				return m.openPoolFilterDialog()
+			}
+
+		// The V4 panel's row keys stay off Enter and ↑/↓, which the page
+		// under the panel (the Uniswap module) needs for its own fields.
+		case "o", "O":
+			if m.activeDialog == dialogNone && m.v4PanelTargeted() && len(m.v4PoolRows) > 0 {
+				return m, m.openSelectedV4Pool()
+			}
+
+		case "{", "}":
+			if m.activeDialog == dialogNone && m.v4PanelTargeted() && len(m.v4PoolRows) > 0 {
+				if msg.String() == "{" {
+					m.moveV4Selection(-1)
+				} else {
+					m.moveV4Selection(1)
+				}
+				return m, nil
+			}
+
 		case "pageup", "pagedown", "up", "down":
-			v4Visible := m.activePage == config.PageUniswap && m.poolEventMonitorActive && !m.uniswapShowingLiquidity
+			v4Visible := m.v4PanelShown
 			bothVisible := v4Visible && m.logEnabled && m.logReady
 			var cmd tea.Cmd
 			switch {
@@ -379,12 +456,24 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleDappsKey(msg)
 	case config.PageSettings:
 		return m.handleSettingsKey(msg)
-	case config.PageUniswap:
-		return m.handleUniswapKey(msg)
-	case config.PageTerraNullius:
-		return m.handleTerraKey(msg)
+	case config.PageDapp:
+		if m.activeModule != nil {
+			return m, m.activeModule.Update(m.host(), msg)
+		}
 	case config.PageWatchedTokens:
 		return m.handleWatchedTokensKey(msg)
+	case config.PageTransactions:
+		return m.handleTransactionsKey(msg)
+	case config.PageApprovals:
+		return m.handleApprovalsKey(msg)
+	case config.PageAddressBook:
+		return m.handleAddressBookKey(msg)
+	case config.PageContract:
+		return m.handleContractKey(msg)
+	case config.PageBlockExplorer:
+		return m.handleExplorerKey(msg)
+	case config.PageTxInspector:
+		return m.handleInspectorKey(msg)
 	}
 	return m, nil
 }
@@ -460,7 +549,7 @@ func (m *model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			m.tokenListViewport, cmd = m.tokenListViewport.Update(msg)
 			return m, cmd
 		}
-		v4Visible := m.activePage == config.PageUniswap && m.poolEventMonitorActive && !m.uniswapShowingLiquidity
+		v4Visible := m.v4PanelShown
 		bothVisible := v4Visible && m.logEnabled && m.logReady
 		var cmd tea.Cmd
 		switch {
@@ -493,8 +582,7 @@ func (m *model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 func (m *model) handleMouseLeft(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	// Panel focus when both V4 events and log are visible.
-	if m.activePage == config.PageUniswap && m.poolEventMonitorActive &&
-		!m.uniswapShowingLiquidity && m.logEnabled && m.logScroll.PanelTop > 3 {
+	if m.v4PanelShown && m.logEnabled && m.logScroll.PanelTop > 3 {
 		if msg.Y >= m.logScroll.PanelTop-3 {
 			m.focusedPanel = focusedPanelLog
 		} else {
@@ -524,7 +612,7 @@ func (m *model) handleMouseLeft(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 	}
-	v4Visible := m.activePage == config.PageUniswap && m.poolEventMonitorActive && !m.uniswapShowingLiquidity
+	v4Visible := m.v4PanelShown
 	if v4Visible && m.v4Scroll.PanelTop > 0 {
 		if m.v4Scroll.HitTest(msg.X, msg.Y, m.v4Scroll.PanelTop+m.v4EventsViewport.Height-1) {
 			m.v4Scroll.Dragging = true
@@ -579,12 +667,20 @@ func (m *model) handleMouseLeft(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		if msg.Y >= m.v4Scroll.PanelTop && msg.Y < m.v4Scroll.PanelTop+vpH {
 			vpLine := msg.Y - m.v4Scroll.PanelTop
 			absLine := vpLine + m.v4EventsViewport.YOffset
-			lines := strings.Split(uniswap.V4EventsContent(m.w-2, m.v4PoolRows), "\n")
+			lines := strings.Split(uniswap.V4EventsContent(m.w-2, m.v4PoolRows, m.v4SelectedIdx()), "\n")
 			if absLine < len(lines) {
 				if url := urlAtCol(lines[absLine], msg.X-3); url != "" {
 					return m.handleURLClick(url)
 				}
 			}
+			if idx := m.v4CardAt(absLine); idx >= 0 {
+				m.v4SelectedPool = m.v4PoolRows[idx].PoolID
+				m.refreshV4EventsContent()
+				if m.isDoubleClick(msg.X, msg.Y) {
+					return m, m.openSelectedV4Pool()
+				}
+				return m, nil
+			}
 		}
 	}
 
@@ -612,7 +708,7 @@ func (m *model) handleMouseLeft(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 							m.activeAddress = area.Address
 							m.highlightedAddress = area.Address
 							m.selectedWallet = i
-							config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+							m.saveConfig()
 							m.logSuccess(fmt.Sprintf("Activated account: %s", helpers.ShortenAddr(area.Address)))
 							if m.activeDialog == dialogAccountList {
 								m.activeDialog = dialogNone
@@ -678,18 +774,3 @@ func (m *model) handleURLClick(url string) (tea.Model, tea.Cmd) {
 	}
 	return m, openInBrowser(url)
 }
-
-// -------------------- HELPERS --------------------
-
-// appendNumericInput appends a digit or '.' to value.
-// On the first keystroke after a non-zero prior value, the field is cleared.
-// Returns (newValue, newEditing, ok) — ok is false when a duplicate '.' is rejected.
-func appendNumericInput(value string, editing bool, char string) (string, bool, bool) {
-	if !editing && value != "" && value != "0" {
-		value = ""
-	}
-	if char == "." && strings.Contains(value, ".") {
-		return value, editing, false
-	}
-	return value + char, true, true
-}
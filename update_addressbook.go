@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/addressbook"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// createContactForm opens the add (idx < 0) or edit form for an address
+// book entry.
+func (m *model) createContactForm(idx int) {
+	tempContactAddr, tempContactLabel, tempContactNotes, tempContactTags = "", "", "", ""
+	m.editingContactIdx = idx
+	m.contactFormMode = "add"
+	if idx >= 0 && idx < len(m.addressBook) {
+		c := m.addressBook[idx]
+		tempContactAddr = c.Address
+		if c.ENS != "" {
+			tempContactAddr = c.ENS
+		}
+		tempContactLabel = c.Label
+		tempContactNotes = c.Notes
+		tempContactTags = strings.Join(c.Tags, ", ")
+		m.contactFormMode = "edit"
+	}
+	m.contactFormButtonFocused = false
+	m.contactFormError = ""
+
+	addrField := huh.NewInput().
+		Title("Address").
+		Description("0x address or .eth name (resolved once, then cached)").
+		Value(&tempContactAddr).
+		Placeholder("0x... or name.eth")
+	labelField := huh.NewInput().
+		Title("Label").
+		Description("Shown in place of the address across the app").
+		Value(&tempContactLabel).
+		CharLimit(32)
+	notesField := huh.NewInput().
+		Title("Notes").
+		Value(&tempContactNotes).
+		Placeholder("optional")
+	tagsField := huh.NewInput().
+		Title("Tags").
+		Value(&tempContactTags).
+		Placeholder("comma-separated, optional")
+
+	m.contactFormFields = []huh.Field{addrField, labelField, notesField, tagsField}
+	m.contactForm = huh.NewForm(
+		huh.NewGroup(m.contactFormFields...),
+	).WithWidth(RPCFormPopupInnerWidth).WithTheme(huh.ThemeCatppuccin())
+
+	m.contactForm.Init()
+}
+
+func (m *model) closeContactForm() {
+	m.contactFormMode = "list"
+	m.contactForm = nil
+	m.contactFormButtonFocused = false
+	m.contactResolving = false
+}
+
+// submitContactForm validates the form. A .eth name is resolved (through the
+// ENS cache) before saving; handleContactENSResolved finishes the save.
+func (m *model) submitContactForm() (tea.Model, tea.Cmd) {
+	addr := strings.TrimSpace(tempContactAddr)
+	label := strings.TrimSpace(tempContactLabel)
+	if label == "" {
+		m.contactFormError = "A label is required"
+		return m, nil
+	}
+	if strings.HasSuffix(strings.ToLower(addr), ".eth") {
+		if m.ethClient == nil {
+			m.contactFormError = "Resolving a .eth name needs an RPC connection"
+			return m, nil
+		}
+		m.contactResolving = true
+		m.contactFormError = ""
+		return m, resolveContactENS(m.ethClient, m.eventStore, addr)
+	}
+	if !helpers.IsValidEthAddress(addr) {
+		m.contactFormError = "Not a valid address or .eth name"
+		return m, nil
+	}
+	return m.saveContact(common.HexToAddress(addr).Hex(), "")
+}
+
+func (m *model) handleContactENSResolved(msg contactENSResolvedMsg) (tea.Model, tea.Cmd) {
+	if !m.contactResolving {
+		return m, nil
+	}
+	m.contactResolving = false
+	if msg.err != nil {
+		m.contactFormError = fmt.Sprintf("Could not resolve %s: %v", msg.name, msg.err)
+		return m, nil
+	}
+	return m.saveContact(msg.address, msg.name)
+}
+
+// saveContact writes the form's label, notes and tags for address into the
+// address book (replacing the entry being edited), persists the config and
+// refreshes the shared address labels.
+func (m *model) saveContact(address, ensName string) (tea.Model, tea.Cmd) {
+	label := strings.TrimSpace(tempContactLabel)
+	for i, c := range m.addressBook {
+		if i == m.editingContactIdx && m.contactFormMode == "edit" {
+			continue
+		}
+		if strings.EqualFold(c.Address, address) {
+			m.contactFormError = fmt.Sprintf("%s is already saved as %q", helpers.ShortenAddr(address), c.Label)
+			return m, nil
+		}
+		if strings.EqualFold(c.Label, label) {
+			m.contactFormError = fmt.Sprintf("Label %q is already used", c.Label)
+			return m, nil
+		}
+	}
+
+	var tags []string
+	for _, t := range strings.Split(tempContactTags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	contact := config.Contact{
+		Address: address,
+		Label:   label,
+		ENS:     ensName,
+		Notes:   strings.TrimSpace(tempContactNotes),
+		Tags:    tags,
+	}
+
+	if m.contactFormMode == "edit" && m.editingContactIdx >= 0 && m.editingContactIdx < len(m.addressBook) {
+		m.addressBook[m.editingContactIdx] = contact
+		m.contactIdx = m.editingContactIdx
+		m.logSuccess(fmt.Sprintf("Updated contact `%s`", label))
+	} else {
+		m.addressBook = append(m.addressBook, contact)
+		m.contactIdx = len(m.addressBook) - 1
+		m.logSuccess(fmt.Sprintf("Added contact `%s` (%s)", label, helpers.ShortenAddr(address)))
+	}
+	m.saveConfig()
+	m.syncAddressLabels()
+	m.closeContactForm()
+
+	// Fill the reverse-ENS cache so the page can show the contact's primary
+	// name; handleENSLookupResult re-syncs the labels when it lands.
+	if ensName == "" && m.ethClient != nil {
+		return m, lookupENS(m.ethClient, m.eventStore, address)
+	}
+	return m, nil
+}
+
+func (m *model) handleContactFormMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if keyMsg.String() == "esc" {
+			m.closeContactForm()
+			return m, nil
+		}
+
+		// Same synchronous clipboard read as the watched-token form — see
+		// handleWatchedTokensFormMsg for why huh's own paste is bypassed.
+		if keyMsg.String() == "ctrl+v" && !m.contactFormButtonFocused {
+			if text, err := clipboard.ReadAll(); err == nil && text != "" {
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+			} else {
+				return m, nil
+			}
+		}
+
+		lastField := m.contactFormFields[len(m.contactFormFields)-1]
+
+		if m.contactFormButtonFocused {
+			switch keyMsg.String() {
+			case "enter", " ":
+				return m.submitContactForm()
+			case "tab":
+				m.contactFormButtonFocused = false
+				return m, focusHuhField(m.contactForm, m.contactFormFields, 0)
+			case "shift+tab":
+				m.contactFormButtonFocused = false
+				return m, focusHuhField(m.contactForm, m.contactFormFields, len(m.contactFormFields)-1)
+			}
+			return m, nil
+		}
+
+		if keyMsg.String() == "tab" && m.contactForm.GetFocusedField() == lastField {
+			m.contactFormButtonFocused = true
+			return m, lastField.Blur()
+		}
+	}
+
+	form, cmd := m.contactForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.contactForm = f
+
+		if m.contactForm.State == huh.StateCompleted {
+			return m.submitContactForm()
+		}
+		if m.contactForm.State == huh.StateAborted {
+			m.closeContactForm()
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+// confirmDeleteContactYes deletes the selected address book entry.
+func (m *model) confirmDeleteContactYes() (tea.Model, tea.Cmd) {
+	if m.contactIdx >= 0 && m.contactIdx < len(m.addressBook) {
+		label := m.addressBook[m.contactIdx].Label
+		m.addressBook = append(m.addressBook[:m.contactIdx], m.addressBook[m.contactIdx+1:]...)
+		if m.contactIdx >= len(m.addressBook) && m.contactIdx > 0 {
+			m.contactIdx--
+		}
+		m.saveConfig()
+		m.syncAddressLabels()
+		m.logWarn(fmt.Sprintf("Removed contact `%s`", label))
+	}
+	m.activeDialog = dialogNone
+	return m, nil
+}
+
+func (m *model) confirmDeleteContactNo() (tea.Model, tea.Cmd) {
+	m.activeDialog = dialogNone
+	return m, nil
+}
+
+func (m *model) handleAddressBookKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.activeDialog == dialogDeleteContact {
+		switch msg.String() {
+		case "left", "right", "tab":
+			m.deleteContactDialogYesSelected = !m.deleteContactDialogYesSelected
+		case "enter":
+			if m.deleteContactDialogYesSelected {
+				return m.confirmDeleteContactYes()
+			}
+			return m.confirmDeleteContactNo()
+		case "esc":
+			m.activeDialog = dialogNone
+		}
+		return m, nil
+	}
+
+	// The form is frozen while its .eth name resolves; Esc still cancels.
+	if m.contactResolving {
+		if msg.String() == "esc" {
+			m.closeContactForm()
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return m, m.navigateTo(config.PageWallets)
+	case "up", "k":
+		if m.contactIdx > 0 {
+			m.contactIdx--
+		}
+	case "down", "j":
+		if m.contactIdx < len(m.addressBook)-1 {
+			m.contactIdx++
+		}
+	case "a", "A":
+		m.createContactForm(-1)
+	case "e", "E", "enter":
+		if m.contactIdx < len(m.addressBook) {
+			m.createContactForm(m.contactIdx)
+		}
+	case "c", "C":
+		if m.contactIdx < len(m.addressBook) {
+			return m, copyToClipboard(m.addressBook[m.contactIdx].Address)
+		}
+	case "delete", "backspace":
+		if m.contactIdx < len(m.addressBook) {
+			m.activeDialog = dialogDeleteContact
+			m.deleteContactDialogYesSelected = true
+		}
+	}
+	return m, nil
+}
+
+func (m *model) renderAddressBookPage() (pageContent, nav string) {
+	c := addressbook.Render(m.contentW-4, m.addressBook, m.contactIdx, m.ensNames)
+	return styles.PanelStyle.Width(m.contentW).Render(c), addressbook.Nav(m.w-2, m.txIndexerActive)
+}
+
+func (m *model) renderContactDeleteDialog() string {
+	label := ""
+	if m.contactIdx < len(m.addressBook) {
+		label = m.addressBook[m.contactIdx].Label
+	}
+	msg := helpers.FadeString("Are you sure you want to remove the contact "+label+"?", "#F25D94", "#EDFF82")
+	return m.renderConfirmDialog("confirmDeleteContact", msg, m.deleteContactDialogYesSelected,
+		(*model).confirmDeleteContactYes, (*model).confirmDeleteContactNo)
+}
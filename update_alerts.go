@@ -0,0 +1,448 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/indexer"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/alerts"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// alertPollInterval is how often balance, price and range rules are checked.
+const alertPollInterval = 30 * time.Second
+
+// alertSignalHold is how long a terminal alert's sequences stay in the
+// frame: long enough for the renderer to flush them once.
+const alertSignalHold = 250 * time.Millisecond
+
+var logColorAlert = lipgloss.NewStyle().Foreground(styles.CWarn).Bold(true).Reverse(true)
+
+// parsedAlert is an enabled rule with the expression it was parsed from.
+type parsedAlert struct {
+	expr string
+	rule helpers.AlertRule
+}
+
+// alertFiring is one alert to raise: the rule and what happened.
+type alertFiring struct {
+	alert   parsedAlert
+	message string
+}
+
+// enabledAlerts parses the enabled rules; rules saved by hand that no
+// longer parse are skipped.
+func (m *model) enabledAlerts() []parsedAlert {
+	var out []parsedAlert
+	for _, r := range m.alertRules {
+		if r.Disabled {
+			continue
+		}
+		if rule, err := helpers.ParseAlertRule(r.Rule); err == nil {
+			out = append(out, parsedAlert{expr: r.Rule, rule: rule})
+		}
+	}
+	return out
+}
+
+func alertsOfKind(list []parsedAlert, kind string) []parsedAlert {
+	var out []parsedAlert
+	for _, a := range list {
+		if a.rule.Kind == kind {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// savedWalletAddrs returns the saved wallets, which rules without a
+// wallet: term cover.
+func (m *model) savedWalletAddrs() []common.Address {
+	addrs := make([]common.Address, len(m.accounts))
+	for i, a := range m.accounts {
+		addrs[i] = common.HexToAddress(a.Address)
+	}
+	return addrs
+}
+
+// startAlerts (re)starts the alert poll loop from a fresh snapshot. The
+// first snapshot is only a baseline: periodic rules fire on changes after
+// it, and event rules on events from its block on.
+func (m *model) startAlerts() tea.Cmd {
+	m.alertGen++
+	m.alertSnapshot = nil
+	m.alertFromBlock = 0
+	if m.ethClient == nil || len(m.enabledAlerts()) == 0 {
+		return nil
+	}
+	return m.takeAlertSnapshot()
+}
+
+func (m *model) takeAlertSnapshot() tea.Cmd {
+	enabled := m.enabledAlerts()
+	rules := make([]helpers.AlertRule, len(enabled))
+	for i, a := range enabled {
+		rules[i] = a.rule
+	}
+	return loadAlertSnapshot(m.ethClient, m.rpcURL, rules, m.savedWalletAddrs(), m.tokenWatchForActiveChain(), m.alertGen)
+}
+
+// loadAlertSnapshot reads what the periodic rules need: balances of the
+// watched wallets, prices, and position ranges.
+func loadAlertSnapshot(client *rpc.Client, rpcURL string, rules []helpers.AlertRule, saved []common.Address, watch []rpc.WatchedToken, gen int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		head, err := client.BlockNumber(ctx)
+		cancel()
+		if err != nil {
+			return alertSnapshotMsg{gen: gen, err: fmt.Errorf("block number: %w", err)}
+		}
+		snap := &helpers.AlertSnapshot{Head: head, Wallets: saved, At: time.Now()}
+
+		needs := make(map[string]bool)
+		wallets := append([]common.Address{}, saved...)
+		for _, r := range rules {
+			needs[r.Kind] = true
+			for _, w := range r.Wallets {
+				if !containsAddr(wallets, w) {
+					wallets = append(wallets, w)
+				}
+			}
+		}
+
+		var errs []error
+		if needs[helpers.AlertKindBalance] {
+			for _, w := range wallets {
+				d := rpc.LoadWalletDetails(client, w, watch)
+				if d.ErrMessage != "" || d.EthWei == nil {
+					errs = append(errs, fmt.Errorf("balances of %s: %s", helpers.ShortenAddr(w.Hex()), d.ErrMessage))
+					continue
+				}
+				snap.Balances = append(snap.Balances, helpers.AlertBalance{Wallet: w, Symbol: "ETH", Amount: alertAmount(d.EthWei, 18)})
+				for _, t := range d.Tokens {
+					if t.Balance != nil {
+						snap.Balances = append(snap.Balances, helpers.AlertBalance{Wallet: w, Token: t.Address, Symbol: t.Symbol, Amount: alertAmount(t.Balance, t.Decimals)})
+					}
+				}
+			}
+		}
+		if needs[helpers.AlertKindPrice] || needs[helpers.AlertKindSwap] {
+			tokens := make(map[string]common.Address, len(watch))
+			for _, t := range watch {
+				tokens[strings.ToUpper(t.Symbol)] = t.Address
+			}
+			prices, usd, err := helpers.LoadAlertPrices(rpcURL, rules, tokens)
+			snap.Prices, snap.USD = prices, usd
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if needs[helpers.AlertKindRange] {
+			positions, err := helpers.LoadAlertPositions(rpcURL, wallets)
+			snap.Positions = positions
+			if err != nil {
+				errs = append(errs, fmt.Errorf("positions: %w", err))
+			}
+		}
+		return alertSnapshotMsg{gen: gen, snap: snap, err: errors.Join(errs...)}
+	}
+}
+
+// alertAmount converts base units to whole tokens.
+func alertAmount(v *big.Int, decimals uint8) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(v),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))).Float64()
+	return f
+}
+
+func containsAddr(list []common.Address, a common.Address) bool {
+	for _, v := range list {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAlertMsg handles the alert loop's messages. Like spinner ticks they
+// are taken before any dialog or form sees them, or an open form would
+// swallow a tick and stop the loop.
+func (m *model) handleAlertMsg(msg tea.Msg) (tea.Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case alertTickMsg:
+		if msg.gen != m.alertGen || m.ethClient == nil || len(m.enabledAlerts()) == 0 {
+			return m, nil, true
+		}
+		return m, m.takeAlertSnapshot(), true
+	case alertSnapshotMsg:
+		return m, m.handleAlertSnapshot(msg), true
+	case alertFiredMsg:
+		return m, m.fireAlerts(msg.firings), true
+	case alertSignalSentMsg:
+		if m.alertSignal == msg.seq {
+			m.alertSignal = ""
+		}
+		return m, nil, true
+	case alertDeliveredMsg:
+		for _, e := range msg.errs {
+			m.logWarn("Alert delivery failed: " + e)
+		}
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+func (m *model) handleAlertSnapshot(msg alertSnapshotMsg) tea.Cmd {
+	if msg.gen != m.alertGen {
+		return nil
+	}
+	next := tea.Tick(alertPollInterval, func(time.Time) tea.Msg { return alertTickMsg{gen: msg.gen} })
+	if msg.err != nil {
+		m.logWarn("Alerts: " + msg.err.Error())
+	}
+	if msg.snap == nil {
+		return next
+	}
+	var firings []alertFiring
+	for _, a := range m.enabledAlerts() {
+		for _, text := range a.rule.Check(m.alertSnapshot, msg.snap) {
+			firings = append(firings, alertFiring{alert: a, message: text})
+		}
+	}
+	if m.alertSnapshot == nil {
+		m.alertFromBlock = msg.snap.Head
+		m.logInfo(fmt.Sprintf("Alerts: watching %d rule(s) from block %d", len(m.enabledAlerts()), msg.snap.Head))
+	}
+	m.alertSnapshot = msg.snap
+	return tea.Batch(next, m.fireAlerts(firings))
+}
+
+// checkTransferAlerts raises transfer alerts for an indexed transfer.
+// Transfers from before the alert baseline (the indexer's backscan) are
+// history and never alert.
+func (m *model) checkTransferAlerts(ev indexer.IndexedEvent) tea.Cmd {
+	if m.alertFromBlock == 0 || ev.Block < m.alertFromBlock {
+		return nil
+	}
+	var firings []alertFiring
+	for _, a := range alertsOfKind(m.enabledAlerts(), helpers.AlertKindTransfer) {
+		if text, ok := a.rule.CheckTransfer(ev, m.savedWalletAddrs()); ok {
+			firings = append(firings, alertFiring{alert: a, message: text})
+		}
+	}
+	return m.fireAlerts(firings)
+}
+
+// checkSwapAlerts sizes a swap from the pool monitor or the indexer against
+// the swap rules, off the UI goroutine since the pool comes from the store.
+func (m *model) checkSwapAlerts(ev indexer.V4PoolEvent) tea.Cmd {
+	if ev.Kind != indexer.V4KindSwap || m.alertFromBlock == 0 || ev.Block < m.alertFromBlock {
+		return nil
+	}
+	rules := alertsOfKind(m.enabledAlerts(), helpers.AlertKindSwap)
+	if len(rules) == 0 {
+		return nil
+	}
+	// The monitor and the indexer can both deliver the same swap.
+	key := ev.TxHash.Hex() + ":" + fmt.Sprint(ev.LogIndex)
+	if m.alertSeenSwaps == nil || len(m.alertSeenSwaps) > 4096 {
+		m.alertSeenSwaps = make(map[string]bool)
+	}
+	if m.alertSeenSwaps[key] {
+		return nil
+	}
+	m.alertSeenSwaps[key] = true
+
+	var usd map[common.Address]float64
+	if m.alertSnapshot != nil {
+		usd = m.alertSnapshot.USD
+	}
+	return checkSwapAlertsCmd(m.eventStore, rules, ev, usd)
+}
+
+func checkSwapAlertsCmd(st *store.Store, rules []parsedAlert, ev indexer.V4PoolEvent, usd map[common.Address]float64) tea.Cmd {
+	return func() tea.Msg {
+		pool := helpers.AlertSwapPool{Symbol0: "?", Symbol1: "?"}
+		var meta store.V4PoolMeta
+		found := false
+		if st != nil {
+			meta, found, _ = st.V4Pool(ev.PoolID.Hex())
+		}
+		if found {
+			pool = helpers.AlertSwapPool{
+				Currency0: common.HexToAddress(meta.Currency0), Currency1: common.HexToAddress(meta.Currency1),
+				Symbol0: meta.Token0Sym, Symbol1: meta.Token1Sym,
+				Decimals0: meta.Decimals0, Decimals1: meta.Decimals1,
+			}
+		} else {
+			// Without the pool's currencies nothing can be priced.
+			usd = nil
+		}
+		var firings []alertFiring
+		for _, a := range rules {
+			if text, ok := a.rule.CheckSwap(ev, pool, usd); ok {
+				firings = append(firings, alertFiring{alert: a, message: text})
+			}
+		}
+		return alertFiredMsg{firings: firings}
+	}
+}
+
+// fireAlerts highlights each alert in the log and delivers it through the
+// channels its rule asks for.
+func (m *model) fireAlerts(firings []alertFiring) tea.Cmd {
+	if len(firings) == 0 {
+		return nil
+	}
+	now := time.Now()
+	var signal strings.Builder
+	for _, f := range firings {
+		m.alertFired[f.alert.expr] = now
+		m.logWarn(logColorAlert.Render(" 🔔 ALERT ") + " " + f.message + "  " + lipgloss.NewStyle().Foreground(styles.CMuted).Render("("+f.alert.expr+")"))
+		_ = helpers.TerminalAlert(&signal, f.alert.rule, "Wallet alert", f.message)
+	}
+	var signalCmd tea.Cmd
+	if signal.Len() > 0 {
+		// The bell/OSC sequences ride inside the next frame (see View) so
+		// the renderer writes them, rather than racing it on stdout.
+		seq := signal.String()
+		m.alertSignal = seq
+		signalCmd = tea.Tick(alertSignalHold, func(time.Time) tea.Msg { return alertSignalSentMsg{seq: seq} })
+	}
+	webhook, command := m.alertWebhook, m.alertCommand
+	deliver := func() tea.Msg {
+		var errs []string
+		for _, f := range firings {
+			p := helpers.AlertPayload{Rule: f.alert.expr, Message: f.message, Time: now}
+			if f.alert.rule.Has(helpers.NotifyWebhook) {
+				if webhook == "" {
+					errs = append(errs, "webhook: alert_webhook is not set in the config")
+				} else if err := helpers.PostAlertWebhook(webhook, p); err != nil {
+					errs = append(errs, "webhook: "+err.Error())
+				}
+			}
+			if f.alert.rule.Has(helpers.NotifyCommand) {
+				if command == "" {
+					errs = append(errs, "command: alert_command is not set in the config")
+				} else if err := helpers.RunAlertCommand(command, p); err != nil {
+					errs = append(errs, "command: "+err.Error())
+				}
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return alertDeliveredMsg{errs: errs}
+	}
+	return tea.Batch(signalCmd, deliver)
+}
+
+// -------------------- ALERTS DIALOG --------------------
+
+func (m *model) openAlertsDialog() (tea.Model, tea.Cmd) {
+	m.activeDialog = dialogAlerts
+	m.alertInput = ""
+	m.alertErr = ""
+	if m.alertIdx >= len(m.alertRules) {
+		m.alertIdx = 0
+	}
+	return m, nil
+}
+
+func (m *model) handleAlertsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.activeDialog = dialogNone
+		return m, nil
+	case "enter":
+		expr := strings.Join(strings.Fields(m.alertInput), " ")
+		if expr == "" {
+			return m, nil
+		}
+		if _, err := helpers.ParseAlertRule(expr); err != nil {
+			m.alertErr = err.Error()
+			return m, nil
+		}
+		for _, r := range m.alertRules {
+			if r.Rule == expr {
+				m.alertErr = "That rule already exists"
+				return m, nil
+			}
+		}
+		m.alertRules = append(m.alertRules, config.AlertRule{Rule: expr})
+		m.alertIdx = len(m.alertRules) - 1
+		m.alertInput = ""
+		m.alertErr = ""
+		m.saveConfig()
+		m.logSuccess("Alert added: " + expr)
+		return m, m.startAlerts()
+	case "up":
+		if m.alertIdx > 0 {
+			m.alertIdx--
+		}
+		return m, nil
+	case "down":
+		if m.alertIdx < len(m.alertRules)-1 {
+			m.alertIdx++
+		}
+		return m, nil
+	case "ctrl+e":
+		if m.alertIdx < len(m.alertRules) {
+			m.alertRules[m.alertIdx].Disabled = !m.alertRules[m.alertIdx].Disabled
+			m.saveConfig()
+			return m, m.startAlerts()
+		}
+		return m, nil
+	case "ctrl+d":
+		if m.alertIdx < len(m.alertRules) {
+			expr := m.alertRules[m.alertIdx].Rule
+			m.alertRules = append(m.alertRules[:m.alertIdx], m.alertRules[m.alertIdx+1:]...)
+			if m.alertIdx > 0 && m.alertIdx >= len(m.alertRules) {
+				m.alertIdx--
+			}
+			m.saveConfig()
+			m.logInfo("Alert deleted: " + expr)
+			return m, m.startAlerts()
+		}
+		return m, nil
+	case "ctrl+t":
+		// Send a test alert through the selected rule's channels.
+		if m.alertIdx < len(m.alertRules) {
+			expr := m.alertRules[m.alertIdx].Rule
+			if rule, err := helpers.ParseAlertRule(expr); err == nil {
+				return m, m.fireAlerts([]alertFiring{{alert: parsedAlert{expr: expr, rule: rule}, message: "Test alert"}})
+			}
+		}
+		return m, nil
+	case "backspace":
+		if len(m.alertInput) > 0 {
+			m.alertInput = m.alertInput[:len(m.alertInput)-1]
+		}
+		return m, nil
+	}
+	if len(msg.Runes) > 0 {
+		m.alertInput += string(msg.Runes)
+		m.alertErr = ""
+	}
+	return m, nil
+}
+
+func (m *model) renderAlertsPopup() string {
+	dialogBoxStyle := styles.DialogBox.Background(styles.CPanel).Width(alerts.Width + 4)
+	content := alerts.Render(m.alertRules, m.alertFired, m.alertInput, m.alertErr, m.alertIdx,
+		m.alertWebhook, m.alertCommand, m.alertSnapshot != nil)
+	dialog := dialogBoxStyle.Render(content)
+	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, dialog)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/approvals"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// refreshApprovals rescans the active wallet's approval events and re-reads
+// live allowances. The first scan walks the wallet's whole history, so it
+// can take a while; later ones resume from the stored cursor.
+func (m *model) refreshApprovals() tea.Cmd {
+	if m.activeAddress == "" {
+		m.approvalsErr = "no active wallet"
+		return nil
+	}
+	m.approvalsLoading = true
+	m.approvalsErr = ""
+	m.logInfo("Approvals: scanning allowance history for " + helpers.ShortenAddr(m.activeAddress) + "…")
+	return loadApprovals(m.ethClient, m.eventStore, m.activeAddress)
+}
+
+func (m *model) handleApprovalsLoaded(msg approvalsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.approvalsLoading = false
+	if msg.err != nil {
+		m.approvalsErr = msg.err.Error()
+		m.logError("Approvals: " + msg.err.Error())
+		return m, nil
+	}
+	m.approvalsErr = ""
+	m.approvalRows = msg.rows
+	m.clampApprovalIdx()
+
+	live := 0
+	for _, r := range m.approvalRows {
+		if r.Active() {
+			live++
+		}
+	}
+	m.logSuccess(fmt.Sprintf("Approvals: %d new event(s) indexed, %d live allowance(s)", msg.scanned, live))
+	return m, nil
+}
+
+// visibleApprovals returns the rows the page currently lists: only live
+// allowances unless approvalsShowAll is set.
+func (m *model) visibleApprovals() []approvals.Row {
+	if m.approvalsShowAll {
+		return m.approvalRows
+	}
+	var rows []approvals.Row
+	for _, r := range m.approvalRows {
+		if r.Active() {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+func (m *model) clampApprovalIdx() {
+	if n := len(m.visibleApprovals()); m.approvalIdx >= n {
+		m.approvalIdx = n - 1
+	}
+	if m.approvalIdx < 0 {
+		m.approvalIdx = 0
+	}
+}
+
+func (m *model) handleApprovalsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.visibleApprovals()
+	switch msg.String() {
+	case "esc", "q":
+		return m, m.navigateTo(config.PageWallets)
+	case "up", "k":
+		if m.approvalIdx > 0 {
+			m.approvalIdx--
+		}
+	case "down", "j":
+		if m.approvalIdx < len(rows)-1 {
+			m.approvalIdx++
+		}
+	case "a", "A":
+		m.approvalsShowAll = !m.approvalsShowAll
+		m.clampApprovalIdx()
+	case "r", "R":
+		if m.approvalsLoading {
+			return m, nil
+		}
+		return m, m.refreshApprovals()
+	case "x", "X", "enter":
+		if m.approvalsLoading || m.approvalIdx >= len(rows) {
+			return m, nil
+		}
+		row := rows[m.approvalIdx]
+		if !row.Active() {
+			m.logWarn("This allowance is already revoked or expired")
+			return m, nil
+		}
+		m.logInfo(fmt.Sprintf("Packaging revoke for %s → %s", row.Symbol, helpers.ShortenAddr(row.Spender.Hex())))
+		m.activeDialog = dialogTxResult
+		m.txResultPackaging = true
+		m.txResultHex = ""
+		m.txResultError = ""
+		m.txResultFormat = "EIP-4527"
+		return m, tea.Batch(packageRevokeTransaction(m.eventStore, m.activeAddress, row, m.rpcURL, m.safeSourceFor(common.HexToAddress(m.activeAddress))), cmdEnableMouseAllMotion())
+	}
+	return m, nil
+}
+
+func (m *model) renderApprovalsPage() (pageContent, nav string) {
+	c := approvals.Render(m.contentW-4, m.visibleApprovals(), m.approvalIdx, m.approvalsShowAll,
+		m.approvalsLoading, m.approvalsErr, m.spin.View())
+	return styles.PanelStyle.Width(m.contentW).Render(c), approvals.Nav(m.w-2, m.approvalsShowAll, m.txIndexerActive)
+}
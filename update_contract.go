@@ -0,0 +1,499 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/contract"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// loadABISource turns the ABI form input into ABI JSON. The input is either
+// the JSON itself — a bare ABI array or a build artifact / explorer export
+// with an "abi" field — or a path to a file holding either.
+func loadABISource(src string) (string, error) {
+	src = strings.TrimSpace(src)
+	data := []byte(src)
+	if !strings.HasPrefix(src, "[") && !strings.HasPrefix(src, "{") {
+		path := src
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("not ABI JSON, and not a readable file: %w", err)
+		}
+		data = b
+	}
+
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if json.Unmarshal(data, &artifact) == nil && len(artifact.ABI) > 0 {
+		data = artifact.ABI
+		// Explorer APIs return the ABI as a JSON string inside the object.
+		var inner string
+		if json.Unmarshal(data, &inner) == nil {
+			data = []byte(inner)
+		}
+	}
+	if _, err := abi.JSON(strings.NewReader(string(data))); err != nil {
+		return "", fmt.Errorf("invalid ABI: %w", err)
+	}
+	return string(data), nil
+}
+
+// contractFunctions lists parsed's methods for the page: view/pure first,
+// then state-changing, each alphabetical.
+func contractFunctions(parsed abi.ABI) []contract.Function {
+	var funcs []contract.Function
+	for key, meth := range parsed.Methods {
+		sig := meth.RawName + "(" + argList(meth.Inputs) + ")"
+		if len(meth.Outputs) > 0 {
+			sig += " → (" + argList(meth.Outputs) + ")"
+		}
+		funcs = append(funcs, contract.Function{
+			Name:    key,
+			Sig:     sig,
+			Read:    meth.IsConstant(),
+			Payable: meth.IsPayable(),
+		})
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		if funcs[i].Read != funcs[j].Read {
+			return funcs[i].Read
+		}
+		return funcs[i].Sig < funcs[j].Sig
+	})
+	return funcs
+}
+
+func argList(args abi.Arguments) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a.Type.String()
+		if a.Name != "" {
+			parts[i] += " " + a.Name
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// refreshABILibrary reloads the saved ABIs from the local store.
+func (m *model) refreshABILibrary() {
+	m.contractLibrary = nil
+	if m.eventStore == nil {
+		return
+	}
+	lib, err := m.eventStore.ABIs()
+	if err != nil {
+		m.logError("Loading ABI library: " + err.Error())
+		return
+	}
+	m.contractLibrary = lib
+	if m.contractLibIdx >= len(lib) {
+		m.contractLibIdx = helpers.Max(0, len(lib)-1)
+	}
+}
+
+// openContract switches the page to addr's function list.
+func (m *model) openContract(name string, addr common.Address, abiJSON string) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return err
+	}
+	m.contractName = name
+	m.contractAddress = addr
+	m.contractABI = parsed
+	m.contractFuncs = contractFunctions(parsed)
+	m.contractFuncIdx = 0
+	m.contractMode = "functions"
+	return nil
+}
+
+// createContractOpenForm opens the form that loads a contract from a
+// pasted ABI or an ABI file, optionally prefilled with a saved ABI or a
+// dApp's address.
+func (m *model) createContractOpenForm(name, address, abiJSON string) {
+	tempContractAddr, tempContractName, tempContractABI = address, name, abiJSON
+	m.contractMode = "open"
+	m.contractFormButtonFocused = false
+	m.contractFormError = ""
+
+	addrField := huh.NewInput().
+		Title("Contract address").
+		Value(&tempContractAddr).
+		Placeholder("0x...").
+		Validate(func(s string) error {
+			if !helpers.IsValidEthAddress(strings.TrimSpace(s)) {
+				return fmt.Errorf("not a valid address")
+			}
+			return nil
+		})
+	abiField := huh.NewInput().
+		Title("ABI").
+		Description("Paste the ABI JSON (Ctrl+V) or a path to a .json file").
+		Value(&tempContractABI).
+		Placeholder(`[{"type":"function",...}] or ~/abis/Token.json`)
+	nameField := huh.NewInput().
+		Title("Name").
+		Description("Saved to the ABI library under this name").
+		Value(&tempContractName).
+		Placeholder("optional — defaults to the short address").
+		CharLimit(32)
+
+	m.contractFormFields = []huh.Field{addrField, abiField, nameField}
+	m.contractForm = huh.NewForm(
+		huh.NewGroup(m.contractFormFields...),
+	).WithWidth(RPCFormPopupInnerWidth).WithTheme(huh.ThemeCatppuccin())
+	m.contractForm.Init()
+}
+
+// createContractCallForm opens the argument form for the selected function:
+// one input per parameter, plus an ETH value for payable functions.
+func (m *model) createContractCallForm() {
+	f := m.contractFuncs[m.contractFuncIdx]
+	meth := m.contractABI.Methods[f.Name]
+	tempContractArgs = make([]string, len(meth.Inputs))
+	tempContractValue = ""
+	m.contractMode = "call"
+	m.contractFormButtonFocused = false
+	m.contractFormError = ""
+
+	var fields []huh.Field
+	for i, in := range meth.Inputs {
+		typ := in.Type
+		title := in.Name
+		if title == "" {
+			title = fmt.Sprintf("arg%d", i)
+		}
+		input := huh.NewInput().
+			Title(title + " (" + typ.String() + ")").
+			Value(&tempContractArgs[i]).
+			Validate(func(s string) error {
+				_, err := helpers.ParseABIArg(typ, s)
+				return err
+			})
+		switch typ.T {
+		case abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			input.Placeholder(`JSON array, e.g. ["0x...", 1]`)
+		case abi.BytesTy, abi.FixedBytesTy:
+			input.Placeholder("0x...")
+		}
+		fields = append(fields, input)
+	}
+	if f.Payable {
+		fields = append(fields, huh.NewInput().
+			Title("Value (ETH)").
+			Value(&tempContractValue).
+			Placeholder("0").
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return nil
+				}
+				_, err := helpers.ParseTokenAmount(s, 18)
+				return err
+			}))
+	}
+	m.contractFormFields = fields
+	m.contractForm = huh.NewForm(
+		huh.NewGroup(m.contractFormFields...),
+	).WithWidth(RPCFormPopupInnerWidth).WithTheme(huh.ThemeCatppuccin())
+	m.contractForm.Init()
+}
+
+func (m *model) closeContractForm() {
+	if m.contractMode == "open" {
+		m.contractMode = "library"
+	} else {
+		m.contractMode = "functions"
+	}
+	m.contractForm = nil
+	m.contractFormButtonFocused = false
+}
+
+func (m *model) submitContractForm() (tea.Model, tea.Cmd) {
+	if m.contractMode == "open" {
+		return m.submitContractOpenForm()
+	}
+	return m.submitContractCallForm()
+}
+
+func (m *model) submitContractOpenForm() (tea.Model, tea.Cmd) {
+	addr := strings.TrimSpace(tempContractAddr)
+	if !helpers.IsValidEthAddress(addr) {
+		m.contractFormError = "Not a valid contract address"
+		return m, nil
+	}
+	abiJSON, err := loadABISource(tempContractABI)
+	if err != nil {
+		m.contractFormError = err.Error()
+		return m, nil
+	}
+	address := common.HexToAddress(addr)
+	name := strings.TrimSpace(tempContractName)
+	if name == "" {
+		name = helpers.ShortenAddr(address.Hex())
+	}
+	if err := m.openContract(name, address, abiJSON); err != nil {
+		m.contractFormError = err.Error()
+		return m, nil
+	}
+	m.contractForm = nil
+	if m.eventStore != nil {
+		if err := m.eventStore.SaveABI(name, address.Hex(), abiJSON); err != nil {
+			m.logWarn("Could not save ABI to the library: " + err.Error())
+		}
+	}
+	m.logSuccess(fmt.Sprintf("Opened contract `%s` (%d functions)", name, len(m.contractFuncs)))
+	return m, nil
+}
+
+func (m *model) submitContractCallForm() (tea.Model, tea.Cmd) {
+	f := m.contractFuncs[m.contractFuncIdx]
+	meth := m.contractABI.Methods[f.Name]
+	args := make([]interface{}, len(meth.Inputs))
+	display := make([]string, len(meth.Inputs))
+	for i, in := range meth.Inputs {
+		v, err := helpers.ParseABIArg(in.Type, tempContractArgs[i])
+		if err != nil {
+			m.contractFormError = fmt.Sprintf("%s: %v", in.Name, err)
+			return m, nil
+		}
+		args[i] = v
+		display[i] = helpers.FormatABIValue(v)
+	}
+	m.contractForm = nil
+	m.contractMode = "functions"
+
+	if f.Read {
+		return m, m.callContractFunction(f.Name, args)
+	}
+	return m, m.packageContractFunction(meth, args, display)
+}
+
+// callContractFunction runs a read function via eth_call.
+func (m *model) callContractFunction(name string, args []interface{}) tea.Cmd {
+	for i := range m.contractFuncs {
+		if m.contractFuncs[i].Name == name {
+			m.contractFuncs[i].Calling = true
+		}
+	}
+	from := common.Address{}
+	if helpers.IsValidEthAddress(m.activeAddress) {
+		from = common.HexToAddress(m.activeAddress)
+	}
+	return callContractMethod(m.ethClient, m.contractAddress, from, m.contractABI, name, args)
+}
+
+func (m *model) packageContractFunction(meth abi.Method, args []interface{}, display []string) tea.Cmd {
+	calldata, err := m.contractABI.Pack(meth.Name, args...)
+	if err != nil {
+		m.logError("ABI encoding failed: " + err.Error())
+		return nil
+	}
+	value, _ := helpers.ParseTokenAmount(strings.TrimSpace(tempContractValue), 18)
+	if strings.TrimSpace(tempContractValue) == "" {
+		value = nil
+	}
+
+	summary := fmt.Sprintf("Contract call: %s.%s(%s)\nContract: %s",
+		m.contractName, meth.RawName, strings.Join(display, ", "), helpers.AddrWithLabel(m.contractAddress.Hex()))
+	if value != nil && value.Sign() > 0 {
+		summary += "\nValue: " + helpers.FormatETH(value) + " ETH"
+	}
+	m.logInfo(fmt.Sprintf("Packaging %s.%s from %s", m.contractName, meth.RawName, helpers.LabelAddr(m.activeAddress)))
+	m.activeDialog = dialogTxResult
+	m.txResultPackaging = true
+	m.txResultHex = ""
+	m.txResultError = ""
+	m.txResultFormat = "EIP-4527"
+	return tea.Batch(packageContractCall(m.eventStore, m.activeAddress, m.contractAddress, value, calldata, summary, m.rpcURL, m.safeSourceFor(common.HexToAddress(m.activeAddress))), cmdEnableMouseAllMotion())
+}
+
+func (m *model) handleContractCallResult(msg contractCallResultMsg) (tea.Model, tea.Cmd) {
+	for i := range m.contractFuncs {
+		f := &m.contractFuncs[i]
+		if f.Name != msg.name {
+			continue
+		}
+		f.Calling = false
+		f.Err = msg.err != nil
+		if msg.err != nil {
+			f.Result = msg.err.Error()
+			m.logWarn(fmt.Sprintf("%s.%s reverted: %v", m.contractName, f.Name, msg.err))
+		} else {
+			f.Result = msg.result
+		}
+	}
+	return m, nil
+}
+
+func (m *model) handleContractFormMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if keyMsg.String() == "esc" {
+			m.closeContractForm()
+			return m, nil
+		}
+
+		// Same synchronous clipboard read as the watched-token form. Pasted
+		// ABI JSON is usually pretty-printed; newlines are only whitespace
+		// to JSON, so they are flattened for the single-line input.
+		if keyMsg.String() == "ctrl+v" && !m.contractFormButtonFocused {
+			if text, err := clipboard.ReadAll(); err == nil && text != "" {
+				text = strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(text)
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+			} else {
+				return m, nil
+			}
+		}
+
+		lastField := m.contractFormFields[len(m.contractFormFields)-1]
+
+		if m.contractFormButtonFocused {
+			switch keyMsg.String() {
+			case "enter", " ":
+				return m.submitContractForm()
+			case "tab":
+				m.contractFormButtonFocused = false
+				return m, focusHuhField(m.contractForm, m.contractFormFields, 0)
+			case "shift+tab":
+				m.contractFormButtonFocused = false
+				return m, focusHuhField(m.contractForm, m.contractFormFields, len(m.contractFormFields)-1)
+			}
+			return m, nil
+		}
+
+		if keyMsg.String() == "tab" && m.contractForm.GetFocusedField() == lastField {
+			m.contractFormButtonFocused = true
+			return m, lastField.Blur()
+		}
+	}
+
+	form, cmd := m.contractForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.contractForm = f
+
+		if m.contractForm.State == huh.StateCompleted {
+			m.contractForm.State = huh.StateNormal
+			return m.submitContractForm()
+		}
+		if m.contractForm.State == huh.StateAborted {
+			m.closeContractForm()
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+func (m *model) handleContractKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.contractMode == "functions" {
+		switch msg.String() {
+		case "esc", "q":
+			m.contractMode = "library"
+			m.refreshABILibrary()
+		case "up", "k":
+			if m.contractFuncIdx > 0 {
+				m.contractFuncIdx--
+			}
+		case "down", "j":
+			if m.contractFuncIdx < len(m.contractFuncs)-1 {
+				m.contractFuncIdx++
+			}
+		case "c", "C":
+			return m, copyToClipboard(m.contractAddress.Hex())
+		case "enter":
+			if m.contractFuncIdx >= len(m.contractFuncs) {
+				return m, nil
+			}
+			f := m.contractFuncs[m.contractFuncIdx]
+			if !f.Read && (m.activeAddress == "" || !m.rpcConnected) {
+				m.logWarn("Packaging a contract call needs an active wallet and an RPC connection")
+				return m, nil
+			}
+			// Nothing to fill in: call or package straight away (a write is
+			// still only a QR until it is signed).
+			if meth := m.contractABI.Methods[f.Name]; len(meth.Inputs) == 0 && !f.Payable {
+				if f.Read {
+					return m, m.callContractFunction(f.Name, nil)
+				}
+				tempContractValue = ""
+				return m, m.packageContractFunction(meth, nil, nil)
+			}
+			m.createContractCallForm()
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return m, m.navigateTo(config.PageDappBrowser)
+	case "up", "k":
+		if m.contractLibIdx > 0 {
+			m.contractLibIdx--
+		}
+	case "down", "j":
+		if m.contractLibIdx < len(m.contractLibrary)-1 {
+			m.contractLibIdx++
+		}
+	case "n", "N", "a", "A":
+		m.createContractOpenForm("", "", "")
+	case "u", "U":
+		// Reuse a saved ABI against another address (e.g. a second ERC-20).
+		if m.contractLibIdx < len(m.contractLibrary) {
+			m.createContractOpenForm("", "", m.contractLibrary[m.contractLibIdx].ABI)
+		}
+	case "enter":
+		if m.contractLibIdx >= len(m.contractLibrary) {
+			m.createContractOpenForm("", "", "")
+			return m, nil
+		}
+		e := m.contractLibrary[m.contractLibIdx]
+		if !helpers.IsValidEthAddress(e.Address) {
+			m.createContractOpenForm(e.Name, "", e.ABI)
+			return m, nil
+		}
+		if err := m.openContract(e.Name, common.HexToAddress(e.Address), e.ABI); err != nil {
+			m.logError(fmt.Sprintf("Saved ABI `%s` no longer parses: %v", e.Name, err))
+		}
+	case "delete", "backspace":
+		if m.contractLibIdx < len(m.contractLibrary) && m.eventStore != nil {
+			name := m.contractLibrary[m.contractLibIdx].Name
+			if err := m.eventStore.DeleteABI(name); err != nil {
+				m.logError("Deleting ABI: " + err.Error())
+			} else {
+				m.logWarn(fmt.Sprintf("Removed `%s` from the ABI library", name))
+			}
+			m.refreshABILibrary()
+		}
+	}
+	return m, nil
+}
+
+func (m *model) renderContractPage() (pageContent, nav string) {
+	var c string
+	if m.contractMode == "library" || m.contractMode == "open" {
+		c = contract.RenderLibrary(m.contentW-4, m.contractLibrary, m.contractLibIdx)
+	} else {
+		c = contract.RenderFunctions(m.contentW-4, m.contractName, m.contractAddress.Hex(), m.contractFuncs, m.contractFuncIdx, m.spin.View())
+	}
+	mode := "functions"
+	if m.contractMode == "library" || m.contractMode == "open" {
+		mode = "library"
+	}
+	return styles.PanelStyle.Width(m.contentW).Render(c), contract.Nav(m.w-2, mode, m.txIndexerActive)
+}
@@ -1,22 +1,374 @@
 package main
 
 import (
+	"fmt"
+	"math/big"
+	"strings"
+
 	"charm-wallet-tui/config"
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
 
+	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/ethereum/go-ethereum/common"
 )
 
+// createDappForm opens the add (idx < 0) or edit form for a user-defined
+// dApp.
+func (m *model) createDappForm(idx int) {
+	tempDappName, tempDappAddr, tempDappIcon, tempDappChainID, tempDappABI, tempDappDesc = "", "", "", "", "", ""
+	m.editingDappIdx = idx
+	m.dappFormMode = "add"
+	if idx >= 0 && idx < len(m.dapps) {
+		d := m.dapps[idx]
+		tempDappName = d.Name
+		tempDappAddr = d.Address
+		tempDappIcon = d.Icon
+		if d.ChainID != nil {
+			tempDappChainID = d.ChainID.String()
+		}
+		tempDappABI = d.ABI
+		tempDappDesc = d.Description
+		m.dappFormMode = "edit"
+	}
+	m.dappFormButtonFocused = false
+	m.dappFormError = ""
+
+	nameField := huh.NewInput().
+		Title("Name").
+		Value(&tempDappName).
+		Placeholder("My Vault").
+		CharLimit(24)
+	addrField := huh.NewInput().
+		Title("Contract address").
+		Value(&tempDappAddr).
+		Placeholder("0x...")
+	iconField := huh.NewInput().
+		Title("Icon").
+		Value(&tempDappIcon).
+		Placeholder("optional emoji, e.g. 🏦").
+		CharLimit(4)
+	chainField := huh.NewInput().
+		Title("Chain ID").
+		Description("The network the contract is deployed on").
+		Value(&tempDappChainID).
+		Placeholder("blank for any network, e.g. 1 or 11155111")
+	abiField := huh.NewInput().
+		Title("ABI").
+		Description("A name from the Contract page's ABI library, or a path to a .json file").
+		Value(&tempDappABI).
+		Placeholder("optional — asked for on first open")
+	descField := huh.NewInput().
+		Title("Description").
+		Value(&tempDappDesc).
+		Placeholder("optional")
+
+	m.dappFormFields = []huh.Field{nameField, addrField, iconField, chainField, abiField, descField}
+	m.dappForm = huh.NewForm(
+		huh.NewGroup(m.dappFormFields...),
+	).WithWidth(RPCFormPopupInnerWidth).WithTheme(huh.ThemeCatppuccin())
+
+	m.dappForm.Init()
+}
+
+func (m *model) closeDappForm() {
+	m.dappFormMode = "list"
+	m.dappForm = nil
+	m.dappFormButtonFocused = false
+}
+
+// submitDappForm validates the form and saves the dApp, replacing the entry
+// being edited.
+func (m *model) submitDappForm() (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(tempDappName)
+	addr := strings.TrimSpace(tempDappAddr)
+	abiRef := strings.TrimSpace(tempDappABI)
+	if name == "" {
+		m.dappFormError = "A name is required"
+		return m, nil
+	}
+	if !helpers.IsValidEthAddress(addr) {
+		m.dappFormError = "Not a valid contract address"
+		return m, nil
+	}
+	var chainID *big.Int
+	if s := strings.TrimSpace(tempDappChainID); s != "" {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok || n.Sign() <= 0 {
+			m.dappFormError = "Chain ID must be a positive number"
+			return m, nil
+		}
+		chainID = n
+	}
+	if abiRef != "" {
+		if _, err := m.resolveDappABI(abiRef); err != nil {
+			m.dappFormError = err.Error()
+			return m, nil
+		}
+	}
+	for i, d := range m.dapps {
+		if i == m.editingDappIdx && m.dappFormMode == "edit" {
+			continue
+		}
+		if strings.EqualFold(d.Name, name) {
+			m.dappFormError = fmt.Sprintf("A dApp named %q already exists", d.Name)
+			return m, nil
+		}
+	}
+
+	dapp := config.DApp{
+		Name:        name,
+		Address:     common.HexToAddress(addr).Hex(),
+		Icon:        strings.TrimSpace(tempDappIcon),
+		ChainID:     chainID,
+		Description: strings.TrimSpace(tempDappDesc),
+		ABI:         abiRef,
+	}
+	if m.dappFormMode == "edit" && m.editingDappIdx >= 0 && m.editingDappIdx < len(m.dapps) {
+		m.dapps[m.editingDappIdx] = dapp
+		m.selectedDappIdx = m.editingDappIdx
+		m.logSuccess(fmt.Sprintf("Updated dApp `%s`", name))
+	} else {
+		m.dapps = append(m.dapps, dapp)
+		m.selectedDappIdx = len(m.dapps) - 1
+		m.logSuccess(fmt.Sprintf("Added dApp `%s` (%s)", name, helpers.ShortenAddr(dapp.Address)))
+	}
+	m.saveConfig()
+	m.closeDappForm()
+	return m, nil
+}
+
+func (m *model) handleDappFormMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if keyMsg.String() == "esc" {
+			m.closeDappForm()
+			return m, nil
+		}
+
+		// Same synchronous clipboard read as the watched-token form — see
+		// handleWatchedTokensFormMsg for why huh's own paste is bypassed.
+		if keyMsg.String() == "ctrl+v" && !m.dappFormButtonFocused {
+			if text, err := clipboard.ReadAll(); err == nil && text != "" {
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+			} else {
+				return m, nil
+			}
+		}
+
+		lastField := m.dappFormFields[len(m.dappFormFields)-1]
+
+		if m.dappFormButtonFocused {
+			switch keyMsg.String() {
+			case "enter", " ":
+				return m.submitDappForm()
+			case "tab":
+				m.dappFormButtonFocused = false
+				return m, focusHuhField(m.dappForm, m.dappFormFields, 0)
+			case "shift+tab":
+				m.dappFormButtonFocused = false
+				return m, focusHuhField(m.dappForm, m.dappFormFields, len(m.dappFormFields)-1)
+			}
+			return m, nil
+		}
+
+		if keyMsg.String() == "tab" && m.dappForm.GetFocusedField() == lastField {
+			m.dappFormButtonFocused = true
+			return m, lastField.Blur()
+		}
+	}
+
+	form, cmd := m.dappForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.dappForm = f
+
+		if m.dappForm.State == huh.StateCompleted {
+			m.dappForm.State = huh.StateNormal
+			return m.submitDappForm()
+		}
+		if m.dappForm.State == huh.StateAborted {
+			m.closeDappForm()
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+// confirmDeleteDappYes deletes the selected user-defined dApp.
+func (m *model) confirmDeleteDappYes() (tea.Model, tea.Cmd) {
+	if m.selectedDappIdx >= 0 && m.selectedDappIdx < len(m.dapps) && !m.dapps[m.selectedDappIdx].BuiltIn {
+		name := m.dapps[m.selectedDappIdx].Name
+		m.dapps = append(m.dapps[:m.selectedDappIdx], m.dapps[m.selectedDappIdx+1:]...)
+		if m.selectedDappIdx >= len(m.dapps) && m.selectedDappIdx > 0 {
+			m.selectedDappIdx--
+		}
+		m.saveConfig()
+		m.logWarn(fmt.Sprintf("Removed dApp `%s`", name))
+	}
+	m.activeDialog = dialogNone
+	return m, nil
+}
+
+func (m *model) confirmDeleteDappNo() (tea.Model, tea.Cmd) {
+	m.activeDialog = dialogNone
+	return m, nil
+}
+
+// customDapps returns the user-defined dApps, i.e. what is saved to the
+// config file.
+func (m *model) customDapps() []config.DApp {
+	var out []config.DApp
+	for _, d := range m.dapps {
+		if !d.BuiltIn {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// selectedDappModule returns the registered module behind the highlighted
+// dApp card, or nil for the Contract page and user-defined dApps.
+func (m *model) selectedDappModule() dapp.Module {
+	if m.selectedDappIdx < 0 || m.selectedDappIdx >= len(m.dapps) || !m.dapps[m.selectedDappIdx].BuiltIn {
+		return nil
+	}
+	return dapp.Lookup(m.dapps[m.selectedDappIdx].Name)
+}
+
+// selectedDappActions returns the names of the highlighted module's commands,
+// listed under its card description.
+func (m *model) selectedDappActions() []string {
+	mod := m.selectedDappModule()
+	if mod == nil {
+		return nil
+	}
+	var names []string
+	for _, c := range mod.Commands() {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// resolveDappABI turns a dApp's ABI reference into ABI JSON: a name in the
+// ABI library first, else anything loadABISource accepts.
+func (m *model) resolveDappABI(ref string) (string, error) {
+	if m.eventStore != nil {
+		if lib, err := m.eventStore.ABIs(); err == nil {
+			for _, e := range lib {
+				if e.Name == ref {
+					return e.ABI, nil
+				}
+			}
+		}
+	}
+	abiJSON, err := loadABISource(ref)
+	if err != nil {
+		return "", fmt.Errorf("ABI %q is not in the library: %w", ref, err)
+	}
+	return abiJSON, nil
+}
+
+// openCustomDapp opens a user-defined dApp on the Contract page, straight
+// into its function list when its ABI resolves, else in the open form
+// prefilled with its address.
+func (m *model) openCustomDapp(d config.DApp) tea.Cmd {
+	if d.ChainID != nil {
+		if active := m.chainID(); active != nil && active.Cmp(d.ChainID) != 0 {
+			m.logWarn(fmt.Sprintf("`%s` is deployed on %s, but the active RPC is on %s",
+				d.Name, helpers.ChainName(d.ChainID), helpers.ChainName(active)))
+			return nil
+		}
+	}
+
+	cmd := m.navigateTo(config.PageContract)
+	if d.ABI == "" {
+		m.createContractOpenForm(d.Name, d.Address, "")
+		return cmd
+	}
+	abiJSON, err := m.resolveDappABI(d.ABI)
+	if err == nil {
+		err = m.openContract(d.Name, common.HexToAddress(d.Address), abiJSON)
+	}
+	if err != nil {
+		m.createContractOpenForm(d.Name, d.Address, d.ABI)
+		m.contractFormError = err.Error()
+	}
+	return cmd
+}
+
 func (m *model) handleDappsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.activeDialog == dialogDeleteDapp {
+		switch msg.String() {
+		case "left", "right", "tab":
+			m.deleteDappDialogYesSelected = !m.deleteDappDialogYesSelected
+		case "enter":
+			if m.deleteDappDialogYesSelected {
+				return m.confirmDeleteDappYes()
+			}
+			return m.confirmDeleteDappNo()
+		case "esc":
+			m.activeDialog = dialogNone
+		}
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "esc":
 		return m, m.navigateTo(config.PageWallets)
 
 	case "enter":
-		if m.selectedDappIdx >= 0 && m.selectedDappIdx < len(m.dapps) &&
-			m.dapps[m.selectedDappIdx].Name == "Terra Nullius" {
-			return m, m.navigateTo(config.PageTerraNullius)
+		if m.selectedDappIdx >= 0 && m.selectedDappIdx < len(m.dapps) {
+			d := m.dapps[m.selectedDappIdx]
+			if !d.BuiltIn {
+				return m, m.openCustomDapp(d)
+			}
+			if mod := m.selectedDappModule(); mod != nil {
+				return m, m.openModule(mod)
+			}
+			if d.Name == "Contract" {
+				return m, m.navigateTo(config.PageContract)
+			}
+		}
+		return m, nil
+
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		mod := m.selectedDappModule()
+		if mod == nil {
+			return m, nil
+		}
+		cmds := mod.Commands()
+		n := int(msg.String()[0] - '1')
+		if n >= len(cmds) {
+			return m, nil
 		}
-		return m, m.navigateTo(config.PageUniswap)
+		cmd := m.openModule(mod)
+		return m, tea.Batch(cmd, cmds[n].Run(m.host()))
+
+	case "a", "A":
+		m.createDappForm(-1)
+		return m, nil
+
+	case "e", "E":
+		if m.selectedDappIdx >= 0 && m.selectedDappIdx < len(m.dapps) {
+			if m.dapps[m.selectedDappIdx].BuiltIn {
+				m.logWarn(fmt.Sprintf("`%s` is built in and cannot be edited", m.dapps[m.selectedDappIdx].Name))
+				return m, nil
+			}
+			m.createDappForm(m.selectedDappIdx)
+		}
+		return m, nil
+
+	case "delete", "backspace":
+		if m.selectedDappIdx >= 0 && m.selectedDappIdx < len(m.dapps) {
+			if m.dapps[m.selectedDappIdx].BuiltIn {
+				m.logWarn(fmt.Sprintf("`%s` is built in and cannot be deleted", m.dapps[m.selectedDappIdx].Name))
+				return m, nil
+			}
+			m.activeDialog = dialogDeleteDapp
+			m.deleteDappDialogYesSelected = true
+		}
+		return m, nil
 
 	case "tab", "down", "right":
 		if len(m.dapps) > 0 {
@@ -35,3 +387,13 @@ func (m *model) handleDappsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
+
+func (m *model) renderDappDeleteDialog() string {
+	name := ""
+	if m.selectedDappIdx >= 0 && m.selectedDappIdx < len(m.dapps) {
+		name = m.dapps[m.selectedDappIdx].Name
+	}
+	msg := helpers.FadeString("Are you sure you want to remove the dApp "+name+"?", "#F25D94", "#EDFF82")
+	return m.renderConfirmDialog("confirmDeleteDapp", msg, m.deleteDappDialogYesSelected,
+		(*model).confirmDeleteDappYes, (*model).confirmDeleteDappNo)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/explorer"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// explorerTimeout bounds one Block Explorer query; a busy block decodes
+// hundreds of receipts and token lookups.
+const explorerTimeout = 3 * time.Minute
+
+// openBlockExplorer switches to the Block Explorer page, remembering where
+// Esc returns to, and runs query when it is not empty.
+func (m *model) openBlockExplorer(query string) tea.Cmd {
+	// Going back to the explorer from an inspector it opened keeps the
+	// explorer's own way back, so Esc does not bounce between the two.
+	if m.activePage != config.PageBlockExplorer &&
+		(m.activePage != config.PageTxInspector || m.inspectorReturn != config.PageBlockExplorer) {
+		m.explorerReturn = m.activePage
+	}
+	cmd := m.navigateTo(config.PageBlockExplorer)
+	if query == "" {
+		return cmd
+	}
+	m.explorerInput = query
+	return tea.Batch(cmd, m.runExplorerQuery())
+}
+
+// runExplorerQuery decodes the query line off the UI goroutine, cancelling
+// any query still in flight.
+func (m *model) runExplorerQuery() tea.Cmd {
+	q, err := helpers.ParseExplorerQuery(m.explorerInput)
+	if err != nil {
+		m.explorerErr = err.Error()
+		return nil
+	}
+	if m.ethClient == nil {
+		m.explorerErr = "no RPC connection — configure an endpoint in Settings"
+		return nil
+	}
+	m.cancelExplorerQuery()
+	ctx, cancel := context.WithTimeout(context.Background(), explorerTimeout)
+	m.explorerCancel = cancel
+	m.explorerGen++
+	m.explorerQuery = q
+	m.explorerLoading = true
+	m.explorerEditing = false
+	m.explorerErr = ""
+	m.logInfo("Block Explorer: decoding " + q.String() + "…")
+
+	gen, rpcURL := m.explorerGen, m.rpcURL
+	ex := helpers.BlockExplorer{PoolLookup: storePoolLookup(m.eventStore)}
+	return func() tea.Msg {
+		defer cancel()
+		roots, err := ex.Explore(ctx, rpcURL, q)
+		return explorerResultMsg{gen: gen, query: q, roots: roots, err: err}
+	}
+}
+
+func (m *model) cancelExplorerQuery() {
+	if m.explorerCancel != nil {
+		m.explorerCancel()
+		m.explorerCancel = nil
+	}
+	m.explorerLoading = false
+}
+
+func (m *model) handleExplorerResult(msg explorerResultMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.explorerGen {
+		return m, nil
+	}
+	m.explorerLoading = false
+	m.explorerCancel = nil
+	if msg.err != nil {
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.explorerErr = msg.err.Error()
+		m.logError("Block Explorer: " + msg.err.Error())
+		return m, nil
+	}
+	m.explorerTree = resultTree{roots: msg.roots}
+	m.logSuccess("Block Explorer: decoded " + msg.query.String())
+	return m, nil
+}
+
+// explorerTreeHeight is how many tree rows fit on the Block Explorer or
+// Transaction Inspector page, leaving the log panel its share when it is
+// shown.
+func (m *model) explorerTreeHeight() int {
+	if m.logEnabled {
+		return helpers.Max(6, m.h/2-8)
+	}
+	return helpers.Max(6, m.h-14)
+}
+
+// resultTree is a foldable ExplorerNode tree with its selected row and
+// scroll offset, as the Block Explorer and Transaction Inspector pages show
+// it.
+type resultTree struct {
+	roots  []*helpers.ExplorerNode
+	idx    int
+	offset int
+}
+
+// move moves the selected row by delta and scrolls it into a window of
+// height rows.
+func (t *resultTree) move(delta, height int) {
+	rows := explorer.Rows(t.roots)
+	t.idx = helpers.Max(0, min(t.idx+delta, len(rows)-1))
+	if t.idx < t.offset {
+		t.offset = t.idx
+	}
+	if t.idx >= t.offset+height {
+		t.offset = t.idx - height + 1
+	}
+	t.offset = helpers.Max(0, min(t.offset, len(rows)-height))
+}
+
+// txAt returns the transaction the selected row belongs to: its own, or
+// its nearest ancestor's.
+func (t *resultTree) txAt() (common.Hash, bool) {
+	rows := explorer.Rows(t.roots)
+	for i := min(t.idx, len(rows)-1); i >= 0; i = rows[i].Parent {
+		if rows[i].Node.Tx != (common.Hash{}) {
+			return rows[i].Node.Tx, true
+		}
+	}
+	return common.Hash{}, false
+}
+
+// handleKey moves through and folds the tree, reporting whether key was a
+// navigation key.
+func (t *resultTree) handleKey(key string, height int) bool {
+	rows := explorer.Rows(t.roots)
+	if len(rows) == 0 {
+		return false
+	}
+	row := rows[helpers.Max(0, min(t.idx, len(rows)-1))]
+	switch key {
+	case "up", "k":
+		t.move(-1, height)
+	case "down", "j":
+		t.move(1, height)
+	case "pgup":
+		t.move(-height, height)
+	case "pgdown":
+		t.move(height, height)
+	case "home", "g":
+		t.move(-len(rows), height)
+	case "end", "G":
+		t.move(len(rows), height)
+	case "enter", " ":
+		if len(row.Node.Children) > 0 {
+			row.Node.Open = !row.Node.Open
+		}
+	case "right":
+		switch {
+		case len(row.Node.Children) > 0 && !row.Node.Open:
+			row.Node.Open = true
+		case len(row.Node.Children) > 0:
+			t.move(1, height)
+		}
+	case "left":
+		if len(row.Node.Children) > 0 && row.Node.Open {
+			row.Node.Open = false
+		} else if row.Parent >= 0 {
+			t.move(row.Parent-t.idx, height)
+		}
+	case "+", "=":
+		setExplorerOpen(t.roots, true)
+	case "-":
+		setExplorerOpen(t.roots, false)
+	default:
+		return false
+	}
+	// Folding changes the rows; keep the selection and window on them.
+	t.move(0, height)
+	return true
+}
+
+// setExplorerOpen opens or closes every node in the tree below nodes.
+func setExplorerOpen(nodes []*helpers.ExplorerNode, open bool) {
+	for _, n := range nodes {
+		if len(n.Children) > 0 {
+			n.Open = open
+			setExplorerOpen(n.Children, open)
+		}
+	}
+}
+
+func (m *model) handleExplorerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.explorerEditing {
+		switch msg.String() {
+		case "esc":
+			if m.explorerTree.roots == nil {
+				return m, m.navigateTo(m.explorerReturn)
+			}
+			m.explorerEditing = false
+			m.explorerErr = ""
+		case "enter":
+			return m, m.runExplorerQuery()
+		case "ctrl+u":
+			m.explorerInput = ""
+		case "backspace":
+			if r := []rune(m.explorerInput); len(r) > 0 {
+				m.explorerInput = string(r[:len(r)-1])
+			}
+		default:
+			if len(msg.Runes) > 0 {
+				m.explorerInput += string(msg.Runes)
+				m.explorerErr = ""
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		if m.explorerLoading {
+			m.cancelExplorerQuery()
+			m.explorerGen++
+			m.logInfo("Block Explorer: query cancelled")
+			return m, nil
+		}
+		return m, m.navigateTo(m.explorerReturn)
+	case "/":
+		m.explorerEditing = true
+		m.explorerErr = ""
+		return m, nil
+	case "r", "R":
+		if !m.explorerLoading && m.explorerInput != "" {
+			return m, m.runExplorerQuery()
+		}
+		return m, nil
+	}
+	if m.explorerLoading {
+		return m, nil
+	}
+	if msg.String() == "t" {
+		if hash, ok := m.explorerTree.txAt(); ok {
+			return m, m.openTxInspector(hash.Hex())
+		}
+		return m, nil
+	}
+	m.explorerTree.handleKey(msg.String(), m.explorerTreeHeight())
+	return m, nil
+}
+
+func (m *model) renderExplorerPage() (pageContent, nav string) {
+	t := m.explorerTree
+	c := explorer.Render(m.contentW-4, m.explorerTreeHeight(), m.explorerInput, m.explorerEditing,
+		m.explorerQuery.String(), explorer.Rows(t.roots), t.idx, t.offset, m.explorerLoading, m.explorerErr, m.spin.View())
+	return styles.PanelStyle.Width(m.contentW).Render(c), explorer.Nav(m.w-2, m.explorerEditing, m.txIndexerActive)
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"math/big"
 	"strings"
 	"time"
 
@@ -12,7 +11,6 @@ import (
 	"charm-wallet-tui/indexer"
 	"charm-wallet-tui/styles"
 	"charm-wallet-tui/views/txqr"
-	"charm-wallet-tui/views/uniswap"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -36,11 +34,12 @@ func (m *model) handleRPCConnected(msg rpcConnectedMsg) (tea.Model, tea.Cmd) {
 	} else {
 		m.ethClient = msg.client
 		m.rpcConnected = true
-		m.pairCache = make(map[string]pairCacheEntry) // chain may have changed
 		m.logSuccess(fmt.Sprintf("RPC connected to `%s`", msg.client.URL))
+		alertsCmd := m.startAlerts()
 		if m.activePage == config.PageWallets && m.detailsInWallets && len(m.accounts) > 0 {
-			return m, m.loadSelectedWalletDetails()
+			return m, tea.Batch(m.loadSelectedWalletDetails(), alertsCmd)
 		}
+		return m, alertsCmd
 	}
 	return m, nil
 }
@@ -104,7 +103,7 @@ func (m *model) handlePackageTransaction(msg packageTransactionMsg) (tea.Model,
 	}
 
 	if msg.approveQRData != "" {
-		// Two-step flow: approve (nonce N) then swap (nonce N+1).
+		// Two-step flow: approve then swap, at consecutive nonces.
 		m.txApproveQRFrames = renderFrames(msg.approveQRData)
 		m.txApproveJSON = msg.approveJSON
 		m.txSwapQRFrames = renderFrames(msg.qrData)
@@ -144,7 +143,11 @@ func (m *model) setTxViewportContent() {
 	stepStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)
 
 	var content string
-	if m.txApproveQRFrames != nil {
+	if m.safeSession != nil {
+		content = m.safeViewportContent()
+	} else if m.typedSign != nil {
+		content = m.typedSignViewportContent()
+	} else if m.txApproveQRFrames != nil {
 		if !m.txSwapStep {
 			content = stepStyle.Render("Step 1 of 2: Approve token spend") + "\n" +
 				warnStyle.Render("Sign and broadcast this transaction before the swap.") + "\n\n" +
@@ -178,32 +181,6 @@ func (m *model) handleQRAnimTick() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *model) handleTerraClaimsCount(msg terraNullClaimsCountMsg) (tea.Model, tea.Cmd) {
-	m.terraNullClaimsLoading = false
-	if msg.err != nil {
-		m.terraNullClaimsCount = "Error"
-		m.logError(fmt.Sprintf("Terra Nullius number_of_claims failed: %s", msg.err.Error()))
-	} else {
-		m.terraNullClaimsCount = msg.count.String()
-		m.logSuccess(fmt.Sprintf("Terra Nullius: %s total claims", msg.count.String()))
-	}
-	return m, nil
-}
-
-func (m *model) handleTerraClaimQuery(msg terraNullClaimQueryMsg) (tea.Model, tea.Cmd) {
-	m.terraNullClaimQuerying = false
-	if msg.err != nil {
-		m.terraNullClaimResultErr = msg.err.Error()
-		m.terraNullClaimResult = nil
-		m.logError(fmt.Sprintf("Terra Nullius claims query failed: %s", msg.err.Error()))
-	} else {
-		m.terraNullClaimResult = msg.result
-		m.terraNullClaimResultErr = ""
-		m.logSuccess(fmt.Sprintf("Terra Nullius claim #%s by %s", m.terraNullClaimInput, helpers.ShortenAddr(msg.result.Claimant)))
-	}
-	return m, nil
-}
-
 func (m *model) handlePoolEventLine(msg poolEventLineMsg) (tea.Model, tea.Cmd) {
 	if m.logBuffer != nil {
 		m.logBuffer.WriteString(msg.line + "\n")
@@ -211,7 +188,7 @@ func (m *model) handlePoolEventLine(msg poolEventLineMsg) (tea.Model, tea.Cmd) {
 			m.updateLogViewport()
 		}
 	}
-	if m.poolEventMonitorActive && m.poolEventMonitor != nil {
+	if m.poolEventMonitorActive && msg.monitor == m.poolEventMonitor {
 		return m, waitForPoolEvent(m.poolEventMonitor)
 	}
 	return m, nil
@@ -224,8 +201,8 @@ func (m *model) handlePoolMonitorEvent(msg poolMonitorEventMsg) (tea.Model, tea.
 			m.logWarn(fmt.Sprintf("[pool-monitor] db write error: %s", err.Error()))
 		}
 	}
-	var cmds []tea.Cmd
-	if m.poolEventMonitorActive && m.poolEventMonitor != nil {
+	cmds := []tea.Cmd{m.checkSwapAlerts(ev)}
+	if m.poolEventMonitorActive && msg.monitor == m.poolEventMonitor {
 		cmds = append(cmds, waitForPoolEventData(m.poolEventMonitor))
 	}
 	if m.eventStore != nil && ev.Kind == indexer.V4KindInitialize {
@@ -236,12 +213,16 @@ func (m *model) handlePoolMonitorEvent(msg poolMonitorEventMsg) (tea.Model, tea.
 }
 
 func (m *model) handleV4PoolTable(msg v4PoolTableMsg) (tea.Model, tea.Cmd) {
-	m.v4PoolRows = msg.rows
-	m.v4EventsViewport.SetContent(uniswap.V4EventsContent(m.w-2, msg.rows))
+	m.v4PoolRows = m.filterV4PoolRows(msg.rows)
+	m.refreshV4EventsContent()
 	return m, nil
 }
 
-func (m *model) handlePoolMonitorStopped() (tea.Model, tea.Cmd) {
+func (m *model) handlePoolMonitorStopped(msg poolEventMonitorStoppedMsg) (tea.Model, tea.Cmd) {
+	if msg.monitor != m.poolEventMonitor {
+		// A monitor replaced by a restart; the new one is still running.
+		return m, nil
+	}
 	wasActive := m.poolEventMonitorActive
 	m.poolEventMonitorActive = false
 	m.poolEventMonitor = nil
@@ -251,25 +232,6 @@ func (m *model) handlePoolMonitorStopped() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *model) handleV4BlockScanLine(msg v4BlockScanLineMsg) (tea.Model, tea.Cmd) {
-	if m.logBuffer != nil {
-		m.logBuffer.WriteString(msg.line + "\n")
-		if m.logReady {
-			m.updateLogViewport()
-		}
-	}
-	if m.v4BlockScanActive && m.v4BlockScanner != nil {
-		return m, waitForV4BlockScanLine(m.v4BlockScanner)
-	}
-	return m, nil
-}
-
-func (m *model) handleV4BlockScanDone() (tea.Model, tea.Cmd) {
-	m.v4BlockScanActive = false
-	m.v4BlockScanner = nil
-	return m, nil
-}
-
 func (m *model) handleIndexedEvent(msg indexedEventMsg) (tea.Model, tea.Cmd) {
 	ev := msg.event
 	if m.eventStore != nil {
@@ -279,10 +241,11 @@ func (m *model) handleIndexedEvent(msg indexedEventMsg) (tea.Model, tea.Cmd) {
 	}
 	m.logInfo("[indexer] transfer detected")
 	m.logIndexedEvent(ev)
+	alertsCmd := m.checkTransferAlerts(ev)
 	if m.txIndexerActive && m.txIndexer != nil {
-		return m, waitForIndexedEvent(m.txIndexer)
+		return m, tea.Batch(alertsCmd, waitForIndexedEvent(m.txIndexer))
 	}
-	return m, nil
+	return m, alertsCmd
 }
 
 func (m *model) handleIndexerStopped() (tea.Model, tea.Cmd) {
@@ -303,7 +266,7 @@ func (m *model) handleV4PoolEvent(msg v4PoolEventMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 	m.logV4PoolEvent(ev)
-	var cmds []tea.Cmd
+	cmds := []tea.Cmd{m.checkSwapAlerts(ev)}
 	if m.txIndexerActive && m.txIndexer != nil {
 		cmds = append(cmds, waitForV4PoolEvent(m.txIndexer))
 	}
@@ -377,99 +340,36 @@ func (m *model) handlePoolKeyResult(msg poolKeyResultMsg) (tea.Model, tea.Cmd) {
 	m.poolInfoKeyErr = ""
 	m.logSuccess(fmt.Sprintf("Pool Info: pool key for %s — currency0=%s currency1=%s fee=%d",
 		shortID, msg.key.Currency0, msg.key.Currency1, msg.key.Fee))
-	return m, nil
-}
-
-func (m *model) handleUniswapQuote(msg uniswapQuoteMsg) (tea.Model, tea.Cmd) {
-	m.uniswapEstimating = false
-	if msg.err != nil {
-		m.uniswapQuoteError = msg.err.Error()
-		m.uniswapQuote = nil
-		m.uniswapToAmount = ""
-		m.uniswapFromAmount = ""
-		m.uniswapPriceImpactWarn = ""
-		m.uniswapHookWarn = ""
-		m.logError(fmt.Sprintf("Swap quote error: %v", msg.err))
+	hook := common.HexToAddress(msg.key.Hooks)
+	if hook == (common.Address{}) {
 		return m, nil
 	}
-	m.uniswapQuoteError = ""
-	m.uniswapQuote = msg.quote
-	m.uniswapPriceImpactWarn = ""
-	m.uniswapHookWarn = ""
+	m.poolInfoHook = nil
+	m.poolInfoHookLoading = true
+	m.poolInfoHookErr = ""
+	m.logInfo(fmt.Sprintf("Pool Info: inspecting hook %s", helpers.ShortenAddr(hook.Hex())))
+	return m, inspectPoolHook(m.rpcURL, msg.poolID, hook, msg.key.Fee)
+}
 
-	if msg.quote == nil {
+func (m *model) handlePoolHookReport(msg poolHookReportMsg) (tea.Model, tea.Cmd) {
+	if msg.poolID != m.poolInfoID {
 		return m, nil
 	}
-
-	tokens := m.buildTokenList()
-	fromToken := tokens[m.uniswapFromTokenIdx]
-	toToken := tokens[m.uniswapToTokenIdx]
-	isReverseQuote := m.uniswapFromAmount == "" && m.uniswapToAmount != ""
-
-	version := "V2"
-	if msg.quote.IsV3 {
-		version = "V3"
-	}
-	if msg.quote.IsV4 {
-		version = "V4"
-	}
-
-	if isReverseQuote {
-		divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromToken.Decimals)), nil))
-		m.uniswapFromAmount = new(big.Float).Quo(new(big.Float).SetInt(msg.quote.AmountIn), divisor).Text('f', 6)
-		m.uniswapEditingFrom = false
-		m.logInfo(fmt.Sprintf("📊 %s Reverse Quote: %s → %s", version, fromToken.Symbol, toToken.Symbol))
-		m.logInfo(fmt.Sprintf("  Amount In: %s %s", m.uniswapFromAmount, fromToken.Symbol))
-		m.logInfo(fmt.Sprintf("  Amount Out: %s %s", m.uniswapToAmount, toToken.Symbol))
-	} else {
-		m.logInfo(fmt.Sprintf("📊 %s Swap Quote: %s → %s", version, fromToken.Symbol, toToken.Symbol))
-		m.logInfo(fmt.Sprintf("  Amount In: %s %s", m.uniswapFromAmount, fromToken.Symbol))
-		divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(toToken.Decimals)), nil))
-		m.uniswapToAmount = new(big.Float).Quo(new(big.Float).SetInt(msg.quote.AmountOut), divisor).Text('f', 6)
-		m.logInfo(fmt.Sprintf("  Amount Out: %s %s", m.uniswapToAmount, toToken.Symbol))
-	}
-
-	m.logInfo(fmt.Sprintf("  Price Impact: %.4f%%", msg.quote.PriceImpact))
-	if !msg.quote.IsV3 && msg.quote.Token0Reserve != nil && msg.quote.Token1Reserve != nil {
-		divisor18 := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-		r0 := new(big.Float).Quo(new(big.Float).SetInt(msg.quote.Token0Reserve), divisor18)
-		r1 := new(big.Float).Quo(new(big.Float).SetInt(msg.quote.Token1Reserve), divisor18)
-		m.logInfo(fmt.Sprintf("  Reserves: %s / %s", r0.Text('f', 2), r1.Text('f', 2)))
-	}
-
-	if msg.quote.PriceImpact > 1.0 {
-		m.uniswapPriceImpactWarn = fmt.Sprintf("⚠ High price impact: %.2f%%", msg.quote.PriceImpact)
-		m.logWarn(m.uniswapPriceImpactWarn)
-	} else if msg.quote.PriceImpact > 0.5 {
-		m.uniswapPriceImpactWarn = fmt.Sprintf("⚠ Moderate price impact: %.2f%%", msg.quote.PriceImpact)
-	}
-
-	if msg.quote.HookAddr != (common.Address{}) {
-		m.uniswapHookWarn = fmt.Sprintf("⚠ Hook-gated pool (%s) — quoting or swapping may fail if you're not allowlisted (e.g. KYC/geo restrictions)", helpers.ShortenAddr(msg.quote.HookAddr.Hex()))
-		m.logWarn(m.uniswapHookWarn)
-	}
-	return m, nil
-}
-
-func (m *model) handleLiquidityPositions(msg liquidityPositionsMsg) (tea.Model, tea.Cmd) {
-	m.liquidityLoading = false
+	m.poolInfoHookLoading = false
 	if msg.err != nil {
-		m.liquidityErr = msg.err.Error()
-		m.logError("Liquidity positions error: " + msg.err.Error())
-		for _, d := range msg.diagnostics {
-			m.logInfo("  " + d)
-		}
-	} else {
-		m.liquidityPositions = msg.positions
-		m.logInfo(fmt.Sprintf("V4 PositionManager: balanceOf=%d NFT(s)", msg.nftCount))
-		for _, d := range msg.diagnostics {
-			m.logInfo("  " + d)
-		}
-		if len(msg.positions) == 0 {
-			m.logInfo("No active V4 liquidity positions found")
-		} else {
-			m.logInfo(fmt.Sprintf("%d position(s) with active liquidity", len(msg.positions)))
-		}
+		m.poolInfoHookErr = msg.err.Error()
+		m.logWarn(fmt.Sprintf("Pool Info: hook inspection failed for pool %s: %s", shortPoolID(msg.poolID), msg.err.Error()))
+		return m, nil
+	}
+	m.poolInfoHook = msg.report
+	m.poolInfoHookErr = ""
+	switch msg.report.Risk {
+	case helpers.HookRiskHigh:
+		m.logError("Pool Info: " + msg.report.Summary())
+	case helpers.HookRiskMedium:
+		m.logWarn("Pool Info: " + msg.report.Summary())
+	default:
+		m.logInfo("Pool Info: " + msg.report.Summary())
 	}
 	return m, nil
 }
@@ -479,6 +379,9 @@ func (m *model) handleENSLookupResult(msg ensLookupResultMsg) (tea.Model, tea.Cm
 	if msg.debugInfo != "" {
 		m.logInfo(fmt.Sprintf("ENS debug: %s", msg.debugInfo))
 	}
+	if msg.err == nil && msg.ensName != "" {
+		m.syncAddressLabels()
+	}
 	if msg.err == nil && msg.ensName != "" && msg.address == m.ensLookupAddr {
 		if strings.TrimSpace(m.nicknameInput.Value()) == "" {
 			m.nicknameInput.SetValue(msg.ensName)
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/outbox"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// openOutboxDialog shows the active wallet's queued transactions and kicks
+// off a refresh against the chain's pending nonce.
+func (m *model) openOutboxDialog() (tea.Model, tea.Cmd) {
+	if m.activeAddress == "" {
+		m.logWarn("Outbox: no active wallet")
+		return m, nil
+	}
+	m.activeDialog = dialogOutbox
+	m.outboxIdx = 0
+	m.outboxErr = ""
+	m.outboxLoading = true
+	return m, loadOutbox(m.ethClient, m.eventStore, m.activeAddress)
+}
+
+func (m *model) handleOutboxLoaded(msg outboxLoadedMsg) (tea.Model, tea.Cmd) {
+	m.outboxLoading = false
+	if msg.err != nil {
+		m.outboxErr = msg.err.Error()
+		m.logError("Outbox refresh failed: " + msg.err.Error())
+		return m, nil
+	}
+	m.outboxErr = ""
+	m.outboxEntries = msg.entries
+	m.outboxPending = msg.pending
+	m.outboxGaps = msg.gaps
+	if m.outboxIdx >= len(m.outboxEntries) {
+		m.outboxIdx = len(m.outboxEntries) - 1
+	}
+	if m.outboxIdx < 0 {
+		m.outboxIdx = 0
+	}
+	if len(m.outboxGaps) > 0 {
+		m.logWarn(fmt.Sprintf("Outbox: nonce gap at %v — later queued txs will not be mined until it is filled", m.outboxGaps))
+	}
+	return m, nil
+}
+
+// showOutboxEntry re-displays a queued tx's QR in dialogTxResult, reusing the
+// same path a fresh packaging result takes. With resign set it goes straight
+// on to the webcam scan, for when the signer's reply was lost.
+func (m *model) showOutboxEntry(r store.NonceReservation, resign bool) (tea.Model, tea.Cmd) {
+	if r.Status == store.NonceStale {
+		m.logWarn(fmt.Sprintf("Nonce %d was already used by another transaction — this package can no longer be mined", r.Nonce))
+	}
+	m.activeDialog = dialogTxResult
+	m.txResultError = ""
+	_, animCmd := m.handlePackageTransaction(packageTransactionMsg{
+		txDisplay: r.Summary,
+		txJSON:    r.TxJSON,
+		qrData:    r.UR,
+		format:    "EIP-4527",
+	})
+	if !resign {
+		return m, animCmd
+	}
+	_, scanCmd := m.openScanTxDialog()
+	return m, tea.Batch(animCmd, scanCmd)
+}
+
+func (m *model) handleOutboxKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.activeDialog = dialogNone
+		return m, nil
+	case "up", "k":
+		if m.outboxIdx > 0 {
+			m.outboxIdx--
+		}
+	case "down", "j":
+		if m.outboxIdx < len(m.outboxEntries)-1 {
+			m.outboxIdx++
+		}
+	case "r", "R":
+		m.outboxLoading = true
+		return m, loadOutbox(m.ethClient, m.eventStore, m.activeAddress)
+	case "enter", "s", "S":
+		if m.outboxLoading || m.outboxIdx >= len(m.outboxEntries) {
+			return m, nil
+		}
+		return m.showOutboxEntry(m.outboxEntries[m.outboxIdx], msg.String() != "enter")
+	case "d", "D", "delete", "backspace":
+		if m.outboxLoading || m.outboxIdx >= len(m.outboxEntries) || m.eventStore == nil {
+			return m, nil
+		}
+		r := m.outboxEntries[m.outboxIdx]
+		if r.Status == store.NonceBroadcast {
+			m.logWarn(fmt.Sprintf("Nonce %d was already broadcast and cannot be discarded", r.Nonce))
+			return m, nil
+		}
+		if err := m.eventStore.SetNonceStatus(r.ID, store.NonceDiscarded); err != nil {
+			m.logError("Discard failed: " + err.Error())
+			return m, nil
+		}
+		m.logInfo(fmt.Sprintf("Discarded queued tx at nonce %d", r.Nonce))
+		m.outboxLoading = true
+		return m, loadOutbox(m.ethClient, m.eventStore, m.activeAddress)
+	}
+	return m, nil
+}
+
+func (m *model) renderOutboxPopup() string {
+	dialogBoxStyle := styles.DialogBox.Background(styles.CPanel).Width(outbox.Width + 4)
+	content := outbox.Render(m.outboxEntries, m.outboxIdx, m.outboxPending, m.outboxGaps,
+		m.outboxLoading, m.outboxErr, m.spin.View())
+	dialog := dialogBoxStyle.Render(content)
+	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, dialog)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/atotto/clipboard"
 
+	"charm-wallet-tui/config"
 	"charm-wallet-tui/helpers"
 	"charm-wallet-tui/rpc"
 	"charm-wallet-tui/styles"
@@ -15,6 +16,7 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // pasteSignedTxDialogWidth is the fixed width of the paste-tx popup across all phases.
@@ -55,7 +57,7 @@ func (m *model) createPasteSignedTxForm(initial string) tea.Cmd {
 
 // formatSignedTxPreview renders the live "JSON and human readable tx" preview
 // shown above the paste input as the user types or pastes.
-func formatSignedTxPreview(rawHex string) string {
+func (m *model) formatSignedTxPreview(rawHex string) string {
 	muteStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
 
 	trimmed := strings.TrimSpace(rawHex)
@@ -84,19 +86,41 @@ func formatSignedTxPreview(rawHex string) string {
 		gasRow = row("Gas", fmt.Sprintf("%d limit, max %s / priority %s", decoded.Gas, decoded.MaxFeeHuman, decoded.PriorityFeeHuman))
 	}
 
-	summary := strings.Join([]string{
+	rows := []string{
 		row("Hash", decoded.Hash),
-		row("From", decoded.From),
-		row("To", to),
+		row("From", helpers.AddrWithLabel(decoded.From)),
+		row("To", helpers.AddrWithLabel(to)),
+	}
+	if decoded.TransferTo != "" {
+		rows = append(rows, row("Recipient", helpers.AddrWithLabel(decoded.TransferTo)))
+	}
+	rows = append(rows,
 		row("Value", decoded.ValueHuman),
 		row("Nonce", fmt.Sprintf("%d", decoded.Nonce)),
 		gasRow,
 		row("Chain ID", decoded.ChainID.String()),
-	}, "\n")
+	)
+	if warning := m.recipientWarning(signedTxRecipient(decoded)); warning != "" {
+		warnStyle := lipgloss.NewStyle().Foreground(styles.CError).Bold(true)
+		rows = append(rows, "", warnStyle.Render("⚠ "+warning))
+		if m.pasteTxWarnAck == strings.TrimSpace(rawHex) {
+			rows = append(rows, warnStyle.Render("Submit again to broadcast anyway."))
+		}
+	}
+	summary := strings.Join(rows, "\n")
 
 	return summary + "\n\n" + labelStyle.Render("JSON:") + "\n" + muteStyle.Render(decoded.JSON)
 }
 
+// signedTxRecipient is where a signed tx sends funds: the transfer()
+// recipient for an ERC-20 send, the To address otherwise.
+func signedTxRecipient(decoded rpc.DecodedSignedTx) string {
+	if decoded.TransferTo != "" {
+		return decoded.TransferTo
+	}
+	return decoded.To
+}
+
 // activeRPCLabel returns the friendly name of the active RPC endpoint,
 // falling back to its URL when no name is set.
 func (m *model) activeRPCLabel() string {
@@ -148,6 +172,8 @@ func (m *model) openPasteSignedTxDialogWithHex(initial string) (tea.Model, tea.C
 	m.pasteTxOnChainInfo = nil
 	m.pasteTxChainID = nil
 	m.pasteTxHashLineY, m.pasteTxHashLineX1, m.pasteTxHashLineX2 = 0, 0, 0
+	m.pasteTxWarnAck = ""
+	m.loadRecipientChecks()
 	cmds = append(cmds, m.createPasteSignedTxForm(initial))
 
 	return m, tea.Batch(cmds...)
@@ -167,6 +193,10 @@ func (m *model) closePasteSignedTxDialog() (tea.Model, tea.Cmd) {
 	m.pasteTxPollErr = ""
 	m.pasteTxOnChainInfo = nil
 	tempPasteSignedTxHex = ""
+	if m.activePage == config.PageTransactions {
+		m.txHistoryLoading = true
+		return m, loadTxHistory(m.ethClient, m.eventStore)
+	}
 	return m, nil
 }
 
@@ -186,6 +216,10 @@ func (m *model) handlePasteSignedTxMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.pasteTxHash = msg.txHash
 		m.pasteTxPhase = pasteTxPhasePolling
+		if m.eventStore != nil && m.pasteTxChainID != nil && m.pasteTxFrom != "" {
+			_ = m.eventStore.MarkNonceBroadcast(m.pasteTxChainID.Uint64(), common.HexToAddress(m.pasteTxFrom), m.pasteTxNonce)
+			_ = m.eventStore.RecordTxBroadcast(m.pasteTxChainID.Uint64(), common.HexToAddress(m.pasteTxFrom), m.pasteTxNonce, m.pasteTxRaw, msg.txHash)
+		}
 		m.pasteTxCountdown = 30
 		m.logSuccess("Broadcast signed transaction — hash " + msg.txHash)
 		return m, tea.Batch(
@@ -206,6 +240,7 @@ func (m *model) handlePasteSignedTxMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.found && msg.info != nil {
 			m.pasteTxOnChainInfo = msg.info
 			m.pasteTxPhase = pasteTxPhaseResult
+			m.recordTxOnChain(msg.info)
 			m.logSuccess(fmt.Sprintf("Transaction confirmed in block %d (%s)", msg.info.BlockNumber, msg.info.Status))
 		}
 		return m, nil
@@ -247,7 +282,20 @@ func (m *model) submitPasteTxIfValid() (tea.Model, tea.Cmd, bool) {
 	if err != nil {
 		return m, nil, false
 	}
+	// A flagged recipient needs a second submit; the preview shows why.
+	recipient := signedTxRecipient(decoded)
+	if warning := m.recipientWarning(recipient); warning != "" && m.pasteTxWarnAck != raw {
+		m.pasteTxWarnAck = raw
+		m.logWarn(fmt.Sprintf("Recipient %s: %s", helpers.LabelAddr(recipient), warning))
+		if m.pasteTxForm != nil {
+			m.pasteTxForm.State = huh.StateNormal
+		}
+		return m, nil, true
+	}
 	m.pasteTxChainID = decoded.ChainID
+	m.pasteTxFrom = decoded.From
+	m.pasteTxNonce = decoded.Nonce
+	m.pasteTxRaw = raw
 	m.pasteTxForm = nil
 	m.pasteTxPhase = pasteTxPhaseSending
 	m.logInfo("Broadcasting pasted signed transaction…")
@@ -339,7 +387,14 @@ func (m *model) handlePasteSignedTxKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case pasteTxPhaseResult:
+		var hash string
+		if m.pasteTxOnChainInfo != nil {
+			hash = m.pasteTxOnChainInfo.Hash
+		}
 		_, closeCmd := m.closePasteSignedTxDialog() // "press any key to return"
+		if msg.String() == "t" && hash != "" {
+			return m, tea.Batch(closeCmd, m.loadSelectedWalletDetailsFresh(), m.openTxInspector(hash))
+		}
 		return m, tea.Batch(closeCmd, m.loadSelectedWalletDetailsFresh())
 	}
 	return m, nil
@@ -393,7 +448,7 @@ func (m *model) renderPasteTxFormPhase() string {
 	// dump line is otherwise unwrapped and can render far wider than the
 	// dialog once a valid tx is pasted, which throws off every width-based
 	// offset computed below (button centering, hit-test geometry).
-	preview := lipgloss.NewStyle().Width(pasteSignedTxDialogWidth - 4).Render(formatSignedTxPreview(tempPasteSignedTxHex))
+	preview := lipgloss.NewStyle().Width(pasteSignedTxDialogWidth - 4).Render(m.formatSignedTxPreview(tempPasteSignedTxHex))
 	body := lipgloss.JoinVertical(lipgloss.Left,
 		title,
 		"",
@@ -520,15 +575,15 @@ func (m *model) renderPasteTxResultPhase() string {
 		row("Hash", info.Hash, valueStyle),
 		row("Block", fmt.Sprintf("%d  (%s)", info.BlockNumber, info.BlockHash), valueStyle),
 		row("Confirmations", fmt.Sprintf("%d", info.Confirmations), valueStyle),
-		row("From", info.From, valueStyle),
-		row("To", to, valueStyle),
+		row("From", helpers.AddrWithLabel(info.From), valueStyle),
+		row("To", helpers.AddrWithLabel(to), valueStyle),
 		row("Value", info.ValueHuman, valueStyle),
 		row("Nonce", fmt.Sprintf("%d", info.Nonce), valueStyle),
 		row("Gas Used", fmt.Sprintf("%d", info.GasUsed), valueStyle),
 		row("Effective Gas Price", info.EffectiveGasPrice, valueStyle),
 		row("Tx Index", fmt.Sprintf("%d", info.TransactionIndex), valueStyle),
 		"",
-		lipgloss.NewStyle().Foreground(styles.CSubtle).Align(lipgloss.Center).Width(pasteSignedTxDialogWidth-4).Render("Press any key to return"),
+		lipgloss.NewStyle().Foreground(styles.CSubtle).Align(lipgloss.Center).Width(pasteSignedTxDialogWidth-4).Render("Press t to inspect, any other key to return"),
 	)
 
 	content := lipgloss.JoinVertical(lipgloss.Left, rows...)
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// openPoolFilterDialog shows the pool event filter (dialogPoolFilter) with
+// the filter in force ready to edit.
+func (m *model) openPoolFilterDialog() (tea.Model, tea.Cmd) {
+	m.activeDialog = dialogPoolFilter
+	m.poolFilterInput = m.poolFilterExpr
+	m.poolFilterName = ""
+	m.poolFilterNaming = false
+	m.poolFilterErr = ""
+	if m.poolFilterIdx >= len(m.poolFilterPresets) {
+		m.poolFilterIdx = 0
+	}
+	return m, nil
+}
+
+func (m *model) handlePoolFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.poolFilterNaming {
+		return m.handlePoolFilterNameKey(msg)
+	}
+	switch msg.String() {
+	case "esc":
+		m.activeDialog = dialogNone
+		return m, nil
+	case "enter":
+		return m, m.applyPoolFilter(m.poolFilterInput)
+	case "up":
+		if m.poolFilterIdx > 0 {
+			m.poolFilterIdx--
+		}
+		return m, nil
+	case "down":
+		if m.poolFilterIdx < len(m.poolFilterPresets)-1 {
+			m.poolFilterIdx++
+		}
+		return m, nil
+	case "tab":
+		if m.poolFilterIdx < len(m.poolFilterPresets) {
+			m.poolFilterInput = m.poolFilterPresets[m.poolFilterIdx].Filter
+			m.poolFilterErr = ""
+		}
+		return m, nil
+	case "ctrl+s":
+		if _, err := helpers.ParsePoolEventFilter(m.poolFilterInput); err != nil {
+			m.poolFilterErr = err.Error()
+			return m, nil
+		}
+		if strings.TrimSpace(m.poolFilterInput) == "" {
+			m.poolFilterErr = "Nothing to save: the filter is empty"
+			return m, nil
+		}
+		m.poolFilterNaming = true
+		m.poolFilterName = ""
+		m.poolFilterErr = ""
+		return m, nil
+	case "ctrl+d":
+		if m.poolFilterIdx < len(m.poolFilterPresets) {
+			name := m.poolFilterPresets[m.poolFilterIdx].Name
+			m.poolFilterPresets = append(m.poolFilterPresets[:m.poolFilterIdx], m.poolFilterPresets[m.poolFilterIdx+1:]...)
+			if m.poolFilterIdx > 0 && m.poolFilterIdx >= len(m.poolFilterPresets) {
+				m.poolFilterIdx--
+			}
+			m.saveConfig()
+			m.logInfo(fmt.Sprintf("Deleted pool filter preset %q", name))
+		}
+		return m, nil
+	case "backspace":
+		if len(m.poolFilterInput) > 0 {
+			m.poolFilterInput = m.poolFilterInput[:len(m.poolFilterInput)-1]
+		}
+		return m, nil
+	}
+	if len(msg.Runes) > 0 {
+		m.poolFilterInput += string(msg.Runes)
+		m.poolFilterErr = ""
+	}
+	return m, nil
+}
+
+// handlePoolFilterNameKey takes a name for the expression being saved as a
+// preset; a preset with the same name is overwritten.
+func (m *model) handlePoolFilterNameKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.poolFilterNaming = false
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.poolFilterName)
+		if name == "" {
+			return m, nil
+		}
+		preset := config.PoolMonitorPreset{Name: name, Filter: strings.TrimSpace(m.poolFilterInput)}
+		m.poolFilterIdx = len(m.poolFilterPresets)
+		for i, p := range m.poolFilterPresets {
+			if strings.EqualFold(p.Name, name) {
+				m.poolFilterIdx = i
+			}
+		}
+		if m.poolFilterIdx < len(m.poolFilterPresets) {
+			m.poolFilterPresets[m.poolFilterIdx] = preset
+		} else {
+			m.poolFilterPresets = append(m.poolFilterPresets, preset)
+		}
+		m.poolFilterNaming = false
+		m.saveConfig()
+		m.logSuccess(fmt.Sprintf("Saved pool filter preset %q", name))
+		return m, nil
+	case "backspace":
+		if len(m.poolFilterName) > 0 {
+			m.poolFilterName = m.poolFilterName[:len(m.poolFilterName)-1]
+		}
+		return m, nil
+	}
+	if len(msg.Runes) > 0 {
+		m.poolFilterName += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// applyPoolFilter puts expr in force: the panel is re-filtered at once and
+// a running monitor is restarted so its subscription uses the new topics.
+func (m *model) applyPoolFilter(expr string) tea.Cmd {
+	f, err := helpers.ParsePoolEventFilter(expr)
+	if err != nil {
+		m.poolFilterErr = err.Error()
+		return nil
+	}
+	m.poolFilter = f
+	m.poolFilterExpr = strings.TrimSpace(expr)
+	m.activeDialog = dialogNone
+	if f.Empty() {
+		m.logInfo("Pool event filter cleared")
+	} else {
+		m.logInfo("Pool event filter: " + f.String())
+	}
+
+	var cmds []tea.Cmd
+	if m.eventStore != nil {
+		cmds = append(cmds, loadV4PoolTableCmd(m.eventStore))
+	}
+	if m.poolEventMonitorActive {
+		m.poolEventMonitor.Stop()
+		cmds = append(cmds, m.startPoolMonitor())
+	}
+	return tea.Batch(cmds...)
+}
+
+// filterV4PoolRows keeps the panel rows whose pool passes the filter's
+// pool and token terms.
+func (m *model) filterV4PoolRows(rows []store.PoolRow) []store.PoolRow {
+	if m.poolFilter.Empty() {
+		return rows
+	}
+	var out []store.PoolRow
+	for _, r := range rows {
+		if m.poolFilter.MatchPool(common.HexToHash(r.PoolID),
+			common.HexToAddress(r.Currency0), common.HexToAddress(r.Currency1), r.Token0Sym, r.Token1Sym) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// storePoolLookup resolves pool keys from the event store, so the monitor
+// can apply token filters to pools initialized before it started.
+func storePoolLookup(s *store.Store) func(common.Hash) (helpers.V4PoolKey, bool) {
+	if s == nil {
+		return nil
+	}
+	return func(id common.Hash) (helpers.V4PoolKey, bool) {
+		meta, ok, err := s.V4Pool(id.Hex())
+		if err != nil || !ok {
+			return helpers.V4PoolKey{}, false
+		}
+		return helpers.V4PoolKey{
+			Currency0:   common.HexToAddress(meta.Currency0),
+			Currency1:   common.HexToAddress(meta.Currency1),
+			Hooks:       common.HexToAddress(meta.Hooks),
+			Fee:         uint32(meta.Fee),
+			TickSpacing: int32(meta.TickSpacing),
+		}, true
+	}
+}
+
+func (m *model) renderPoolFilterPopup() string {
+	dialogBoxStyle := styles.DialogBox.Background(styles.CPanel).Width(uniswap.PoolFilterWidth + 4)
+	content := uniswap.RenderPoolFilter(m.poolFilterInput, m.poolFilter.String(), m.poolFilterName,
+		m.poolFilterNaming, m.poolFilterErr, m.poolFilterPresets, m.poolFilterIdx)
+	dialog := dialogBoxStyle.Render(content)
+	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, dialog)
+}
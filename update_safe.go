@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// safeSession is a Safe transaction being signed: the SafeTx built from a
+// send/swap flow, its EIP-712 hash and the owner signatures scanned so far.
+// It lives in dialogTxResult, which shows the typed-data QR for one owner
+// at a time; Tab moves to the next owner still to sign.
+type safeSession struct {
+	info     *rpc.SafeInfo
+	chainID  *big.Int
+	tx       rpc.SafeTx
+	hash     common.Hash
+	summary  string
+	sigs     map[common.Address][65]byte
+	signer   int               // index in info.Owners the current QR is addressed to
+	requests map[[16]byte]bool // request-ids of every eth-sign-request shown so far
+}
+
+// ownsRequest reports whether reqID came from one of this session's sign
+// requests. Earlier owners' QRs stay valid after Tab moves to the next one.
+func (s *safeSession) ownsRequest(reqID [16]byte) bool {
+	return s.requests[reqID]
+}
+
+// handleSafeProposal wraps the calls a send/swap flow built for a Safe into
+// one SafeTx at the Safe's current nonce and shows the first owner's
+// signing QR.
+func (m *model) handleSafeProposal(msg safeProposalMsg) (tea.Model, tea.Cmd) {
+	m.txResultPackaging = false
+	tx, err := rpc.BuildSafeTx(msg.calls, msg.info.Nonce)
+	if err != nil {
+		m.txResultError = err.Error()
+		return m, nil
+	}
+	hash, err := rpc.SafeTxHash(msg.chainID, msg.info.Address, msg.info.Version, tx)
+	if err != nil {
+		m.txResultError = "safeTxHash: " + err.Error()
+		return m, nil
+	}
+
+	s := &safeSession{
+		info:     msg.info,
+		chainID:  msg.chainID,
+		tx:       tx,
+		hash:     hash,
+		summary:  msg.summary,
+		sigs:     map[common.Address][65]byte{},
+		requests: map[[16]byte]bool{},
+	}
+	// Start with an owner this wallet knows about, if any.
+	for i, o := range msg.info.Owners {
+		if m.isOwnWallet(o) {
+			s.signer = i
+			break
+		}
+	}
+	m.safeSession = s
+	m.logInfo(fmt.Sprintf("Safe %s: collecting %d of %d owner signatures for nonce %d",
+		helpers.LabelAddr(msg.info.Address.Hex()), msg.info.Threshold, len(msg.info.Owners), tx.Nonce))
+	return m, m.showSafeSignRequest()
+}
+
+// showSafeSignRequest displays the EIP-4527 typed-data sign request for the
+// session's current signer in dialogTxResult.
+func (m *model) showSafeSignRequest() tea.Cmd {
+	s := m.safeSession
+	owner := s.info.Owners[s.signer]
+	typed := rpc.SafeTypedData(s.chainID, s.info.Address, s.info.Version, s.tx)
+	urStr, reqID, err := rpc.BuildTypedDataSignRequestEIP4527(owner, s.chainID, typed)
+	if err != nil {
+		m.txResultError = "Building Safe sign request: " + err.Error()
+		return nil
+	}
+	s.requests[reqID] = true
+	typedJSON, _ := json.MarshalIndent(typed, "", "  ")
+
+	m.activeDialog = dialogTxResult
+	m.txResultError = ""
+	_, animCmd := m.handlePackageTransaction(packageTransactionMsg{
+		txDisplay: s.summary,
+		txJSON:    string(typedJSON),
+		qrData:    urStr,
+		format:    "EIP-4527 typed data",
+	})
+	return animCmd
+}
+
+// cycleSafeSigner addresses the QR to the next owner that has not signed.
+func (m *model) cycleSafeSigner() tea.Cmd {
+	s := m.safeSession
+	for step := 1; step <= len(s.info.Owners); step++ {
+		i := (s.signer + step) % len(s.info.Owners)
+		if _, signed := s.sigs[s.info.Owners[i]]; !signed {
+			s.signer = i
+			return m.showSafeSignRequest()
+		}
+	}
+	return nil
+}
+
+// handleSafeSignature records a scanned owner signature for the session.
+// The signer is recovered from the signature itself, so a reply from any
+// owner counts regardless of which owner the QR was addressed to. Once the
+// threshold is met the execTransaction is packaged for an owner to submit.
+func (m *model) handleSafeSignature(sig [65]byte) (tea.Model, tea.Cmd) {
+	s := m.safeSession
+	signer, err := rpc.RecoverSafeSigner(s.hash, sig)
+	if err != nil {
+		m.logWarn("Could not recover Safe signer: " + err.Error())
+		return m, waitForWebcamFrame(m.webcamFrameCh)
+	}
+	if !s.info.IsOwner(signer) {
+		m.logWarn(fmt.Sprintf("Signature is from %s, which is not an owner of this Safe", helpers.LabelAddr(signer.Hex())))
+		return m, waitForWebcamFrame(m.webcamFrameCh)
+	}
+	if _, dup := s.sigs[signer]; dup {
+		m.logWarn(fmt.Sprintf("%s has already signed this Safe transaction", helpers.LabelAddr(signer.Hex())))
+		return m, waitForWebcamFrame(m.webcamFrameCh)
+	}
+	s.sigs[signer] = sig
+	m.logSuccess(fmt.Sprintf("Safe signature %d/%d from %s", len(s.sigs), s.info.Threshold, helpers.LabelAddr(signer.Hex())))
+
+	_, closeCmd := m.closeScanTxDialog()
+	if uint64(len(s.sigs)) < s.info.Threshold {
+		return m, tea.Batch(closeCmd, m.cycleSafeSigner())
+	}
+	return m, tea.Batch(closeCmd, m.packageSafeExec())
+}
+
+// packageSafeExec builds execTransaction with the collected signatures and
+// packages it as an ordinary transaction from the submitting owner.
+func (m *model) packageSafeExec() tea.Cmd {
+	s := m.safeSession
+	calldata, err := rpc.BuildSafeExecCalldata(s.tx, rpc.PackSafeSignatures(s.sigs))
+	if err != nil {
+		m.txResultError = "Encoding execTransaction: " + err.Error()
+		return nil
+	}
+	submitter := m.safeSubmitter()
+	summary := fmt.Sprintf("Safe execTransaction (nonce %d, %d/%d signatures)\nSafe: %s\nSubmitted by: %s\n%s",
+		s.tx.Nonce, len(s.sigs), s.info.Threshold,
+		helpers.AddrWithLabel(s.info.Address.Hex()), helpers.AddrWithLabel(submitter.Hex()), s.summary)
+
+	m.safeSession = nil
+	m.txResultPackaging = true
+	m.txResultHex = ""
+	m.txResultError = ""
+	m.logInfo(fmt.Sprintf("Safe threshold met — packaging execTransaction from %s", helpers.LabelAddr(submitter.Hex())))
+	return packageSafeExec(m.eventStore, m.rpcURL, submitter, s.info.Address, calldata, summary, m.safeSourceFor(submitter))
+}
+
+// safeSubmitter picks the account that broadcasts execTransaction: the
+// first owner that is one of this app's wallets, else the owner the last
+// QR was addressed to. Anyone can submit, but an owner is the one who has
+// a signer at hand and a reason to pay the gas.
+func (m *model) safeSubmitter() common.Address {
+	s := m.safeSession
+	for _, o := range s.info.Owners {
+		if m.isOwnWallet(o) {
+			return o
+		}
+	}
+	return s.info.Owners[s.signer]
+}
+
+func (m *model) isOwnWallet(a common.Address) bool {
+	for _, w := range m.accounts {
+		if strings.EqualFold(w.Address, a.Hex()) {
+			return true
+		}
+	}
+	return false
+}
+
+// safeViewportContent is dialogTxResult's scrollable text while a Safe
+// session is active: the action, safeTxHash and per-owner signature state.
+func (m *model) safeViewportContent() string {
+	s := m.safeSession
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CSuccess)
+	muteStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	stepStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)
+
+	op := "call"
+	if s.tx.Operation == rpc.SafeDelegateCall {
+		op = "delegatecall (MultiSendCallOnly)"
+	}
+	lines := []string{
+		stepStyle.Render(fmt.Sprintf("Safe transaction — %d of %d signatures", len(s.sigs), s.info.Threshold)),
+		"",
+		s.summary,
+		"",
+		"Safe:        " + helpers.AddrWithLabel(s.info.Address.Hex()),
+		fmt.Sprintf("Nonce:       %d", s.tx.Nonce),
+		"Operation:   " + op,
+		"safeTxHash:  " + s.hash.Hex(),
+		"",
+		labelStyle.Render("Owners:"),
+	}
+	for i, o := range s.info.Owners {
+		mark := "  ○ "
+		if _, ok := s.sigs[o]; ok {
+			mark = "  ✓ "
+		} else if i == s.signer {
+			mark = "  ▶ "
+		}
+		lines = append(lines, mark+helpers.AddrWithLabel(o.Hex()))
+	}
+	lines = append(lines, "",
+		muteStyle.Render("Scan the QR with the ▶ owner's signer — it signs the EIP-712 safeTxHash"),
+		muteStyle.Render("Tab next owner • Enter to scan signature • Ctrl+C to copy typed data • ESC to cancel"),
+	)
+	return strings.Join(lines, "\n")
+}
@@ -71,14 +71,14 @@ func (m *model) submitRPCForm() (tea.Model, tea.Cmd) {
 		if tempRPCFormName != "" && tempRPCFormURL != "" {
 			newRPC := config.RPCUrl{Name: tempRPCFormName, URL: tempRPCFormURL, Active: false}
 			m.rpcURLs = append(m.rpcURLs, newRPC)
-			config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+			m.saveConfig()
 			m.logSuccess(fmt.Sprintf("Added RPC endpoint: `%s` (%s)", tempRPCFormName, tempRPCFormURL))
 		}
 	} else if m.settingsMode == "edit" {
 		if m.selectedRPCIdx >= 0 && m.selectedRPCIdx < len(m.rpcURLs) {
 			m.rpcURLs[m.selectedRPCIdx].Name = tempRPCFormName
 			m.rpcURLs[m.selectedRPCIdx].URL = tempRPCFormURL
-			config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+			m.saveConfig()
 			m.logSuccess(fmt.Sprintf("Updated RPC endpoint: `%s`", tempRPCFormName))
 		}
 	}
@@ -155,7 +155,7 @@ func (m *model) confirmDeleteRPCYes() (tea.Model, tea.Cmd) {
 		if m.selectedRPCIdx >= len(m.rpcURLs) && m.selectedRPCIdx > 0 {
 			m.selectedRPCIdx--
 		}
-		config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+		m.saveConfig()
 		m.logWarn(fmt.Sprintf("Deleted RPC endpoint `%s`", deletedName))
 	}
 	m.activeDialog = dialogNone
@@ -235,7 +235,7 @@ func (m *model) handleSettingsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.rpcURLs[i].Active = (i == m.selectedRPCIdx)
 				}
 				m.rpcURL = m.rpcURLs[m.selectedRPCIdx].URL
-				config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+				m.saveConfig()
 				// Set connecting state and reconnect with new RPC
 				m.rpcConnecting = true
 				m.rpcConnected = false
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/transactions"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *model) handleTxHistoryLoaded(msg txHistoryLoadedMsg) (tea.Model, tea.Cmd) {
+	m.txHistoryLoading = false
+	if msg.err != nil {
+		m.txHistoryErr = msg.err.Error()
+		m.logError("Transaction history: " + msg.err.Error())
+		return m, nil
+	}
+	m.txHistoryErr = ""
+	m.txHistory = msg.records
+	if m.txHistoryIdx >= len(m.txHistory) {
+		m.txHistoryIdx = len(m.txHistory) - 1
+	}
+	if m.txHistoryIdx < 0 {
+		m.txHistoryIdx = 0
+	}
+	return m, nil
+}
+
+// recordTxOnChain stores a mined tx's receipt against its history row. Called
+// from the paste dialog's poller once the tx is found on-chain.
+func (m *model) recordTxOnChain(info *rpc.TxOnChainInfo) {
+	if m.eventStore == nil || info == nil {
+		return
+	}
+	if err := recordTxReceipt(m.eventStore, info); err != nil {
+		m.logWarn("Could not record receipt in history: " + err.Error())
+	}
+}
+
+// showHistoryQR re-displays a history row's unsigned QR in dialogTxResult,
+// the same way a fresh packaging result is shown.
+func (m *model) showHistoryQR(r store.TxRecord) (tea.Model, tea.Cmd) {
+	if r.UR == "" {
+		m.logWarn("This transaction was pasted already signed — there is no unsigned QR to show")
+		return m, nil
+	}
+	if r.Status != store.TxPackaged && r.Status != store.TxSigned {
+		m.logWarn(fmt.Sprintf("Nonce %d was already broadcast — signing this request again cannot be mined", r.Nonce))
+	}
+	m.activeDialog = dialogTxResult
+	m.txResultError = ""
+	return m.handlePackageTransaction(packageTransactionMsg{
+		txDisplay: r.Summary,
+		txJSON:    r.TxJSON,
+		qrData:    r.UR,
+		format:    "EIP-4527",
+	})
+}
+
+// rebroadcastHistoryTx opens the paste-signed-tx dialog pre-filled with a
+// history row's signed payload, so it goes through the usual review, send
+// and poll flow.
+func (m *model) rebroadcastHistoryTx(r store.TxRecord) (tea.Model, tea.Cmd) {
+	if r.SignedRaw == "" {
+		m.logWarn("No signed payload stored for this transaction — scan the signer's QR first")
+		return m, nil
+	}
+	updated, formCmd := m.openPasteSignedTxDialogWithHex(r.SignedRaw)
+	um, ok := updated.(*model)
+	if !ok {
+		return updated, formCmd
+	}
+	um.pasteTxButtonFocused = true
+	return um, tea.Batch(formCmd, um.pasteTxFormField.Blur())
+}
+
+func (m *model) handleTransactionsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m, m.navigateTo(config.PageWallets)
+	case "up", "k":
+		if m.txHistoryIdx > 0 {
+			m.txHistoryIdx--
+		}
+	case "down", "j":
+		if m.txHistoryIdx < len(m.txHistory)-1 {
+			m.txHistoryIdx++
+		}
+	case "r", "R":
+		m.txHistoryLoading = true
+		return m, loadTxHistory(m.ethClient, m.eventStore)
+	}
+
+	if m.txHistoryIdx >= len(m.txHistory) {
+		return m, nil
+	}
+	r := m.txHistory[m.txHistoryIdx]
+	switch msg.String() {
+	case "enter":
+		return m.showHistoryQR(r)
+	case "b", "B":
+		return m.rebroadcastHistoryTx(r)
+	case "o", "O":
+		if r.TxHash == "" {
+			m.logWarn("This transaction has not been broadcast yet")
+			return m, nil
+		}
+		return m, openInBrowser(etherscanTxURL(new(big.Int).SetUint64(r.ChainID), r.TxHash))
+	case "t", "T":
+		if r.TxHash == "" {
+			m.logWarn("This transaction has not been broadcast yet")
+			return m, nil
+		}
+		return m, m.openTxInspector(r.TxHash)
+	}
+	return m, nil
+}
+
+func (m *model) renderTransactionsPage() (pageContent, nav string) {
+	c := transactions.Render(m.contentW-4, m.txHistory, m.txHistoryIdx, m.txHistoryLoading, m.txHistoryErr, m.spin.View())
+	return styles.PanelStyle.Width(m.contentW).Render(c), transactions.Nav(m.w-2, m.txIndexerActive)
+}
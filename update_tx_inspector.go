@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/explorer"
+	"charm-wallet-tui/views/txinspector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openTxInspector switches to the Transaction Inspector page, remembering
+// where Esc returns to, and inspects hash when it is not empty.
+func (m *model) openTxInspector(hash string) tea.Cmd {
+	// As in openBlockExplorer, returning to an inspector that opened the
+	// explorer keeps the inspector's way back.
+	if m.activePage != config.PageTxInspector &&
+		(m.activePage != config.PageBlockExplorer || m.explorerReturn != config.PageTxInspector) {
+		m.inspectorReturn = m.activePage
+	}
+	cmd := m.navigateTo(config.PageTxInspector)
+	if hash == "" {
+		return cmd
+	}
+	m.inspectorInput = hash
+	return tea.Batch(cmd, m.runTxInspection())
+}
+
+// runTxInspection decodes the transaction on the hash line off the UI
+// goroutine, cancelling any lookup still in flight.
+func (m *model) runTxInspection() tea.Cmd {
+	hash, err := helpers.ParseTxHash(m.inspectorInput)
+	if err != nil {
+		m.inspectorErr = err.Error()
+		return nil
+	}
+	if m.ethClient == nil {
+		m.inspectorErr = "no RPC connection — configure an endpoint in Settings"
+		return nil
+	}
+	m.cancelTxInspection()
+	ctx, cancel := context.WithTimeout(context.Background(), explorerTimeout)
+	m.inspectorCancel = cancel
+	m.inspectorGen++
+	m.inspectorHash = hash
+	m.inspectorLoading = true
+	m.inspectorEditing = false
+	m.inspectorErr = ""
+	m.logInfo("Transaction Inspector: inspecting " + helpers.ShortenAddr(hash.Hex()) + "…")
+
+	// The Contract page's saved ABIs decode calls to and logs from the
+	// contracts they were opened against.
+	m.refreshABILibrary()
+	gen, rpcURL := m.inspectorGen, m.rpcURL
+	in := helpers.TxInspector{PoolLookup: storePoolLookup(m.eventStore), ABIs: m.contractLibrary}
+	return func() tea.Msg {
+		defer cancel()
+		roots, err := in.Inspect(ctx, rpcURL, hash)
+		return inspectorResultMsg{gen: gen, hash: hash, roots: roots, err: err}
+	}
+}
+
+func (m *model) cancelTxInspection() {
+	if m.inspectorCancel != nil {
+		m.inspectorCancel()
+		m.inspectorCancel = nil
+	}
+	m.inspectorLoading = false
+}
+
+func (m *model) handleInspectorResult(msg inspectorResultMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.inspectorGen {
+		return m, nil
+	}
+	m.inspectorLoading = false
+	m.inspectorCancel = nil
+	if msg.err != nil {
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.inspectorErr = msg.err.Error()
+		m.logError("Transaction Inspector: " + msg.err.Error())
+		return m, nil
+	}
+	m.inspectorTree = resultTree{roots: msg.roots}
+	m.logSuccess("Transaction Inspector: decoded " + helpers.ShortenAddr(msg.hash.Hex()))
+	return m, nil
+}
+
+func (m *model) handleInspectorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.inspectorEditing {
+		switch msg.String() {
+		case "esc":
+			if m.inspectorTree.roots == nil {
+				return m, m.navigateTo(m.inspectorReturn)
+			}
+			m.inspectorEditing = false
+			m.inspectorErr = ""
+		case "enter":
+			return m, m.runTxInspection()
+		case "ctrl+u":
+			m.inspectorInput = ""
+		case "backspace":
+			if r := []rune(m.inspectorInput); len(r) > 0 {
+				m.inspectorInput = string(r[:len(r)-1])
+			}
+		default:
+			if len(msg.Runes) > 0 {
+				m.inspectorInput += string(msg.Runes)
+				m.inspectorErr = ""
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		if m.inspectorLoading {
+			m.cancelTxInspection()
+			m.inspectorGen++
+			m.logInfo("Transaction Inspector: lookup cancelled")
+			return m, nil
+		}
+		return m, m.navigateTo(m.inspectorReturn)
+	case "/":
+		m.inspectorEditing = true
+		m.inspectorErr = ""
+		return m, nil
+	case "r", "R":
+		if !m.inspectorLoading && m.inspectorInput != "" {
+			return m, m.runTxInspection()
+		}
+		return m, nil
+	}
+	if m.inspectorLoading || m.inspectorTree.roots == nil {
+		return m, nil
+	}
+	if msg.String() == "b" {
+		return m, m.openBlockExplorer(m.inspectorHash.Hex())
+	}
+	m.inspectorTree.handleKey(msg.String(), m.explorerTreeHeight())
+	return m, nil
+}
+
+func (m *model) renderInspectorPage() (pageContent, nav string) {
+	t := m.inspectorTree
+	c := txinspector.Render(m.contentW-4, m.explorerTreeHeight(), m.inspectorInput, m.inspectorEditing,
+		explorer.Rows(t.roots), t.idx, t.offset, m.inspectorLoading, m.inspectorErr, m.spin.View())
+	return styles.PanelStyle.Width(m.contentW).Render(c), txinspector.Nav(m.w-2, m.inspectorEditing, m.txIndexerActive)
+}
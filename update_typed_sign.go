@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// typedSignSession is an EIP-712 message a dApp module asked the active
+// wallet to sign (dapp.Host.SignTypedData), e.g. a Permit2 permit. Like a
+// safeSession it lives in dialogTxResult: the QR is the eth-sign-request and
+// Enter scans the eth-signature back.
+type typedSignSession struct {
+	signer    common.Address
+	typed     apitypes.TypedData
+	summary   string
+	requestID [16]byte
+	onSigned  func(h dapp.Host, sig [65]byte) tea.Cmd
+}
+
+// showTypedSignRequest builds the EIP-4527 typed-data sign request and shows
+// it in dialogTxResult.
+func (m *model) showTypedSignRequest(summary string, typed apitypes.TypedData, onSigned func(dapp.Host, [65]byte) tea.Cmd) tea.Cmd {
+	signer := common.HexToAddress(m.activeAddress)
+	m.activeDialog = dialogTxResult
+	m.txResultPackaging = false
+	m.txResultHex = ""
+	m.txResultError = ""
+	m.safeSession = nil
+
+	// The request's chain is the one the message is bound to.
+	chainID := big.NewInt(1)
+	if typed.Domain.ChainId != nil {
+		chainID = (*big.Int)(typed.Domain.ChainId)
+	}
+	urStr, reqID, err := rpc.BuildTypedDataSignRequestEIP4527(signer, chainID, typed)
+	if err != nil {
+		m.txResultError = "Building typed-data sign request: " + err.Error()
+		return nil
+	}
+	m.typedSign = &typedSignSession{signer: signer, typed: typed, summary: summary, requestID: reqID, onSigned: onSigned}
+	typedJSON, _ := json.MarshalIndent(typed, "", "  ")
+
+	_, animCmd := m.handlePackageTransaction(packageTransactionMsg{
+		txDisplay: summary,
+		txJSON:    string(typedJSON),
+		qrData:    urStr,
+		format:    "EIP-4527 typed data",
+	})
+	return animCmd
+}
+
+// handleTypedSignature checks a scanned signature against the pending
+// request's signer and hands it to the module that asked for it.
+func (m *model) handleTypedSignature(sig [65]byte) (tea.Model, tea.Cmd) {
+	s := m.typedSign
+	signer, err := rpc.RecoverTypedDataSigner(s.typed, sig)
+	if err != nil {
+		m.logWarn("Could not recover typed-data signer: " + err.Error())
+		return m, waitForWebcamFrame(m.webcamFrameCh)
+	}
+	if signer != s.signer {
+		m.logWarn(fmt.Sprintf("Signature is from %s, expected %s", helpers.LabelAddr(signer.Hex()), helpers.LabelAddr(s.signer.Hex())))
+		return m, waitForWebcamFrame(m.webcamFrameCh)
+	}
+	m.logSuccess("Typed-data signature received from " + helpers.LabelAddr(signer.Hex()))
+
+	m.typedSign = nil
+	_, closeCmd := m.closeScanTxDialog()
+	return m, tea.Batch(closeCmd, s.onSigned(m.host(), sig))
+}
+
+// typedSignViewportContent is dialogTxResult's scrollable text while a
+// typed-data sign request is displayed.
+func (m *model) typedSignViewportContent() string {
+	s := m.typedSign
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CSuccess)
+	muteStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	stepStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)
+
+	lines := []string{
+		stepStyle.Render("Signature request — no transaction is sent by signing this"),
+		"",
+		s.summary,
+		"",
+		"Signer:      " + helpers.AddrWithLabel(s.signer.Hex()),
+		"Domain:      " + s.typed.Domain.Name + " · " + helpers.AddrWithLabel(s.typed.Domain.VerifyingContract),
+		"",
+		labelStyle.Render("Typed data (JSON):"),
+		"",
+		m.txResultHex,
+		"",
+		muteStyle.Render("Scan the QR with your signer — it signs the EIP-712 message"),
+		muteStyle.Render("Enter to scan signature • Ctrl+C to copy typed data • ESC to cancel"),
+	}
+	return strings.Join(lines, "\n")
+}
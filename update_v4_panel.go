@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"charm-wallet-tui/dapp"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/views/uniswap"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// v4PanelTargeted reports whether keys and clicks meant for a scrollable
+// panel go to the V4 events panel rather than the log.
+func (m *model) v4PanelTargeted() bool {
+	if !m.v4PanelShown {
+		return false
+	}
+	bothVisible := m.logEnabled && m.logReady
+	return !bothVisible || m.focusedPanel == focusedPanelV4Events
+}
+
+// v4SelectedIdx returns the index of the highlighted pool row, falling back
+// to the first row when the selected pool is gone or none was picked.
+func (m *model) v4SelectedIdx() int {
+	for i, r := range m.v4PoolRows {
+		if r.PoolID == m.v4SelectedPool {
+			return i
+		}
+	}
+	return 0
+}
+
+// refreshV4EventsContent re-renders the panel's cards with the current
+// selection highlighted.
+func (m *model) refreshV4EventsContent() {
+	m.v4EventsViewport.SetContent(uniswap.V4EventsContent(m.w-2, m.v4PoolRows, m.v4SelectedIdx()))
+}
+
+// v4CardSpans returns each pool card's first content line and height.
+func (m *model) v4CardSpans() (starts, heights []int) {
+	line := 0
+	for _, c := range uniswap.V4EventsCards(m.w-2, m.v4PoolRows, m.v4SelectedIdx()) {
+		h := lipgloss.Height(c)
+		starts = append(starts, line)
+		heights = append(heights, h)
+		line += h
+	}
+	return starts, heights
+}
+
+// moveV4Selection moves the highlighted row by delta and scrolls the panel
+// so the whole card stays in view.
+func (m *model) moveV4Selection(delta int) {
+	if len(m.v4PoolRows) == 0 {
+		return
+	}
+	idx := helpers.Max(0, helpers.Min(len(m.v4PoolRows)-1, m.v4SelectedIdx()+delta))
+	m.v4SelectedPool = m.v4PoolRows[idx].PoolID
+	m.refreshV4EventsContent()
+
+	starts, heights := m.v4CardSpans()
+	top, bottom := starts[idx], starts[idx]+heights[idx]
+	vp := &m.v4EventsViewport
+	switch {
+	case top < vp.YOffset:
+		vp.SetYOffset(top)
+	case bottom > vp.YOffset+vp.Height:
+		vp.SetYOffset(bottom - vp.Height)
+	}
+}
+
+// v4CardAt returns the index of the pool card on content line, or -1.
+func (m *model) v4CardAt(line int) int {
+	starts, heights := m.v4CardSpans()
+	for i := range starts {
+		if line >= starts[i] && line < starts[i]+heights[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// openSelectedV4Pool hands the highlighted pool to the modules, which open
+// their detail view for it.
+func (m *model) openSelectedV4Pool() tea.Cmd {
+	if len(m.v4PoolRows) == 0 {
+		return nil
+	}
+	r := m.v4PoolRows[m.v4SelectedIdx()]
+	m.logInfo(fmt.Sprintf("Opening pool analytics for %s", helpers.ShortenAddr(r.PoolID)))
+	id := common.HexToHash(r.PoolID)
+	return func() tea.Msg { return dapp.PoolSelectedMsg{PoolID: id} }
+}
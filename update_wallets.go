@@ -9,6 +9,7 @@ import (
 	"charm-wallet-tui/config"
 	"charm-wallet-tui/helpers"
 	"charm-wallet-tui/rpc"
+	"charm-wallet-tui/store"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,57 +20,167 @@ import (
 func (m *model) createSendForm() {
 	tempSendToAddr = ""
 	tempSendAmount = ""
+	tempSendAsset = ""
 	m.sendFormError = ""
 	m.sendFormButtonFocused = false
+	m.sendRecipientAck = ""
+	m.loadRecipientChecks()
 
 	addrField := huh.NewInput().
 		Title("Send To").
-		Description("Enter a valid Ethereum address (Ctrl+v to paste)").
+		DescriptionFunc(func() string {
+			if addr, err := m.resolveRecipient(tempSendToAddr); err == nil {
+				if warning := m.recipientWarning(addr); warning != "" {
+					return "⚠ " + warning
+				}
+				if label, ok := helpers.AddressLabel(common.HexToAddress(addr)); ok {
+					return "→ " + label + " (" + helpers.ShortenAddr(addr) + ")"
+				}
+			}
+			if len(m.addressBook) > 0 {
+				return "Address or contact label (Ctrl+e completes, Ctrl+v pastes)"
+			}
+			return "Enter a valid Ethereum address (Ctrl+v to paste)"
+		}, &tempSendToAddr).
 		Value(&tempSendToAddr).
 		Placeholder("0x...").
+		Suggestions(m.recipientSuggestions()).
 		Validate(func(s string) error {
-			if !helpers.IsValidEthAddress(s) {
-				return fmt.Errorf("invalid ethereum address")
-			}
-			return nil
+			_, err := m.resolveRecipient(s)
+			return err
 		})
 
 	amountField := huh.NewInput().
-		Title("Amount (ETH)").
-		Description(fmt.Sprintf("Available: %s ETH", helpers.FormatETH(m.details.EthWei))).
+		TitleFunc(func() string {
+			symbol, _, _, _ := m.sendAsset()
+			return "Amount (" + symbol + ")"
+		}, &tempSendAsset).
+		DescriptionFunc(func() string {
+			symbol, decimals, balance, _ := m.sendAsset()
+			return "Available: " + helpers.FormatToken(balance, decimals, symbol)
+		}, &tempSendAsset).
 		Value(&tempSendAmount).
 		Placeholder("0.0").
 		Validate(func(s string) error {
-			if s == "" {
-				return fmt.Errorf("amount is required")
-			}
-			// Parse amount as big.Float
-			amount := new(big.Float)
-			_, ok := amount.SetString(s)
-			if !ok {
-				return fmt.Errorf("invalid amount")
-			}
-			// Check if amount is <= balance
-			balanceFloat := new(big.Float).SetInt(m.details.EthWei)
-			balanceETH := new(big.Float).Quo(balanceFloat, big.NewFloat(1e18))
-			if amount.Cmp(balanceETH) > 0 {
-				return fmt.Errorf("amount exceeds balance")
-			}
-			if amount.Cmp(big.NewFloat(0)) <= 0 {
-				return fmt.Errorf("amount must be greater than 0")
-			}
-			return nil
+			_, err := m.validateSendAmount(s)
+			return err
 		})
 
 	m.sendFormFields = []huh.Field{addrField, amountField}
+	if opts := m.sendAssetOptions(); len(opts) > 1 {
+		assetField := huh.NewSelect[string]().
+			Title("Asset").
+			Options(opts...).
+			Inline(true).
+			Value(&tempSendAsset)
+		m.sendFormFields = []huh.Field{assetField, addrField, amountField}
+	}
 	m.sendForm = huh.NewForm(
-		huh.NewGroup(addrField, amountField),
+		huh.NewGroup(m.sendFormFields...),
 	).WithWidth(SendFormPopupInnerWidth).WithTheme(huh.ThemeCatppuccin())
 
 	// Initialize the form
 	m.sendForm.Init()
 }
 
+// recipientSuggestions feeds the send form's autocomplete: every contact
+// label and .eth name in the address book.
+func (m *model) recipientSuggestions() []string {
+	var out []string
+	for _, c := range m.addressBook {
+		out = append(out, c.Label)
+		if c.ENS != "" {
+			out = append(out, c.ENS)
+		}
+	}
+	return out
+}
+
+// resolveRecipient turns what was typed into the send form's recipient
+// field into a checksummed address: a 0x address as-is, an address book
+// label or .eth name to its saved address, or any other .eth name already in
+// the ENS cache.
+func (m *model) resolveRecipient(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if helpers.IsValidEthAddress(s) {
+		return common.HexToAddress(s).Hex(), nil
+	}
+	for _, c := range m.addressBook {
+		if strings.EqualFold(c.Label, s) || (c.ENS != "" && strings.EqualFold(c.ENS, s)) {
+			return c.Address, nil
+		}
+	}
+	if strings.HasSuffix(strings.ToLower(s), ".eth") && m.eventStore != nil {
+		if addr, ok := m.eventStore.CachedENS(store.ENSForward, s, ensCacheTTL); ok && addr != "" {
+			return addr, nil
+		}
+		return "", fmt.Errorf("unknown ENS name — add it to the address book first")
+	}
+	return "", fmt.Errorf("invalid ethereum address")
+}
+
+// sendAssetOptions lists what the send form can transfer: ETH plus every
+// loaded token the active wallet holds a non-zero balance of.
+func (m *model) sendAssetOptions() []huh.Option[string] {
+	opts := []huh.Option[string]{huh.NewOption("ETH", "")}
+	for _, t := range m.details.Tokens {
+		if t.Balance == nil || t.Balance.Sign() <= 0 {
+			continue
+		}
+		opts = append(opts, huh.NewOption(t.Symbol, t.Address.Hex()))
+	}
+	return opts
+}
+
+// sendAsset resolves tempSendAsset to the selected asset's symbol, decimals
+// and balance; token is nil for ETH.
+func (m *model) sendAsset() (symbol string, decimals uint8, balance *big.Int, token *rpc.TokenBalance) {
+	if tempSendAsset != "" {
+		for i := range m.details.Tokens {
+			t := &m.details.Tokens[i]
+			if t.Address.Hex() == tempSendAsset {
+				return t.Symbol, t.Decimals, t.Balance, t
+			}
+		}
+	}
+	return "ETH", 18, m.details.EthWei, nil
+}
+
+// validateSendAmount parses s in the selected asset's decimals and checks it
+// against that asset's balance, returning the amount in base units.
+func (m *model) validateSendAmount(s string) (*big.Int, error) {
+	_, decimals, balance, _ := m.sendAsset()
+	amount, err := helpers.ParseTokenAmount(s, decimals)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("amount must be greater than 0")
+	}
+	if balance == nil || amount.Cmp(balance) > 0 {
+		return nil, fmt.Errorf("amount exceeds balance")
+	}
+	return amount, nil
+}
+
+// packageSendForm packages the send form's transfer — a plain ETH value
+// transfer, or an ERC-20 transfer() when a token is selected.
+func (m *model) packageSendForm(toAddr string, amount *big.Int) tea.Cmd {
+	symbol, _, _, token := m.sendAsset()
+	m.logInfo(fmt.Sprintf("Packaging transaction: %s %s to %s", strings.TrimSpace(tempSendAmount), symbol, helpers.LabelAddr(toAddr)))
+	m.sendFormError = ""
+	m.sendForm = nil
+	m.activeDialog = dialogTxResult
+	m.txResultPackaging = true
+	m.txResultHex = ""
+	m.txResultError = ""
+	m.txResultFormat = "EIP-4527"
+	if token != nil {
+		return tea.Batch(packageTokenTransfer(m.eventStore, m.activeAddress, toAddr, *token, amount, strings.TrimSpace(tempSendAmount), m.rpcURL, m.safeSourceFor(common.HexToAddress(m.activeAddress))), cmdEnableMouseAllMotion())
+	}
+	return tea.Batch(packageTransaction(m.eventStore, m.activeAddress, toAddr, amount, strings.TrimSpace(tempSendAmount), m.rpcURL, m.safeSourceFor(common.HexToAddress(m.activeAddress))), cmdEnableMouseAllMotion())
+}
+
 func (m *model) handleSendFormMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Belt-and-suspenders: drop mouse events that slipped past the Update() guard
 	// (raw SGR sequences can arrive as tea.KeyMsg on some terminals).
@@ -92,7 +203,8 @@ func (m *model) handleSendFormMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// field's Update twice for any non-KeyMsg message — once via its
 		// broadcast-to-all-fields branch, once via its focused-field branch
 		// — which double-inserts the pasted text.
-		if keyMsg.String() == "ctrl+v" && !m.sendFormButtonFocused {
+		_, onAssetSelect := m.sendForm.GetFocusedField().(*huh.Select[string])
+		if keyMsg.String() == "ctrl+v" && !m.sendFormButtonFocused && !onAssetSelect {
 			text, err := clipboard.ReadAll()
 			switch {
 			case err != nil:
@@ -142,14 +254,23 @@ func (m *model) handleSendFormMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Check if form is completed
 		if m.sendForm.State == huh.StateCompleted {
 			// Package the transaction
-			m.logInfo(fmt.Sprintf("Packaging transaction: %s ETH to %s", tempSendAmount, helpers.ShortenAddr(tempSendToAddr)))
-			m.sendForm = nil
-			m.activeDialog = dialogTxResult
-			m.txResultPackaging = true
-			m.txResultHex = ""
-			m.txResultError = ""
-			m.txResultFormat = "EIP-4527"
-			return m, tea.Batch(packageTransaction(m.activeAddress, tempSendToAddr, tempSendAmount, m.rpcURL), cmdEnableMouseAllMotion())
+			toAddr, err := m.resolveRecipient(tempSendToAddr)
+			if err == nil {
+				err = m.confirmSendRecipient(toAddr)
+			}
+			var amount *big.Int
+			if err == nil {
+				amount, err = m.validateSendAmount(tempSendAmount)
+			}
+			if err != nil {
+				// Reopen the form so the user can fix the field (or, for a
+				// flagged recipient, confirm with a second Enter).
+				m.sendForm.State = huh.StateNormal
+				m.sendFormError = err.Error()
+				m.sendFormErrTime = time.Now()
+				return m, nil
+			}
+			return m, m.packageSendForm(toAddr, amount)
 		}
 
 		// Check if form was aborted (ESC pressed)
@@ -167,47 +288,23 @@ func (m *model) handleSendFormMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 // Used by the mouse-clickable Submit button, since clicks never reach huh's
 // own per-field validation (which only runs on a real Enter keypress).
 func (m *model) trySubmitSendForm() (tea.Model, tea.Cmd) {
-	addr := strings.TrimSpace(tempSendToAddr)
-	if !helpers.IsValidEthAddress(addr) {
-		m.sendFormError = "Invalid Ethereum Address"
-		m.sendFormErrTime = time.Now()
-		return m, nil
-	}
-
-	amtStr := strings.TrimSpace(tempSendAmount)
-	if amtStr == "" {
-		m.sendFormError = "Amount is required"
-		m.sendFormErrTime = time.Now()
-		return m, nil
+	addr, err := m.resolveRecipient(tempSendToAddr)
+	if err == nil {
+		err = m.confirmSendRecipient(addr)
 	}
-	amount := new(big.Float)
-	if _, ok := amount.SetString(amtStr); !ok {
-		m.sendFormError = "Invalid amount"
+	if err != nil {
+		m.sendFormError = err.Error()
 		m.sendFormErrTime = time.Now()
 		return m, nil
 	}
-	balanceFloat := new(big.Float).SetInt(m.details.EthWei)
-	balanceETH := new(big.Float).Quo(balanceFloat, big.NewFloat(1e18))
-	if amount.Cmp(balanceETH) > 0 {
-		m.sendFormError = "Amount exceeds balance"
-		m.sendFormErrTime = time.Now()
-		return m, nil
-	}
-	if amount.Cmp(big.NewFloat(0)) <= 0 {
-		m.sendFormError = "Amount must be greater than 0"
+
+	amount, err := m.validateSendAmount(tempSendAmount)
+	if err != nil {
+		m.sendFormError = err.Error()
 		m.sendFormErrTime = time.Now()
 		return m, nil
 	}
-
-	m.logInfo(fmt.Sprintf("Packaging transaction: %s ETH to %s", tempSendAmount, helpers.ShortenAddr(addr)))
-	m.sendFormError = ""
-	m.sendForm = nil
-	m.activeDialog = dialogTxResult
-	m.txResultPackaging = true
-	m.txResultHex = ""
-	m.txResultError = ""
-	m.txResultFormat = "EIP-4527"
-	return m, tea.Batch(packageTransaction(m.activeAddress, addr, tempSendAmount, m.rpcURL), cmdEnableMouseAllMotion())
+	return m, m.packageSendForm(addr, amount)
 }
 
 // confirmDeleteWalletYes deletes the wallet pending confirmation. Shared by
@@ -232,7 +329,8 @@ func (m *model) confirmDeleteWalletYes() (tea.Model, tea.Cmd) {
 		m.highlightedAddress = ""
 		m.activeAddress = ""
 	}
-	config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+	m.saveConfig()
+	m.syncAddressLabels()
 	m.logWarn(fmt.Sprintf("Deleted wallet `%s`", helpers.ShortenAddr(deletedAddr)))
 	m.activeDialog = dialogNone
 	return m, m.loadSelectedWalletDetails()
@@ -323,7 +421,7 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						m.focusedInput = 1
 						m.input.Blur()
 						m.nicknameInput.Focus()
-						return m, lookupENS(m.ethClient, newAddr)
+						return m, lookupENS(m.ethClient, m.eventStore, newAddr)
 					}
 				}
 				m.focusedInput = 1
@@ -343,7 +441,7 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					if m.ethClient != nil {
 						m.ensLookupActive = true
 						m.ensLookupAddr = val
-						return m, resolveENS(m.ethClient, val)
+						return m, resolveENS(m.ethClient, m.eventStore, val)
 					}
 					return m, nil
 				}
@@ -355,7 +453,7 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					if m.ethClient != nil && (!m.ensLookupActive || m.ensLookupAddr != newAddr) {
 						m.ensLookupActive = true
 						m.ensLookupAddr = newAddr
-						return m, lookupENS(m.ethClient, newAddr)
+						return m, lookupENS(m.ethClient, m.eventStore, newAddr)
 					}
 					return m, nil
 				}
@@ -389,7 +487,8 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.activeAddress = newAddr
 				m.highlightedAddress = newAddr
 			}
-			config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+			m.saveConfig()
+			m.syncAddressLabels()
 			m.logSuccess(fmt.Sprintf("Updated wallet `%s`", helpers.ShortenAddr(newAddr)))
 			m.activeDialog = dialogNone
 			m.input.SetValue("")
@@ -473,7 +572,7 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						m.focusedInput = 1
 						m.input.Blur()
 						m.nicknameInput.Focus()
-						return m, lookupENS(m.ethClient, newAddr)
+						return m, lookupENS(m.ethClient, m.eventStore, newAddr)
 					}
 				}
 				m.focusedInput = 1
@@ -493,7 +592,7 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					if m.ethClient != nil {
 						m.ensLookupActive = true
 						m.ensLookupAddr = val
-						return m, resolveENS(m.ethClient, val)
+						return m, resolveENS(m.ethClient, m.eventStore, val)
 					}
 					return m, nil
 				}
@@ -505,7 +604,7 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					if m.ethClient != nil && (!m.ensLookupActive || m.ensLookupAddr != newAddr) {
 						m.ensLookupActive = true
 						m.ensLookupAddr = newAddr
-						return m, lookupENS(m.ethClient, newAddr)
+						return m, lookupENS(m.ethClient, m.eventStore, newAddr)
 					}
 					return m, nil
 				}
@@ -546,7 +645,8 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.nicknameInput.Blur()
 			m.focusedInput = 0
 			m.addError = ""
-			config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+			m.saveConfig()
+			m.syncAddressLabels()
 			if nickname != "" {
 				m.logSuccess(fmt.Sprintf("Added wallet `%s` with nickname `%s`", helpers.ShortenAddr(newAddr), nickname))
 			} else {
@@ -604,7 +704,7 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			// Update active address to the newly activated wallet
 			m.activeAddress = m.accounts[m.selectedWallet].Address
-			config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled})
+			m.saveConfig()
 			m.logInfo(fmt.Sprintf("Activated wallet `%s`", helpers.ShortenAddr(m.activeAddress)))
 
 			// If split view is enabled, refresh details for the newly activated wallet
@@ -644,6 +744,24 @@ func (m *model) handleWalletsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "w", "W":
 		return m, m.navigateTo(config.PageWatchedTokens)
 
+	case "o", "O":
+		return m.openOutboxDialog()
+
+	case "!":
+		return m.openAlertsDialog()
+
+	case "/":
+		return m, m.openBlockExplorer("")
+
+	case "t", "T":
+		return m, m.navigateTo(config.PageTransactions)
+
+	case "p", "P":
+		return m, m.navigateTo(config.PageApprovals)
+
+	case "c", "C":
+		return m, m.navigateTo(config.PageAddressBook)
+
 	case "h", "H":
 
 	case "esc":
@@ -89,7 +89,7 @@ func (m *model) handleTokenMetadataMsg(msg tokenMetadataMsg) (tea.Model, tea.Cmd
 			m.logSuccess(fmt.Sprintf("Updated watched token: `%s`", msg.symbol))
 		}
 	}
-	config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled, WatchedTokens: tokenWatchToConfigList(m.tokenWatch)})
+	m.saveConfig()
 
 	m.tokenFormMode = "list"
 	m.tokenForm = nil
@@ -168,7 +168,7 @@ func (m *model) confirmDeleteTokenYes() (tea.Model, tea.Cmd) {
 		if m.selectedTokenIdx >= len(m.tokenWatch) && m.selectedTokenIdx > 0 {
 			m.selectedTokenIdx--
 		}
-		config.Save(m.configPath, config.Config{RPCURLs: m.rpcURLs, Wallets: m.accounts, Logger: m.logEnabled, WatchedTokens: tokenWatchToConfigList(m.tokenWatch)})
+		m.saveConfig()
 		m.logWarn(fmt.Sprintf("Removed watched token `%s`", deletedName))
 	}
 	m.activeDialog = dialogNone
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -123,6 +124,15 @@ func (m *model) handleScannedURFrame(urString string) (tea.Model, tea.Cmd) {
 		return m, waitForWebcamFrame(m.webcamFrameCh)
 	}
 
+	// An owner's signature over the pending Safe transaction's safeTxHash.
+	if m.safeSession != nil && m.safeSession.ownsRequest(reqID) {
+		return m.handleSafeSignature(signature)
+	}
+	// A module's typed-data request, e.g. a Permit2 permit for a swap.
+	if m.typedSign != nil && reqID == m.typedSign.requestID {
+		return m.handleTypedSignature(signature)
+	}
+
 	to, value, nonce, tip, maxFee, gasLimit, chainID, data, pendingReqID, err := rpc.ParsePackagedTxJSON(m.txResultHex)
 	if err != nil || pendingReqID != reqID {
 		m.logWarn("Scanned signature does not match the displayed transaction request")
@@ -134,6 +144,9 @@ func (m *model) handleScannedURFrame(urString string) (tea.Model, tea.Cmd) {
 		m.logWarn("Failed to assemble signed transaction: " + err.Error())
 		return m, waitForWebcamFrame(m.webcamFrameCh)
 	}
+	if m.eventStore != nil {
+		_ = m.eventStore.RecordTxSignature(hex.EncodeToString(reqID[:]), "0x"+hex.EncodeToString(signature[:]), rawHex)
+	}
 
 	updated, formCmd := m.openPasteSignedTxDialogWithHex(rawHex)
 	um, ok := updated.(*model)
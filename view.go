@@ -15,7 +15,6 @@ import (
 	logview "charm-wallet-tui/views/log"
 	"charm-wallet-tui/views/scrollbar"
 	"charm-wallet-tui/views/settings"
-	"charm-wallet-tui/views/terra"
 	"charm-wallet-tui/views/uniswap"
 	"charm-wallet-tui/views/wallets"
 	"charm-wallet-tui/views/watchedtokens"
@@ -170,7 +169,11 @@ func (m *model) renderOndoPickerPopup() string {
 
 func (m *model) renderTxResultContent() string {
 	titleStr := "Transaction Ready To Sign (EIP-4527)"
-	if m.txApproveQRFrames != nil {
+	if m.safeSession != nil {
+		titleStr = "Safe Transaction — Owner Signature (EIP-712)"
+	} else if m.typedSign != nil {
+		titleStr = "Signature Request (EIP-712)"
+	} else if m.txApproveQRFrames != nil {
 		if !m.txSwapStep {
 			titleStr = "Step 1 of 2: Approve — Tab to switch steps"
 		} else {
@@ -399,10 +402,23 @@ func (m *model) renderPoolInfoPopup() string {
 			Render("✓ Pool ID Copied")
 	}
 
+	// Hook risk summary (V4 pools with a hook, once the key is known)
+	var hookRow string
+	if m.poolInfoHookLoading {
+		hookRow = lipgloss.NewStyle().Foreground(styles.CMuted).Render(m.spin.View() + " Inspecting hook…")
+	} else if m.poolInfoHookErr != "" {
+		hookRow = lipgloss.NewStyle().Foreground(styles.CWarn).Render("⚠ Hook inspection failed: " + m.poolInfoHookErr)
+	} else if m.poolInfoHook != nil {
+		hookRow = uniswap.RenderHookReport(68, m.poolInfoHook)
+	}
+
 	rows := []string{title, poolIDLine, "", body}
 	if keyRow != "" {
 		rows = append(rows, keyRow)
 	}
+	if hookRow != "" {
+		rows = append(rows, "", hookRow)
+	}
 	if copiedRow != "" {
 		rows = append(rows, copiedRow)
 	}
@@ -436,6 +452,9 @@ func (m *model) renderPoolInfoPopup() string {
 		m.poolInfoCopied = false
 		m.poolInfoKeyLoading = false
 		m.poolInfoKeyErr = ""
+		m.poolInfoHook = nil
+		m.poolInfoHookLoading = false
+		m.poolInfoHookErr = ""
 		return m, nil
 	})
 
@@ -451,6 +470,15 @@ func (m *model) renderActiveOverlay() string {
 	if m.activePage == config.PageWatchedTokens && (m.tokenFormMode == "add" || m.tokenFormMode == "edit") && m.tokenForm != nil {
 		return m.renderTokenFormPopup()
 	}
+	if m.activePage == config.PageAddressBook && (m.contactFormMode == "add" || m.contactFormMode == "edit") && m.contactForm != nil {
+		return m.renderContactFormPopup()
+	}
+	if m.activePage == config.PageContract && (m.contractMode == "open" || m.contractMode == "call") && m.contractForm != nil {
+		return m.renderContractFormPopup()
+	}
+	if m.activePage == config.PageDappBrowser && (m.dappFormMode == "add" || m.dappFormMode == "edit") && m.dappForm != nil {
+		return m.renderDappFormPopup()
+	}
 
 	switch m.activeDialog {
 	case dialogTxResult:
@@ -465,25 +493,22 @@ func (m *model) renderActiveOverlay() string {
 		return m.renderRPCDeleteDialog()
 	case dialogDeleteToken:
 		return m.renderTokenDeleteDialog()
+	case dialogDeleteContact:
+		return m.renderContactDeleteDialog()
+	case dialogDeleteDapp:
+		return m.renderDappDeleteDialog()
 	case dialogOndoPicker:
 		return m.renderOndoPickerPopup()
+	case dialogOutbox:
+		return m.renderOutboxPopup()
+	case dialogPoolFilter:
+		return m.renderPoolFilterPopup()
+	case dialogAlerts:
+		return m.renderAlertsPopup()
 	case dialogAccountList:
 		return m.renderAccountListPopup()
 	case dialogPoolInfo:
 		return m.renderPoolInfoPopup()
-	case dialogTerraClaim:
-		popup, geo := terra.RenderClaimPopup(m.w, m.h, m.terraNullMsgInput.View(), m.terraNullMsgError, m.terraNullFormFocused)
-		m.registerRegion("terraClaim.submit", uiRegionButton, geo.ButtonX1, geo.ButtonY, geo.ButtonX2, geo.ButtonY+1,
-			func(m *model) (tea.Model, tea.Cmd) { return m.submitTerraClaim() })
-		m.registerRegion("terraClaim.input", uiRegionInput, geo.InputX1, geo.InputY, geo.InputX2, geo.InputY+1,
-			func(m *model) (tea.Model, tea.Cmd) {
-				if m.terraNullFormFocused != 0 {
-					m.terraNullFormFocused = 0
-					return m, m.terraNullMsgInput.Focus()
-				}
-				return m, nil
-			})
-		return popup
 	case dialogEditWallet:
 		return m.renderEditWalletDialog()
 	case dialogAddWallet:
@@ -491,7 +516,7 @@ func (m *model) renderActiveOverlay() string {
 	case dialogSendTx:
 		return m.renderSendTxPopup()
 	}
-	return ""
+	return m.renderModuleOverlay()
 }
 
 func (m *model) renderSendTxPopup() string {
@@ -786,9 +811,206 @@ func (m *model) renderTokenFormPopup() string {
 	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, dialog)
 }
 
+func (m *model) renderContactFormPopup() string {
+	titleText := "Add Contact"
+	if m.contactFormMode == "edit" {
+		titleText = "Edit Contact"
+	}
+
+	title := lipgloss.NewStyle().
+		Foreground(styles.CAccent2).
+		Bold(true).
+		Align(lipgloss.Center).
+		Width(RPCFormPopupWidth - 8).
+		Render(titleText)
+
+	formView := m.contactForm.View()
+	if m.contactResolving {
+		formView += "\n\n" + m.spin.View() + " Resolving ENS name…"
+	}
+
+	btnStyle := styles.ButtonNormal
+	if m.hoveredRegionID == "contactForm.save" || m.contactFormButtonFocused {
+		btnStyle = styles.ButtonActive
+	}
+	saveBtn := btnStyle.Render("Save")
+	btnRow := lipgloss.NewStyle().
+		Width(RPCFormPopupWidth - 8).
+		Align(lipgloss.Center).
+		Render(saveBtn)
+
+	hints := lipgloss.NewStyle().Foreground(styles.CMuted).Render(
+		styles.HotkeyStyle.Render("Tab") + " next   " +
+			styles.HotkeyStyle.Render("Enter") + " save   " +
+			styles.HotkeyStyle.Render("Esc") + " cancel",
+	)
+
+	var errLine string
+	if m.contactFormError != "" {
+		errLine = "\n" + lipgloss.NewStyle().Foreground(styles.CWarn).Bold(true).
+			Width(RPCFormPopupWidth - 8).Align(lipgloss.Center).Render(m.contactFormError)
+	}
+
+	ui := lipgloss.JoinVertical(lipgloss.Left, title, "", formView, "", btnRow, errLine, "", hints)
+	dialog := styles.DialogBox.Padding(1, 2).Render(ui)
+
+	dialogW := lipgloss.Width(dialog)
+	dialogH := lipgloss.Height(dialog)
+	dialogStartX := (m.w - dialogW) / 2
+	dialogStartY := (m.h - dialogH) / 2
+	contentLeft := dialogStartX + 3
+	fieldsTop := dialogStartY + 2 + lipgloss.Height(title) + 1
+	m.registerHuhFieldRegions("contactForm", fieldsTop, contentLeft, m.contactForm, m.contactFormFields)
+
+	btnRowY := fieldsTop + lipgloss.Height(formView) + 1
+	rowWidth := RPCFormPopupWidth - 8
+	btnWidth := lipgloss.Width(saveBtn)
+	btnX1 := contentLeft + (rowWidth-btnWidth)/2
+	btnX2 := btnX1 + btnWidth
+	m.registerRegion("contactForm.save", uiRegionButton, btnX1, btnRowY, btnX2, btnRowY+1, func(m *model) (tea.Model, tea.Cmd) {
+		return m.submitContactForm()
+	})
+
+	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, dialog)
+}
+
+func (m *model) renderContractFormPopup() string {
+	titleText := "Open Contract"
+	btnText := "Open"
+	if m.contractMode == "call" && m.contractFuncIdx < len(m.contractFuncs) {
+		f := m.contractFuncs[m.contractFuncIdx]
+		titleText = m.contractABI.Methods[f.Name].RawName
+		btnText = "Package"
+		if f.Read {
+			btnText = "Call"
+		}
+	}
+
+	title := lipgloss.NewStyle().
+		Foreground(styles.CAccent2).
+		Bold(true).
+		Align(lipgloss.Center).
+		Width(RPCFormPopupWidth - 8).
+		Render(titleText)
+
+	formView := m.contractForm.View()
+
+	btnStyle := styles.ButtonNormal
+	if m.hoveredRegionID == "contractForm.submit" || m.contractFormButtonFocused {
+		btnStyle = styles.ButtonActive
+	}
+	submitBtn := btnStyle.Render(btnText)
+	btnRow := lipgloss.NewStyle().
+		Width(RPCFormPopupWidth - 8).
+		Align(lipgloss.Center).
+		Render(submitBtn)
+
+	hints := lipgloss.NewStyle().Foreground(styles.CMuted).Render(
+		styles.HotkeyStyle.Render("Tab") + " next   " +
+			styles.HotkeyStyle.Render("Enter") + " " + strings.ToLower(btnText) + "   " +
+			styles.HotkeyStyle.Render("Esc") + " cancel",
+	)
+
+	var errLine string
+	if m.contractFormError != "" {
+		errLine = "\n" + lipgloss.NewStyle().Foreground(styles.CWarn).Bold(true).
+			Width(RPCFormPopupWidth - 8).Align(lipgloss.Center).Render(m.contractFormError)
+	}
+
+	ui := lipgloss.JoinVertical(lipgloss.Left, title, "", formView, "", btnRow, errLine, "", hints)
+	dialog := styles.DialogBox.Padding(1, 2).Render(ui)
+
+	dialogW := lipgloss.Width(dialog)
+	dialogH := lipgloss.Height(dialog)
+	dialogStartX := (m.w - dialogW) / 2
+	dialogStartY := (m.h - dialogH) / 2
+	contentLeft := dialogStartX + 3
+	fieldsTop := dialogStartY + 2 + lipgloss.Height(title) + 1
+	m.registerHuhFieldRegions("contractForm", fieldsTop, contentLeft, m.contractForm, m.contractFormFields)
+
+	btnRowY := fieldsTop + lipgloss.Height(formView) + 1
+	rowWidth := RPCFormPopupWidth - 8
+	btnWidth := lipgloss.Width(submitBtn)
+	btnX1 := contentLeft + (rowWidth-btnWidth)/2
+	btnX2 := btnX1 + btnWidth
+	m.registerRegion("contractForm.submit", uiRegionButton, btnX1, btnRowY, btnX2, btnRowY+1, func(m *model) (tea.Model, tea.Cmd) {
+		return m.submitContractForm()
+	})
+
+	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, dialog)
+}
+
+func (m *model) renderDappFormPopup() string {
+	titleText := "Add dApp"
+	if m.dappFormMode == "edit" {
+		titleText = "Edit dApp"
+	}
+
+	title := lipgloss.NewStyle().
+		Foreground(styles.CAccent2).
+		Bold(true).
+		Align(lipgloss.Center).
+		Width(RPCFormPopupWidth - 8).
+		Render(titleText)
+
+	formView := m.dappForm.View()
+
+	btnStyle := styles.ButtonNormal
+	if m.hoveredRegionID == "dappForm.save" || m.dappFormButtonFocused {
+		btnStyle = styles.ButtonActive
+	}
+	saveBtn := btnStyle.Render("Save")
+	btnRow := lipgloss.NewStyle().
+		Width(RPCFormPopupWidth - 8).
+		Align(lipgloss.Center).
+		Render(saveBtn)
+
+	hints := lipgloss.NewStyle().Foreground(styles.CMuted).Render(
+		styles.HotkeyStyle.Render("Tab") + " next   " +
+			styles.HotkeyStyle.Render("Enter") + " save   " +
+			styles.HotkeyStyle.Render("Esc") + " cancel",
+	)
+
+	var errLine string
+	if m.dappFormError != "" {
+		errLine = "\n" + lipgloss.NewStyle().Foreground(styles.CWarn).Bold(true).
+			Width(RPCFormPopupWidth - 8).Align(lipgloss.Center).Render(m.dappFormError)
+	}
+
+	ui := lipgloss.JoinVertical(lipgloss.Left, title, "", formView, "", btnRow, errLine, "", hints)
+	dialog := styles.DialogBox.Padding(1, 2).Render(ui)
+
+	dialogW := lipgloss.Width(dialog)
+	dialogH := lipgloss.Height(dialog)
+	dialogStartX := (m.w - dialogW) / 2
+	dialogStartY := (m.h - dialogH) / 2
+	contentLeft := dialogStartX + 3
+	fieldsTop := dialogStartY + 2 + lipgloss.Height(title) + 1
+	m.registerHuhFieldRegions("dappForm", fieldsTop, contentLeft, m.dappForm, m.dappFormFields)
+
+	btnRowY := fieldsTop + lipgloss.Height(formView) + 1
+	rowWidth := RPCFormPopupWidth - 8
+	btnWidth := lipgloss.Width(saveBtn)
+	btnX1 := contentLeft + (rowWidth-btnWidth)/2
+	btnX2 := btnX1 + btnWidth
+	m.registerRegion("dappForm.save", uiRegionButton, btnX1, btnRowY, btnX2, btnRowY+1, func(m *model) (tea.Model, tea.Cmd) {
+		return m.submitDappForm()
+	})
+
+	return lipgloss.Place(m.w, m.h, lipgloss.Center, lipgloss.Center, dialog)
+}
+
 func (m *model) View() string {
+	// A fired terminal alert's bell/OSC sequences are zero-width, so they
+	// ride at the start of the frame and go out in the renderer's own write.
+	return m.alertSignal + m.renderView()
+}
+
+func (m *model) renderView() string {
 	m.clickableAreas = nil
 	m.uiRegions = nil
+	m.moduleFrame = nil
+	m.v4PanelShown = false
 	globalHdr := m.globalHeader()
 	headerPanel := styles.PanelStyle.Width(m.contentW).Render(globalHdr)
 
@@ -803,8 +1025,7 @@ func (m *model) View() string {
 		usedHeight := lipgloss.Height(headerPanel) + lipgloss.Height(pageContent) + lipgloss.Height(nav)
 		viewportHeight := helpers.Max(3, m.h-usedHeight-4)
 		m.logViewport.Height = viewportHeight
-		logFocused := m.poolEventMonitorActive && !m.uniswapShowingLiquidity &&
-			m.activePage == config.PageUniswap && m.focusedPanel == focusedPanelLog
+		logFocused := m.v4PanelShown && m.focusedPanel == focusedPanelLog
 		logPanel = logview.Render(m.w, viewportHeight, m.logReady, m.logSpinner.View(), m.logViewport, logFocused)
 		// log border top = m.h - height(logPanel); +3 for top border(1)+title(1)+blank(1)
 		m.logScroll.PanelTop = (m.h - lipgloss.Height(logPanel)) + 3
@@ -826,7 +1047,7 @@ func (m *model) renderPage(headerPanel string) (pageContent, nav string) {
 		return m.renderWalletsPage(headerPanel)
 
 	case config.PageDappBrowser:
-		c := dapps.Render(m.w-2, m.dapps, m.selectedDappIdx, m.chainID())
+		c := dapps.Render(m.w-2, m.dapps, m.selectedDappIdx, m.chainID(), m.selectedDappActions())
 		return styles.PanelStyle.Width(m.contentW).Render(c), dapps.Nav(m.w-2, m.txIndexerActive)
 
 	case config.PageDetails:
@@ -837,14 +1058,29 @@ func (m *model) renderPage(headerPanel string) (pageContent, nav string) {
 		c := settings.Render(m.rpcURLs, m.selectedRPCIdx)
 		return styles.PanelStyle.Width(m.contentW).Render(c), settings.Nav(m.w-2, m.settingsMode, m.txIndexerActive)
 
-	case config.PageUniswap:
-		return m.renderUniswapPage(headerPanel)
-
-	case config.PageTerraNullius:
-		return m.renderTerraPage(headerPanel)
+	case config.PageDapp:
+		return m.renderModulePage(headerPanel)
 
 	case config.PageWatchedTokens:
 		return m.renderWatchedTokensPage(headerPanel)
+
+	case config.PageTransactions:
+		return m.renderTransactionsPage()
+
+	case config.PageApprovals:
+		return m.renderApprovalsPage()
+
+	case config.PageAddressBook:
+		return m.renderAddressBookPage()
+
+	case config.PageContract:
+		return m.renderContractPage()
+
+	case config.PageBlockExplorer:
+		return m.renderExplorerPage()
+
+	case config.PageTxInspector:
+		return m.renderInspectorPage()
 	}
 	return "", ""
 }
@@ -921,92 +1157,4 @@ func (m *model) renderWalletsPage(headerPanel string) (pageContent, nav string)
 	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel), nav
 }
 
-func (m *model) renderUniswapPage(headerPanel string) (pageContent, nav string) {
-	tokens := m.buildTokenList()
-	navStr := uniswap.Nav(m.w-2, m.poolEventMonitorActive, m.uniswapShowingLiquidity, m.v4BlockScanActive)
-
-	if m.uniswapShowingSelector {
-		c := uniswap.RenderTokenSelector(m.w, m.h-8, tokens, m.uniswapSelectorIdx, m.uniswapSelectorFor == 0)
-		return c, navStr
-	}
-	if m.uniswapShowingLiquidity {
-		c := uniswap.RenderLiquidity(m.w-2, m.h-8, m.liquidityPositions, m.liquidityLoading,
-			m.liquidityFocusedIdx, m.liquidityErr, m.spin.View())
-		return styles.PanelStyle.Width(m.contentW).Render(c), navStr
-	}
-	if m.poolEventMonitorActive {
-		// PanelStyle adds 4 vertical lines; RenderV4Events overhead is 4 — so m.h/2-4 yields half-height.
-		v4View := uniswap.RenderV4Events(m.w-2, helpers.Max(1, m.h/2-4), m.v4EventsViewport)
-		borderColor := styles.CBorder
-		if m.focusedPanel == focusedPanelV4Events {
-			borderColor = styles.CAccent
-		}
-		c := styles.PanelStyle.BorderForeground(borderColor).Width(m.contentW).Render(v4View)
-		m.v4Scroll.PanelTop = lipgloss.Height(headerPanel) + 4
-		m.v4Scroll.TrackCol = m.v4EventsViewport.Width + 3
-		return c, navStr
-	}
-	c, geo := uniswap.Render(m.w-2, m.h-8, tokens,
-		m.uniswapFromTokenIdx, m.uniswapToTokenIdx,
-		m.uniswapFromAmount, m.uniswapToAmount,
-		m.uniswapFocusedField, m.uniswapEstimating, m.uniswapResolvingPair,
-		m.uniswapPriceImpactWarn, m.uniswapHookWarn)
-
-	// Content top-left within pageContent = PanelStyle's border(1)+padding(1,2).
-	contentLeft := 3
-	contentTop := lipgloss.Height(headerPanel) + 2
-
-	m.registerRegion("uniswap.fromBox", uiRegionInput,
-		contentLeft+geo.FromX1, contentTop+geo.FromY,
-		contentLeft+geo.FromX2, contentTop+geo.FromY+geo.FromH,
-		func(m *model) (tea.Model, tea.Cmd) { return m, m.focusUniswapField(0) })
-	m.registerRegion("uniswap.toBox", uiRegionInput,
-		contentLeft+geo.ToX1, contentTop+geo.ToY,
-		contentLeft+geo.ToX2, contentTop+geo.ToY+geo.ToH,
-		func(m *model) (tea.Model, tea.Cmd) { return m, m.focusUniswapField(1) })
-	m.registerRegion("uniswap.swapButton", uiRegionButton,
-		contentLeft+geo.SwapX1, contentTop+geo.SwapY,
-		contentLeft+geo.SwapX2, contentTop+geo.SwapY+geo.SwapH,
-		func(m *model) (tea.Model, tea.Cmd) {
-			m.uniswapFocusedField = 2
-			return m.executeUniswapSwap()
-		})
-
-	return styles.PanelStyle.Width(m.contentW).Render(c), navStr
-}
-
-func (m *model) renderTerraPage(headerPanel string) (pageContent, nav string) {
-	var terraNullDesc string
-	for _, d := range m.dapps {
-		if d.Name == "Terra Nullius" {
-			terraNullDesc = d.Description
-			break
-		}
-	}
-	c, geo := terra.Render(m.w-2, m.h-8, m.terraNullFocusedField, terraNullDesc,
-		m.terraNullClaimsCount, m.terraNullClaimsLoading,
-		m.terraNullClaimInput, m.terraNullClaimQuerying,
-		m.terraNullLastQueriedIdx, m.terraNullClaimResult, m.terraNullClaimResultErr)
-
-	contentLeft := 3
-	contentTop := lipgloss.Height(headerPanel) + 2
-
-	m.registerRegion("terra.claimsBox", uiRegionInput,
-		contentLeft+geo.ClaimsX1, contentTop+geo.ClaimsY,
-		contentLeft+geo.ClaimsX2, contentTop+geo.ClaimsY+geo.ClaimsH,
-		func(m *model) (tea.Model, tea.Cmd) {
-			m.terraNullFocusedField = 1
-			return m, nil
-		})
-	m.registerRegion("terra.claimBox", uiRegionButton,
-		contentLeft+geo.ClaimX1, contentTop+geo.ClaimY,
-		contentLeft+geo.ClaimX2, contentTop+geo.ClaimY+geo.ClaimH,
-		func(m *model) (tea.Model, tea.Cmd) {
-			m.terraNullFocusedField = 2
-			return m.openTerraClaimPopup()
-		})
-
-	return styles.PanelStyle.Width(m.contentW).Render(c), terra.Nav(m.w-2, m.txIndexerActive)
-}
-
 
@@ -0,0 +1,128 @@
+package addressbook
+
+import (
+	"fmt"
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ListRows is the maximum number of contacts Render shows at once; the list
+// scrolls to keep the selection in view.
+const ListRows = 12
+
+// Nav returns the navigation bar for the Address Book page.
+func Nav(width int, indexerActive bool) string {
+	var iItem string
+	if indexerActive {
+		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
+		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
+		iItem = iKey + " " + iLabel
+	} else {
+		iItem = styles.Key("i") + " indexer"
+	}
+
+	left := strings.Join([]string{
+		styles.Key("↑/↓") + " select",
+		styles.Key("a") + " add",
+		styles.Key("e") + " edit",
+		styles.Key("c") + " copy",
+		styles.Key("Del") + " delete",
+		styles.Key("l") + " logger",
+		iItem,
+		styles.Key("Esc") + " back",
+	}, "   ")
+
+	return styles.NavStyle.Width(width).Render(left)
+}
+
+// Render draws the address book: one row per contact (label, address, ENS
+// name, tags) followed by the selected contact's details. ensNames maps
+// checksummed addresses to their cached reverse-ENS names.
+func Render(width int, contacts []config.Contact, selectedIdx int, ensNames map[string]string) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	rowStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+
+	lines := []string{styles.TitleStyle.Render("Address Book"), ""}
+
+	if len(contacts) == 0 {
+		lines = append(lines, mutedStyle.Render("No contacts yet. Press ")+styles.Key("a")+
+			mutedStyle.Render(" to add one — its label then replaces the address in logs, previews and the send form."))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("%d contact(s):", len(contacts))), "")
+
+	start := 0
+	if selectedIdx >= ListRows {
+		start = selectedIdx - ListRows + 1
+	}
+	end := start + ListRows
+	if end > len(contacts) {
+		end = len(contacts)
+	}
+	for i := start; i < end; i++ {
+		c := contacts[i]
+		label := fmt.Sprintf("%-20s %s  %-20s %s", c.Label, helpers.ShortenAddr(c.Address), ensName(c, ensNames), tagText(c.Tags))
+		label = ansi.Truncate(label, helpers.Max(10, width-2), "…")
+		if i == selectedIdx {
+			lines = append(lines, selStyle.Render("▶ "+label))
+		} else {
+			lines = append(lines, "  "+rowStyle.Render(label))
+		}
+	}
+	if len(contacts) > ListRows {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d–%d of %d", start+1, end, len(contacts))))
+	}
+
+	if selectedIdx >= 0 && selectedIdx < len(contacts) {
+		lines = append(lines, "", renderDetails(contacts[selectedIdx], ensNames))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderDetails(c config.Contact, ensNames map[string]string) string {
+	keyStyle := lipgloss.NewStyle().Foreground(styles.CMuted).Width(10)
+	valStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	row := func(k, v string) string {
+		return keyStyle.Render(k) + valStyle.Render(v)
+	}
+
+	lines := []string{
+		row("Label", c.Label),
+		row("Address", c.Address),
+	}
+	if name := ensName(c, ensNames); name != "" {
+		lines = append(lines, row("ENS", name))
+	}
+	if len(c.Tags) > 0 {
+		lines = append(lines, row("Tags", tagText(c.Tags)))
+	}
+	if c.Notes != "" {
+		lines = append(lines, row("Notes", c.Notes))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ensName prefers the name the contact was added by, falling back to the
+// address's cached primary name.
+func ensName(c config.Contact, ensNames map[string]string) string {
+	if c.ENS != "" {
+		return c.ENS
+	}
+	return ensNames[c.Address]
+}
+
+func tagText(tags []string) string {
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = "#" + t
+	}
+	return strings.Join(out, " ")
+}
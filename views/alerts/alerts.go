@@ -0,0 +1,89 @@
+package alerts
+
+import (
+	"strings"
+	"time"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Width is the inner width of the alerts dialog.
+const Width = 76
+
+// Render renders the alert rules dialog: the saved rules with their state
+// and last firing, the rule being typed, and where hooks will deliver.
+// watching reports whether the poll loop has a baseline yet.
+func Render(rules []config.AlertRule, fired map[string]time.Time, input, errMsg string, selected int, webhook, command string, watching bool) string {
+	titleStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	rowStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+	offStyle := lipgloss.NewStyle().Foreground(styles.CMuted).Strikethrough(true)
+	errStyle := lipgloss.NewStyle().Foreground(styles.CFail)
+	okStyle := lipgloss.NewStyle().Foreground(styles.CSuccess)
+	wrap := lipgloss.NewStyle().Width(Width)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("Alerts"))
+	for _, ex := range []string{
+		"balance [wallet:0x…] [token:USDC] change:100",
+		"transfer [wallet:0x…] [token:USDC] min:1000",
+		"price token:WETH above:4000 below:3000",
+		"swap pool:0x… min:50k",
+		"range [wallet:0x…] [position:v3:123]",
+	} {
+		lines = append(lines, mutedStyle.Render("  "+ex))
+	}
+	lines = append(lines, wrap.Inherit(mutedStyle).Render("  notify:bell,osc9,osc777,webhook,command (default bell)"))
+	lines = append(lines, "")
+
+	lines = append(lines, wrap.Inherit(rowStyle).Render("Rule: "+input+"▏"))
+	if errMsg != "" {
+		lines = append(lines, wrap.Inherit(errStyle).Render(errMsg))
+	}
+	lines = append(lines, "")
+
+	status := mutedStyle.Render("idle")
+	if watching {
+		status = okStyle.Render("watching")
+	}
+	lines = append(lines, titleStyle.Render("Rules")+"  "+status)
+	if len(rules) == 0 {
+		lines = append(lines, mutedStyle.Render("  none yet"))
+	}
+	for i, r := range rules {
+		text := r.Rule
+		style := rowStyle
+		if r.Disabled {
+			style = offStyle
+		}
+		row := style.Render("  " + text)
+		if i == selected {
+			row = selStyle.Render("▶ " + text)
+		}
+		if at, ok := fired[r.Rule]; ok {
+			row += "  " + mutedStyle.Render("fired "+at.Format("15:04:05"))
+		} else if r.Disabled {
+			row += "  " + mutedStyle.Render("off")
+		}
+		lines = append(lines, row)
+	}
+	lines = append(lines, "")
+
+	hook := func(name, value, key string) string {
+		if value == "" {
+			return mutedStyle.Render(name + ": not set (" + key + " in config)")
+		}
+		return mutedStyle.Render(name+": ") + rowStyle.Render(value)
+	}
+	lines = append(lines, wrap.Render(hook("Webhook", webhook, "alert_webhook")))
+	lines = append(lines, wrap.Render(hook("Command", command, "alert_command")))
+	lines = append(lines, "")
+
+	help := "Enter add • ↑/↓ select • Ctrl+E enable/disable • Ctrl+T test • Ctrl+D delete • Esc close"
+	lines = append(lines, wrap.Inherit(mutedStyle).Render(help))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,211 @@
+package approvals
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ListRows is the maximum number of approval rows Render shows at once; the
+// list scrolls to keep the selection in view.
+const ListRows = 12
+
+// Row is one spender allowance as shown on the Approvals page, with its
+// live on-chain value rather than the amount from the last indexed event.
+type Row struct {
+	Token        common.Address
+	Symbol       string
+	Decimals     uint8
+	Spender      common.Address
+	SpenderLabel string // known protocol name, "" if unknown
+	Permit2      bool   // Permit2 sub-allowance rather than a token approve()
+	Allowance    *big.Int
+	Expiration   uint64 // Permit2 only, unix seconds
+	LastBlock    uint64 // block of the last indexed change
+	Err          string // live read failed
+}
+
+// maxUint160 is Permit2's "unlimited" amount.
+var maxUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+// Active reports whether the row still lets its spender move tokens.
+func (r Row) Active() bool {
+	if r.Allowance == nil || r.Allowance.Sign() == 0 {
+		return false
+	}
+	return !r.Permit2 || r.Expiration > uint64(time.Now().Unix())
+}
+
+// Nav returns the navigation bar for the Approvals page.
+func Nav(width int, showAll, indexerActive bool) string {
+	var iItem string
+	if indexerActive {
+		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
+		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
+		iItem = iKey + " " + iLabel
+	} else {
+		iItem = styles.Key("i") + " indexer"
+	}
+
+	filter := " show revoked"
+	if showAll {
+		filter = " hide revoked"
+	}
+	left := strings.Join([]string{
+		styles.Key("↑/↓") + " select",
+		styles.Key("x") + " revoke",
+		styles.Key("a") + filter,
+		styles.Key("r") + " rescan",
+		styles.Key("l") + " logger",
+		iItem,
+		styles.Key("Esc") + " back",
+	}, "   ")
+
+	return styles.NavStyle.Width(width).Render(left)
+}
+
+// Render draws the active wallet's allowances: one row per (token, spender,
+// ledger) with its live amount, followed by the selected row's details.
+func Render(width int, rows []Row, selectedIdx int, showAll, loading bool, errMsg, spinner string) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	rowStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(styles.CWarn)
+
+	lines := []string{styles.TitleStyle.Render("Token Approvals"), ""}
+
+	switch {
+	case loading:
+		lines = append(lines, mutedStyle.Render(spinner+" Scanning approval history and reading live allowances…"))
+		return strings.Join(lines, "\n")
+	case errMsg != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CError).Render("Error: "+errMsg))
+		return strings.Join(lines, "\n")
+	case len(rows) == 0 && showAll:
+		lines = append(lines, mutedStyle.Render("This wallet has never approved a spender."))
+		return strings.Join(lines, "\n")
+	case len(rows) == 0:
+		lines = append(lines, mutedStyle.Render("No live allowances. Press ")+styles.Key("a")+mutedStyle.Render(" to include revoked and expired ones."))
+		return strings.Join(lines, "\n")
+	}
+
+	intro := fmt.Sprintf("%d live allowance(s):", len(rows))
+	if showAll {
+		intro = fmt.Sprintf("%d spender(s) ever approved:", len(rows))
+	}
+	lines = append(lines, mutedStyle.Render(intro), "")
+
+	start := 0
+	if selectedIdx >= ListRows {
+		start = selectedIdx - ListRows + 1
+	}
+	end := start + ListRows
+	if end > len(rows) {
+		end = len(rows)
+	}
+	for i := start; i < end; i++ {
+		r := rows[i]
+		ledger := "ERC-20 "
+		if r.Permit2 {
+			ledger = "Permit2"
+		}
+		label := fmt.Sprintf("%-8s %s  %-22s %s", tokenLabel(r), ledger, spenderText(r), amountText(r))
+		label = ansi.Truncate(label, helpers.Max(10, width-2), "…")
+		switch {
+		case i == selectedIdx:
+			lines = append(lines, selStyle.Render("▶ "+label))
+		case r.Active() && isUnlimited(r):
+			lines = append(lines, "  "+warnStyle.Render(label))
+		case r.Active():
+			lines = append(lines, "  "+rowStyle.Render(label))
+		default:
+			lines = append(lines, "  "+mutedStyle.Render(label))
+		}
+	}
+	if len(rows) > ListRows {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d–%d of %d", start+1, end, len(rows))))
+	}
+
+	if selectedIdx >= 0 && selectedIdx < len(rows) {
+		lines = append(lines, "", renderDetails(rows[selectedIdx]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderDetails(r Row) string {
+	keyStyle := lipgloss.NewStyle().Foreground(styles.CMuted).Width(12)
+	valStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	row := func(k, v string) string {
+		return keyStyle.Render(k) + valStyle.Render(v)
+	}
+
+	lines := []string{
+		row("Token", tokenLabel(r)+"  "+r.Token.Hex()),
+		row("Spender", r.Spender.Hex()),
+	}
+	if r.SpenderLabel != "" {
+		lines = append(lines, row("", r.SpenderLabel))
+	}
+	lines = append(lines, row("Allowance", amountText(r)))
+	if r.Permit2 && r.Expiration > 0 {
+		lines = append(lines, row("Expires", time.Unix(int64(r.Expiration), 0).Format("2006-01-02 15:04")))
+	}
+	lines = append(lines, row("Last change", fmt.Sprintf("block %d", r.LastBlock)))
+	if r.Err != "" {
+		lines = append(lines, row("Live read", lipgloss.NewStyle().Foreground(styles.CError).Render(r.Err)))
+	}
+	revoke := "approve(spender, 0) on the token"
+	if r.Permit2 {
+		revoke = "Permit2 lockdown([(token, spender)])"
+	}
+	lines = append(lines, row("Revoke via", revoke))
+	return strings.Join(lines, "\n")
+}
+
+func tokenLabel(r Row) string {
+	if r.Symbol != "" {
+		return r.Symbol
+	}
+	return helpers.ShortenAddr(r.Token.Hex())
+}
+
+func spenderText(r Row) string {
+	if r.SpenderLabel != "" {
+		return r.SpenderLabel
+	}
+	return helpers.ShortenAddr(r.Spender.Hex())
+}
+
+// isUnlimited reports whether the allowance is at (or within a rounding
+// error of) the ledger's max value — the usual "infinite approval".
+func isUnlimited(r Row) bool {
+	if r.Allowance == nil {
+		return false
+	}
+	if r.Permit2 {
+		return r.Allowance.Cmp(maxUint160) >= 0
+	}
+	return r.Allowance.BitLen() >= 255
+}
+
+func amountText(r Row) string {
+	switch {
+	case r.Err != "" && r.Allowance == nil:
+		return "?"
+	case r.Allowance == nil || r.Allowance.Sign() == 0:
+		return "revoked"
+	case r.Permit2 && r.Expiration <= uint64(time.Now().Unix()):
+		return "expired"
+	case isUnlimited(r):
+		return "unlimited"
+	}
+	return helpers.FormatToken(r.Allowance, r.Decimals, r.Symbol)
+}
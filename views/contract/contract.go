@@ -0,0 +1,166 @@
+package contract
+
+import (
+	"fmt"
+	"strings"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ListRows is the maximum number of library entries or functions Render
+// shows at once; the list scrolls to keep the selection in view.
+const ListRows = 14
+
+// Function is one ABI method as listed on the Contract page.
+type Function struct {
+	Name    string // key in abi.ABI.Methods (unique across overloads)
+	Sig     string // e.g. "balanceOf(address owner) → (uint256)"
+	Read    bool   // view/pure: called with eth_call
+	Payable bool
+	Result  string // last call's result or error, "" before the first call
+	Err     bool
+	Calling bool
+}
+
+// Nav returns the navigation bar for the Contract page. mode is "library"
+// or "functions".
+func Nav(width int, mode string, indexerActive bool) string {
+	var iItem string
+	if indexerActive {
+		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
+		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
+		iItem = iKey + " " + iLabel
+	} else {
+		iItem = styles.Key("i") + " indexer"
+	}
+
+	var items []string
+	if mode == "functions" {
+		items = []string{
+			styles.Key("↑/↓") + " select",
+			styles.Key("Enter") + " call",
+			styles.Key("c") + " copy address",
+		}
+	} else {
+		items = []string{
+			styles.Key("↑/↓") + " select",
+			styles.Key("Enter") + " open",
+			styles.Key("n") + " new",
+			styles.Key("u") + " use ABI at…",
+			styles.Key("Del") + " delete",
+		}
+	}
+	items = append(items, styles.Key("l")+" logger", iItem, styles.Key("Esc")+" back")
+	return styles.NavStyle.Width(width).Render(strings.Join(items, "   "))
+}
+
+// RenderLibrary lists the saved ABIs the user can reopen.
+func RenderLibrary(width int, entries []store.SavedABI, selectedIdx int) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	rowStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+
+	lines := []string{styles.TitleStyle.Render("Contract"), ""}
+	if len(entries) == 0 {
+		lines = append(lines, mutedStyle.Render("The ABI library is empty. Press ")+styles.Key("n")+
+			mutedStyle.Render(" to open a contract from a pasted ABI or an ABI file — it is saved here for next time."))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("ABI library — %d saved:", len(entries))), "")
+	start, end := window(selectedIdx, len(entries))
+	for i := start; i < end; i++ {
+		e := entries[i]
+		addr := "no address"
+		if e.Address != "" {
+			addr = helpers.LabelAddr(e.Address)
+		}
+		label := fmt.Sprintf("%-24s %s", e.Name, addr)
+		label = ansi.Truncate(label, helpers.Max(10, width-2), "…")
+		if i == selectedIdx {
+			lines = append(lines, selStyle.Render("▶ "+label))
+		} else {
+			lines = append(lines, "  "+rowStyle.Render(label))
+		}
+	}
+	if len(entries) > ListRows {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d–%d of %d", start+1, end, len(entries))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderFunctions lists an opened contract's functions, reads first, with
+// the selected function's last result underneath.
+func RenderFunctions(width int, name, address string, funcs []Function, selectedIdx int, spinner string) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	readStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	writeStyle := lipgloss.NewStyle().Foreground(styles.CWarn)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+
+	lines := []string{
+		styles.TitleStyle.Render("Contract — " + name),
+		mutedStyle.Render(helpers.AddrWithLabel(address)),
+		"",
+	}
+	if len(funcs) == 0 {
+		lines = append(lines, mutedStyle.Render("This ABI has no functions."))
+		return strings.Join(lines, "\n")
+	}
+
+	start, end := window(selectedIdx, len(funcs))
+	for i := start; i < end; i++ {
+		f := funcs[i]
+		kind := "read "
+		style := readStyle
+		if !f.Read {
+			kind = "write"
+			style = writeStyle
+			if f.Payable {
+				kind = "pay  "
+			}
+		}
+		label := ansi.Truncate(kind+"  "+f.Sig, helpers.Max(10, width-2), "…")
+		if i == selectedIdx {
+			lines = append(lines, selStyle.Render("▶ "+label))
+		} else {
+			lines = append(lines, "  "+style.Render(label))
+		}
+	}
+	if len(funcs) > ListRows {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d–%d of %d", start+1, end, len(funcs))))
+	}
+
+	if selectedIdx >= 0 && selectedIdx < len(funcs) {
+		f := funcs[selectedIdx]
+		lines = append(lines, "")
+		switch {
+		case f.Calling:
+			lines = append(lines, mutedStyle.Render(spinner+" Calling…"))
+		case f.Err:
+			lines = append(lines, lipgloss.NewStyle().Foreground(styles.CError).Render("Error: "+f.Result))
+		case f.Result != "":
+			lines = append(lines, lipgloss.NewStyle().Foreground(styles.CSuccess).Render("Result: ")+readStyle.Render(f.Result))
+		case f.Read:
+			lines = append(lines, mutedStyle.Render("Enter to call (eth_call — free, nothing is signed)."))
+		default:
+			lines = append(lines, mutedStyle.Render("Enter to fill in the arguments and package the call as an EIP-4527 transaction."))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func window(selectedIdx, n int) (start, end int) {
+	if selectedIdx >= ListRows {
+		start = selectedIdx - ListRows + 1
+	}
+	end = start + ListRows
+	if end > n {
+		end = n
+	}
+	return start, end
+}
@@ -5,8 +5,10 @@ import (
 	"charm-wallet-tui/helpers"
 	"charm-wallet-tui/styles"
 	"math/big"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -24,6 +26,9 @@ func Nav(width int, indexerActive bool) string {
 	left := strings.Join([]string{
 		styles.Key("Tab") + " select next",
 		styles.Key("Enter") + " open",
+		styles.Key("a") + " add",
+		styles.Key("e") + " edit",
+		styles.Key("Del") + " delete",
 		styles.Key("l") + " logger",
 		iItem,
 		styles.Key("Esc") + " back",
@@ -32,6 +37,34 @@ func Nav(width int, indexerActive bool) string {
 	return styles.NavStyle.Width(width).Render(left)
 }
 
+// ModuleNav returns the navigation bar for a dApp module's page: the
+// module's enabled key bindings followed by the global logger, indexer and
+// back keys.
+func ModuleNav(width int, bindings []key.Binding, indexerActive bool) string {
+	var items []string
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		h := b.Help()
+		items = append(items, styles.Key(h.Key)+" "+h.Desc)
+	}
+
+	iItem := styles.Key("i") + " indexer"
+	if indexerActive {
+		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
+		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
+		iItem = iKey + " " + iLabel
+	}
+	items = append(items,
+		styles.Key("l")+" logger",
+		iItem,
+		styles.Key("Esc")+" back",
+	)
+
+	return styles.NavStyle.Width(width).Render(strings.Join(items, "   "))
+}
+
 // dAppCardStyle returns the style for a dApp card (unfocused)
 func dAppCardStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
@@ -57,8 +90,8 @@ func dAppCardFocusedStyle() lipgloss.Style {
 
 // renderDAppCard renders a single dApp card. chainID is the currently
 // connected network, used as the card's network label when dapp.Network is
-// blank (i.e. the dapp follows whichever network is active, rather than
-// being pinned to one — see config.DefaultDapps).
+// blank and no dapp.ChainID is set (i.e. the dapp follows whichever network
+// is active, rather than being pinned to one — see config.DefaultDapps).
 func renderDAppCard(dapp config.DApp, focused bool, chainID *big.Int) string {
 	icon := dapp.Icon
 	if icon == "" {
@@ -78,7 +111,11 @@ func renderDAppCard(dapp config.DApp, focused bool, chainID *big.Int) string {
 	// Network badge: dapp.Network pins a fixed label (e.g. Terra Nullius,
 	// whose contract only ever exists on mainnet); blank means "follow the
 	// active connection" (e.g. Uniswap v4, supported on both networks).
+	// User-defined dapps are pinned by chain ID instead.
 	label := dapp.Network
+	if label == "" && dapp.ChainID != nil {
+		label = helpers.ChainName(dapp.ChainID)
+	}
 	if label == "" {
 		label = helpers.ChainName(chainID)
 	}
@@ -100,14 +137,15 @@ func renderDAppCard(dapp config.DApp, focused bool, chainID *big.Int) string {
 }
 
 // Render renders the dApp browser view with grid layout. chainID is the
-// currently connected network (see renderDAppCard).
-func Render(width int, dapps []config.DApp, selectedIdx int, chainID *big.Int) string {
+// currently connected network (see renderDAppCard). actions lists the
+// highlighted card's module commands, shown numbered under its description.
+func Render(width int, dapps []config.DApp, selectedIdx int, chainID *big.Int, actions []string) string {
 	h := styles.TitleStyle.Render("dApp Browser")
 
 	if len(dapps) == 0 {
 		emptyMsg := lipgloss.NewStyle().
 			Foreground(styles.CMuted).
-			Render("No dApps configured.")
+			Render("No dApps configured. Press a to add one.")
 		return h + "\n\n" + emptyMsg
 	}
 
@@ -162,5 +200,16 @@ func Render(width int, dapps []config.DApp, selectedIdx int, chainID *big.Int) s
 		}
 	}
 
+	if len(actions) > 0 {
+		items := make([]string, len(actions))
+		for i, a := range actions {
+			items[i] = styles.Key(strconv.Itoa(i+1)) + " " + a
+		}
+		out += "\n\n" + lipgloss.NewStyle().
+			Width(width-4).
+			Align(lipgloss.Center).
+			Render(strings.Join(items, "   "))
+	}
+
 	return out
 }
@@ -84,6 +84,14 @@ func Render(details rpc.WalletDetails, wallets []config.WalletEntry, loading boo
 
 	lines := []string{h, sub, "", ethLine, ""}
 
+	if details.Safe != nil {
+		lines = append(lines, renderSafe(details.Safe)...)
+		lines = append(lines, "")
+	}
+	if details.SafeErr != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CWarn).Render("⚠ Could not check for a Gnosis Safe: "+details.SafeErr), "")
+	}
+
 	if len(details.Tokens) == 0 {
 		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CMuted).Render("No watched token balances found (non-zero)."))
 		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CMuted).Render("Edit tokenWatch in code (or add config) to track more tokens."))
@@ -103,3 +111,33 @@ func Render(details rpc.WalletDetails, wallets []config.WalletEntry, loading boo
 
 	return strings.Join(lines, "\n")
 }
+
+// renderSafe lists a Safe's configuration: version, threshold, nonce, owners
+// and enabled modules (modules can move funds without owner signatures, so
+// they are shown in the warning colour).
+func renderSafe(s *rpc.SafeInfo) []string {
+	muted := lipgloss.NewStyle().Foreground(styles.CMuted)
+	text := lipgloss.NewStyle().Foreground(styles.CText)
+
+	version := s.Version
+	if version == "" {
+		version = "unknown version"
+	}
+	lines := []string{
+		lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true).Render("Gnosis Safe") + "  " + muted.Render("v"+version),
+		muted.Render("Threshold ") + text.Render(fmt.Sprintf("%d of %d", s.Threshold, len(s.Owners))) +
+			muted.Render("   Nonce ") + text.Render(fmt.Sprintf("%d", s.Nonce)),
+		muted.Render("Owners"),
+	}
+	for _, o := range s.Owners {
+		lines = append(lines, "  "+text.Render(helpers.AddrWithLabel(o.Hex())))
+	}
+	if len(s.Modules) > 0 {
+		warn := lipgloss.NewStyle().Foreground(styles.CWarn)
+		lines = append(lines, warn.Render(fmt.Sprintf("Modules (%d) — can execute without owner signatures", len(s.Modules))))
+		for _, mod := range s.Modules {
+			lines = append(lines, "  "+warn.Render(helpers.AddrWithLabel(mod.Hex())))
+		}
+	}
+	return lines
+}
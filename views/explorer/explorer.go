@@ -0,0 +1,139 @@
+package explorer
+
+import (
+	"strings"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/scrollbar"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Row is one visible line of the explorer's result tree.
+type Row struct {
+	Node   *helpers.ExplorerNode
+	Depth  int
+	Parent int // row index of the parent node, -1 for a top-level section
+}
+
+// Rows flattens the open parts of the tree in display order.
+func Rows(roots []*helpers.ExplorerNode) []Row {
+	var rows []Row
+	var walk func(nodes []*helpers.ExplorerNode, depth, parent int)
+	walk = func(nodes []*helpers.ExplorerNode, depth, parent int) {
+		for _, n := range nodes {
+			rows = append(rows, Row{Node: n, Depth: depth, Parent: parent})
+			if n.Open {
+				walk(n.Children, depth+1, len(rows)-1)
+			}
+		}
+	}
+	walk(roots, 0, -1)
+	return rows
+}
+
+// Nav returns the navigation bar for the Block Explorer page.
+func Nav(width int, editing, indexerActive bool) string {
+	if editing {
+		left := strings.Join([]string{
+			styles.Key("Enter") + " explore",
+			styles.Key("Ctrl+U") + " clear",
+			styles.Key("Esc") + " back",
+		}, "   ")
+		return styles.NavStyle.Width(width).Render(left)
+	}
+
+	var iItem string
+	if indexerActive {
+		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
+		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
+		iItem = iKey + " " + iLabel
+	} else {
+		iItem = styles.Key("i") + " indexer"
+	}
+	left := strings.Join([]string{
+		styles.Key("↑/↓") + " move",
+		styles.Key("←/→") + " fold",
+		styles.Key("Enter") + " toggle",
+		styles.Key("+/-") + " expand/collapse all",
+		styles.Key("t") + " inspect tx",
+		styles.Key("/") + " new query",
+		styles.Key("r") + " reload",
+		styles.Key("l") + " logger",
+		iItem,
+		styles.Key("Esc") + " back",
+	}, "   ")
+	return styles.NavStyle.Width(width).Render(left)
+}
+
+// Render draws the query line and the result tree (see Tree).
+func Render(width, height int, input string, editing bool, title string, rows []Row, selected, offset int, loading bool, errMsg, spinner string) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	inputStyle := lipgloss.NewStyle().Foreground(styles.CText)
+
+	lines := []string{styles.TitleStyle.Render("Block Explorer")}
+	query := inputStyle.Render(input)
+	if editing {
+		query += "▏"
+	} else if input == "" {
+		query = mutedStyle.Render("press / to enter a query")
+	}
+	lines = append(lines, mutedStyle.Render("Query: ")+query)
+	lines = append(lines, mutedStyle.Render("block number or latest · tx hash · address · block and address"), "")
+
+	switch {
+	case loading:
+		lines = append(lines, mutedStyle.Render(spinner+" Decoding "+title+"…"))
+		return strings.Join(lines, "\n")
+	case errMsg != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CError).Render("Error: "+errMsg))
+		return strings.Join(lines, "\n")
+	case len(rows) == 0:
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, Tree(width, height, rows, selected, offset))
+	return strings.Join(lines, "\n")
+}
+
+// Tree draws height rows of a result tree from offset, with the selected
+// row marked and a scrollbar when the rows overflow.
+func Tree(width, height int, rows []Row, selected, offset int) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	selStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)
+
+	end := min(offset+height, len(rows))
+	var tree []string
+	for i := offset; i < end; i++ {
+		r := rows[i]
+		marker := "  "
+		switch {
+		case len(r.Node.Children) > 0 && r.Node.Open:
+			marker = "▾ "
+		case len(r.Node.Children) > 0:
+			marker = "▸ "
+		}
+		cursor := "  "
+		if i == selected {
+			cursor = selStyle.Render("▶ ")
+			marker = selStyle.Render(marker)
+		} else {
+			marker = mutedStyle.Render(marker)
+		}
+		line := cursor + strings.Repeat("  ", r.Depth) + marker + r.Node.Text
+		tree = append(tree, ansi.Truncate(line, helpers.Max(10, width-2), "…"))
+	}
+	track := scrollbar.Track(height, len(rows), offset)
+	for len(tree) < len(track) {
+		tree = append(tree, "")
+	}
+	// Pad rows to a common width so the scrollbar lines up.
+	if len(track) > 0 {
+		for i := range tree {
+			tree[i] += strings.Repeat(" ", helpers.Max(0, width-2-ansi.StringWidth(tree[i])))
+		}
+	}
+	return scrollbar.Decorate(strings.Join(tree, "\n"), track)
+}
@@ -0,0 +1,76 @@
+package outbox
+
+import (
+	"fmt"
+	"strings"
+
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Width is the content width of the outbox popup (excluding DialogBox padding).
+const Width = 76
+
+// Render draws the outbox popup: every locally reserved nonce for the active
+// wallet with its status, followed by any nonce gaps that would block the
+// queued txs above them.
+func Render(entries []store.NonceReservation, selectedIdx int, pending uint64, gaps []uint64, loading bool, errMsg, spinner string) string {
+	titleStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true).Align(lipgloss.Center).Width(Width)
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	rowStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(styles.CWarn).Bold(true)
+
+	lines := []string{titleStyle.Render("Outbox — Queued Transactions"), ""}
+
+	switch {
+	case loading:
+		lines = append(lines, mutedStyle.Render(spinner+" Checking pending nonce…"))
+	case errMsg != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CError).Render("Error: "+errMsg))
+	default:
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("Chain pending nonce: %d", pending)))
+		if len(gaps) > 0 {
+			gapStrs := make([]string, len(gaps))
+			for i, g := range gaps {
+				gapStrs[i] = fmt.Sprint(g)
+			}
+			lines = append(lines, warnStyle.Render("⚠ Nonce gap at "+strings.Join(gapStrs, ", ")+
+				" — txs queued above it cannot be mined until it is filled"))
+		}
+		lines = append(lines, "")
+
+		if len(entries) == 0 {
+			lines = append(lines, mutedStyle.Render("No queued transactions."))
+		}
+		for i, e := range entries {
+			summary := strings.SplitN(e.Summary, "\n", 2)[0]
+			label := fmt.Sprintf("#%-4d %-9s %s", e.Nonce, statusLabel(e.Status), summary)
+			label = ansi.Truncate(label, Width-2, "…")
+			if i == selectedIdx {
+				lines = append(lines, selStyle.Render("▶ "+label))
+			} else {
+				lines = append(lines, rowStyle.Render("  "+label))
+			}
+		}
+	}
+
+	lines = append(lines, "",
+		mutedStyle.Render("↑/↓ select • Enter show QR • s re-sign • d discard • r refresh • Esc close"))
+	return strings.Join(lines, "\n")
+}
+
+// statusLabel maps a reservation status to the short tag shown in its row.
+func statusLabel(status string) string {
+	switch status {
+	case store.NonceBroadcast:
+		return "sent"
+	case store.NonceStale:
+		return "stale"
+	default:
+		return "queued"
+	}
+}
@@ -10,28 +10,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Nav returns the navigation bar for Terra Nullius view
-func Nav(width int, indexerActive bool) string {
-	var iItem string
-	if indexerActive {
-		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
-		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
-		iItem = iKey + " " + iLabel
-	} else {
-		iItem = styles.Key("i") + " indexer"
-	}
-
-	left := strings.Join([]string{
-		styles.Key("↑/↓") + " navigate",
-		styles.Key("Tab") + " next",
-		styles.Key("Enter") + " select",
-		styles.Key("Esc") + " back",
-		styles.Key("l") + " logger",
-		iItem,
-	}, "   ")
-	return styles.NavStyle.Width(width).Render(left)
-}
-
 // MainGeometry reports hit-test rectangles for the clickable elements of the
 // main Terra Nullius page (the claims-query box and the Claim box), relative
 // to Render's own returned string (row/col 0 = its top-left corner). The
@@ -0,0 +1,174 @@
+package transactions
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/store"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ListRows is the maximum number of history rows Render shows at once; the
+// list scrolls to keep the selection in view.
+const ListRows = 10
+
+// Nav returns the navigation bar for the Transactions page.
+func Nav(width int, indexerActive bool) string {
+	var iItem string
+	if indexerActive {
+		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
+		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
+		iItem = iKey + " " + iLabel
+	} else {
+		iItem = styles.Key("i") + " indexer"
+	}
+
+	left := strings.Join([]string{
+		styles.Key("↑/↓") + " select",
+		styles.Key("Enter") + " show QR",
+		styles.Key("b") + " re-broadcast",
+		styles.Key("o") + " explorer",
+		styles.Key("t") + " inspect",
+		styles.Key("r") + " refresh",
+		styles.Key("l") + " logger",
+		iItem,
+		styles.Key("Esc") + " back",
+	}, "   ")
+
+	return styles.NavStyle.Width(width).Render(left)
+}
+
+// Render draws the transaction history: a scrolling list of every packaged,
+// signed and broadcast tx (newest first) followed by the selected row's
+// details.
+func Render(width int, records []store.TxRecord, selectedIdx int, loading bool, errMsg, spinner string) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	rowStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+
+	lines := []string{styles.TitleStyle.Render("Transactions"), ""}
+
+	switch {
+	case loading && len(records) == 0:
+		lines = append(lines, mutedStyle.Render(spinner+" Loading transaction history…"))
+		return strings.Join(lines, "\n")
+	case errMsg != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CError).Render("Error: "+errMsg))
+		return strings.Join(lines, "\n")
+	case len(records) == 0:
+		lines = append(lines, mutedStyle.Render("No transactions yet — everything you package, sign or broadcast is recorded here."))
+		return strings.Join(lines, "\n")
+	}
+
+	intro := fmt.Sprintf("%d recorded, newest first:", len(records))
+	if loading {
+		intro = spinner + " " + intro
+	}
+	lines = append(lines, mutedStyle.Render(intro), "")
+
+	start := 0
+	if selectedIdx >= ListRows {
+		start = selectedIdx - ListRows + 1
+	}
+	end := start + ListRows
+	if end > len(records) {
+		end = len(records)
+	}
+	for i := start; i < end; i++ {
+		r := records[i]
+		summary := strings.SplitN(r.Summary, "\n", 2)[0]
+		tag := fmt.Sprintf("%-10s", statusLabel(r.Status))
+		rest := fmt.Sprintf("%-9s #%-4d %s", helpers.ChainName(new(big.Int).SetUint64(r.ChainID)), r.Nonce, summary)
+		rest = ansi.Truncate(rest, helpers.Max(10, width-len(tag)-4), "…")
+		if i == selectedIdx {
+			lines = append(lines, selStyle.Render("▶ "+tag+rest))
+		} else {
+			lines = append(lines, "  "+statusStyle(r.Status).Render(tag)+rowStyle.Render(rest))
+		}
+	}
+	if len(records) > ListRows {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d–%d of %d", start+1, end, len(records))))
+	}
+
+	if selectedIdx >= 0 && selectedIdx < len(records) {
+		lines = append(lines, "", renderDetails(records[selectedIdx]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderDetails draws the key/value block for one history row.
+func renderDetails(r store.TxRecord) string {
+	keyStyle := lipgloss.NewStyle().Foreground(styles.CMuted).Width(12)
+	valStyle := lipgloss.NewStyle().Foreground(styles.CText)
+
+	row := func(k, v string) string {
+		return keyStyle.Render(k) + valStyle.Render(v)
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true).Render(strings.SplitN(r.Summary, "\n", 2)[0]),
+		row("Status", statusStyle(r.Status).Render(r.Status)),
+		row("From", r.From.Hex()),
+		row("Nonce", fmt.Sprint(r.Nonce)),
+		row("Chain", helpers.ChainName(new(big.Int).SetUint64(r.ChainID))),
+	}
+	if r.TxHash != "" {
+		lines = append(lines, row("Hash", r.TxHash))
+	}
+	if r.Block > 0 {
+		lines = append(lines, row("Block", fmt.Sprint(r.Block)), row("Gas used", fmt.Sprint(r.GasUsed)))
+	}
+	if r.RevertReason != "" {
+		lines = append(lines, row("Revert", lipgloss.NewStyle().Foreground(styles.CError).Render(r.RevertReason)))
+	}
+	lines = append(lines, row("Created", r.CreatedAt))
+	if r.UpdatedAt != "" && r.UpdatedAt != r.CreatedAt {
+		lines = append(lines, row("Updated", r.UpdatedAt))
+	}
+
+	var avail []string
+	if r.UR != "" {
+		avail = append(avail, "unsigned QR")
+	}
+	if r.SignedRaw != "" {
+		avail = append(avail, "signed tx")
+	}
+	if len(avail) > 0 {
+		lines = append(lines, row("Stored", strings.Join(avail, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statusLabel maps a history status to the fixed-width tag shown in its row.
+func statusLabel(status string) string {
+	switch status {
+	case store.TxSigned:
+		return "signed"
+	case store.TxBroadcast:
+		return "pending"
+	case store.TxConfirmed:
+		return "confirmed"
+	case store.TxFailed:
+		return "failed"
+	default:
+		return "packaged"
+	}
+}
+
+func statusStyle(status string) lipgloss.Style {
+	switch status {
+	case store.TxConfirmed:
+		return lipgloss.NewStyle().Foreground(styles.CAccent)
+	case store.TxFailed:
+		return lipgloss.NewStyle().Foreground(styles.CError)
+	case store.TxBroadcast, store.TxSigned:
+		return lipgloss.NewStyle().Foreground(styles.CWarn)
+	default:
+		return lipgloss.NewStyle().Foreground(styles.CMuted)
+	}
+}
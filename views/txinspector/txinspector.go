@@ -0,0 +1,75 @@
+package txinspector
+
+import (
+	"strings"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+	"charm-wallet-tui/views/explorer"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Nav returns the navigation bar for the Transaction Inspector page.
+func Nav(width int, editing, indexerActive bool) string {
+	if editing {
+		left := strings.Join([]string{
+			styles.Key("Enter") + " inspect",
+			styles.Key("Ctrl+U") + " clear",
+			styles.Key("Esc") + " back",
+		}, "   ")
+		return styles.NavStyle.Width(width).Render(left)
+	}
+
+	var iItem string
+	if indexerActive {
+		iKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("i")
+		iLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("indexer")
+		iItem = iKey + " " + iLabel
+	} else {
+		iItem = styles.Key("i") + " indexer"
+	}
+	left := strings.Join([]string{
+		styles.Key("↑/↓") + " move",
+		styles.Key("←/→") + " fold",
+		styles.Key("Enter") + " toggle",
+		styles.Key("+/-") + " expand/collapse all",
+		styles.Key("b") + " block",
+		styles.Key("/") + " new hash",
+		styles.Key("r") + " reload",
+		styles.Key("l") + " logger",
+		iItem,
+		styles.Key("Esc") + " back",
+	}, "   ")
+	return styles.NavStyle.Width(width).Render(left)
+}
+
+// Render draws the hash line and the decoded transaction's tree.
+func Render(width, height int, input string, editing bool, rows []explorer.Row, selected, offset int, loading bool, errMsg, spinner string) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	inputStyle := lipgloss.NewStyle().Foreground(styles.CText)
+
+	lines := []string{styles.TitleStyle.Render("Transaction Inspector")}
+	hash := inputStyle.Render(input)
+	if editing {
+		hash += "▏"
+	} else if input == "" {
+		hash = mutedStyle.Render("press / to enter a transaction hash")
+	}
+	lines = append(lines, mutedStyle.Render("Tx: ")+hash)
+	lines = append(lines, mutedStyle.Render("receipt · decoded input and logs · balance changes · call trace · revert reason"), "")
+
+	switch {
+	case loading:
+		lines = append(lines, mutedStyle.Render(spinner+" Inspecting "+helpers.ShortenAddr(input)+"…"))
+		return strings.Join(lines, "\n")
+	case errMsg != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CError).Render("Error: "+errMsg))
+		return strings.Join(lines, "\n")
+	case len(rows) == 0:
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, explorer.Tree(width, height, rows, selected, offset))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,72 @@
+package uniswap
+
+import (
+	"fmt"
+	"strings"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// HookRiskColor is the color a hook risk level is shown in.
+func HookRiskColor(r helpers.HookRisk) lipgloss.Color {
+	switch r {
+	case helpers.HookRiskHigh:
+		return styles.CError
+	case helpers.HookRiskMedium:
+		return styles.CWarn
+	default:
+		return styles.CSuccess
+	}
+}
+
+// RenderHookReport renders a V4 hook's risk summary: the risk level, its
+// findings, the enabled callbacks, and the proxy and deployment details.
+func RenderHookReport(width int, r *helpers.HookReport) string {
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	textStyle := lipgloss.NewStyle().Foreground(styles.CText).Width(width)
+	riskStyle := lipgloss.NewStyle().Foreground(HookRiskColor(r.Risk)).Bold(true)
+
+	lines := []string{
+		labelStyle.Render("Hook ") + helpers.HyperAddr(r.Address) + labelStyle.Render("  risk ") + riskStyle.Render(r.Risk.String()),
+	}
+	for _, f := range r.Findings {
+		lines = append(lines, textStyle.Render("• "+f))
+	}
+
+	names := make([]string, len(r.Permissions))
+	for i, p := range r.Permissions {
+		names[i] = p.Name
+	}
+	callbacks := "none"
+	if len(names) > 0 {
+		callbacks = strings.Join(names, ", ")
+	}
+	lines = append(lines, labelStyle.Width(width).Render("Callbacks: "+callbacks))
+
+	code := fmt.Sprintf("Code: %d bytes", r.CodeSize)
+	switch {
+	case r.Upgradeable():
+		code += " · EIP-1967 proxy"
+	case r.CloneOf != (common.Address{}):
+		code += " · EIP-1167 clone"
+	default:
+		code += " · not a proxy"
+	}
+	lines = append(lines, labelStyle.Render(code))
+
+	switch {
+	case r.Deployer != (common.Address{}):
+		lines = append(lines, labelStyle.Render("Deployer: ")+helpers.HyperAddr(r.Deployer)+
+			labelStyle.Render(fmt.Sprintf(" in block %d", r.DeployBlock)))
+	case r.DeployBlock > 0:
+		lines = append(lines, labelStyle.Width(width).Render(fmt.Sprintf("Deployed in block %d — %s", r.DeployBlock, r.DeployNote)))
+	case r.DeployNote != "":
+		lines = append(lines, labelStyle.Width(width).Render("Deployer: "+r.DeployNote))
+	}
+	lines = append(lines, labelStyle.Width(width).Render("Source verification is not checked (needs a block explorer)."))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+package uniswap
+
+import (
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LiquidityFormField is one row of the liquidity form: an edit buffer (or,
+// for the pool row, the selected pool) with its unit or hint.
+type LiquidityFormField struct {
+	Label string
+	Value string
+	Unit  string
+}
+
+// LiquidityForm is the state RenderLiquidityForm draws for the increase,
+// decrease and new-position forms.
+type LiquidityForm struct {
+	Title   string
+	Pool    string // e.g. "ETH/USDC 0.30% · spacing 60"
+	Price   string // current pool price line, or its loading state
+	Fields  []LiquidityFormField
+	Focused int
+	Preview []string // what the action deposits or returns at the current price
+	Err     string
+	Warn    string
+	Hint    string
+}
+
+// RenderLiquidityForm renders a V4 liquidity action form.
+func RenderLiquidityForm(width, height int, f LiquidityForm) string {
+	containerWidth := helpers.Min(72, width-4)
+
+	title := lipgloss.NewStyle().
+		Foreground(styles.CAccent2).
+		Bold(true).
+		Align(lipgloss.Center).
+		Width(containerWidth).
+		Render("🦄 " + f.Title)
+
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	valueStyle := lipgloss.NewStyle().Foreground(styles.CAccent2)
+	centered := func(s lipgloss.Style) lipgloss.Style { return s.Width(containerWidth).Align(lipgloss.Center) }
+
+	parts := []string{
+		title,
+		centered(labelStyle).Render(f.Pool),
+	}
+	if f.Price != "" {
+		parts = append(parts, centered(lipgloss.NewStyle().Foreground(styles.CText)).Render(f.Price))
+	}
+	parts = append(parts, "")
+
+	for i, fld := range f.Fields {
+		value := fld.Value
+		if value == "" {
+			value = "_"
+		}
+		content := labelStyle.Render(fld.Label) + "\n" + valueStyle.Render(value) + labelStyle.Render(" "+fld.Unit)
+		card := styles.CardNormal
+		if i == f.Focused {
+			card = styles.CardFocused
+		}
+		parts = append(parts, card.Width(containerWidth-4).Render(content))
+	}
+
+	if len(f.Preview) > 0 {
+		parts = append(parts, "")
+		for _, line := range f.Preview {
+			parts = append(parts, centered(lipgloss.NewStyle().Foreground(styles.CAccent)).Render(line))
+		}
+	}
+	if f.Err != "" {
+		parts = append(parts, "", centered(lipgloss.NewStyle().Foreground(styles.CError)).Render(f.Err))
+	}
+	if f.Warn != "" {
+		parts = append(parts, "", centered(lipgloss.NewStyle().Foreground(styles.CWarn)).Render(f.Warn))
+	}
+	parts = append(parts, "", centered(labelStyle).Render(f.Hint))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Align(lipgloss.Center).
+		Render(lipgloss.JoinVertical(lipgloss.Center, parts...))
+}
@@ -0,0 +1,263 @@
+package uniswap
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// chartLabelWidth is the width of the y-axis label column on both charts.
+const chartLabelWidth = 12
+
+// PoolAnalytics is the state RenderPoolAnalytics draws for one V4 pool.
+type PoolAnalytics struct {
+	Title    string // e.g. "ETH / USDC"
+	Subtitle string // fee, tick spacing, hooks, pool ID
+	Loading  bool
+	Err      string
+
+	Sym0, Sym1           string
+	Decimals0, Decimals1 uint8
+	Price                float64 // current price of token0 in token1
+	Swaps, LiqEvents     int
+
+	Candles  []helpers.Candle
+	Interval string // e.g. "1h (300 blocks)"
+
+	Depth       []helpers.DepthBucket
+	CurrentTick int32
+
+	Fees    helpers.FeeEstimate
+	Traders []helpers.Trader
+	Hint    string
+}
+
+// PoolChartColumns is how many candles or depth buckets fit across a page
+// of the given width.
+func PoolChartColumns(width int) int {
+	return helpers.Max(10, helpers.Min(width-4, 120)-chartLabelWidth-1)
+}
+
+// RenderPoolAnalytics renders the pool detail page: price candles, the
+// liquidity-depth histogram, the fee APR estimate and the top traders.
+func RenderPoolAnalytics(width, height int, p PoolAnalytics, spinView string) string {
+	containerWidth := helpers.Min(width-4, 120)
+
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	accentStyle := lipgloss.NewStyle().Foreground(styles.CAccent)
+	sectionStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)
+	centered := func(s lipgloss.Style) lipgloss.Style { return s.Width(containerWidth).Align(lipgloss.Center) }
+
+	parts := []string{
+		centered(lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)).Render("🦄 " + p.Title),
+		centered(labelStyle).Render(p.Subtitle),
+		"",
+	}
+
+	switch {
+	case p.Loading:
+		parts = append(parts, centered(labelStyle).Render(spinView+" Loading indexed pool events…"))
+	case p.Err != "":
+		parts = append(parts, centered(lipgloss.NewStyle().Foreground(styles.CError)).Render(p.Err))
+	case p.Swaps == 0 && p.LiqEvents == 0:
+		parts = append(parts, centered(labelStyle).Render("No swaps or liquidity events indexed for this pool yet."))
+	default:
+		parts = append(parts, centered(lipgloss.NewStyle().Foreground(styles.CText)).Render(
+			fmt.Sprintf("1 %s = %s %s   ·   %d swaps   ·   %d liquidity events",
+				p.Sym0, liquidityFormatPrice(p.Price), p.Sym1, p.Swaps, p.LiqEvents)))
+
+		// Everything below the charts takes about 12 lines; the charts
+		// split what is left, candles getting the larger share.
+		avail := helpers.Max(8, height-16-len(p.Traders))
+		candleH := helpers.Max(5, avail*3/5)
+		depthH := helpers.Max(3, avail-candleH)
+
+		parts = append(parts, "",
+			sectionStyle.Render("Price · "+p.Interval+" candles"),
+			candleChart(p.Candles, containerWidth, candleH),
+			"",
+			sectionStyle.Render("Liquidity depth by tick"),
+			depthChart(p.Depth, p.CurrentTick, p.Decimals0, p.Decimals1, containerWidth, depthH),
+			"",
+			feeLine(p, labelStyle, accentStyle),
+		)
+		if len(p.Traders) > 0 {
+			parts = append(parts, "", sectionStyle.Render("Top traders")+labelStyle.Render("  (swap callers — usually routers)"))
+			for i, t := range p.Traders {
+				parts = append(parts, labelStyle.Render(fmt.Sprintf("%2d. ", i+1))+
+					helpers.HyperAddr(common.HexToAddress(t.Sender))+
+					labelStyle.Render(fmt.Sprintf("   %5d swaps   ", t.Swaps))+
+					accentStyle.Render(v4FormatVolume(t.Volume)+" "+p.Sym1))
+			}
+		}
+	}
+
+	parts = append(parts, "", centered(labelStyle).Render(p.Hint))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Align(lipgloss.Center).
+		Render(lipgloss.NewStyle().Width(containerWidth).Render(lipgloss.JoinVertical(lipgloss.Left, parts...)))
+}
+
+// feeLine summarises fee income and the APR it implies.
+func feeLine(p PoolAnalytics, labelStyle, accentStyle lipgloss.Style) string {
+	f := p.Fees
+	days := float64(f.Blocks) * 12 / 86400
+	line := labelStyle.Render("Fees: ") +
+		accentStyle.Render(fmt.Sprintf("%s %s + %s %s", v4FormatVolume(f.Fees0), p.Sym0, v4FormatVolume(f.Fees1), p.Sym1)) +
+		labelStyle.Render(fmt.Sprintf(" ≈ %s %s over %.1f days", v4FormatVolume(f.FeesValue), p.Sym1, days))
+	if f.TVL > 0 {
+		line += labelStyle.Render("   indexed TVL ≈ ") + accentStyle.Render(v4FormatVolume(f.TVL)+" "+p.Sym1)
+	}
+	if f.APR > 0 {
+		line += labelStyle.Render("   est. APR ") + lipgloss.NewStyle().Foreground(styles.CSuccess).Bold(true).
+			Render(fmt.Sprintf("%.2f%%", f.APR*100))
+	} else {
+		line += labelStyle.Render("   est. APR n/a")
+	}
+	return line
+}
+
+// candleChart draws candles as wicks (│) and bodies (┃, ━ when open equals
+// close) on a height-row grid, newest on the right, with the price scale on
+// the left and the block range underneath.
+func candleChart(candles []helpers.Candle, width, height int) string {
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	cols := width - chartLabelWidth - 1
+	if len(candles) == 0 || cols <= 0 {
+		return labelStyle.Render("no swaps indexed")
+	}
+	if len(candles) > cols {
+		candles = candles[len(candles)-cols:]
+	}
+
+	hi, lo := candles[0].High, candles[0].Low
+	for _, c := range candles {
+		hi, lo = math.Max(hi, c.High), math.Min(lo, c.Low)
+	}
+	if hi == lo {
+		hi, lo = hi*1.005, lo*0.995
+	}
+	step := (hi - lo) / float64(height)
+
+	up := lipgloss.NewStyle().Foreground(styles.CSuccess)
+	down := lipgloss.NewStyle().Foreground(styles.CError)
+	flat := lipgloss.NewStyle().Foreground(styles.CBorder)
+
+	rows := make([]string, 0, height+1)
+	for r := 0; r < height; r++ {
+		bandHi := hi - float64(r)*step
+		bandLo := bandHi - step
+		var label string
+		switch r {
+		case 0:
+			label = liquidityFormatPrice(hi)
+		case height / 2:
+			label = liquidityFormatPrice((hi + lo) / 2)
+		case height - 1:
+			label = liquidityFormatPrice(lo)
+		}
+		var b strings.Builder
+		b.WriteString(labelStyle.Render(fmt.Sprintf("%*s ", chartLabelWidth, label)))
+		for _, c := range candles {
+			style := up
+			switch {
+			case c.Swaps == 0:
+				style = flat
+			case c.Close < c.Open:
+				style = down
+			}
+			top, bot := math.Max(c.Open, c.Close), math.Min(c.Open, c.Close)
+			switch {
+			case top >= bandLo && bot <= bandHi && top == bot:
+				b.WriteString(style.Render("━"))
+			case top >= bandLo && bot <= bandHi:
+				b.WriteString(style.Render("┃"))
+			case c.High >= bandLo && c.Low <= bandHi:
+				b.WriteString(style.Render("│"))
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		rows = append(rows, b.String())
+	}
+
+	first := fmt.Sprintf("block %d", candles[0].StartBlock)
+	last := fmt.Sprintf("block %d", candles[len(candles)-1].StartBlock)
+	gap := helpers.Max(1, len(candles)-len(first)-len(last))
+	rows = append(rows, strings.Repeat(" ", chartLabelWidth+1)+labelStyle.Render(first+strings.Repeat(" ", gap)+last))
+	return strings.Join(rows, "\n")
+}
+
+// depthBlocks are the eighth-height bar glyphs, empty first.
+var depthBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// depthChart draws one bar per bucket, scaled to the busiest bucket, with
+// the bucket holding the current tick highlighted and marked underneath.
+func depthChart(buckets []helpers.DepthBucket, currentTick int32, decimals0, decimals1 uint8, width, height int) string {
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	if len(buckets) == 0 {
+		return labelStyle.Render("no liquidity events indexed")
+	}
+
+	values := make([]float64, len(buckets))
+	peak := 0.0
+	for i, b := range buckets {
+		values[i], _ = new(big.Float).SetInt(b.Liquidity).Float64()
+		peak = math.Max(peak, values[i])
+	}
+	if peak == 0 {
+		return labelStyle.Render("no active liquidity reconstructed near the current tick")
+	}
+
+	current := -1
+	for i, b := range buckets {
+		if currentTick >= b.TickLower && currentTick < b.TickUpper {
+			current = i
+		}
+	}
+	bar := lipgloss.NewStyle().Foreground(styles.CAccent2)
+	here := lipgloss.NewStyle().Foreground(styles.CAccent)
+
+	rows := make([]string, 0, height+2)
+	for r := 0; r < height; r++ {
+		var label string
+		switch r {
+		case 0:
+			label = "L " + v4FormatVolume(peak)
+		case height - 1:
+			label = "0"
+		}
+		base := (height - 1 - r) * 8
+		var b strings.Builder
+		b.WriteString(labelStyle.Render(fmt.Sprintf("%*s ", chartLabelWidth, label)))
+		for i, v := range values {
+			fill := int(math.Round(v/peak*float64(height*8))) - base
+			fill = helpers.Max(0, helpers.Min(8, fill))
+			style := bar
+			if i == current {
+				style = here
+			}
+			b.WriteString(style.Render(string(depthBlocks[fill])))
+		}
+		rows = append(rows, b.String())
+	}
+
+	pad := strings.Repeat(" ", chartLabelWidth+1)
+	if current >= 0 {
+		rows = append(rows, pad+strings.Repeat(" ", current)+here.Render("▲ current"))
+	}
+	left := liquidityFormatPrice(helpers.TickToPrice(buckets[0].TickLower, decimals0, decimals1))
+	right := liquidityFormatPrice(helpers.TickToPrice(buckets[len(buckets)-1].TickUpper, decimals0, decimals1))
+	gap := helpers.Max(1, len(buckets)-len(left)-len(right))
+	rows = append(rows, pad+labelStyle.Render(left+strings.Repeat(" ", gap)+right))
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,69 @@
+package uniswap
+
+import (
+	"strings"
+
+	"charm-wallet-tui/config"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PoolFilterWidth is the inner width of the pool event filter dialog.
+const PoolFilterWidth = 72
+
+// RenderPoolFilter renders the pool event monitor's filter dialog: the
+// expression being edited, the filter in force, and the saved presets.
+// While naming is set the preset name line takes the cursor instead.
+func RenderPoolFilter(input, active, name string, naming bool, errMsg string, presets []config.PoolMonitorPreset, selected int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	rowStyle := lipgloss.NewStyle().Foreground(styles.CText)
+	selStyle := lipgloss.NewStyle().Background(styles.CPanel).Foreground(styles.CAccent2).Bold(true)
+	errStyle := lipgloss.NewStyle().Foreground(styles.CFail)
+	wrap := lipgloss.NewStyle().Width(PoolFilterWidth)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("Pool Event Filter"))
+	lines = append(lines, wrap.Inherit(mutedStyle).Render("pool:0x… token:USDC,0x… sender:0x… min:25k kind:swap,liquidity,init,donate"))
+	lines = append(lines, "")
+
+	cursor := func(on bool) string {
+		if on {
+			return "▏"
+		}
+		return ""
+	}
+	lines = append(lines, wrap.Inherit(rowStyle).Render("Filter: "+input+cursor(!naming)))
+	if active == "" {
+		active = "(none — all events)"
+	}
+	lines = append(lines, wrap.Inherit(mutedStyle).Render("Active: "+active))
+	if naming {
+		lines = append(lines, rowStyle.Render("Preset name: "+name+cursor(true)))
+	}
+	if errMsg != "" {
+		lines = append(lines, wrap.Inherit(errStyle).Render(errMsg))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, titleStyle.Render("Presets"))
+	if len(presets) == 0 {
+		lines = append(lines, mutedStyle.Render("  none saved"))
+	}
+	for i, p := range presets {
+		name := rowStyle.Render("  " + p.Name)
+		if i == selected {
+			name = selStyle.Render("▶ " + p.Name)
+		}
+		lines = append(lines, name+"  "+mutedStyle.Render(p.Filter))
+	}
+	lines = append(lines, "")
+
+	help := "Enter apply • ↑/↓ preset • Tab load preset • Ctrl+S save as preset • Ctrl+D delete preset • Esc close"
+	if naming {
+		help = "Enter save preset • Esc cancel"
+	}
+	lines = append(lines, wrap.Inherit(mutedStyle).Render(help))
+	return strings.Join(lines, "\n")
+}
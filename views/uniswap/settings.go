@@ -0,0 +1,75 @@
+package uniswap
+
+import (
+	"charm-wallet-tui/helpers"
+	"charm-wallet-tui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SwapSettingsForm is the state RenderSettings draws: the two edit buffers,
+// the approval-mode toggle, which field has focus, and the current
+// validation error or warning.
+type SwapSettingsForm struct {
+	Slippage string // percent, e.g. "0.5"
+	Deadline string // minutes
+	Permit   bool   // approve Universal Router swaps with a signed Permit2 permit
+	Focused  int    // 0=slippage, 1=deadline, 2=approval mode
+	Err      string
+	Warn     string
+	Default  string // the persisted default, e.g. "0.50% · 20 min"
+}
+
+// RenderSettings renders the swap's slippage tolerance, deadline and
+// approval-mode form.
+func RenderSettings(width, height int, f SwapSettingsForm) string {
+	containerWidth := helpers.Min(60, width-4)
+
+	title := lipgloss.NewStyle().
+		Foreground(styles.CAccent2).
+		Bold(true).
+		Align(lipgloss.Center).
+		Width(containerWidth).
+		Render("⚙ Swap Settings")
+
+	labelStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
+	valueStyle := lipgloss.NewStyle().Foreground(styles.CAccent2)
+	field := func(label, value, unit string, focused bool) string {
+		if value == "" {
+			value = "_"
+		}
+		content := labelStyle.Render(label) + "\n" + valueStyle.Render(value) + labelStyle.Render(" "+unit)
+		if focused {
+			return styles.CardFocused.Width(containerWidth - 4).Render(content)
+		}
+		return styles.CardNormal.Width(containerWidth - 4).Render(content)
+	}
+
+	parts := []string{
+		title, "",
+		field("Slippage tolerance", f.Slippage, "%", f.Focused == 0),
+		field("Transaction deadline", f.Deadline, "minutes", f.Focused == 1),
+	}
+	approval := "○ Approval transactions (Permit2.approve, on-chain)"
+	if f.Permit {
+		approval = "● Permit2 signature (signed off-chain, one transaction)"
+	}
+	card := styles.CardNormal
+	if f.Focused == 2 {
+		card = styles.CardFocused
+	}
+	parts = append(parts, card.Width(containerWidth-4).Render(labelStyle.Render("Universal Router approval")+"\n"+valueStyle.Render(approval)))
+	if f.Err != "" {
+		parts = append(parts, "", lipgloss.NewStyle().Foreground(styles.CError).Width(containerWidth).Align(lipgloss.Center).Render(f.Err))
+	} else if f.Warn != "" {
+		parts = append(parts, "", lipgloss.NewStyle().Foreground(styles.CWarn).Width(containerWidth).Align(lipgloss.Center).Render(f.Warn))
+	}
+	parts = append(parts, "",
+		labelStyle.Width(containerWidth).Align(lipgloss.Center).Render("Default: "+f.Default),
+		labelStyle.Width(containerWidth).Align(lipgloss.Center).Render("Space toggle • Enter apply to this swap • d save as default • Esc cancel"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Align(lipgloss.Center).
+		Render(lipgloss.JoinVertical(lipgloss.Center, parts...))
+}
@@ -25,48 +25,15 @@ type TokenOption struct {
 	Address  common.Address
 }
 
-// Nav returns the navigation bar for Uniswap view.
-// poolMonitorActive controls the color of the pool event monitor hotkey.
-// liquidityActive controls the color of the liquidity hotkey.
-// blockScanActive controls the color of the block scan hotkey.
-func Nav(width int, poolMonitorActive, liquidityActive, blockScanActive bool) string {
-	var pItem string
-	if poolMonitorActive {
-		pKey := lipgloss.NewStyle().Foreground(styles.CError).Bold(true).Render("p")
-		pLabel := lipgloss.NewStyle().Foreground(styles.CWarn).Render("pool event monitor")
-		pItem = pKey + " " + pLabel
-	} else {
-		pItem = styles.Key("p") + " pool event monitor"
-	}
-
-	var qItem string
-	if liquidityActive {
-		qKey := lipgloss.NewStyle().Foreground(styles.CAccent2).Bold(true).Render("q")
-		qLabel := lipgloss.NewStyle().Foreground(styles.CAccent2).Render("liquidity positions")
-		qItem = qKey + " " + qLabel
-	} else {
-		qItem = styles.Key("q") + " liquidity positions"
-	}
-
-	var bItem string
-	if blockScanActive {
-		bKey := lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true).Render("b")
-		bLabel := lipgloss.NewStyle().Foreground(styles.CAccent).Render("block scan")
-		bItem = bKey + " " + bLabel
-	} else {
-		bItem = styles.Key("b") + " block scan"
-	}
-
-	left := strings.Join([]string{
-		styles.Key("↑/↓") + " navigate",
-		styles.Key("m") + " max",
-		styles.Key("l") + " logger",
-		pItem,
-		qItem,
-		bItem,
-	}, "   ")
-
-	return styles.NavStyle.Width(width).Render(left)
+// PoolRow is one line of the swap view's pool comparison: the router's pick
+// or a single pool, with its quote preformatted by the caller.
+type PoolRow struct {
+	Label     string // e.g. "V3 0.05%", or "Best route" for the router's pick
+	Output    string // quoted output, or why the pool has none
+	Impact    string
+	Liquidity string
+	Selected  bool // the swap will be packaged through this row
+	Failed    bool // quoting this pool failed
 }
 
 // SwapGeometry reports hit-test rectangles for the From/To token boxes and
@@ -78,10 +45,15 @@ type SwapGeometry struct {
 	FromY, FromX1, FromX2, FromH int
 	ToY, ToX1, ToX2, ToH         int
 	SwapY, SwapX1, SwapX2, SwapH int
+	PoolY, PoolX1, PoolX2        int // first pool comparison row; one row per PoolRow
 }
 
-// Render renders the Uniswap swap interface
-func Render(width, height int, tokens []TokenOption, fromIdx, toIdx int, fromAmount, toAmount string, focusedField int, estimating, resolvingPair bool, priceImpactWarn, hookWarn string) (string, SwapGeometry) {
+// Render renders the Uniswap swap interface. route is the router's one-line
+// path summary for the current quote and minReceived the slippage-adjusted
+// minimum, both shown under the To box when non-empty; pools, when
+// non-empty, is listed below them as a comparison table. slippageWarn flags
+// an unusual slippage tolerance.
+func Render(width, height int, tokens []TokenOption, fromIdx, toIdx int, fromAmount, toAmount string, focusedField int, estimating, resolvingPair bool, priceImpactWarn, hookWarn, route, minReceived, slippageWarn string, pools []PoolRow) (string, SwapGeometry) {
 	// Create the main swap container
 	containerWidth := helpers.Min(80, width-4)
 	
@@ -186,7 +158,7 @@ func Render(width, height int, tokens []TokenOption, fromIdx, toIdx int, fromAmo
 	if toAmount == "" || estimating || resolvingPair {
 		displayText := "0.0"
 		if resolvingPair {
-			displayText = "Finding pool..."
+			displayText = "Finding routes..."
 		} else if estimating {
 			displayText = "Estimating..."
 		}
@@ -207,6 +179,27 @@ func Render(width, height int, tokens []TokenOption, fromIdx, toIdx int, fromAmo
 		toBox = tokenBoxStyle.Render(toContent)
 	}
 	
+	// Route the quote was priced along (if any)
+	var routeDisplay string
+	if route != "" {
+		routeDisplay = lipgloss.NewStyle().
+			Foreground(styles.CMuted).
+			Width(containerWidth).
+			Align(lipgloss.Center).
+			Render(route)
+	}
+
+	var minReceivedDisplay string
+	if minReceived != "" {
+		minReceivedDisplay = lipgloss.NewStyle().
+			Foreground(styles.CMuted).
+			Width(containerWidth).
+			Align(lipgloss.Center).
+			Render(minReceived)
+	}
+
+	poolTable := renderPoolTable(containerWidth, pools)
+
 	// Price impact warning (if any)
 	var warningDisplay string
 	if priceImpactWarn != "" {
@@ -269,6 +262,18 @@ func Render(width, height int, tokens []TokenOption, fromIdx, toIdx int, fromAmo
 	contentParts = append(contentParts, fromBox, swapArrow)
 	toBoxIdx := len(contentParts)
 	contentParts = append(contentParts, toBox)
+	if routeDisplay != "" {
+		contentParts = append(contentParts, routeDisplay)
+	}
+	if minReceivedDisplay != "" {
+		contentParts = append(contentParts, minReceivedDisplay)
+	}
+	poolTableIdx := -1
+	if poolTable != "" {
+		contentParts = append(contentParts, "")
+		poolTableIdx = len(contentParts)
+		contentParts = append(contentParts, poolTable)
+	}
 
 	// Add warnings if present
 	if warningDisplay != "" {
@@ -277,6 +282,13 @@ func Render(width, height int, tokens []TokenOption, fromIdx, toIdx int, fromAmo
 	if hookWarningDisplay != "" {
 		contentParts = append(contentParts, "", hookWarningDisplay)
 	}
+	if slippageWarn != "" {
+		contentParts = append(contentParts, "", lipgloss.NewStyle().
+			Foreground(styles.CWarn).
+			Width(containerWidth).
+			Align(lipgloss.Center).
+			Render(slippageWarn))
+	}
 
 	contentParts = append(contentParts, "")
 	swapButtonIdx := len(contentParts)
@@ -322,10 +334,42 @@ func Render(width, height int, tokens []TokenOption, fromIdx, toIdx int, fromAmo
 	geo.FromX2 = geo.FromX1 + lipgloss.Width(fromBox)
 	geo.ToX2 = geo.ToX1 + lipgloss.Width(toBox)
 	geo.SwapX2 = geo.SwapX1 + lipgloss.Width(swapButton)
+	if poolTableIdx >= 0 {
+		// Rows start below the table's header line.
+		geo.PoolY = rowY(poolTableIdx) + 1
+		geo.PoolX1 = outerPad + colX(poolTable)
+		geo.PoolX2 = geo.PoolX1 + lipgloss.Width(poolTable)
+	}
 
 	return rendered, geo
 }
 
+// renderPoolTable renders the pool comparison: a header line, then one
+// fixed-column line per row with the selected row marked.
+func renderPoolTable(width int, rows []PoolRow) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	line := func(marker, label, output, impact, liq string) string {
+		return fmt.Sprintf("%s%-16s %-22s %-9s %s", marker, label, output, impact, liq)
+	}
+	lines := []string{lipgloss.NewStyle().Foreground(styles.CMuted).
+		Render(line("  ", "Pool", "Output", "Impact", "Liquidity"))}
+	for _, r := range rows {
+		marker := "  "
+		style := lipgloss.NewStyle().Foreground(styles.CText)
+		switch {
+		case r.Selected:
+			marker = "▸ "
+			style = lipgloss.NewStyle().Foreground(styles.CAccent).Bold(true)
+		case r.Failed:
+			style = lipgloss.NewStyle().Foreground(styles.CMuted)
+		}
+		lines = append(lines, style.Render(line(marker, r.Label, r.Output, r.Impact, r.Liquidity)))
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(strings.Join(lines, "\n"))
+}
+
 // RenderTokenSelector renders a token selection popup
 func RenderTokenSelector(width, height int, tokens []TokenOption, selectedIdx int, isForFromField bool) string {
 	title := "Select Token"
@@ -387,7 +431,8 @@ func RenderTokenSelector(width, height int, tokens []TokenOption, selectedIdx in
 	)
 }
 
-// RenderLiquidity renders the Uniswap V3 liquidity positions view.
+// RenderLiquidity renders the wallet's Uniswap V3 and V4 liquidity positions,
+// each card badged with its version and in-range status.
 func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, loading bool, focusedIdx int, errMsg, spinView string) string {
 	containerWidth := helpers.Min(80, width-4)
 
@@ -421,7 +466,7 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 			Foreground(styles.CMuted).
 			Align(lipgloss.Center).
 			Width(containerWidth).
-			Render("No V4 liquidity positions found for this address")
+			Render("No V3 or V4 liquidity positions found for this address")
 
 	default:
 		cardWidth := containerWidth - 4
@@ -438,10 +483,11 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 		var cards []string
 		for i, pos := range positions {
 			idStr := "#" + pos.TokenID.String()
+			badge := liquidityVersionBadge(pos.Version)
 
 			// Stub card when positions() call failed entirely.
 			if pos.Stub {
-				content := boldStyle.Render(idStr) + "\n" +
+				content := badge + " " + boldStyle.Render(idStr) + "\n" +
 					warnStyle.Render("positions() call failed — raw NFT token only")
 				var card string
 				if i == focusedIdx {
@@ -456,9 +502,13 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 			feePercent := fmt.Sprintf("%.4f%%", float64(pos.Fee)/10000.0)
 			pair := pos.Token0Symbol + "/" + pos.Token1Symbol
 
-			headerLine := boldStyle.Render(pair) +
+			headerLine := badge + " " + boldStyle.Render(pair) +
 				"   " + valueStyle.Render(feePercent) +
-				"   " + mutedStyle.Render(idStr)
+				"   " + mutedStyle.Render(idStr) +
+				"   " + liquidityRangeStatus(pos)
+			if pos.USDValue > 0 {
+				headerLine += "   " + accentStyle.Render(liquidityFormatUSD(pos.USDValue))
+			}
 
 			tok0Line := labelStyle.Render("Token0: ") + valueStyle.Render(pos.Token0Symbol) +
 				mutedStyle.Render("  "+pos.Token0.Hex())
@@ -466,8 +516,13 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 				mutedStyle.Render("  "+pos.Token1.Hex())
 
 			tickLine := labelStyle.Render("Ticks:  ") +
-				valueStyle.Render(fmt.Sprintf("%d → %d", pos.TickLower, pos.TickUpper)) +
-				mutedStyle.Render(fmt.Sprintf("  spacing=%d", pos.TickSpacing))
+				valueStyle.Render(fmt.Sprintf("%d → %d", pos.TickLower, pos.TickUpper))
+			if pos.PoolTickKnown {
+				tickLine += mutedStyle.Render(fmt.Sprintf("  current=%d", pos.PoolTick))
+			}
+			if pos.Version == helpers.PoolVersionV4 {
+				tickLine += mutedStyle.Render(fmt.Sprintf("  spacing=%d", pos.TickSpacing))
+			}
 
 			minStr := liquidityFormatPrice(pos.MinPrice)
 			maxStr := liquidityFormatPrice(pos.MaxPrice)
@@ -482,15 +537,21 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 			liqLine := labelStyle.Render("Liq:    ") +
 				lipgloss.NewStyle().Foreground(styles.CText).Render(liqVal)
 
-			hooksLine := labelStyle.Render("Hooks:  ") + mutedStyle.Render(pos.Hooks.Hex())
-
 			content := headerLine + "\n" +
 				tok0Line + "\n" +
 				tok1Line + "\n" +
 				tickLine + "\n" +
 				rangeLine + "\n" +
-				liqLine + "\n" +
-				hooksLine
+				liqLine
+
+			if pos.Amount0 != nil && pos.Amount1 != nil {
+				content += "\n" + labelStyle.Render("Holds:  ") + valueStyle.Render(
+					liquidityFormatAmount(pos.Amount0, pos.Token0Decimals)+" "+pos.Token0Symbol+" / "+
+						liquidityFormatAmount(pos.Amount1, pos.Token1Decimals)+" "+pos.Token1Symbol)
+			}
+			if pos.Version == helpers.PoolVersionV4 {
+				content += "\n" + labelStyle.Render("Hooks:  ") + mutedStyle.Render(pos.Hooks.Hex())
+			}
 
 			if (pos.TokensOwed0 != nil && pos.TokensOwed0.Sign() > 0) ||
 				(pos.TokensOwed1 != nil && pos.TokensOwed1.Sign() > 0) {
@@ -500,6 +561,9 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 					accentStyle.Render(f0+" "+pos.Token0Symbol+" / "+f1+" "+pos.Token1Symbol)
 				content += "\n" + feesLine
 			}
+			if pos.PnL != nil {
+				content += "\n" + liquidityPnLLines(pos, labelStyle, valueStyle, mutedStyle)
+			}
 
 			var card string
 			if i == focusedIdx {
@@ -516,7 +580,7 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 		Foreground(styles.CMuted).
 		Width(containerWidth).
 		Align(lipgloss.Center).
-		Render("↑/↓ navigate   Esc back to swap")
+		Render("↑/↓ navigate  + add  - remove  c collect  x burn  n new position  Esc back")
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
@@ -529,6 +593,83 @@ func RenderLiquidity(width, height int, positions []helpers.LiquidityPosition, l
 		Render(content)
 }
 
+// liquidityVersionBadge renders a position's "V3"/"V4" badge.
+func liquidityVersionBadge(v helpers.PoolVersion) string {
+	label, color := "V4", styles.CAccent2
+	if v == helpers.PoolVersionV3 {
+		label, color = "V3", styles.CAccent
+	}
+	return lipgloss.NewStyle().Foreground(styles.CBg).Background(color).Bold(true).Padding(0, 1).Render(label)
+}
+
+// liquidityRangeStatus renders whether a position is earning fees: in range,
+// out of range, or closed (no liquidity left).
+func liquidityRangeStatus(pos helpers.LiquidityPosition) string {
+	switch {
+	case pos.Liquidity == nil || pos.Liquidity.Sign() == 0:
+		return lipgloss.NewStyle().Foreground(styles.CMuted).Render("○ closed")
+	case !pos.PoolTickKnown:
+		return lipgloss.NewStyle().Foreground(styles.CMuted).Render("? range unknown")
+	case pos.InRange():
+		return lipgloss.NewStyle().Foreground(styles.CSuccess).Render("● in range")
+	}
+	return lipgloss.NewStyle().Foreground(styles.CWarn).Render("○ out of range")
+}
+
+// liquidityFormatUSD formats a position's USD value, e.g. "$12,345.67".
+func liquidityFormatUSD(v float64) string {
+	whole := fmt.Sprintf("%.2f", v)
+	intPart, frac := whole[:len(whole)-3], whole[len(whole)-3:]
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return "$" + b.String() + frac
+}
+
+// liquidityPnLLines renders a position's deposits, PnL and impermanent
+// loss.
+func liquidityPnLLines(pos helpers.LiquidityPosition, labelStyle, valueStyle, mutedStyle lipgloss.Style) string {
+	r := pos.PnL
+	lines := []string{labelStyle.Render("Dep:    ") + valueStyle.Render(
+		liquidityFormatAmount(r.Deposited0, pos.Token0Decimals)+" "+pos.Token0Symbol+" / "+
+			liquidityFormatAmount(r.Deposited1, pos.Token1Decimals)+" "+pos.Token1Symbol)}
+	if r.FirstBlock > 0 {
+		lines[0] += mutedStyle.Render(fmt.Sprintf("  since block %d", r.FirstBlock))
+	}
+	if r.Withdrawn0.Sign() > 0 || r.Withdrawn1.Sign() > 0 {
+		lines = append(lines, labelStyle.Render("Wdr:    ")+valueStyle.Render(
+			liquidityFormatAmount(r.Withdrawn0, pos.Token0Decimals)+" "+pos.Token0Symbol+" / "+
+				liquidityFormatAmount(r.Withdrawn1, pos.Token1Decimals)+" "+pos.Token1Symbol))
+	}
+	if r.Priced {
+		signed := func(v float64) string {
+			style := lipgloss.NewStyle().Foreground(styles.CSuccess)
+			if v < 0 {
+				style = style.Foreground(styles.CError)
+			}
+			sign := "+"
+			if v < 0 {
+				sign = "-"
+			}
+			return style.Render(sign + liquidityFormatUSD(math.Abs(v)))
+		}
+		lines = append(lines,
+			labelStyle.Render("PnL:    ")+signed(r.NetUSD)+
+				mutedStyle.Render(fmt.Sprintf("  on %s deposited · fees ", liquidityFormatUSD(r.DepositUSD)))+
+				signed(r.FeesUSD),
+			labelStyle.Render("IL:     ")+signed(r.ILUSD)+
+				mutedStyle.Render(fmt.Sprintf("  (%+.2f%%) vs holding %s", r.ILPct, liquidityFormatUSD(r.HoldUSD))))
+	}
+	if len(r.Notes) > 0 {
+		lines = append(lines, mutedStyle.Render("        "+strings.Join(r.Notes, "; ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // liquidityFormatPrice formats a tick-derived price for display.
 func liquidityFormatPrice(price float64) string {
 	if price <= 0 || math.IsInf(price, 0) || math.IsNaN(price) {
@@ -550,17 +691,23 @@ func liquidityFormatPrice(price float64) string {
 
 
 // V4EventsContent builds the scrollable body string (pool cards) for the V4 Events panel.
-// width is the outer panel width; the content is sized to fit inside it.
-func V4EventsContent(width int, pools []store.PoolRow) string {
-	containerWidth := helpers.Min(width-2, 120)
-
+// width is the outer panel width; the content is sized to fit inside it. The
+// card at index selected is highlighted.
+func V4EventsContent(width int, pools []store.PoolRow, selected int) string {
 	if len(pools) == 0 {
 		return lipgloss.NewStyle().
 			Foreground(styles.CMuted).
 			Align(lipgloss.Center).
-			Width(containerWidth).
+			Width(helpers.Min(width-2, 120)).
 			Render("Listening for V4 pool events…")
 	}
+	return strings.Join(V4EventsCards(width, pools, selected), "\n")
+}
+
+// V4EventsCards renders one card per pool, in the order V4EventsContent
+// stacks them, so callers can map content lines back to pools.
+func V4EventsCards(width int, pools []store.PoolRow, selected int) []string {
+	containerWidth := helpers.Min(width-2, 120)
 
 	labelStyle := lipgloss.NewStyle().Foreground(styles.CMuted)
 	accentStyle := lipgloss.NewStyle().Foreground(styles.CAccent)
@@ -569,10 +716,13 @@ func V4EventsContent(width int, pools []store.PoolRow) string {
 	warnStyle := lipgloss.NewStyle().Foreground(styles.CWarn)
 	eventTypeStyle := lipgloss.NewStyle().Foreground(styles.CBorder).Bold(true).Align(lipgloss.Center)
 	cardWidth := containerWidth - 4
-	card := styles.CardNormal.Width(cardWidth)
 
 	var cards []string
-	for _, r := range pools {
+	for i, r := range pools {
+		card := styles.CardNormal.Width(cardWidth)
+		if i == selected {
+			card = styles.CardFocused.Width(cardWidth)
+		}
 		poolLink := helpers.HyperPoolID(common.HexToHash(r.PoolID))
 
 		tok0Sym := r.Token0Sym
@@ -641,12 +791,13 @@ func V4EventsContent(width int, pools []store.PoolRow) string {
 		content := typeLine + "\n" + headerLine + "\n" + tok0Line + "\n" + tok1Line + "\n" + metaLine + "\n" + blockLine + hooksLine
 		cards = append(cards, card.Render(content))
 	}
-	return strings.Join(cards, "\n")
+	return cards
 }
 
 // RenderV4Events renders the V4 Events panel shown when the Pool Event Monitor is active.
 // vp must have its content pre-set via V4EventsContent; width/height are the available dimensions.
-func RenderV4Events(width, height int, vp viewport.Model) string {
+// filter is the monitor's filter expression, shown in the title when set.
+func RenderV4Events(width, height int, vp viewport.Model, filter string) string {
 	containerWidth := helpers.Min(width-2, 120)
 
 	titleStyle := lipgloss.NewStyle().
@@ -654,13 +805,17 @@ func RenderV4Events(width, height int, vp viewport.Model) string {
 		Bold(true).
 		Align(lipgloss.Center).
 		Width(containerWidth)
-	title := titleStyle.Render("🦄 Uniswap V4 Events")
+	titleText := "🦄 Uniswap V4 Events"
+	if filter != "" {
+		titleText += "  ·  filter: " + filter
+	}
+	title := titleStyle.Render(titleText)
 
 	infoText := lipgloss.NewStyle().
 		Foreground(styles.CMuted).
 		Width(containerWidth).
 		Align(lipgloss.Center).
-		Render("click pool ID → pool info   click address → Etherscan   ↑↓/PgUp/PgDn to scroll")
+		Render("{/} select   o/double-click → pool analytics   f filter   click pool ID → pool info   click address → Etherscan   PgUp/PgDn scroll")
 
 	// Reserve lines for title (1), blank (1), info (1), blank (1) = 4 lines overhead.
 	vpHeight := helpers.Max(1, height-4)
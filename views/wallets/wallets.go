@@ -37,6 +37,12 @@ func Nav(width int, indexerActive bool) string {
 		styles.Key("s") + " settings",
 		styles.Key("b") + " dApps",
 		styles.Key("w") + " watched",
+		styles.Key("o") + " outbox",
+		styles.Key("!") + " alerts",
+		styles.Key("/") + " explorer",
+		styles.Key("t") + " history",
+		styles.Key("p") + " approvals",
+		styles.Key("c") + " contacts",
 		styles.Key("l") + " logger",
 		iItem,
 		styles.Key("Esc") + " quit",